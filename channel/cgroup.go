@@ -0,0 +1,190 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package channel
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+	"github.com/chaosblade-io/chaosblade-spec-go/util"
+)
+
+// CgroupLimitKey is the context key of the resource limits of the executed command
+const CgroupLimitKey spec.ContextKey = "cgroupLimit"
+
+const cpuPeriodUs = 100000
+
+// cgroupRoot is replaced in the tests
+var cgroupRoot = spec.DefaultCGroupPath
+
+// CgroupLimit defines the cpu and memory caps of the cgroup which the command runs in
+type CgroupLimit struct {
+	// CPUPercent is the cpu quota in percent of one core, 0 means no limit
+	CPUPercent int
+	// MemoryBytes is the memory limit in bytes, 0 means no limit
+	MemoryBytes int64
+}
+
+// WithCgroupLimit returns a copy of ctx that makes the local channel run the command in a new cgroup
+// restricted by the limit. Both cgroup v1 and v2 are supported.
+func WithCgroupLimit(ctx context.Context, limit *CgroupLimit) context.Context {
+	return context.WithValue(ctx, CgroupLimitKey, limit)
+}
+
+func getCgroupLimit(ctx context.Context) *CgroupLimit {
+	limit, ok := ctx.Value(CgroupLimitKey).(*CgroupLimit)
+	if !ok || limit == nil || (limit.CPUPercent <= 0 && limit.MemoryBytes <= 0) {
+		return nil
+	}
+	return limit
+}
+
+// limitedCgroup is the cgroup created for one command execution
+type limitedCgroup struct {
+	// dirs contains the cgroup directory of each hierarchy, only one for cgroup v2
+	dirs []string
+}
+
+// newLimitedCgroup creates the cgroup directories and writes the limits
+func newLimitedCgroup(ctx context.Context, limit *CgroupLimit) (*limitedCgroup, error) {
+	name := fmt.Sprintf("chaosblade-%s", util.GenerateExecID())
+	cg := &limitedCgroup{dirs: make([]string, 0)}
+	var err error
+	if isCgroupV2() {
+		err = cg.setupV2(name, limit)
+	} else {
+		err = cg.setupV1(name, limit)
+	}
+	if err != nil {
+		cg.remove(ctx)
+		return nil, err
+	}
+	return cg, nil
+}
+
+// isCgroupV2 returns true if the cgroup root is the unified hierarchy, like util.CgroupModeUnified
+func isCgroupV2() bool {
+	return util.IsExist(path.Join(cgroupRoot, "cgroup.controllers"))
+}
+
+func (cg *limitedCgroup) setupV2(name string, limit *CgroupLimit) error {
+	controllers := make([]string, 0)
+	if limit.CPUPercent > 0 {
+		controllers = append(controllers, "+cpu")
+	}
+	if limit.MemoryBytes > 0 {
+		controllers = append(controllers, "+memory")
+	}
+	err := writeCgroupFile(path.Join(cgroupRoot, "cgroup.subtree_control"), strings.Join(controllers, " "))
+	if err != nil {
+		return err
+	}
+	dir, err := cg.mkdir(path.Join(cgroupRoot, name))
+	if err != nil {
+		return err
+	}
+	if limit.CPUPercent > 0 {
+		quota := fmt.Sprintf("%d %d", limit.CPUPercent*cpuPeriodUs/100, cpuPeriodUs)
+		if err := writeCgroupFile(path.Join(dir, "cpu.max"), quota); err != nil {
+			return err
+		}
+	}
+	if limit.MemoryBytes > 0 {
+		if err := writeCgroupFile(path.Join(dir, "memory.max"), strconv.FormatInt(limit.MemoryBytes, 10)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cg *limitedCgroup) setupV1(name string, limit *CgroupLimit) error {
+	if limit.CPUPercent > 0 {
+		dir, err := cg.mkdir(path.Join(cgroupRoot, "cpu", name))
+		if err != nil {
+			return err
+		}
+		if err := writeCgroupFile(path.Join(dir, "cpu.cfs_period_us"), strconv.Itoa(cpuPeriodUs)); err != nil {
+			return err
+		}
+		quota := strconv.Itoa(limit.CPUPercent * cpuPeriodUs / 100)
+		if err := writeCgroupFile(path.Join(dir, "cpu.cfs_quota_us"), quota); err != nil {
+			return err
+		}
+	}
+	if limit.MemoryBytes > 0 {
+		dir, err := cg.mkdir(path.Join(cgroupRoot, "memory", name))
+		if err != nil {
+			return err
+		}
+		limitBytes := strconv.FormatInt(limit.MemoryBytes, 10)
+		if err := writeCgroupFile(path.Join(dir, "memory.limit_in_bytes"), limitBytes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cg *limitedCgroup) mkdir(dir string) (string, error) {
+	if err := os.Mkdir(dir, 0755); err != nil {
+		return "", fmt.Errorf("create cgroup %s failed, %v", dir, err)
+	}
+	cg.dirs = append(cg.dirs, dir)
+	return dir, nil
+}
+
+// joinScript returns the shell script that moves the current shell into the cgroup
+func (cg *limitedCgroup) joinScript() string {
+	joins := make([]string, 0)
+	for _, dir := range cg.dirs {
+		joins = append(joins, "echo $$ > "+spec.QuoteArgs([]string{path.Join(dir, "cgroup.procs")}))
+	}
+	return strings.Join(joins, " && ")
+}
+
+// wrap returns the command arguments which join the cgroup before replacing the shell with the original command.
+// If root is not empty, the original command is executed by chroot after joining the cgroup, the root is quoted
+// so the shell special characters in it are not interpreted.
+func (cg *limitedCgroup) wrap(args []string, root string) []string {
+	execLine := `exec "$0" "$@"`
+	if root != "" {
+		execLine = "exec chroot " + spec.QuoteArgs([]string{root}) + ` "$0" "$@"`
+	}
+	return append([]string{"-c", cg.joinScript() + " && " + execLine}, args...)
+}
+
+// remove deletes the cgroup directories, it fails if any process is still in the cgroup
+func (cg *limitedCgroup) remove(ctx context.Context) {
+	for idx := len(cg.dirs) - 1; idx >= 0; idx-- {
+		if err := os.Remove(cg.dirs[idx]); err != nil {
+			log.Warnf(ctx, "remove cgroup %s failed, %v", cg.dirs[idx], err)
+		}
+	}
+}
+
+func writeCgroupFile(file, value string) error {
+	if err := ioutil.WriteFile(file, []byte(value), 0644); err != nil {
+		return fmt.Errorf("write %s to %s failed, %v", value, file, err)
+	}
+	return nil
+}
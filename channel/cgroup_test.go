@@ -0,0 +1,147 @@
+//go:build !windows
+// +build !windows
+
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package channel
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeCgroupRoot replaces the cgroup root by a temporary directory, the v2 root has cgroup.controllers,
+// the v1 root has the cpu and memory hierarchies
+func fakeCgroupRoot(t *testing.T, v2 bool) string {
+	root := t.TempDir()
+	if v2 {
+		os.WriteFile(filepath.Join(root, "cgroup.controllers"), []byte("cpu memory"), 0644)
+	} else {
+		os.Mkdir(filepath.Join(root, "cpu"), 0755)
+		os.Mkdir(filepath.Join(root, "memory"), 0755)
+	}
+	original := cgroupRoot
+	cgroupRoot = root
+	t.Cleanup(func() {
+		cgroupRoot = original
+	})
+	return root
+}
+
+func readCgroupFile(t *testing.T, file string) string {
+	bytes, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("read %s failed, %v", file, err)
+	}
+	return string(bytes)
+}
+
+func TestLimitedCgroupV2(t *testing.T) {
+	root := fakeCgroupRoot(t, true)
+	cg, err := newLimitedCgroup(context.Background(), &CgroupLimit{CPUPercent: 50, MemoryBytes: 1024})
+	if err != nil {
+		t.Fatalf("newLimitedCgroup() error = %v", err)
+	}
+	if len(cg.dirs) != 1 || filepath.Dir(cg.dirs[0]) != root {
+		t.Fatalf("newLimitedCgroup() dirs = %v, want one under %s", cg.dirs, root)
+	}
+	if got := readCgroupFile(t, filepath.Join(root, "cgroup.subtree_control")); got != "+cpu +memory" {
+		t.Errorf("cgroup.subtree_control = %q", got)
+	}
+	if got := readCgroupFile(t, filepath.Join(cg.dirs[0], "cpu.max")); got != "50000 100000" {
+		t.Errorf("cpu.max = %q", got)
+	}
+	if got := readCgroupFile(t, filepath.Join(cg.dirs[0], "memory.max")); got != "1024" {
+		t.Errorf("memory.max = %q", got)
+	}
+
+	cmd := exec.Command("/bin/sh", cg.wrap([]string{"echo", "hello"}, "")...)
+	if output, err := cmd.CombinedOutput(); err != nil || string(output) != "hello\n" {
+		t.Errorf("run the wrapped command = %q, %v", output, err)
+	}
+	procs := strings.TrimSpace(readCgroupFile(t, filepath.Join(cg.dirs[0], "cgroup.procs")))
+	if _, err := strconv.Atoi(procs); err != nil {
+		t.Errorf("cgroup.procs = %q, want the pid of the shell", procs)
+	}
+
+	// the fake cgroup directory is not empty, unlike the cgroupfs
+	for _, file := range []string{"cpu.max", "memory.max", "cgroup.procs"} {
+		os.Remove(filepath.Join(cg.dirs[0], file))
+	}
+	cg.remove(context.Background())
+	if _, err := os.Stat(cg.dirs[0]); !os.IsNotExist(err) {
+		t.Errorf("remove() left the cgroup %s, %v", cg.dirs[0], err)
+	}
+}
+
+func TestLimitedCgroupV1(t *testing.T) {
+	root := fakeCgroupRoot(t, false)
+	cg, err := newLimitedCgroup(context.Background(), &CgroupLimit{CPUPercent: 150, MemoryBytes: 2048})
+	if err != nil {
+		t.Fatalf("newLimitedCgroup() error = %v", err)
+	}
+	if len(cg.dirs) != 2 || filepath.Dir(cg.dirs[0]) != filepath.Join(root, "cpu") ||
+		filepath.Dir(cg.dirs[1]) != filepath.Join(root, "memory") {
+		t.Fatalf("newLimitedCgroup() dirs = %v, want the cpu and memory hierarchies", cg.dirs)
+	}
+	for file, want := range map[string]string{
+		filepath.Join(cg.dirs[0], "cpu.cfs_period_us"):     "100000",
+		filepath.Join(cg.dirs[0], "cpu.cfs_quota_us"):      "150000",
+		filepath.Join(cg.dirs[1], "memory.limit_in_bytes"): "2048",
+	} {
+		if got := readCgroupFile(t, file); got != want {
+			t.Errorf("%s = %q, want %q", file, got, want)
+		}
+	}
+	if script := cg.joinScript(); strings.Count(script, "cgroup.procs") != 2 {
+		t.Errorf("joinScript() = %s, want joining both hierarchies", script)
+	}
+	for _, dir := range cg.dirs {
+		files, _ := filepath.Glob(filepath.Join(dir, "*"))
+		for _, file := range files {
+			os.Remove(file)
+		}
+	}
+	cg.remove(context.Background())
+	for _, dir := range cg.dirs {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("remove() left the cgroup %s, %v", dir, err)
+		}
+	}
+}
+
+func TestGetCgroupLimit(t *testing.T) {
+	tests := []struct {
+		limit *CgroupLimit
+		want  bool
+	}{
+		{nil, false},
+		{&CgroupLimit{}, false},
+		{&CgroupLimit{CPUPercent: 10}, true},
+		{&CgroupLimit{MemoryBytes: 1}, true},
+	}
+	for _, tt := range tests {
+		if got := getCgroupLimit(WithCgroupLimit(context.Background(), tt.limit)) != nil; got != tt.want {
+			t.Errorf("getCgroupLimit(%+v) = %t, want %t", tt.limit, got, tt.want)
+		}
+	}
+}
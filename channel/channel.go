@@ -22,14 +22,99 @@ import (
 	"github.com/chaosblade-io/chaosblade-spec-go/log"
 	"github.com/chaosblade-io/chaosblade-spec-go/spec"
 	"github.com/chaosblade-io/chaosblade-spec-go/util"
-	"regexp"
-	"strings"
+	"strconv"
+	"time"
+	"unicode/utf8"
 )
 
 // grep ${key}
-const ProcessKey = "process"
-const ExcludeProcessKey = "excludeProcess"
-const ProcessCommandKey = "processCommand"
+const ProcessKey spec.ContextKey = "process"
+const ExcludeProcessKey spec.ContextKey = "excludeProcess"
+const ProcessCommandKey spec.ContextKey = "processCommand"
+
+// WithProcess returns a copy of ctx that makes the process lookups match the keyword too
+func WithProcess(ctx context.Context, process string) context.Context {
+	return context.WithValue(ctx, ProcessKey, process)
+}
+
+// WithExcludeProcess returns a copy of ctx that makes the process lookups skip the processes,
+// excludeProcess is a comma separated list of keywords
+func WithExcludeProcess(ctx context.Context, excludeProcess string) context.Context {
+	return context.WithValue(ctx, ExcludeProcessKey, excludeProcess)
+}
+
+// WithProcessCommand returns a copy of ctx that makes the process lookups match the command name
+func WithProcessCommand(ctx context.Context, command string) context.Context {
+	return context.WithValue(ctx, ProcessCommandKey, command)
+}
+
+// defaultCommandTimeout is the timeout of the command if the ctx has no deadline
+const defaultCommandTimeout = 60 * time.Second
+
+// withDefaultTimeout returns the ctx with the default command timeout if it has no deadline. The ctx is not
+// compared with context.Background, because it's wrapped by the tracing span and the execution options.
+func withDefaultTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, defaultCommandTimeout)
+}
+
+// maxOutputSize is the max bytes of the command output kept in the response, the exceeded part is dropped
+// and the response is marked by spec.MetadataTruncated
+const maxOutputSize = 1024 * 1024
+
+// truncateOutput truncates the output in the result and the error message of the response to maxOutputSize
+func truncateOutput(response *spec.Response) {
+	truncated := false
+	if result, ok := response.Result.(string); ok && len(result) > maxOutputSize {
+		response.Result = truncateString(result, maxOutputSize)
+		truncated = true
+	}
+	if len(response.Err) > maxOutputSize {
+		response.Err = truncateString(response.Err, maxOutputSize)
+		truncated = true
+	}
+	if truncated {
+		response.SetMetadata(spec.MetadataTruncated, true)
+	}
+}
+
+// truncateString cuts the string to the max bytes without splitting the last rune
+func truncateString(s string, max int) string {
+	s = s[:max]
+	for idx := 0; idx < utf8.UTFMax && len(s) > 0; idx++ {
+		if r, size := utf8.DecodeLastRuneInString(s); r != utf8.RuneError || size != 1 {
+			break
+		}
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+// invoke checks the command policy, waits for the execution slot and runs the command in the tracing span,
+// the output exceeding maxOutputSize is truncated and the execution metadata is set to the response
+func invoke(ctx context.Context, channelName, script, args string,
+	run func(ctx context.Context) *spec.Response) *spec.Response {
+	if response := checkCommandPolicy(script, args); response != nil {
+		return response
+	}
+	release, err := executions.acquire(ctx)
+	if err != nil {
+		return spec.ResponseFailWithFlags(spec.OsCmdExecFailed, script, err)
+	}
+	defer release()
+	start := time.Now()
+	response := traceRun(ctx, channelName, script, args, run)
+	end := time.Now()
+	truncateOutput(response)
+	return response.SetMetadata(spec.MetadataHost, util.LocalHostname()).
+		SetMetadata(spec.MetadataHostIP, util.LocalIP()).
+		SetMetadata(spec.MetadataChannel, channelName).
+		SetMetadata(spec.MetadataStartTime, start.Format(time.RFC3339Nano)).
+		SetMetadata(spec.MetadataEndTime, end.Format(time.RFC3339Nano)).
+		SetMetadata(spec.MetadataDurationMs, end.Sub(start).Milliseconds())
+}
 
 
 func GetPidsByLocalPort(ctx context.Context, channel spec.Channel, localPort string) ([]string, error) {
@@ -56,49 +141,14 @@ func GetPidsByLocalPort(ctx context.Context, channel spec.Channel, localPort str
 	if util.IsNil(response.Result) {
 		return pids, nil
 	}
-	result := response.Result.(string)
-	ssMsg := strings.TrimSpace(result)
-	if ssMsg == "" {
-		return pids, nil
+	sockets, err := util.ParseSS(response.Result.(string))
+	if err != nil {
+		return pids, err
 	}
-	sockets := strings.Split(ssMsg, "\n")
-	log.Infof(ctx, "sockets for %s, %v", localPort, sockets)
-	for idx, s := range sockets {
-		if idx == 0 {
-			continue
-		}
-		fields := strings.Fields(s)
-		// centos7: users:(("tengine",pid=237768,fd=6),("tengine",pid=237767,fd=6))
-		// centos6: users:(("tengine",237768,fd=6),("tengine",237767,fd=6))
-		lastField := fields[len(fields)-1]
-		log.Infof(ctx,"GetPidsByLocalPort: lastField: %v", lastField)
-		pidExp := regexp.MustCompile(`pid=(\d+)|,(\d+),`)
-		// extract all the pids that conforms to pidExp
-		matchedPidArrays := pidExp.FindAllStringSubmatch(lastField, -1)
-		if matchedPidArrays == nil || len(matchedPidArrays) == 0 {
-			return pids, nil
-		}
-
-		for _, matchedPidArray := range matchedPidArrays {
-
-			var pid string
-
-			// centos7: matchedPidArray is [pid=29863 29863 ], matchedPidArray[len(matchedPidArray)-1] is whitespace
-
-			pid = strings.TrimSpace(matchedPidArray[len(matchedPidArray)-1])
-
-			if pid != "" {
-				pids = append(pids, pid)
-				continue
-			}
-
-			// centos6: matchedPidArray is [,237768,  237768] matchedPidArray[len(matchedPidArray)-1] is pid
-			pid = strings.TrimSpace(matchedPidArray[len(matchedPidArray)-2])
-			if pid != "" {
-				pids = append(pids, pid)
-				continue
-			}
-
+	log.Infof(ctx, "sockets for %s, %+v", localPort, sockets)
+	for _, socket := range sockets {
+		for _, pid := range socket.Pids() {
+			pids = append(pids, strconv.Itoa(pid))
 		}
 	}
 	log.Infof(ctx, "GetPidsByLocalPort: pids: %v", pids)
@@ -0,0 +1,75 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package channel
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+func TestWithDefaultTimeout(t *testing.T) {
+	type spanKey struct{}
+	// the span ctx of the tracer is not Background but has no deadline either
+	spanCtx := context.WithValue(context.Background(), spanKey{}, "span")
+	ctx, cancel := withDefaultTimeout(spanCtx)
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	if !ok || time.Until(deadline) > defaultCommandTimeout {
+		t.Errorf("the default timeout is not applied to the span ctx, deadline: %v, %t", deadline, ok)
+	}
+
+	parent, parentCancel := context.WithTimeout(context.Background(), time.Hour)
+	defer parentCancel()
+	ctx, cancel = withDefaultTimeout(parent)
+	defer cancel()
+	if got, _ := ctx.Deadline(); time.Until(got) < time.Minute*59 {
+		t.Errorf("the deadline of the ctx is overridden, %v", got)
+	}
+}
+
+func TestInvokeMetadata(t *testing.T) {
+	response := invoke(context.Background(), "local", "echo", "abc", func(ctx context.Context) *spec.Response {
+		return spec.ReturnSuccess("abc")
+	})
+	for _, key := range []string{spec.MetadataHost, spec.MetadataHostIP, spec.MetadataChannel,
+		spec.MetadataStartTime, spec.MetadataEndTime, spec.MetadataDurationMs} {
+		if _, ok := response.Metadata[key]; !ok {
+			t.Errorf("invoke() metadata %s is absent, metadata: %v", key, response.Metadata)
+		}
+	}
+	if response.Metadata[spec.MetadataChannel] != "local" {
+		t.Errorf("invoke() metadata channel = %v, want local", response.Metadata[spec.MetadataChannel])
+	}
+	if _, ok := response.Metadata[spec.MetadataTruncated]; ok || response.Result != "abc" {
+		t.Errorf("invoke() truncated the short output, result: %v", response.Result)
+	}
+
+	output := strings.Repeat("a", maxOutputSize-1) + "中文"
+	response = invoke(context.Background(), "local", "cat", "large", func(ctx context.Context) *spec.Response {
+		return spec.ResponseFailWithFlags(spec.OsCmdExecFailed, "cat", output)
+	})
+	if response.Metadata[spec.MetadataTruncated] != true {
+		t.Errorf("invoke() metadata truncated = %v, want true", response.Metadata[spec.MetadataTruncated])
+	}
+	if len(response.Err) > maxOutputSize || !strings.HasSuffix(response.Err, "a") {
+		t.Errorf("invoke() truncated error length = %d, want no split rune within %d", len(response.Err), maxOutputSize)
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package channel
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// ExecOptionsKey is the context key of the options applied to the exec.Cmd
+const ExecOptionsKey spec.ContextKey = "execOptions"
+
+// ExecOption customizes the exec.Cmd before the channel starts it
+type ExecOption func(cmd *exec.Cmd)
+
+// WithExecOptions returns a copy of ctx carrying the options, which are applied in order to
+// the command created by the channel Run func. The options are appended to the existing ones in ctx.
+func WithExecOptions(ctx context.Context, options ...ExecOption) context.Context {
+	return context.WithValue(ctx, ExecOptionsKey, append(getExecOptions(ctx), options...))
+}
+
+func getExecOptions(ctx context.Context) []ExecOption {
+	options, ok := ctx.Value(ExecOptionsKey).([]ExecOption)
+	if !ok {
+		return nil
+	}
+	// copy to avoid sharing the underlying array between derived contexts
+	return append([]ExecOption{}, options...)
+}
+
+func applyExecOptions(ctx context.Context, cmd *exec.Cmd) {
+	for _, option := range getExecOptions(ctx) {
+		option(cmd)
+	}
+}
+
+// ExecDir sets the working directory of the command
+func ExecDir(dir string) ExecOption {
+	return func(cmd *exec.Cmd) {
+		cmd.Dir = dir
+	}
+}
+
+// ExecExtraFiles sets the open files inherited by the command, the file i becomes the descriptor 3+i
+func ExecExtraFiles(files ...*os.File) ExecOption {
+	return func(cmd *exec.Cmd) {
+		cmd.ExtraFiles = files
+	}
+}
+
+// ExecSysProcAttr replaces the os specific attributes of the command by a copy of attr, so the attributes set
+// by the channel, such as the chroot, are not leaked into the caller's struct shared by the other commands
+func ExecSysProcAttr(attr *syscall.SysProcAttr) ExecOption {
+	return func(cmd *exec.Cmd) {
+		if attr == nil {
+			cmd.SysProcAttr = nil
+			return
+		}
+		copied := *attr
+		cmd.SysProcAttr = &copied
+	}
+}
+
+func sysProcAttr(cmd *exec.Cmd) *syscall.SysProcAttr {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	return cmd.SysProcAttr
+}
@@ -0,0 +1,43 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package channel
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// ExecCredential runs the command as the uid and gid
+func ExecCredential(uid, gid uint32) ExecOption {
+	return func(cmd *exec.Cmd) {
+		sysProcAttr(cmd).Credential = &syscall.Credential{Uid: uid, Gid: gid}
+	}
+}
+
+// ExecPdeathsig sets the signal that the command gets when the thread which started it dies
+func ExecPdeathsig(signal syscall.Signal) ExecOption {
+	return func(cmd *exec.Cmd) {
+		sysProcAttr(cmd).Pdeathsig = signal
+	}
+}
+
+// ExecSetpgid places the command in a new process group, so the whole group can be killed together
+func ExecSetpgid() ExecOption {
+	return func(cmd *exec.Cmd) {
+		sysProcAttr(cmd).Setpgid = true
+	}
+}
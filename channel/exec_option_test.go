@@ -0,0 +1,83 @@
+//go:build linux
+// +build linux
+
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package channel
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+)
+
+func TestWithExecOptions(t *testing.T) {
+	base := WithExecOptions(context.Background(), ExecDir("/tmp"))
+	first := WithExecOptions(base, ExecSetpgid())
+	second := WithExecOptions(base, ExecCredential(1000, 1000))
+
+	cmd := exec.Command("true")
+	applyExecOptions(first, cmd)
+	if cmd.Dir != "/tmp" || cmd.SysProcAttr == nil || !cmd.SysProcAttr.Setpgid || cmd.SysProcAttr.Credential != nil {
+		t.Errorf("unexpected command of the first ctx, dir: %s, attr: %+v", cmd.Dir, cmd.SysProcAttr)
+	}
+	cmd = exec.Command("true")
+	applyExecOptions(second, cmd)
+	if cmd.SysProcAttr.Setpgid || cmd.SysProcAttr.Credential == nil || cmd.SysProcAttr.Credential.Uid != 1000 {
+		t.Errorf("the options of the derived contexts are shared, attr: %+v", cmd.SysProcAttr)
+	}
+	if len(getExecOptions(base)) != 1 {
+		t.Errorf("the options of the parent ctx are modified")
+	}
+}
+
+func TestExecOptions(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "extra")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	cmd := exec.Command("true")
+	for _, option := range []ExecOption{ExecExtraFiles(file), ExecPdeathsig(syscall.SIGKILL)} {
+		option(cmd)
+	}
+	if len(cmd.ExtraFiles) != 1 || cmd.ExtraFiles[0] != file || cmd.SysProcAttr.Pdeathsig != syscall.SIGKILL {
+		t.Errorf("unexpected command, extra files: %v, attr: %+v", cmd.ExtraFiles, cmd.SysProcAttr)
+	}
+}
+
+func TestExecSysProcAttrCopied(t *testing.T) {
+	shared := &syscall.SysProcAttr{Setpgid: true}
+	ctx := WithExecOptions(context.Background(), ExecSysProcAttr(shared))
+	cmd := exec.Command("true")
+	applyExecOptions(ctx, cmd)
+	// the channel sets the chroot to the attributes of the command
+	sysProcAttr(cmd).Chroot = "/mnt/image"
+	if shared.Chroot != "" {
+		t.Errorf("the chroot is leaked into the shared attributes")
+	}
+	if !cmd.SysProcAttr.Setpgid {
+		t.Errorf("the attributes are not applied, %+v", cmd.SysProcAttr)
+	}
+	another := exec.Command("true")
+	applyExecOptions(ctx, another)
+	if another.SysProcAttr == cmd.SysProcAttr || another.SysProcAttr.Chroot != "" {
+		t.Errorf("the attributes are shared between the commands")
+	}
+}
@@ -36,6 +36,7 @@ type MockLocalChannel struct {
 	GetPidUserFunc              func(pid string) (string, error)
 	GetPidsByLocalPortsFunc     func(ctx context.Context, localPorts []string) ([]string, error)
 	GetPidsByLocalPortFunc      func(ctx context.Context, localPort string) ([]string, error)
+	ListProcessesFunc           func(ctx context.Context, filter spec.ProcessFilter) ([]spec.ProcessInfo, error)
 }
 
 func NewMockLocalChannel() spec.Channel {
@@ -50,6 +51,7 @@ func NewMockLocalChannel() spec.Channel {
 		GetPidUserFunc:              defaultGetPidUserFunc,
 		GetPidsByLocalPortsFunc:     defaultGetPidsByLocalPortsFunc,
 		GetPidsByLocalPortFunc:      defaultGetPidsByLocalPortFunc,
+		ListProcessesFunc:           defaultListProcessesFunc,
 	}
 }
 
@@ -96,7 +98,14 @@ func (mlc *MockLocalChannel) GetPidsByLocalPort(ctx context.Context, localPort s
 	return mlc.GetPidsByLocalPortFunc(ctx, localPort)
 }
 
+func (mlc *MockLocalChannel) ListProcesses(ctx context.Context, filter spec.ProcessFilter) ([]spec.ProcessInfo, error) {
+	return mlc.ListProcessesFunc(ctx, filter)
+}
+
 func (mlc *MockLocalChannel) Run(ctx context.Context, script, args string) *spec.Response {
+	if response := checkCommandPolicy(script, args); response != nil {
+		return response
+	}
 	return mlc.RunFunc(ctx, script, args)
 }
 
@@ -128,6 +137,9 @@ var defaultGetPidsByLocalPortsFunc = func(ctx context.Context, localPorts []stri
 var defaultGetPidsByLocalPortFunc = func(ctx context.Context, localPort string) ([]string, error) {
 	return []string{}, nil
 }
+var defaultListProcessesFunc = func(ctx context.Context, filter spec.ProcessFilter) ([]spec.ProcessInfo, error) {
+	return []spec.ProcessInfo{}, nil
+}
 var defaultRunFunc = func(ctx context.Context, script, args string) *spec.Response {
 	return spec.ReturnSuccess("success")
 }
@@ -26,7 +26,6 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/chaosblade-io/chaosblade-spec-go/log"
 	"github.com/chaosblade-io/chaosblade-spec-go/spec"
@@ -47,7 +46,9 @@ func (l *LocalChannel) Name() string {
 }
 
 func (l *LocalChannel) Run(ctx context.Context, script, args string) *spec.Response {
-	return execScript(ctx, script, args)
+	return invoke(ctx, l.Name(), script, args, func(ctx context.Context) *spec.Response {
+		return execScript(ctx, script, args)
+	})
 }
 
 func (l *LocalChannel) GetScriptPath() string {
@@ -107,16 +108,8 @@ func (l *LocalChannel) GetPidsByProcessName(processName string, ctx context.Cont
 	if err != nil {
 		return []string{}, err
 	}
-	otherConditionProcessValue := ctx.Value(ProcessKey)
-	otherConditionProcessName := ""
-	if otherConditionProcessValue != nil {
-		otherConditionProcessName = otherConditionProcessValue.(string)
-	}
-	processCommandValue := ctx.Value(ProcessCommandKey)
-	processCommandName := ""
-	if processCommandValue != nil {
-		processCommandName = processCommandValue.(string)
-	}
+	otherConditionProcessName, _ := spec.ContextString(ctx, ProcessKey)
+	processCommandName, _ := spec.ContextString(ctx, ProcessCommandKey)
 	currPid := os.Getpid()
 	excludeProcesses := getExcludeProcesses(ctx)
 	pids := make([]string, 0)
@@ -164,10 +157,8 @@ func (l *LocalChannel) GetPidsByProcessName(processName string, ctx context.Cont
 }
 
 func getExcludeProcesses(ctx context.Context) []string {
-	excludeProcessValue := ctx.Value(ExcludeProcessKey)
 	excludeProcesses := make([]string, 0)
-	if excludeProcessValue != nil {
-		excludeProcessesString := excludeProcessValue.(string)
+	if excludeProcessesString, ok := spec.ContextString(ctx, ExcludeProcessKey); ok {
 		processNames := strings.Split(excludeProcessesString, ",")
 		for _, name := range processNames {
 			name = strings.TrimSpace(name)
@@ -254,6 +245,10 @@ func (l *LocalChannel) GetPidsByLocalPort(ctx context.Context, localPort string)
 	return GetPidsByLocalPort(ctx, l, localPort)
 }
 
+func (l *LocalChannel) ListProcesses(ctx context.Context, filter spec.ProcessFilter) ([]spec.ProcessInfo, error) {
+	return listLocalProcesses(ctx, filter)
+}
+
 // execScript invokes exec.CommandContext
 func execScript(ctx context.Context, script, args string) *spec.Response {
 	isBladeCommand := isBladeCommand(script)
@@ -261,12 +256,9 @@ func execScript(ctx context.Context, script, args string) *spec.Response {
 		// TODO nohup invoking
 		return spec.ResponseFailWithFlags(spec.ChaosbladeFileNotFound, script)
 	}
-	newCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	ctx, cancel := withDefaultTimeout(ctx)
 	defer cancel()
-	if ctx == context.Background() {
-		ctx = newCtx
-	}
-	log.Debugf(ctx, "Command: %s %s", script, args)
+	log.Debugf(ctx, "Command: %s %s", script, spec.MaskContextSecrets(ctx, args))
 
 	//区分.py和.sh脚本
 	// TODO /bin/sh 的问题
@@ -293,11 +285,33 @@ func execScript(ctx context.Context, script, args string) *spec.Response {
 	} else {
 		cmd = exec.CommandContext(ctx, "/bin/sh", "-c", script+" "+args)
 	}
+	root := getRoot(ctx)
+	if root != "" && !util.IsDir(root) {
+		return spec.ResponseFailWithFlags(spec.FileNotExist, root)
+	}
+	if limit := getCgroupLimit(ctx); limit != nil {
+		cg, err := newLimitedCgroup(ctx, limit)
+		if err != nil {
+			return spec.ResponseFailWithFlags(spec.OsCmdExecFailed, script, err)
+		}
+		defer cg.remove(ctx)
+		cmd = exec.CommandContext(ctx, "/bin/sh", cg.wrap(cmd.Args, root)...)
+		// the wrapper script executes chroot after joining the cgroup
+		root = ""
+	}
+	applyExecOptions(ctx, cmd)
+	if root != "" {
+		sysProcAttr(cmd).Chroot = root
+		if cmd.Dir == "" {
+			cmd.Dir = "/"
+		}
+	}
 	output, err := cmd.CombinedOutput()
 	outMsg := string(output)
-	log.Debugf(ctx, "Command Result, output: %v, err: %v", outMsg, err)
+	log.Debugf(ctx, "Command Result, output: %v, err: %v", spec.MaskContextSecrets(ctx, outMsg), err)
 	// TODO shell-init错误
-	if strings.TrimSpace(outMsg) != "" && (strings.HasPrefix(strings.TrimSpace(outMsg), "{") || strings.HasPrefix(strings.TrimSpace(outMsg), "[")) {
+	// the response JSON may follow the log lines, Decode extracts it if the output ends with it
+	if spec.EndsWithJSON(outMsg) {
 		resp := spec.Decode(outMsg, nil)
 		if resp.Code != spec.ResultUnmarshalFailed.Code {
 			return resp
@@ -306,8 +320,8 @@ func execScript(ctx context.Context, script, args string) *spec.Response {
 	if err == nil {
 		return spec.ReturnSuccess(outMsg)
 	}
-	outMsg += " " + err.Error()
-	return spec.ResponseFailWithFlags(spec.OsCmdExecFailed, cmd, outMsg)
+	outMsg = spec.MaskContextSecrets(ctx, outMsg+" "+err.Error())
+	return spec.ResponseFailWithFlags(spec.OsCmdExecFailed, spec.MaskContextSecrets(ctx, cmd.String()), outMsg).AddCauses(spec.CausesFromError(err, script)...)
 }
 
 func isBladeCommand(script string) bool {
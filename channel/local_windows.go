@@ -26,7 +26,6 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/chaosblade-io/chaosblade-spec-go/log"
 	"github.com/chaosblade-io/chaosblade-spec-go/spec"
@@ -47,7 +46,9 @@ func (l *LocalChannel) Name() string {
 }
 
 func (l *LocalChannel) Run(ctx context.Context, script, args string) *spec.Response {
-	return execScript(ctx, script, args)
+	return invoke(ctx, l.Name(), script, args, func(ctx context.Context) *spec.Response {
+		return execScript(ctx, script, args)
+	})
 }
 
 func (l *LocalChannel) GetScriptPath() string {
@@ -107,16 +108,8 @@ func (l *LocalChannel) GetPidsByProcessName(processName string, ctx context.Cont
 	if err != nil {
 		return []string{}, err
 	}
-	otherConditionProcessValue := ctx.Value(ProcessKey)
-	otherConditionProcessName := ""
-	if otherConditionProcessValue != nil {
-		otherConditionProcessName = otherConditionProcessValue.(string)
-	}
-	processCommandValue := ctx.Value(ProcessCommandKey)
-	processCommandName := ""
-	if processCommandValue != nil {
-		processCommandName = processCommandValue.(string)
-	}
+	otherConditionProcessName, _ := spec.ContextString(ctx, ProcessKey)
+	processCommandName, _ := spec.ContextString(ctx, ProcessCommandKey)
 	currPid := os.Getpid()
 	excludeProcesses := getExcludeProcesses(ctx)
 	pids := make([]string, 0)
@@ -164,10 +157,8 @@ func (l *LocalChannel) GetPidsByProcessName(processName string, ctx context.Cont
 }
 
 func getExcludeProcesses(ctx context.Context) []string {
-	excludeProcessValue := ctx.Value(ExcludeProcessKey)
 	excludeProcesses := make([]string, 0)
-	if excludeProcessValue != nil {
-		excludeProcessesString := excludeProcessValue.(string)
+	if excludeProcessesString, ok := spec.ContextString(ctx, ExcludeProcessKey); ok {
 		processNames := strings.Split(excludeProcessesString, ",")
 		for _, name := range processNames {
 			name = strings.TrimSpace(name)
@@ -254,6 +245,10 @@ func (l *LocalChannel) GetPidsByLocalPort(ctx context.Context, localPort string)
 	return GetPidsByLocalPort(ctx, l, localPort)
 }
 
+func (l *LocalChannel) ListProcesses(ctx context.Context, filter spec.ProcessFilter) ([]spec.ProcessInfo, error) {
+	return listLocalProcesses(ctx, filter)
+}
+
 // execScript invokes exec.CommandContext
 func execScript(ctx context.Context, script, args string) *spec.Response {
 	isBladeCommand := isBladeCommand(script)
@@ -261,17 +256,19 @@ func execScript(ctx context.Context, script, args string) *spec.Response {
 		// TODO nohup invoking
 		return spec.ResponseFailWithFlags(spec.ChaosbladeFileNotFound, script)
 	}
-	newCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
-	defer cancel()
-	if ctx == context.Background() {
-		ctx = newCtx
+	if root := getRoot(ctx); root != "" {
+		return spec.ResponseFailWithFlags(spec.CommandIllegal, "alternate root is not supported on windows")
 	}
-	log.Debugf(ctx, "Command: %s %s", script, args)
+	ctx, cancel := withDefaultTimeout(ctx)
+	defer cancel()
+	log.Debugf(ctx, "Command: %s %s", script, spec.MaskContextSecrets(ctx, args))
 	cmd := exec.CommandContext(ctx, "cmd", "/C", script+` `+args)
+	applyExecOptions(ctx, cmd)
 	output, err := cmd.CombinedOutput()
 	outMsg := string(output)
-	log.Debugf(ctx, "Command Result, output: %v, err: %v", outMsg, err)
-	if strings.TrimSpace(outMsg) != "" && (strings.HasPrefix(strings.TrimSpace(outMsg), "{") || strings.HasPrefix(strings.TrimSpace(outMsg), "[")) {
+	log.Debugf(ctx, "Command Result, output: %v, err: %v", spec.MaskContextSecrets(ctx, outMsg), err)
+	// the response JSON may follow the log lines, Decode extracts it if the output ends with it
+	if spec.EndsWithJSON(outMsg) {
 		resp := spec.Decode(outMsg, nil)
 		if resp.Code != spec.ResultUnmarshalFailed.Code {
 			return resp
@@ -280,8 +277,8 @@ func execScript(ctx context.Context, script, args string) *spec.Response {
 	if err == nil {
 		return spec.ReturnSuccess(outMsg)
 	}
-	outMsg += " " + err.Error()
-	return spec.ResponseFailWithFlags(spec.OsCmdExecFailed, cmd, outMsg)
+	outMsg = spec.MaskContextSecrets(ctx, outMsg+" "+err.Error())
+	return spec.ResponseFailWithFlags(spec.OsCmdExecFailed, spec.MaskContextSecrets(ctx, cmd.String()), outMsg).AddCauses(spec.CausesFromError(err, script)...)
 }
 
 func isBladeCommand(script string) bool {
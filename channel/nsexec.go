@@ -6,12 +6,10 @@ import (
 	"github.com/chaosblade-io/chaosblade-spec-go/log"
 	"github.com/chaosblade-io/chaosblade-spec-go/spec"
 	"github.com/chaosblade-io/chaosblade-spec-go/util"
-	"os"
 	"os/exec"
 	"path"
 	"strconv"
 	"strings"
-	"time"
 )
 
 const (
@@ -21,6 +19,32 @@ const (
 	NSNetFlagName    = "ns_net"
 )
 
+// The context keys of the nsexec channel, named after the flags
+const (
+	NSTargetKey spec.ContextKey = NSTargetFlagName
+	NSPidKey    spec.ContextKey = NSPidFlagName
+	NSMntKey    spec.ContextKey = NSMntFlagName
+	NSNetKey    spec.ContextKey = NSNetFlagName
+)
+
+// WithNSTarget returns a copy of ctx that makes the nsexec channel enter the namespaces of the target pid
+func WithNSTarget(ctx context.Context, pid string) context.Context {
+	return context.WithValue(ctx, NSTargetKey, pid)
+}
+
+// WithNamespaces returns a copy of ctx that selects the namespaces of the target pid to enter
+func WithNamespaces(ctx context.Context, pidNS, mntNS, netNS bool) context.Context {
+	ctx = context.WithValue(ctx, NSPidKey, strconv.FormatBool(pidNS))
+	ctx = context.WithValue(ctx, NSMntKey, strconv.FormatBool(mntNS))
+	return context.WithValue(ctx, NSNetKey, strconv.FormatBool(netNS))
+}
+
+// NSTargetFromContext returns the target pid of the nsexec channel in ctx
+func NSTargetFromContext(ctx context.Context) (string, bool) {
+	pid, ok := spec.ContextString(ctx, NSTargetKey)
+	return pid, ok && pid != ""
+}
+
 type NSExecChannel struct {
 	LocalChannel
 }
@@ -34,23 +58,15 @@ func (l *NSExecChannel) Name() string {
 }
 
 func (l *NSExecChannel) Run(ctx context.Context, script, args string) *spec.Response {
-	pid := ctx.Value(NSTargetFlagName)
-	if pid == nil {
-		return spec.ResponseFailWithFlags(spec.CommandIllegal, script)
-	}
-
-	ns_script := fmt.Sprintf("-t %s", pid)
-
-	if ctx.Value(NSPidFlagName) == spec.True {
-		ns_script = fmt.Sprintf("%s -p", ns_script)
-	}
-
-	if ctx.Value(NSMntFlagName) == spec.True {
-		ns_script = fmt.Sprintf("%s -m", ns_script)
-	}
+	return invoke(ctx, l.Name(), script, args, func(ctx context.Context) *spec.Response {
+		return l.run(ctx, script, args)
+	})
+}
 
-	if ctx.Value(NSNetFlagName) == spec.True {
-		ns_script = fmt.Sprintf("%s -n", ns_script)
+func (l *NSExecChannel) run(ctx context.Context, script, args string) *spec.Response {
+	pid, ok := NSTargetFromContext(ctx)
+	if !ok {
+		return spec.ResponseFailWithFlags(spec.CommandIllegal, script)
 	}
 
 	isBladeCommand := isBladeCommand(script)
@@ -58,7 +74,7 @@ func (l *NSExecChannel) Run(ctx context.Context, script, args string) *spec.Resp
 		// TODO nohup invoking
 		return spec.ResponseFailWithFlags(spec.ChaosbladeFileNotFound, script)
 	}
-	timeoutCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	ctx, cancel := withDefaultTimeout(ctx)
 	defer cancel()
 
 	if args != "" {
@@ -67,23 +83,32 @@ func (l *NSExecChannel) Run(ctx context.Context, script, args string) *spec.Resp
 		args = script
 	}
 
-	ns_script = fmt.Sprintf("%s -- /bin/sh -c", ns_script)
-
 	programPath := util.GetProgramPath()
 	if path.Base(programPath) != spec.BinPath {
 		programPath = path.Join(programPath, spec.BinPath)
 	}
 	bin := path.Join(programPath, spec.NSExecBin)
-	log.Debugf(ctx, `Command: %s %s "%s"`, bin, ns_script, args)
-
-	split := strings.Split(ns_script, " ")
-
-	cmd := exec.CommandContext(timeoutCtx, bin, append(split, args)...)
+	nsArgs := nsexecArgs(ctx, pid)
+	log.Debugf(ctx, `Command: %s %s "%s"`, bin, strings.Join(nsArgs, " "), spec.MaskContextSecrets(ctx, args))
+
+	cmd := exec.CommandContext(ctx, bin, append(nsArgs, args)...)
+	if limit := getCgroupLimit(ctx); limit != nil {
+		cg, err := newLimitedCgroup(ctx, limit)
+		if err != nil {
+			return spec.ResponseFailWithFlags(spec.OsCmdExecFailed, script, err)
+		}
+		defer cg.remove(ctx)
+		// the nsexec joins the cgroup before entering the namespaces, so the command inherits the cgroup,
+		// the root is entered by nsexec instead of chroot
+		cmd = exec.CommandContext(ctx, "/bin/sh", cg.wrap(cmd.Args, "")...)
+	}
+	applyExecOptions(ctx, cmd)
 	output, err := cmd.CombinedOutput()
 	outMsg := string(output)
-	log.Debugf(ctx, "Command Result, output: %v, err: %v", outMsg, err)
+	log.Debugf(ctx, "Command Result, output: %v, err: %v", spec.MaskContextSecrets(ctx, outMsg), err)
 	// TODO shell-init错误
-	if strings.TrimSpace(outMsg) != "" && (strings.HasPrefix(strings.TrimSpace(outMsg), "{") || strings.HasPrefix(strings.TrimSpace(outMsg), "[")) {
+	// the response JSON may follow the log lines, Decode extracts it if the output ends with it
+	if spec.EndsWithJSON(outMsg) {
 		resp := spec.Decode(outMsg, nil)
 		if resp.Code != spec.ResultUnmarshalFailed.Code {
 			return resp
@@ -92,81 +117,40 @@ func (l *NSExecChannel) Run(ctx context.Context, script, args string) *spec.Resp
 	if err == nil {
 		return spec.ReturnSuccess(outMsg)
 	}
-	outMsg += " " + err.Error()
-	return spec.ResponseFailWithFlags(spec.OsCmdExecFailed, cmd, outMsg)
+	outMsg = spec.MaskContextSecrets(ctx, outMsg+" "+err.Error())
+	return spec.ResponseFailWithFlags(spec.OsCmdExecFailed, spec.MaskContextSecrets(ctx, cmd.String()), outMsg).AddCauses(spec.CausesFromError(err, script)...)
 }
 
-func (l *NSExecChannel) GetPidsByProcessCmdName(processName string, ctx context.Context) ([]string, error) {
-	excludeProcesses := ctx.Value(ExcludeProcessKey)
-	excludeGrepInfo := ""
-	if excludeProcesses != nil {
-		excludeProcessesString := excludeProcesses.(string)
-		excludeProcessArrays := strings.Split(excludeProcessesString, ",")
-		for _, excludeProcess := range excludeProcessArrays {
-			if excludeProcess != "" {
-				excludeGrepInfo += fmt.Sprintf(`| grep -v -w %s`, excludeProcess)
-			}
-		}
+// nsexecArgs returns the arguments of the nsexec binary which enters the namespaces of the target pid and runs the
+// command by /bin/sh. The root set by WithRoot is passed by --root like nsenter, it is the path in the mount
+// namespace of the target, the optional argument of --root must be joined by '='.
+func nsexecArgs(ctx context.Context, pid string) []string {
+	args := []string{"-t", pid}
+	if spec.ContextValue(ctx, NSPidKey) == spec.True {
+		args = append(args, "-p")
 	}
-	response := l.Run(ctx, "pgrep",
-		fmt.Sprintf(`-l %s %s | grep -v -w chaos_killprocess | grep -v -w chaos_stopprocess | awk '{print $1}' | tr '\n' ' '`,
-			processName, excludeGrepInfo))
-	if !response.Success {
-		return nil, fmt.Errorf(response.Err)
+	if spec.ContextValue(ctx, NSMntKey) == spec.True {
+		args = append(args, "-m")
 	}
-	pidString := response.Result.(string)
-	pids := strings.Fields(strings.TrimSpace(pidString))
-	currPid := strconv.Itoa(os.Getpid())
-	for idx, pid := range pids {
-		if pid == currPid {
-			return util.Remove(pids, idx), nil
-		}
+	if spec.ContextValue(ctx, NSNetKey) == spec.True {
+		args = append(args, "-n")
+	}
+	if root := getRoot(ctx); root != "" {
+		args = append(args, "--root="+root)
 	}
-	return pids, nil
+	return append(args, "--", "/bin/sh", "-c")
+}
+
+func (l *NSExecChannel) GetPidsByProcessCmdName(processName string, ctx context.Context) ([]string, error) {
+	return getPidsByPs(ctx, l, processCmdNameFilter(ctx, processName))
 }
 
 func (l *NSExecChannel) GetPidsByProcessName(processName string, ctx context.Context) ([]string, error) {
-	psArgs := l.GetPsArgs(ctx)
-	otherProcess := ctx.Value(ProcessKey)
-	otherGrepInfo := ""
-	if otherProcess != nil {
-		processString := otherProcess.(string)
-		if processString != "" {
-			otherGrepInfo = fmt.Sprintf(`| grep "%s"`, processString)
-		}
-	}
-	excludeProcesses := ctx.Value(ExcludeProcessKey)
-	excludeGrepInfo := ""
-	if excludeProcesses != nil {
-		excludeProcessesString := excludeProcesses.(string)
-		excludeProcessArrays := strings.Split(excludeProcessesString, ",")
-		for _, excludeProcess := range excludeProcessArrays {
-			if excludeProcess != "" {
-				excludeGrepInfo += fmt.Sprintf(`| grep -v -w %s`, excludeProcess)
-			}
-		}
-	}
-	if strings.HasPrefix(processName, "-") {
-		processName = fmt.Sprintf(`\%s`, processName)
-	}
-	response := l.Run(ctx, "ps",
-		fmt.Sprintf(`%s | grep "%s" %s %s | grep -v -w grep | grep -v -w chaos_killprocess | grep -v -w chaos_stopprocess | awk '{print $2}' | tr '\n' ' '`,
-			psArgs, processName, otherGrepInfo, excludeGrepInfo))
-	if !response.Success {
-		return nil, fmt.Errorf(response.Err)
+	processName = strings.TrimSpace(processName)
+	if processName == "" {
+		return []string{}, fmt.Errorf("process keyword is blank")
 	}
-	pidString := strings.TrimSpace(response.Result.(string))
-	if pidString == "" {
-		return make([]string, 0), nil
-	}
-	pids := strings.Fields(pidString)
-	currPid := strconv.Itoa(os.Getpid())
-	for idx, pid := range pids {
-		if pid == currPid {
-			return util.Remove(pids, idx), nil
-		}
-	}
-	return pids, nil
+	return getPidsByPs(ctx, l, processNameFilter(ctx, processName))
 }
 
 func (l *NSExecChannel) IsAllCommandsAvailable(ctx context.Context, commandNames []string) (*spec.Response, bool) {
@@ -205,3 +189,7 @@ func (l *NSExecChannel) IsAlpinePlatform(ctx context.Context) bool {
 func (l *NSExecChannel) GetPidsByLocalPort(ctx context.Context, localPort string) ([]string, error) {
 	return GetPidsByLocalPort(ctx, l, localPort)
 }
+
+func (l *NSExecChannel) ListProcesses(ctx context.Context, filter spec.ProcessFilter) ([]spec.ProcessInfo, error) {
+	return listProcessesByPs(ctx, l, filter)
+}
@@ -0,0 +1,43 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package channel
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestNSExecArgs(t *testing.T) {
+	ctx := WithNamespaces(context.Background(), true, false, true)
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want []string
+	}{
+		{"no namespaces", context.Background(), []string{"-t", "1", "--", "/bin/sh", "-c"}},
+		{"namespaces", ctx, []string{"-t", "1", "-p", "-n", "--", "/bin/sh", "-c"}},
+		{"root", WithRoot(ctx, "/rootfs"), []string{"-t", "1", "-p", "-n", "--root=/rootfs", "--", "/bin/sh", "-c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nsexecArgs(tt.ctx, "1"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("nsexecArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package channel
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// CommandPolicy constrains the commands executed by the channels.
+// The command line, script and args joined by a space, is checked before the execution.
+type CommandPolicy struct {
+	// Allow contains the patterns of the allowed command lines, empty means all are allowed
+	Allow []*regexp.Regexp
+	// Deny contains the patterns of the denied command lines, it takes precedence over Allow
+	Deny []*regexp.Regexp
+	// MaxArgsLength is the max length of the args, 0 means no limit
+	MaxArgsLength int
+}
+
+var (
+	commandPolicy     *CommandPolicy
+	commandPolicyLock sync.RWMutex
+)
+
+// NewCommandPolicy compiles the allow and deny patterns to the policy
+func NewCommandPolicy(allow, deny []string, maxArgsLength int) (*CommandPolicy, error) {
+	policy := &CommandPolicy{MaxArgsLength: maxArgsLength}
+	var err error
+	if policy.Allow, err = compilePatterns(allow); err != nil {
+		return nil, err
+	}
+	if policy.Deny, err = compilePatterns(deny); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	regexps := make([]*regexp.Regexp, 0)
+	for _, pattern := range patterns {
+		r, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("illegal policy pattern `%s`, %v", pattern, err)
+		}
+		regexps = append(regexps, r)
+	}
+	return regexps, nil
+}
+
+// SetCommandPolicy sets the policy evaluated by all channels, nil removes the policy
+func SetCommandPolicy(policy *CommandPolicy) {
+	commandPolicyLock.Lock()
+	defer commandPolicyLock.Unlock()
+	commandPolicy = policy
+}
+
+func getCommandPolicy() *CommandPolicy {
+	commandPolicyLock.RLock()
+	defer commandPolicyLock.RUnlock()
+	return commandPolicy
+}
+
+// Check returns error if the command is not permitted by the policy
+func (p *CommandPolicy) Check(script, args string) error {
+	if p.MaxArgsLength > 0 && len(args) > p.MaxArgsLength {
+		return fmt.Errorf("the args length %d exceeds the max length %d", len(args), p.MaxArgsLength)
+	}
+	commandLine := strings.TrimSpace(script + " " + args)
+	for _, deny := range p.Deny {
+		if deny.MatchString(commandLine) {
+			return fmt.Errorf("matches the denied pattern `%s`", deny.String())
+		}
+	}
+	if len(p.Allow) == 0 {
+		return nil
+	}
+	for _, allow := range p.Allow {
+		if allow.MatchString(commandLine) {
+			return nil
+		}
+	}
+	return fmt.Errorf("does not match any allowed pattern")
+}
+
+// checkCommandPolicy returns the CommandRejectedByPolicy response if the policy rejects the command, otherwise nil
+func checkCommandPolicy(script, args string) *spec.Response {
+	policy := getCommandPolicy()
+	if policy == nil {
+		return nil
+	}
+	if err := policy.Check(script, args); err != nil {
+		return spec.ResponseFailWithFlags(spec.CommandRejectedByPolicy, script, err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package channel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+func TestCommandPolicy_Check(t *testing.T) {
+	policy, err := NewCommandPolicy([]string{`^tc `, `^iptables `}, []string{`rm -rf /`}, 20)
+	if err != nil {
+		t.Fatalf("NewCommandPolicy() error = %v", err)
+	}
+	tests := []struct {
+		name    string
+		script  string
+		args    string
+		wantErr bool
+	}{
+		{name: "allowed", script: "tc", args: "qdisc show"},
+		{name: "not allowed", script: "dd", args: "if=/dev/zero", wantErr: true},
+		{name: "denied", script: "tc", args: "; rm -rf /", wantErr: true},
+		{name: "args too long", script: "tc", args: "qdisc add dev eth0 root netem delay 10ms", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := policy.Check(tt.script, tt.args); (err != nil) != tt.wantErr {
+				t.Errorf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMockLocalChannel_RunRejectedByPolicy(t *testing.T) {
+	policy, err := NewCommandPolicy(nil, []string{`^kill `}, 0)
+	if err != nil {
+		t.Fatalf("NewCommandPolicy() error = %v", err)
+	}
+	SetCommandPolicy(policy)
+	defer SetCommandPolicy(nil)
+
+	response := NewMockLocalChannel().Run(context.Background(), "kill", "-9 1")
+	if response.Success || response.Code != spec.CommandRejectedByPolicy.Code {
+		t.Errorf("unexpected response: %s", response.Print())
+	}
+}
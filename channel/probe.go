@@ -0,0 +1,82 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package channel
+
+import (
+	"context"
+	"strings"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// ActionSupport is the support status of the action on the current host
+type ActionSupport struct {
+	// Path is the action path resolved by spec.ResolveAction, such as network/delay
+	Path      string `json:"path" yaml:"path"`
+	Supported bool   `json:"supported" yaml:"supported"`
+	// Missing lists the requirements not satisfied if the action is not supported
+	Missing *spec.ActionRequirements `json:"missing,omitempty" yaml:"missing,omitempty"`
+}
+
+// SupportReport is the result of ProbeActions, the blade cli and server use it to list the supported actions
+type SupportReport struct {
+	KernelVersion string          `json:"kernelVersion,omitempty" yaml:"kernelVersion,omitempty"`
+	CgroupVersion string          `json:"cgroupVersion,omitempty" yaml:"cgroupVersion,omitempty"`
+	Actions       []ActionSupport `json:"actions" yaml:"actions"`
+}
+
+// ProbeActions evaluates the requirements of all the actions of the models against the current host,
+// the registered model specs are probed if no models are specified. Each command is looked up once.
+func ProbeActions(ctx context.Context, channel spec.Channel, models ...spec.ExpModelCommandSpec) *SupportReport {
+	if len(models) == 0 {
+		models = spec.RegisteredModelSpecs()
+	}
+	available := make(map[string]bool)
+	isCommandAvailable := func(command string) bool {
+		if ok, checked := available[command]; checked {
+			return ok
+		}
+		available[command] = channel.IsCommandAvailable(ctx, command)
+		return available[command]
+	}
+	report := &SupportReport{KernelVersion: kernelVersion(), CgroupVersion: cgroupVersion(), Actions: make([]ActionSupport, 0)}
+	for _, model := range models {
+		spec.WalkModels(model, func(path spec.ModelPath) error {
+			for _, action := range path.Last().Actions() {
+				names := append(path.Names(), action.Name())
+				support := ActionSupport{Path: strings.Join(names, spec.ActionPathSeparator), Supported: true}
+				if missing := missingRequirements(ctx, isCommandAvailable, spec.ActionRequirementsOf(action)); !missing.IsEmpty() {
+					support.Supported, support.Missing = false, missing
+				}
+				report.Actions = append(report.Actions, support)
+			}
+			return nil
+		})
+	}
+	return report
+}
+
+// SupportedActions returns the paths of the supported actions in the report
+func (r *SupportReport) SupportedActions() []string {
+	paths := make([]string, 0)
+	for _, action := range r.Actions {
+		if action.Supported {
+			paths = append(paths, action.Path)
+		}
+	}
+	return paths
+}
@@ -0,0 +1,180 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package channel
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+	"github.com/chaosblade-io/chaosblade-spec-go/util"
+	"github.com/shirou/gopsutil/process"
+)
+
+// listLocalProcesses returns the processes of the host matched by the filter
+func listLocalProcesses(ctx context.Context, filter spec.ProcessFilter) ([]spec.ProcessInfo, error) {
+	processes, err := process.Processes()
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]spec.ProcessInfo, 0, len(processes))
+	for _, p := range processes {
+		cmdline, err := p.Cmdline()
+		if err != nil {
+			// the process may exit during the scan
+			log.Debugf(ctx, "get command line error, pid: %v, err: %v", p.Pid, err)
+			continue
+		}
+		info := spec.ProcessInfo{
+			Pid:     fmt.Sprintf("%d", p.Pid),
+			Cmdline: cmdline,
+		}
+		if ppid, err := p.Ppid(); err == nil {
+			info.Ppid = fmt.Sprintf("%d", ppid)
+		}
+		if user, err := p.Username(); err == nil {
+			info.User = user
+		}
+		if name, err := p.Name(); err == nil {
+			info.Name = name
+		}
+		if filter != nil && !filter(info) {
+			continue
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// listProcessesByPs returns the processes matched by the filter from the `ps` output of the channel,
+// the columns of the output must be user,pid,ppid,args. The comm column is inserted before args by psCommArgs.
+func listProcessesByPs(ctx context.Context, channel spec.Channel, filter spec.ProcessFilter) ([]spec.ProcessInfo, error) {
+	psArgs, columns := psCommArgs(channel.GetPsArgs(ctx))
+	response := channel.Run(ctx, "ps", psArgs)
+	if !response.Success {
+		return nil, fmt.Errorf(response.Err)
+	}
+	output, ok := response.Result.(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected ps result: %v", response.Result)
+	}
+	records, err := util.ParsePS(output, columns...)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]spec.ProcessInfo, 0, len(records))
+	for _, record := range records {
+		info := spec.ProcessInfo{
+			User:    record.User,
+			Pid:     strconv.Itoa(record.Pid),
+			Ppid:    strconv.Itoa(record.Ppid),
+			Name:    record.Comm,
+			Cmdline: record.Args,
+		}
+		if filter != nil && !filter(info) {
+			continue
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// getPidsByPs returns the pids of the processes matched by the filter from the `ps` output of the channel,
+// the current process is excluded
+func getPidsByPs(ctx context.Context, channel spec.Channel, filter spec.ProcessFilter) ([]string, error) {
+	processes, err := listProcessesByPs(ctx, channel, filter)
+	if err != nil {
+		return nil, err
+	}
+	currPid := strconv.Itoa(os.Getpid())
+	pids := make([]string, 0, len(processes))
+	for _, process := range processes {
+		if process.Pid != currPid {
+			pids = append(pids, process.Pid)
+		}
+	}
+	return pids, nil
+}
+
+// psCommArgs inserts the comm column before the args column of the ps arguments and returns the columns of the
+// output. The comm is the executable name matched by pgrep, which differs from the base name of the args if the
+// process renames itself. The comm is parsed from the args if the ps arguments don't end with the args column.
+func psCommArgs(psArgs string) (string, []string) {
+	if !strings.HasSuffix(psArgs, ",args") {
+		return psArgs, []string{"user", "pid", "ppid", "args"}
+	}
+	return strings.TrimSuffix(psArgs, ",args") + ",comm,args", []string{"user", "pid", "ppid", "comm", "args"}
+}
+
+// processNameFilter matches the processes whose command line contains the process name and the ProcessKey
+// keyword in ctx, the processes containing the ExcludeProcessKey keywords as words are skipped like `grep -v -w`
+func processNameFilter(ctx context.Context, processName string) spec.ProcessFilter {
+	otherConditionProcessName, _ := spec.ContextString(ctx, ProcessKey)
+	excludeProcesses := getExcludeProcesses(ctx)
+	return func(process spec.ProcessInfo) bool {
+		return strings.Contains(process.Cmdline, processName) &&
+			strings.Contains(process.Cmdline, otherConditionProcessName) &&
+			!containsAnyWord(process.Cmdline, excludeProcesses)
+	}
+}
+
+// processCmdNameFilter matches the processes whose executable name contains the process name like `pgrep`,
+// the processes whose executable name contains the ExcludeProcessKey keywords as words are skipped
+func processCmdNameFilter(ctx context.Context, processName string) spec.ProcessFilter {
+	excludeProcesses := getExcludeProcesses(ctx)
+	return func(process spec.ProcessInfo) bool {
+		return strings.Contains(process.Name, processName) && !containsAnyWord(process.Name, excludeProcesses)
+	}
+}
+
+// containsAnyWord returns true if the text contains any of the keywords as a whole word like `grep -w`,
+// which is not preceded or followed by the letters, the digits and the underscore
+func containsAnyWord(text string, keywords []string) bool {
+	for _, keyword := range keywords {
+		if containsWord(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsWord(text, word string) bool {
+	if word == "" {
+		return false
+	}
+	for start := 0; start < len(text); {
+		idx := strings.Index(text[start:], word)
+		if idx < 0 {
+			return false
+		}
+		idx += start
+		end := idx + len(word)
+		if (idx == 0 || !isWordByte(text[idx-1])) && (end == len(text) || !isWordByte(text[end])) {
+			return true
+		}
+		start = idx + 1
+	}
+	return false
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
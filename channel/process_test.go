@@ -0,0 +1,189 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package channel
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+const psOutput = `USER       PID  PPID COMMAND         COMMAND
+root         1     0 systemd         /sbin/init
+admin      100     1 java            /usr/bin/java -jar app.jar
+admin      101     1 java            /usr/bin/java -jar chaos_killprocess.jar
+admin      102     1 sh              /bin/sh -c tail -f app.log
+admin      103     1 agent           /opt/javaagent/bin/agent --name app
+admin      104     1 app-worker      /usr/bin/java -jar worker.jar
+`
+
+func newPsChannel(output string) *MockLocalChannel {
+	channel := NewMockLocalChannel().(*MockLocalChannel)
+	channel.GetPsArgsFunc = func(ctx context.Context) string {
+		return "-eo user,pid,ppid,args"
+	}
+	channel.RunFunc = func(ctx context.Context, script, args string) *spec.Response {
+		return spec.ReturnSuccess(output)
+	}
+	return channel
+}
+
+func TestListLocalProcesses(t *testing.T) {
+	currPid := strconv.Itoa(os.Getpid())
+	processes, err := NewLocalChannel().ListProcesses(context.Background(), func(process spec.ProcessInfo) bool {
+		return process.Pid == currPid
+	})
+	if err != nil {
+		t.Fatalf("ListProcesses() error = %v", err)
+	}
+	if len(processes) != 1 {
+		t.Fatalf("ListProcesses() = %v, want the current process only", processes)
+	}
+	process := processes[0]
+	if process.Ppid != strconv.Itoa(os.Getppid()) || process.Cmdline == "" || process.Name == "" {
+		t.Errorf("ListProcesses() current process = %+v", process)
+	}
+
+	all, err := NewLocalChannel().ListProcesses(context.Background(), nil)
+	if err != nil || len(all) < 2 {
+		t.Errorf("ListProcesses() without filter = %d processes, %v", len(all), err)
+	}
+}
+
+func TestListProcessesByPs(t *testing.T) {
+	channel := newPsChannel(psOutput)
+	processes, err := listProcessesByPs(context.Background(), channel, nil)
+	if err != nil {
+		t.Fatalf("listProcessesByPs() error = %v", err)
+	}
+	if len(processes) != 6 {
+		t.Fatalf("listProcessesByPs() = %v, want 6 processes", processes)
+	}
+	want := spec.ProcessInfo{User: "admin", Pid: "104", Ppid: "1", Name: "app-worker", Cmdline: "/usr/bin/java -jar worker.jar"}
+	if !reflect.DeepEqual(processes[5], want) {
+		t.Errorf("listProcessesByPs() = %+v, want %+v", processes[5], want)
+	}
+
+	processes, err = listProcessesByPs(context.Background(), channel, func(process spec.ProcessInfo) bool {
+		return process.User == "root"
+	})
+	if err != nil || len(processes) != 1 || processes[0].Pid != "1" {
+		t.Errorf("listProcessesByPs() filtered = %v, %v", processes, err)
+	}
+
+	channel.RunFunc = func(ctx context.Context, script, args string) *spec.Response {
+		return spec.ResponseFailWithFlags(spec.OsCmdExecFailed, "ps", "not found")
+	}
+	if _, err := listProcessesByPs(context.Background(), channel, nil); err == nil {
+		t.Errorf("listProcessesByPs() expected error when ps fails")
+	}
+	channel = newPsChannel("USER PID PPID COMMAND\nroot x 0 /sbin/init\n")
+	if _, err := listProcessesByPs(context.Background(), channel, nil); err == nil {
+		t.Errorf("listProcessesByPs() expected error for the illegal pid")
+	}
+}
+
+func TestGetPidsByPs(t *testing.T) {
+	currPid := strconv.Itoa(os.Getpid())
+	channel := newPsChannel(psOutput + "admin " + currPid + " 1 java /usr/bin/java -jar test.jar\n")
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		filter func(ctx context.Context) spec.ProcessFilter
+		want   []string
+	}{
+		{"process name", context.Background(), func(ctx context.Context) spec.ProcessFilter {
+			return processNameFilter(ctx, "java")
+		}, []string{"100", "103", "104"}},
+		{"process name with keyword", WithProcess(context.Background(), "tail"), func(ctx context.Context) spec.ProcessFilter {
+			return processNameFilter(ctx, "app")
+		}, []string{"102"}},
+		{"process name excluded", WithExcludeProcess(context.Background(), "app.jar,agent"), func(ctx context.Context) spec.ProcessFilter {
+			return processNameFilter(ctx, "app")
+		}, []string{"102"}},
+		{"process name excluded by word", WithExcludeProcess(context.Background(), "java"), func(ctx context.Context) spec.ProcessFilter {
+			return processNameFilter(ctx, "app")
+		}, []string{"102", "103"}},
+		{"command name", context.Background(), func(ctx context.Context) spec.ProcessFilter {
+			return processCmdNameFilter(ctx, "java")
+		}, []string{"100", "101"}},
+		{"command name substring", context.Background(), func(ctx context.Context) spec.ProcessFilter {
+			return processCmdNameFilter(ctx, "jav")
+		}, []string{"100", "101"}},
+		{"command name renamed", context.Background(), func(ctx context.Context) spec.ProcessFilter {
+			return processCmdNameFilter(ctx, "worker")
+		}, []string{"104"}},
+		{"command name excluded by word", WithExcludeProcess(context.Background(), "java"), func(ctx context.Context) spec.ProcessFilter {
+			return processCmdNameFilter(ctx, "jav")
+		}, []string{}},
+		{"command name not excluded by part of word", WithExcludeProcess(context.Background(), "jav"), func(ctx context.Context) spec.ProcessFilter {
+			return processCmdNameFilter(ctx, "java")
+		}, []string{"100", "101"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pids, err := getPidsByPs(tt.ctx, channel, tt.filter(tt.ctx))
+			if err != nil {
+				t.Fatalf("getPidsByPs() error = %v", err)
+			}
+			if !reflect.DeepEqual(pids, tt.want) {
+				t.Errorf("getPidsByPs() = %v, want %v", pids, tt.want)
+			}
+		})
+	}
+}
+
+func TestPsCommArgs(t *testing.T) {
+	tests := []struct {
+		psArgs      string
+		wantArgs    string
+		wantColumns []string
+	}{
+		{"-eo user,pid,ppid,args", "-eo user,pid,ppid,comm,args", []string{"user", "pid", "ppid", "comm", "args"}},
+		{"-o user,pid,ppid,args", "-o user,pid,ppid,comm,args", []string{"user", "pid", "ppid", "comm", "args"}},
+		{"aux", "aux", []string{"user", "pid", "ppid", "args"}},
+	}
+	for _, tt := range tests {
+		args, columns := psCommArgs(tt.psArgs)
+		if args != tt.wantArgs || !reflect.DeepEqual(columns, tt.wantColumns) {
+			t.Errorf("psCommArgs(%s) = %s, %v, want %s, %v", tt.psArgs, args, columns, tt.wantArgs, tt.wantColumns)
+		}
+	}
+}
+
+func TestContainsWord(t *testing.T) {
+	tests := []struct {
+		text, word string
+		want       bool
+	}{
+		{"/usr/bin/java -jar app.jar", "java", true},
+		{"/opt/javaagent/bin/agent", "java", false},
+		{"/opt/javaagent/bin/agent", "agent", true},
+		{"java -jar chaos_killprocess.jar", "chaos_killprocess", true},
+		{"java -jar my_chaos_killprocess.jar", "chaos_killprocess", false},
+		{"java", "", false},
+	}
+	for _, tt := range tests {
+		if got := containsWord(tt.text, tt.word); got != tt.want {
+			t.Errorf("containsWord(%s, %s) = %t, want %t", tt.text, tt.word, got, tt.want)
+		}
+	}
+}
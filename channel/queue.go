@@ -0,0 +1,173 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package channel
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// Priority is the execution priority, the higher priority execution runs first under the concurrency cap
+type Priority int
+
+const (
+	PriorityQuery Priority = iota
+	PriorityCreate
+	PriorityDestroy
+)
+
+// PriorityKey is the context key of the execution priority
+const PriorityKey spec.ContextKey = "priority"
+
+// WithPriority returns a copy of ctx tagged with the execution priority. If ctx is not tagged,
+// the priority is PriorityDestroy for destroy context, otherwise PriorityCreate.
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, PriorityKey, priority)
+}
+
+func getPriority(ctx context.Context) Priority {
+	if priority, ok := ctx.Value(PriorityKey).(Priority); ok {
+		return priority
+	}
+	if _, ok := spec.IsDestroy(ctx); ok {
+		return PriorityDestroy
+	}
+	return PriorityCreate
+}
+
+// SetMaxConcurrentExecutions sets the max number of the commands executed by the channels at the same time,
+// 0 means no limit
+func SetMaxConcurrentExecutions(max int) {
+	executions.setMax(max)
+}
+
+var executions = &executionQueue{}
+
+// executionQueue limits the concurrent executions and grants the slot to the waiters by priority
+type executionQueue struct {
+	lock    sync.Mutex
+	max     int
+	running int
+	seq     uint64
+	waiters waiterHeap
+}
+
+type waiter struct {
+	priority Priority
+	seq      uint64
+	ready    chan struct{}
+	index    int
+}
+
+func (q *executionQueue) setMax(max int) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.max = max
+	q.grant()
+}
+
+// acquire blocks until the execution slot is granted or ctx is done, the returned func releases the slot,
+// calling it more than once releases the slot only once
+func (q *executionQueue) acquire(ctx context.Context) (func(), error) {
+	q.lock.Lock()
+	if q.max <= 0 || (q.running < q.max && q.waiters.Len() == 0) {
+		q.running++
+		q.lock.Unlock()
+		return q.releaseOnce(), nil
+	}
+	q.seq++
+	w := &waiter{priority: getPriority(ctx), seq: q.seq, ready: make(chan struct{})}
+	heap.Push(&q.waiters, w)
+	q.lock.Unlock()
+
+	select {
+	case <-w.ready:
+		return q.releaseOnce(), nil
+	case <-ctx.Done():
+		q.lock.Lock()
+		defer q.lock.Unlock()
+		select {
+		case <-w.ready:
+			// granted while canceling, give the slot to the next waiter
+			q.running--
+			q.grant()
+		default:
+			heap.Remove(&q.waiters, w.index)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// releaseOnce returns the func releasing one slot, the repeated calls are ignored
+func (q *executionQueue) releaseOnce() func() {
+	var once sync.Once
+	return func() {
+		once.Do(q.release)
+	}
+}
+
+func (q *executionQueue) release() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.running--
+	q.grant()
+}
+
+// grant wakes up the waiters by priority until the running count reaches the max, must be called with lock held
+func (q *executionQueue) grant() {
+	for q.waiters.Len() > 0 && (q.max <= 0 || q.running < q.max) {
+		w := heap.Pop(&q.waiters).(*waiter)
+		q.running++
+		close(w.ready)
+	}
+}
+
+// waiterHeap orders the waiters by priority desc and then by arrival
+type waiterHeap []*waiter
+
+func (h waiterHeap) Len() int { return len(h) }
+
+func (h waiterHeap) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority > h[j].priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h waiterHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *waiterHeap) Push(x interface{}) {
+	w := x.(*waiter)
+	w.index = len(*h)
+	*h = append(*h, w)
+}
+
+func (h *waiterHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	w := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return w
+}
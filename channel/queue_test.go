@@ -0,0 +1,91 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package channel
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExecutionQueue_DestroyFirst(t *testing.T) {
+	q := &executionQueue{max: 1}
+	release, err := q.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+
+	order := make(chan Priority, 3)
+	waitFor := func(priority Priority, waiters int) {
+		go func() {
+			release, err := q.acquire(WithPriority(context.Background(), priority))
+			if err != nil {
+				t.Errorf("acquire() error = %v", err)
+				return
+			}
+			order <- priority
+			release()
+		}()
+		for {
+			q.lock.Lock()
+			n := q.waiters.Len()
+			q.lock.Unlock()
+			if n == waiters {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	waitFor(PriorityQuery, 1)
+	waitFor(PriorityCreate, 2)
+	waitFor(PriorityDestroy, 3)
+	release()
+
+	got := []Priority{<-order, <-order, <-order}
+	want := []Priority{PriorityDestroy, PriorityCreate, PriorityQuery}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("execution order = %v, want %v", got, want)
+	}
+}
+
+func TestExecutionQueue_AcquireCanceled(t *testing.T) {
+	q := &executionQueue{max: 1}
+	release, _ := q.acquire(context.Background())
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := q.acquire(ctx); err == nil {
+		t.Errorf("acquire() expected error after ctx done")
+	}
+	if q.waiters.Len() != 0 {
+		t.Errorf("unexpected waiters: %d", q.waiters.Len())
+	}
+}
+
+func TestExecutionQueue_ReleaseTwice(t *testing.T) {
+	q := &executionQueue{max: 2}
+	release, _ := q.acquire(context.Background())
+	other, _ := q.acquire(context.Background())
+	defer other()
+	release()
+	release()
+	if q.running != 1 {
+		t.Errorf("running = %d after releasing twice, want 1", q.running)
+	}
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package channel
+
+import (
+	"context"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// CheckRequirements checks the commands by the channel, the kernel modules, the capabilities, the kernel version
+// and the cgroup version on the local host. It returns nil if satisfied, otherwise the EnvironmentNotSatisfied
+// response whose result lists all missing items.
+func CheckRequirements(ctx context.Context, channel spec.Channel, requirements *spec.ActionRequirements) *spec.Response {
+	missing := missingRequirements(ctx, func(command string) bool {
+		return channel.IsCommandAvailable(ctx, command)
+	}, requirements)
+	if missing.IsEmpty() {
+		return nil
+	}
+	response := spec.ResponseFailWithFlags(spec.EnvironmentNotSatisfied, missing)
+	response.Result = missing
+	return response
+}
+
+// missingRequirements returns the requirements not satisfied, the commands are checked by isCommandAvailable
+func missingRequirements(ctx context.Context, isCommandAvailable func(command string) bool,
+	requirements *spec.ActionRequirements) *spec.ActionRequirements {
+	missing := &spec.ActionRequirements{}
+	if requirements.IsEmpty() {
+		return missing
+	}
+	for _, command := range requirements.Commands {
+		if !isCommandAvailable(command) {
+			missing.Commands = append(missing.Commands, command)
+		}
+	}
+	for _, module := range requirements.KernelModules {
+		if !isKernelModuleLoaded(module) {
+			missing.KernelModules = append(missing.KernelModules, module)
+		}
+	}
+	for _, capability := range requirements.Capabilities {
+		ok, err := hasCapability(capability)
+		if err != nil {
+			log.Warnf(ctx, "check capability %s failed, %v", capability, err)
+		}
+		if !ok {
+			missing.Capabilities = append(missing.Capabilities, capability)
+		}
+	}
+	if requirements.KernelVersion != "" {
+		if current := kernelVersion(); current != "" {
+			matched, err := matchKernelVersion(current, requirements)
+			if err != nil {
+				log.Warnf(ctx, "match kernel version %s with %s failed, %v", current, requirements.KernelVersion, err)
+			}
+			if err != nil || !matched {
+				missing.KernelVersion = requirements.KernelVersion
+			}
+		}
+	}
+	if requirements.CgroupVersion != "" {
+		if current := cgroupVersion(); current != "" && current != requirements.CgroupVersion {
+			missing.CgroupVersion = requirements.CgroupVersion
+		}
+	}
+	return missing
+}
+
+func matchKernelVersion(current string, requirements *spec.ActionRequirements) (bool, error) {
+	constraint, err := requirements.KernelVersionConstraint()
+	if err != nil {
+		return false, err
+	}
+	return constraint.Check(current)
+}
+
+// RequirementsChecker returns the spec.RequirementsChecker which checks the requirements by CheckRequirements
+// with the channel. Set it by spec.SetRequirementsChecker, so spec.ExecExperiment checks the requirements of the
+// registered actions before the executors:
+//
+//	spec.SetRequirementsChecker(channel.RequirementsChecker(channel.NewLocalChannel()))
+func RequirementsChecker(channel spec.Channel) spec.RequirementsChecker {
+	return func(ctx context.Context, requirements *spec.ActionRequirements) *spec.Response {
+		return CheckRequirements(ctx, channel, requirements)
+	}
+}
+
+// RequirementsMiddleware returns the middleware which checks the requirements of the action before executing.
+// It's opt-in for the executors invoked without spec.ExecExperiment, see RequirementsChecker.
+func RequirementsMiddleware(channel spec.Channel, action spec.ExpActionCommandSpec) spec.ExecutorMiddleware {
+	return spec.ExecutorMiddlewareFunc(func(next spec.ExecFunc) spec.ExecFunc {
+		return func(uid string, ctx context.Context, model *spec.ExpModel) *spec.Response {
+			if response := CheckRequirements(ctx, channel, spec.ActionRequirementsOf(action)); response != nil {
+				return response
+			}
+			return next(uid, ctx, model)
+		}
+	})
+}
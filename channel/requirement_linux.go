@@ -0,0 +1,48 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package channel
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/util"
+)
+
+// isKernelModuleLoaded returns true if the module is loaded or built into the kernel
+func isKernelModuleLoaded(module string) bool {
+	return util.IsKernelModuleLoaded(module)
+}
+
+// hasCapability returns true if the capability is in the effective set of the current process
+func hasCapability(capability string) (bool, error) {
+	return util.HasCapability(capability)
+}
+
+// kernelVersion returns the release of the running kernel, such as 5.10.0-60.el8.x86_64
+func kernelVersion() string {
+	release, err := ioutil.ReadFile("/proc/sys/kernel/osrelease")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(release))
+}
+
+// cgroupVersion returns v2 if the unified hierarchy is mounted, otherwise v1
+func cgroupVersion() string {
+	return util.GetCgroupMode().Version()
+}
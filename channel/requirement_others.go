@@ -0,0 +1,40 @@
+//go:build !linux
+// +build !linux
+
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package channel
+
+// isKernelModuleLoaded is not checked except linux
+func isKernelModuleLoaded(module string) bool {
+	return true
+}
+
+// hasCapability is not checked except linux
+func hasCapability(capability string) (bool, error) {
+	return true, nil
+}
+
+// kernelVersion is empty except linux, so the kernel version is not checked
+func kernelVersion() string {
+	return ""
+}
+
+// cgroupVersion is empty except linux, so the cgroup version is not checked
+func cgroupVersion() string {
+	return ""
+}
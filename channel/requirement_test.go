@@ -0,0 +1,133 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package channel
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+func TestCheckRequirements(t *testing.T) {
+	mock := NewMockLocalChannel().(*MockLocalChannel)
+	mock.IsCommandAvailableFunc = func(ctx context.Context, commandName string) bool {
+		return commandName == "tc"
+	}
+	if response := CheckRequirements(context.Background(), mock, &spec.ActionRequirements{Commands: []string{"tc"}}); response != nil {
+		t.Errorf("CheckRequirements() = %v, want nil", response)
+	}
+	requirements := &spec.ActionRequirements{
+		Commands:     []string{"tc", "iptables", "ss"},
+		Capabilities: []string{"CAP_UNKNOWN"},
+	}
+	response := CheckRequirements(context.Background(), mock, requirements)
+	if response == nil || response.Code != spec.EnvironmentNotSatisfied.Code {
+		t.Fatalf("CheckRequirements() = %v, want EnvironmentNotSatisfied", response)
+	}
+	missing := response.Result.(*spec.ActionRequirements)
+	if !reflect.DeepEqual(missing.Commands, []string{"iptables", "ss"}) {
+		t.Errorf("missing commands = %v, want [iptables ss]", missing.Commands)
+	}
+}
+
+func TestRequirementsChecker(t *testing.T) {
+	mock := NewMockLocalChannel().(*MockLocalChannel)
+	mock.IsCommandAvailableFunc = func(ctx context.Context, commandName string) bool {
+		return commandName == "tc"
+	}
+	checker := RequirementsChecker(mock)
+	if response := checker(context.Background(), &spec.ActionRequirements{Commands: []string{"tc"}}); response != nil {
+		t.Errorf("RequirementsChecker() = %v, want nil", response)
+	}
+	response := checker(context.Background(), &spec.ActionRequirements{Commands: []string{"iptables"}})
+	if response == nil || response.Code != spec.EnvironmentNotSatisfied.Code {
+		t.Errorf("RequirementsChecker() = %v, want EnvironmentNotSatisfied", response)
+	}
+}
+
+func TestProbeActions(t *testing.T) {
+	mock := NewMockLocalChannel().(*MockLocalChannel)
+	lookups := make(map[string]int)
+	mock.IsCommandAvailableFunc = func(ctx context.Context, commandName string) bool {
+		lookups[commandName]++
+		return commandName == "tc"
+	}
+	model := &spec.ExpCommandModel{
+		ExpName: "network",
+		ExpActions: []spec.ActionModel{
+			{ActionName: "delay", ActionRequirements: &spec.ActionRequirements{Commands: []string{"tc"}, KernelVersion: "1.0"}},
+			{ActionName: "drop", ActionRequirements: &spec.ActionRequirements{Commands: []string{"tc", "iptables"}}},
+			{ActionName: "dns"},
+		},
+		ExpSubModels: []spec.ExpCommandModel{{
+			ExpName:    "bpf",
+			ExpActions: []spec.ActionModel{{ActionName: "loss", ActionRequirements: &spec.ActionRequirements{KernelVersion: "999"}}},
+		}},
+	}
+	report := ProbeActions(context.Background(), mock, model)
+	want := []string{"network/delay", "network/dns"}
+	if kernelVersion() == "" {
+		want = append(want, "network/bpf/loss")
+	}
+	if got := report.SupportedActions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("SupportedActions() = %v, want %v", got, want)
+	}
+	if missing := report.Actions[1].Missing; missing == nil || !reflect.DeepEqual(missing.Commands, []string{"iptables"}) {
+		t.Errorf("Missing = %+v, want iptables", missing)
+	}
+	if lookups["tc"] != 1 {
+		t.Errorf("the tc command is looked up %d times, want once", lookups["tc"])
+	}
+}
+
+func TestCheckCgroupVersion(t *testing.T) {
+	current := cgroupVersion()
+	if current == "" {
+		t.Skip("the cgroup version is not checked on this platform")
+	}
+	other := spec.CgroupV1
+	if current == spec.CgroupV1 {
+		other = spec.CgroupV2
+	}
+	mock := NewMockLocalChannel()
+	if response := CheckRequirements(context.Background(), mock, &spec.ActionRequirements{CgroupVersion: current}); response != nil {
+		t.Errorf("CheckRequirements() = %v, want nil", response)
+	}
+	response := CheckRequirements(context.Background(), mock, &spec.ActionRequirements{CgroupVersion: other})
+	if response == nil || response.Result.(*spec.ActionRequirements).CgroupVersion != other {
+		t.Errorf("CheckRequirements() = %v, want the missing cgroup version %s", response, other)
+	}
+}
+
+func TestMatchKernelVersion(t *testing.T) {
+	tests := []struct {
+		kernelVersion string
+		want          bool
+	}{
+		{kernelVersion: "4.9", want: true},
+		{kernelVersion: ">=4.9, <5.0", want: false},
+		{kernelVersion: "~4.19 || >=5.4", want: true},
+	}
+	for _, tt := range tests {
+		got, err := matchKernelVersion("5.10.0-60.el8.x86_64", &spec.ActionRequirements{KernelVersion: tt.kernelVersion})
+		if err != nil || got != tt.want {
+			t.Errorf("matchKernelVersion(%s) = %t, %v, want %t", tt.kernelVersion, got, err, tt.want)
+		}
+	}
+}
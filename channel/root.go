@@ -0,0 +1,47 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package channel
+
+import (
+	"context"
+	"strings"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// RootKey is the context key of the alternate root directory which the command is executed in
+const RootKey spec.ContextKey = "root"
+
+// WithRoot returns a copy of ctx that makes the local channel chroot to the root path before running the command,
+// so experiments can target images mounted on the host or other alternate root filesystems.
+// The script and its interpreter must exist under the root path. The nsexec channel passes the root by --root,
+// which is the path in the mount namespace of the target. Not supported on windows.
+func WithRoot(ctx context.Context, root string) context.Context {
+	return context.WithValue(ctx, RootKey, root)
+}
+
+func getRoot(ctx context.Context) string {
+	root, ok := ctx.Value(RootKey).(string)
+	if !ok {
+		return ""
+	}
+	root = strings.TrimSpace(root)
+	if root == "/" {
+		return ""
+	}
+	return root
+}
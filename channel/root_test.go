@@ -0,0 +1,90 @@
+//go:build !windows
+// +build !windows
+
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package channel
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+func TestGetRoot(t *testing.T) {
+	tests := []struct {
+		ctx  context.Context
+		want string
+	}{
+		{ctx: context.Background(), want: ""},
+		{ctx: WithRoot(context.Background(), " /mnt/image "), want: "/mnt/image"},
+		{ctx: WithRoot(context.Background(), "/"), want: ""},
+	}
+	for _, tt := range tests {
+		if got := getRoot(tt.ctx); got != tt.want {
+			t.Errorf("getRoot() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestWrapRootQuoted(t *testing.T) {
+	dir := t.TempDir()
+	// the fake chroot prints the arguments instead of changing the root
+	bin := filepath.Join(dir, "bin")
+	os.Mkdir(bin, 0755)
+	if err := os.WriteFile(filepath.Join(bin, "chroot"), []byte("#!/bin/sh\nprintf '%s\\n' \"$@\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cgroupDir := filepath.Join(dir, "cg $(touch injected)")
+	os.Mkdir(cgroupDir, 0755)
+	cg := &limitedCgroup{dirs: []string{cgroupDir}}
+
+	root := filepath.Join(dir, `root "$(touch injected)" `+"`touch injected`")
+	cmd := exec.Command("/bin/sh", cg.wrap([]string{"echo", "hello world"}, root)...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "PATH="+bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("run the wrapped command failed, %v, %s", err, output)
+	}
+	if want := root + "\necho\nhello world\n"; string(output) != want {
+		t.Errorf("chroot arguments = %q, want %q", output, want)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "injected")); err == nil {
+		t.Errorf("the root path is interpreted by the shell")
+	}
+	if procs, _ := os.ReadFile(filepath.Join(cgroupDir, "cgroup.procs")); strings.TrimSpace(string(procs)) == "" {
+		t.Errorf("the shell does not join the cgroup")
+	}
+}
+
+func TestExecScriptMasksSecrets(t *testing.T) {
+	model := &spec.ExpModel{ActionFlags: map[string]string{"password": "p@ss"}, SecretFlags: []string{"password"}}
+	ctx := spec.WithSecrets(context.Background(), model)
+	response := execScript(ctx, "echo", "p@ss; exit 3")
+	if response.Success {
+		t.Fatalf("execScript() = %s, want failure", response.Print())
+	}
+	if strings.Contains(response.Err, "p@ss") || !strings.Contains(response.Err, spec.SecretMask) {
+		t.Errorf("execScript() error = %s, want the secret masked", response.Err)
+	}
+}
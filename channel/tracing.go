@@ -0,0 +1,94 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package channel
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// Span attribute names of the channel execution
+const (
+	SpanAttrChannel    = "chaosblade.channel"
+	SpanAttrCommand    = "chaosblade.command"
+	SpanAttrArgsHash   = "chaosblade.args.hash"
+	SpanAttrCode       = "chaosblade.response.code"
+	SpanAttrSuccess    = "chaosblade.response.success"
+	SpanAttrDurationMs = "chaosblade.duration.ms"
+)
+
+// Span is the tracing span of one channel execution
+type Span interface {
+	// SetAttributes sets the attributes to the span
+	SetAttributes(attributes map[string]interface{})
+
+	// End completes the span
+	End()
+}
+
+// Tracer starts the span of channel execution. It is designed to be adapted to OpenTelemetry:
+// Start maps to trace.Tracer.Start, so the span is the child of the span propagated in the ctx
+// and the returned ctx carries the new span to the command execution.
+type Tracer interface {
+	Start(ctx context.Context, spanName string) (context.Context, Span)
+}
+
+var (
+	tracer     Tracer
+	tracerLock sync.RWMutex
+)
+
+// SetTracer sets the tracer used by all channels, nil disables tracing
+func SetTracer(t Tracer) {
+	tracerLock.Lock()
+	defer tracerLock.Unlock()
+	tracer = t
+}
+
+func getTracer() Tracer {
+	tracerLock.RLock()
+	defer tracerLock.RUnlock()
+	return tracer
+}
+
+// traceRun invokes the run func in a span named by the channel name
+func traceRun(ctx context.Context, channelName, script, args string,
+	run func(ctx context.Context) *spec.Response) *spec.Response {
+	t := getTracer()
+	if t == nil {
+		return run(ctx)
+	}
+	ctx, span := t.Start(ctx, channelName+".Run")
+	defer span.End()
+	start := time.Now()
+	response := run(ctx)
+	sum := sha256.Sum256([]byte(args))
+	span.SetAttributes(map[string]interface{}{
+		SpanAttrChannel:    channelName,
+		SpanAttrCommand:    script,
+		SpanAttrArgsHash:   hex.EncodeToString(sum[:]),
+		SpanAttrCode:       response.Code,
+		SpanAttrSuccess:    response.Success,
+		SpanAttrDurationMs: time.Since(start).Milliseconds(),
+	})
+	return response
+}
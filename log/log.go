@@ -9,7 +9,7 @@ import (
 )
 
 func Panicf(ctx context.Context, format string, a ...interface{}) {
-	uid := ctx.Value(spec.Uid)
+	uid, _ := spec.UIDFromContext(ctx)
 	logrus.WithFields(logrus.Fields{
 		"uid":      uid,
 		"location": GetRunFuncLocation(),
@@ -17,7 +17,7 @@ func Panicf(ctx context.Context, format string, a ...interface{}) {
 }
 
 func Fatalf(ctx context.Context, format string, a ...interface{}) {
-	uid := ctx.Value(spec.Uid)
+	uid, _ := spec.UIDFromContext(ctx)
 	logrus.WithFields(logrus.Fields{
 		"uid":      uid,
 		"location": GetRunFuncLocation(),
@@ -25,7 +25,7 @@ func Fatalf(ctx context.Context, format string, a ...interface{}) {
 }
 
 func Errorf(ctx context.Context, format string, a ...interface{}) {
-	uid := ctx.Value(spec.Uid)
+	uid, _ := spec.UIDFromContext(ctx)
 	logrus.WithFields(logrus.Fields{
 		"uid":      uid,
 		"location": GetRunFuncLocation(),
@@ -33,7 +33,7 @@ func Errorf(ctx context.Context, format string, a ...interface{}) {
 }
 
 func Warnf(ctx context.Context, format string, a ...interface{}) {
-	uid := ctx.Value(spec.Uid)
+	uid, _ := spec.UIDFromContext(ctx)
 	logrus.WithFields(logrus.Fields{
 		"uid":      uid,
 		"location": GetRunFuncLocation(),
@@ -41,7 +41,7 @@ func Warnf(ctx context.Context, format string, a ...interface{}) {
 }
 
 func Infof(ctx context.Context, format string, a ...interface{}) {
-	uid := ctx.Value(spec.Uid)
+	uid, _ := spec.UIDFromContext(ctx)
 	logrus.WithFields(logrus.Fields{
 		"uid":      uid,
 		"location": GetRunFuncLocation(),
@@ -49,7 +49,7 @@ func Infof(ctx context.Context, format string, a ...interface{}) {
 }
 
 func Debugf(ctx context.Context, format string, a ...interface{}) {
-	uid := ctx.Value(spec.Uid)
+	uid, _ := spec.UIDFromContext(ctx)
 	logrus.WithFields(logrus.Fields{
 		"uid":      uid,
 		"location": GetRunFuncLocation(),
@@ -57,7 +57,7 @@ func Debugf(ctx context.Context, format string, a ...interface{}) {
 }
 
 func Tracef(ctx context.Context, format string, a ...interface{}) {
-	uid := ctx.Value(spec.Uid)
+	uid, _ := spec.UIDFromContext(ctx)
 	logrus.WithFields(logrus.Fields{
 		"uid":      uid,
 		"location": GetRunFuncLocation(),
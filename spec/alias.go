@@ -0,0 +1,68 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"fmt"
+)
+
+// FindFlag returns the flag whose name or alias is the name
+func FindFlag(flags []ExpFlagSpec, name string) (ExpFlagSpec, bool) {
+	for _, flag := range flags {
+		if flag.FlagName() == name {
+			return flag, true
+		}
+	}
+	for _, flag := range flags {
+		for _, alias := range FlagAliasesOf(flag) {
+			if alias == name {
+				return flag, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// ResolveFlagAliases renames the flags specified by the aliases of the action matchers and flags to the flag names,
+// so the executors only read the flag names. It should be invoked before ApplyFlagDefaults and validating the model.
+// The ParameterConflict response is returned if the flag and its alias are specified with the different values.
+func ResolveFlagAliases(action ExpActionCommandSpec, model *ExpModel) *Response {
+	return resolveFlagAliases(actionFlagSpecList(action), model)
+}
+
+func resolveFlagAliases(flags []ExpFlagSpec, model *ExpModel) *Response {
+	for _, flag := range flags {
+		name := flag.FlagName()
+		for _, alias := range FlagAliasesOf(flag) {
+			value, ok := model.ActionFlags[alias]
+			if !ok || alias == name {
+				continue
+			}
+			if existing, ok := model.ActionFlags[name]; ok && existing != value {
+				return ResponseFailWithFlags(ParameterConflict, fmt.Sprintf("%s, %s", name, alias)).
+					AddFieldErrors(FieldError{Field: name, Constraint: FlagGroupMutuallyExclusive, Value: value})
+			}
+			model.ActionFlags[name] = value
+			delete(model.ActionFlags, alias)
+			if values, ok := model.ActionFlagValues[alias]; ok {
+				model.ActionFlagValues[name] = values
+				delete(model.ActionFlagValues, alias)
+			}
+		}
+	}
+	return nil
+}
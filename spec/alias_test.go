@@ -0,0 +1,75 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResolveFlagAliases(t *testing.T) {
+	action := &ActionModel{
+		ActionMatchers: []ExpFlag{{Name: "process", Aliases: []string{"process-name"}}},
+		ActionFlags:    []ExpFlag{{Name: "exclude-port", Aliases: []string{"exclude"}, Repeated: true}},
+	}
+	tests := []struct {
+		name       string
+		flags      map[string]string
+		values     map[string][]string
+		want       map[string]string
+		wantValues map[string][]string
+		wantCode   int32
+	}{
+		{name: "name", flags: map[string]string{"process": "java"}, want: map[string]string{"process": "java"}},
+		{name: "alias", flags: map[string]string{"process-name": "java"}, want: map[string]string{"process": "java"}},
+		{name: "same value", flags: map[string]string{"process": "java", "process-name": "java"},
+			want: map[string]string{"process": "java"}},
+		{name: "conflict", flags: map[string]string{"process": "java", "process-name": "nginx"}, wantCode: ParameterConflict.Code},
+		{name: "repeated alias", flags: map[string]string{"exclude": "22,80"}, values: map[string][]string{"exclude": {"22", "80"}},
+			want: map[string]string{"exclude-port": "22,80"}, wantValues: map[string][]string{"exclude-port": {"22", "80"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := &ExpModel{ActionFlags: tt.flags, ActionFlagValues: tt.values}
+			response := ResolveFlagAliases(action, model)
+			if tt.wantCode != 0 {
+				if response == nil || response.Code != tt.wantCode {
+					t.Errorf("ResolveFlagAliases() = %v, want code %d", response, tt.wantCode)
+				}
+				return
+			}
+			if response != nil {
+				t.Fatalf("ResolveFlagAliases() = %s, want nil", response.Print())
+			}
+			if !reflect.DeepEqual(model.ActionFlags, tt.want) || !reflect.DeepEqual(model.ActionFlagValues, tt.wantValues) {
+				t.Errorf("ResolveFlagAliases() flags = %v %v, want %v %v", model.ActionFlags, model.ActionFlagValues, tt.want, tt.wantValues)
+			}
+		})
+	}
+}
+
+func TestFindFlag(t *testing.T) {
+	flags := []ExpFlagSpec{&ExpFlag{Name: "process", Aliases: []string{"process-name"}}, &ExpFlag{Name: "pid"}}
+	for _, name := range []string{"process", "process-name"} {
+		if flag, ok := FindFlag(flags, name); !ok || flag.FlagName() != "process" {
+			t.Errorf("FindFlag(%s) = %v, %v, want process", name, flag, ok)
+		}
+	}
+	if _, ok := FindFlag(flags, "name"); ok {
+		t.Errorf("FindFlag(name) = true, want false")
+	}
+}
@@ -0,0 +1,172 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ModelToArgs returns the flags of the model as the command arguments sorted by the flag name, every flag is
+// formatted as --name=value, so the value beginning with - or containing spaces is kept as is by ArgsToModel.
+// The repeated flag in ActionFlagValues is formatted once for every value.
+func ModelToArgs(model *ExpModel) []string {
+	names := make([]string, 0, len(model.ActionFlags))
+	for name := range model.ActionFlags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	args := make([]string, 0, len(names))
+	for _, name := range names {
+		values, ok := model.ActionFlagValues[name]
+		if !ok {
+			values = []string{model.ActionFlags[name]}
+		}
+		for _, value := range values {
+			args = append(args, fmt.Sprintf("--%s=%s", name, value))
+		}
+	}
+	return args
+}
+
+// ArgsToModel parses the flag arguments to the model of the target and the action. The flag is --name=value,
+// --name value or --name without value which is true, the value of the last form must begin with -- or be the end.
+// The values of the flag specified multiple times are added to ActionFlagValues.
+func ArgsToModel(target, action string, args []string) (*ExpModel, error) {
+	model := &ExpModel{Target: target, ActionName: action, ActionFlags: make(map[string]string)}
+	for idx := 0; idx < len(args); idx++ {
+		arg := args[idx]
+		if !strings.HasPrefix(arg, "--") || arg == "--" {
+			return nil, fmt.Errorf("unexpected argument `%s`", arg)
+		}
+		name, value, hasValue := strings.Cut(arg[2:], "=")
+		if name == "" {
+			return nil, fmt.Errorf("illegal flag `%s`", arg)
+		}
+		if !hasValue {
+			value = "true"
+			if idx+1 < len(args) && !strings.HasPrefix(args[idx+1], "--") {
+				idx++
+				value = args[idx]
+			}
+		}
+		if previous, ok := model.ActionFlags[name]; ok {
+			if _, ok := model.ActionFlagValues[name]; !ok {
+				model.SetFlagValues(name, []string{previous})
+			}
+			model.AddFlagValue(name, value)
+			continue
+		}
+		model.ActionFlags[name] = value
+	}
+	return model, nil
+}
+
+// ModelToCommandLine returns the target, the action and the flags of the model joined by QuoteArgs
+func ModelToCommandLine(model *ExpModel) string {
+	args := make([]string, 0)
+	for _, arg := range []string{model.Target, model.ActionName} {
+		if arg != "" {
+			args = append(args, arg)
+		}
+	}
+	return QuoteArgs(append(args, ModelToArgs(model)...))
+}
+
+// QuoteArgs joins the arguments by spaces, the argument containing the shell special characters is single quoted,
+// so the command line is split to the same arguments by SplitArgs or the shell.
+func QuoteArgs(args []string) string {
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		quoted = append(quoted, quoteArg(arg))
+	}
+	return strings.Join(quoted, " ")
+}
+
+func quoteArg(arg string) string {
+	if arg == "" {
+		return "''"
+	}
+	for _, r := range arg {
+		if !isSafeArgRune(r) {
+			return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+	}
+	return arg
+}
+
+func isSafeArgRune(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_=+@%:,./", r)
+}
+
+// SplitArgs splits the command line to the arguments like the shell, the single quoted text is literal,
+// the backslash escapes the next character outside the quotes, and escapes \ " $ ` in the double quotes
+func SplitArgs(line string) ([]string, error) {
+	args := make([]string, 0)
+	var current strings.Builder
+	inArg := false
+	runes := []rune(line)
+	for idx := 0; idx < len(runes); idx++ {
+		r := runes[idx]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		case r == '\'':
+			inArg = true
+			end := idx + 1
+			for end < len(runes) && runes[end] != '\'' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated single quote in `%s`", line)
+			}
+			current.WriteString(string(runes[idx+1 : end]))
+			idx = end
+		case r == '"':
+			inArg = true
+			idx++
+			for ; idx < len(runes) && runes[idx] != '"'; idx++ {
+				if runes[idx] == '\\' && idx+1 < len(runes) && strings.ContainsRune("\\\"$`", runes[idx+1]) {
+					idx++
+				}
+				current.WriteRune(runes[idx])
+			}
+			if idx == len(runes) {
+				return nil, fmt.Errorf("unterminated double quote in `%s`", line)
+			}
+		case r == '\\':
+			inArg = true
+			if idx+1 == len(runes) {
+				return nil, fmt.Errorf("trailing backslash in `%s`", line)
+			}
+			idx++
+			current.WriteRune(runes[idx])
+		default:
+			inArg = true
+			current.WriteRune(r)
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestArgsToModel(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "equals", args: []string{"--time=3000", "--offset=-10"}, want: map[string]string{"time": "3000", "offset": "-10"}},
+		{name: "separated", args: []string{"--interface", "eth0"}, want: map[string]string{"interface": "eth0"}},
+		{name: "no args", args: []string{"--climb", "--timeout", "30", "--force"},
+			want: map[string]string{"climb": "true", "timeout": "30", "force": "true"}},
+		{name: "empty value", args: []string{"--exclude-port="}, want: map[string]string{"exclude-port": ""}},
+		{name: "positional", args: []string{"eth0"}, wantErr: true},
+		{name: "repeated", args: []string{"--exclude-port=22", "--exclude-port", "80,443"}, want: map[string]string{"exclude-port": "22,80,443"}},
+		{name: "no name", args: []string{"--=1"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := ArgsToModel("network", "delay", tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ArgsToModel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(model.ActionFlags, tt.want) {
+				t.Errorf("ArgsToModel() = %v, want %v", model.ActionFlags, tt.want)
+			}
+		})
+	}
+}
+
+func TestModelToArgsRoundTrip(t *testing.T) {
+	model := &ExpModel{Target: "network", ActionName: "delay", ActionFlags: map[string]string{
+		"time":      "3000",
+		"offset":    "-10",
+		"empty":     "",
+		"script":    `echo 'it''s' "$HOME" \n`,
+		"multiline": "a b\tc\nd",
+		"unicode":   "网卡 eth0",
+	}}
+	args := ModelToArgs(model)
+	if args[0] != "--empty=" || args[len(args)-1] != "--unicode=网卡 eth0" {
+		t.Errorf("ModelToArgs() = %v, want sorted flags", args)
+	}
+	parsed, err := ArgsToModel(model.Target, model.ActionName, args)
+	if err != nil || !reflect.DeepEqual(parsed, model) {
+		t.Errorf("ArgsToModel(ModelToArgs()) = %v, %v, want %v", parsed, err, model)
+	}
+
+	line := ModelToCommandLine(model)
+	split, err := SplitArgs(line)
+	if err != nil {
+		t.Fatalf("SplitArgs(%s) error = %v", line, err)
+	}
+	if split[0] != "network" || split[1] != "delay" {
+		t.Fatalf("SplitArgs(%s) = %v, want the target and the action first", line, split)
+	}
+	parsed, err = ArgsToModel(split[0], split[1], split[2:])
+	if err != nil || !reflect.DeepEqual(parsed, model) {
+		t.Errorf("ArgsToModel(SplitArgs(%s)) = %v, %v, want %v", line, parsed, err, model)
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    []string
+		wantErr bool
+	}{
+		{line: "blade create  cpu load", want: []string{"blade", "create", "cpu", "load"}},
+		{line: `--file '/tmp/a b' --desc "say \"hi\" \$x" --path a\ b`,
+			want: []string{"--file", "/tmp/a b", "--desc", `say "hi" $x`, "--path", "a b"}},
+		{line: `--empty '' --mixed a'b'"c"`, want: []string{"--empty", "", "--mixed", "abc"}},
+		{line: `--file '/tmp/a`, wantErr: true},
+		{line: `--desc "a`, wantErr: true},
+		{line: `a\`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := SplitArgs(tt.line)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SplitArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ExpModelBuilder builds the ExpModel fluently, for example:
+//
+//	model, err := spec.NewExpModelBuilder().Target("network").Action("delay").
+//		Flag("time", "3000").Flag("interface", "eth0").Build()
+type ExpModelBuilder struct {
+	model      *ExpModel
+	actionSpec ExpActionCommandSpec
+	ctx        context.Context
+	errs       []string
+}
+
+// NewExpModelBuilder returns an empty builder
+func NewExpModelBuilder() *ExpModelBuilder {
+	return &ExpModelBuilder{
+		model: &ExpModel{ActionFlags: make(map[string]string)},
+		ctx:   context.Background(),
+		errs:  make([]string, 0),
+	}
+}
+
+// Target sets the experiment target, such as network
+func (b *ExpModelBuilder) Target(target string) *ExpModelBuilder {
+	b.model.Target = target
+	return b
+}
+
+// Scope sets the experiment scope, such as host or docker
+func (b *ExpModelBuilder) Scope(scope string) *ExpModelBuilder {
+	b.model.Scope = scope
+	return b
+}
+
+// Action sets the experiment action, such as delay
+func (b *ExpModelBuilder) Action(action string) *ExpModelBuilder {
+	b.model.ActionName = action
+	return b
+}
+
+// Flag sets the flag value, the flag name must not be empty or start with --
+func (b *ExpModelBuilder) Flag(name, value string) *ExpModelBuilder {
+	if strings.TrimSpace(name) == "" || strings.HasPrefix(name, "-") {
+		b.errs = append(b.errs, fmt.Sprintf("illegal flag name `%s`", name))
+		return b
+	}
+	b.model.ActionFlags[name] = value
+	return b
+}
+
+// BoolFlag sets the flag without arguments if the value is true
+func (b *ExpModelBuilder) BoolFlag(name string, value bool) *ExpModelBuilder {
+	if !value {
+		return b
+	}
+	return b.Flag(name, True)
+}
+
+// Flags sets the flags in batch
+func (b *ExpModelBuilder) Flags(flags map[string]string) *ExpModelBuilder {
+	for name, value := range flags {
+		b.Flag(name, value)
+	}
+	return b
+}
+
+// Programs sets the programs executed by the experiment
+func (b *ExpModelBuilder) Programs(programs ...string) *ExpModelBuilder {
+	b.model.ActionPrograms = append(b.model.ActionPrograms, programs...)
+	return b
+}
+
+// Categories sets the scenario categories
+func (b *ExpModelBuilder) Categories(categories ...string) *ExpModelBuilder {
+	b.model.ActionCategories = append(b.model.ActionCategories, categories...)
+	return b
+}
+
+// ProcessHang sets whether the process is hang up
+func (b *ExpModelBuilder) ProcessHang(hang bool) *ExpModelBuilder {
+	b.model.ActionProcessHang = hang
+	return b
+}
+
+// Label sets the label of the experiment, the key must not be empty
+func (b *ExpModelBuilder) Label(key, value string) *ExpModelBuilder {
+	if strings.TrimSpace(key) == "" {
+		b.errs = append(b.errs, "the label key can not be empty")
+		return b
+	}
+	b.model.SetLabel(key, value)
+	return b
+}
+
+// Annotation sets the annotation of the experiment, the key must not be empty
+func (b *ExpModelBuilder) Annotation(key, value string) *ExpModelBuilder {
+	if strings.TrimSpace(key) == "" {
+		b.errs = append(b.errs, "the annotation key can not be empty")
+		return b
+	}
+	b.model.SetAnnotation(key, value)
+	return b
+}
+
+// ActionSpec sets the action spec which the flags are validated by when building
+func (b *ExpModelBuilder) ActionSpec(actionSpec ExpActionCommandSpec) *ExpModelBuilder {
+	b.actionSpec = actionSpec
+	return b
+}
+
+// Context sets the context used to validate the flags, for example the destroy context
+func (b *ExpModelBuilder) Context(ctx context.Context) *ExpModelBuilder {
+	b.ctx = ctx
+	return b
+}
+
+// Build returns the model if the target and action are set, the flag names are legal and the flags pass
+// the validation of the action spec. The absent flags are resolved by ApplyFlagDefaults before validating.
+// The error is the failed *Response if the validation fails.
+func (b *ExpModelBuilder) Build() (*ExpModel, error) {
+	errs := append([]string{}, b.errs...)
+	if strings.TrimSpace(b.model.Target) == "" {
+		errs = append(errs, "target is required")
+	}
+	if strings.TrimSpace(b.model.ActionName) == "" {
+		errs = append(errs, "action is required")
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("build experiment model failed, %s", strings.Join(errs, "; "))
+	}
+	if b.actionSpec != nil {
+		ApplyFlagDefaults(b.actionSpec, b.model)
+		if response := ValidateExpModel(b.ctx, b.actionSpec, b.model); response != nil {
+			return nil, response
+		}
+	}
+	return b.model.Clone(), nil
+}
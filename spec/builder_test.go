@@ -0,0 +1,46 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestExpModelBuilder_Build(t *testing.T) {
+	model, err := NewExpModelBuilder().Target("network").Action("delay").
+		Flag("time", "3000").BoolFlag("force", true).BoolFlag("debug", false).Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	want := &ExpModel{Target: "network", ActionName: "delay",
+		ActionFlags: map[string]string{"time": "3000", "force": "true"}}
+	if !reflect.DeepEqual(model, want) {
+		t.Errorf("Build() = %v, want %v", model, want)
+	}
+
+	if _, err := NewExpModelBuilder().Target("network").Flag("--time", "1").Build(); err == nil {
+		t.Errorf("Build() expected error for missing action and illegal flag")
+	}
+
+	action := &ActionModel{ActionFlags: []ExpFlag{{Name: "time", Required: true}}}
+	_, err = NewExpModelBuilder().Target("network").Action("delay").ActionSpec(action).Build()
+	if !errors.Is(err, ParameterLess) {
+		t.Errorf("Build() error = %v, want ParameterLess", err)
+	}
+}
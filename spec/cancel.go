@@ -0,0 +1,115 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// inflightExperiment is the create execution which can be canceled by the uid
+type inflightExperiment struct {
+	cancel   context.CancelFunc
+	canceled bool
+}
+
+var (
+	inflights     = make(map[string]*inflightExperiment)
+	inflightsLock sync.Mutex
+)
+
+// CancelableMiddleware returns the middleware which makes the create execution cancelable by CancelExperiment.
+// After the canceled execution returns, the experiment is destroyed with the destroy flag, so the parts
+// injected before the cancellation are recovered. The response is ExecCanceled with the failed destroy
+// response as the cause.
+func CancelableMiddleware() ExecutorMiddleware {
+	return ExecutorMiddlewareFunc(func(next ExecFunc) ExecFunc {
+		return func(uid string, ctx context.Context, model *ExpModel) *Response {
+			if _, isDestroy := IsDestroy(ctx); isDestroy {
+				return next(uid, ctx, model)
+			}
+			execCtx, cancel := context.WithCancel(ctx)
+			inflight := &inflightExperiment{cancel: cancel}
+			inflightsLock.Lock()
+			inflights[uid] = inflight
+			inflightsLock.Unlock()
+
+			response := next(uid, execCtx, model)
+
+			inflightsLock.Lock()
+			if inflights[uid] == inflight {
+				delete(inflights, uid)
+			}
+			canceled := inflight.canceled
+			inflightsLock.Unlock()
+			cancel()
+			if !canceled {
+				return response
+			}
+			destroyResponse := next(uid, SetDestroyFlag(detachedContext{ctx}, uid), model)
+			if destroyResponse != nil && !destroyResponse.Success {
+				return WrapResponse(destroyResponse, "destroy", ExecCanceled, uid)
+			}
+			return ResponseFailWithFlags(ExecCanceled, uid)
+		}
+	})
+}
+
+// CancelExperiment cancels the in-flight create execution of the uid, it returns false if not found.
+// The execution is destroyed by CancelableMiddleware after the executor returns.
+func CancelExperiment(uid string) bool {
+	inflightsLock.Lock()
+	defer inflightsLock.Unlock()
+	inflight, ok := inflights[uid]
+	if !ok {
+		return false
+	}
+	inflight.canceled = true
+	inflight.cancel()
+	return true
+}
+
+// IsExecuting returns true if the create execution of the uid is in flight
+func IsExecuting(uid string) bool {
+	inflightsLock.Lock()
+	defer inflightsLock.Unlock()
+	_, ok := inflights[uid]
+	return ok
+}
+
+// detachedContext keeps the values of the parent but not its cancellation, the destroy runs with it
+// after the create execution is canceled
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
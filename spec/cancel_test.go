@@ -0,0 +1,84 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCancelExperiment(t *testing.T) {
+	tests := []struct {
+		name          string
+		destroyResult *Response
+		wantCauses    int
+	}{
+		{"destroyed", Success(), 0},
+		{"destroy failed", ResponseFailWithFlags(OsCmdExecFailed, "tc", "busy"), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			started := make(chan struct{})
+			destroyed := false
+			executor := &testExecutor{exec: func(uid string, ctx context.Context, model *ExpModel) *Response {
+				if _, ok := IsDestroy(ctx); ok {
+					destroyed = ctx.Err() == nil
+					return tt.destroyResult
+				}
+				close(started)
+				<-ctx.Done()
+				return ResponseFailWithFlags(OsCmdExecFailed, "tc", ctx.Err())
+			}}
+			result := make(chan *Response)
+			go func() {
+				result <- WithMiddlewares(executor, CancelableMiddleware()).Exec("abc", context.Background(), &ExpModel{})
+			}()
+			<-started
+			if !IsExecuting("abc") {
+				t.Fatalf("IsExecuting() = false, want true")
+			}
+			if !CancelExperiment("abc") {
+				t.Fatalf("CancelExperiment() = false, want true")
+			}
+			select {
+			case response := <-result:
+				if response.Code != ExecCanceled.Code || len(response.Causes) != tt.wantCauses {
+					t.Errorf("Exec() = %v, want ExecCanceled with %d causes", response, tt.wantCauses)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("Exec() does not return after canceled")
+			}
+			if !destroyed {
+				t.Errorf("the experiment is not destroyed with an alive context")
+			}
+			if IsExecuting("abc") || CancelExperiment("abc") {
+				t.Errorf("the finished execution is still in flight")
+			}
+		})
+	}
+}
+
+func TestCancelableMiddleware_NotCanceled(t *testing.T) {
+	executor := &testExecutor{exec: func(uid string, ctx context.Context, model *ExpModel) *Response {
+		return ReturnSuccess(uid)
+	}}
+	response := WithMiddlewares(executor, CancelableMiddleware()).Exec("abc", context.Background(), &ExpModel{})
+	if !response.Success || IsExecuting("abc") {
+		t.Errorf("Exec() = %v, executing %v", response, IsExecuting("abc"))
+	}
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Cause is one layer of the failure chain carried by the response
+type Cause struct {
+	Code    int32  `json:"code,omitempty"`
+	Message string `json:"message"`
+	// Origin names the layer which produced the failure, for example the command, the channel or the executor
+	Origin string `json:"origin,omitempty"`
+}
+
+// Error returns the message of the cause
+func (c Cause) Error() string {
+	if c.Origin == "" {
+		return c.Message
+	}
+	return fmt.Sprintf("%s: %s", c.Origin, c.Message)
+}
+
+// Is reports whether the cause matches the target by code, the target can be CodeType or *Response
+func (c Cause) Is(target error) bool {
+	if c.Code == 0 {
+		return false
+	}
+	return (&Response{Code: c.Code}).Is(target)
+}
+
+// AddCauses appends the causes to the response, the first cause is the nearest one
+func (response *Response) AddCauses(causes ...Cause) *Response {
+	response.Causes = append(response.Causes, causes...)
+	return response
+}
+
+// RootCause returns the innermost cause, ok is false if the response has no causes
+func (response *Response) RootCause() (Cause, bool) {
+	if len(response.Causes) == 0 {
+		return Cause{}, false
+	}
+	return response.Causes[len(response.Causes)-1], true
+}
+
+// Unwrap returns the causes, so errors.Is matches the codes of the whole chain
+func (response *Response) Unwrap() []error {
+	if len(response.Causes) == 0 {
+		return nil
+	}
+	errs := make([]error, len(response.Causes))
+	for idx, cause := range response.Causes {
+		errs[idx] = cause
+	}
+	return errs
+}
+
+// WrapResponse returns the failed response of the code type which keeps the cause response and its causes
+// in the chain. The origin names the layer which returned the cause response.
+func WrapResponse(cause *Response, origin string, codeType CodeType, flags ...interface{}) *Response {
+	response := ResponseFailWithFlags(codeType, flags...)
+	if cause == nil {
+		return response
+	}
+	response.AddCauses(Cause{Code: cause.Code, Message: cause.Err, Origin: origin})
+	return response.AddCauses(cause.Causes...)
+}
+
+// CausesFromError converts the err to the causes. The code and the causes of the *Response in the err chain
+// are kept, so the returned slice starts with err itself followed by the causes of the response.
+func CausesFromError(err error, origin string) []Cause {
+	if err == nil {
+		return nil
+	}
+	cause := Cause{Message: err.Error(), Origin: origin}
+	var response *Response
+	if errors.As(err, &response) && response != nil {
+		cause.Code = response.Code
+		cause.Message = response.Err
+		return append([]Cause{cause}, response.Causes...)
+	}
+	return []Cause{cause}
+}
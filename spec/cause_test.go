@@ -0,0 +1,71 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestWrapResponse(t *testing.T) {
+	channelResponse := ResponseFailWithFlags(OsCmdExecFailed, "tc", "exit status 2").
+		AddCauses(Cause{Message: "exit status 2", Origin: "tc"})
+	executorResponse := WrapResponse(channelResponse, "local", ParameterIllegal, "interface", "eth9", "not found")
+	dispatcherResponse := WrapResponse(executorResponse, "network-delay", ExecutorPanic, "delay", "boom")
+
+	want := []Cause{
+		{Code: ParameterIllegal.Code, Message: executorResponse.Err, Origin: "network-delay"},
+		{Code: OsCmdExecFailed.Code, Message: channelResponse.Err, Origin: "local"},
+		{Message: "exit status 2", Origin: "tc"},
+	}
+	if !reflect.DeepEqual(dispatcherResponse.Causes, want) {
+		t.Fatalf("Causes = %+v, want %+v", dispatcherResponse.Causes, want)
+	}
+	if cause, ok := dispatcherResponse.RootCause(); !ok || cause.Origin != "tc" {
+		t.Errorf("RootCause() = %+v, %v", cause, ok)
+	}
+	if !errors.Is(dispatcherResponse, OsCmdExecFailed) {
+		t.Errorf("errors.Is(response, OsCmdExecFailed) = false, want true")
+	}
+	if errors.Is(dispatcherResponse, FileNotExist) {
+		t.Errorf("errors.Is(response, FileNotExist) = true, want false")
+	}
+	if _, ok := Success().RootCause(); ok {
+		t.Errorf("RootCause() of success response = true, want false")
+	}
+}
+
+func TestCausesFromError(t *testing.T) {
+	if causes := CausesFromError(nil, "local"); causes != nil {
+		t.Errorf("CausesFromError(nil) = %v, want nil", causes)
+	}
+	causes := CausesFromError(errors.New("exit status 1"), "rm")
+	if !reflect.DeepEqual(causes, []Cause{{Message: "exit status 1", Origin: "rm"}}) {
+		t.Errorf("CausesFromError() = %+v", causes)
+	}
+	response := ResponseFailWithFlags(FileNotExist, "/tmp/a").AddCauses(Cause{Message: "no such file", Origin: "stat"})
+	causes = CausesFromError(fmt.Errorf("prepare failed: %w", response), "executor")
+	want := []Cause{
+		{Code: FileNotExist.Code, Message: response.Err, Origin: "executor"},
+		{Message: "no such file", Origin: "stat"},
+	}
+	if !reflect.DeepEqual(causes, want) {
+		t.Errorf("CausesFromError() = %+v, want %+v", causes, want)
+	}
+}
@@ -62,4 +62,19 @@ type Channel interface {
 
 	// GetPidsByLocalPort returns the process pid corresponding to the port
 	GetPidsByLocalPort(ctx context.Context, localPort string) ([]string, error)
+
+	// ListProcesses returns the processes matched by the filter in one scan, nil filter matches all processes
+	ListProcesses(ctx context.Context, filter ProcessFilter) ([]ProcessInfo, error)
+}
+
+// ProcessInfo is the snapshot of one process
+type ProcessInfo struct {
+	Pid     string `json:"pid"`
+	Ppid    string `json:"ppid"`
+	User    string `json:"user"`
+	Name    string `json:"name"`
+	Cmdline string `json:"cmdline"`
 }
+
+// ProcessFilter returns true if the process should be included in the snapshot
+type ProcessFilter func(process ProcessInfo) bool
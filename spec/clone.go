@@ -0,0 +1,349 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import "reflect"
+
+// Clone returns the deep copy of the model, so the copy can be mutated without affecting the origin
+func (exp *ExpModel) Clone() *ExpModel {
+	if exp == nil {
+		return nil
+	}
+	cloned := *exp
+	cloned.ActionFlags = copyStringMap(exp.ActionFlags)
+	cloned.ActionFlagValues = copyStringSliceMap(exp.ActionFlagValues)
+	cloned.ActionPrograms = copyStrings(exp.ActionPrograms)
+	cloned.ActionCategories = copyStrings(exp.ActionCategories)
+	cloned.SecretFlags = copyStrings(exp.SecretFlags)
+	cloned.Labels = copyStringMap(exp.Labels)
+	cloned.Annotations = copyStringMap(exp.Annotations)
+	return &cloned
+}
+
+// Equal returns true if the models have the same fields, it is used to detect the duplicate submissions.
+// The nil and empty maps or slices are equal, and the order of the programs and categories matters.
+func (exp *ExpModel) Equal(other *ExpModel) bool {
+	if exp == nil || other == nil {
+		return exp == other
+	}
+	if exp.Target != other.Target || exp.Scope != other.Scope || exp.ActionName != other.ActionName ||
+		exp.ActionProcessHang != other.ActionProcessHang || exp.IdempotencyKey != other.IdempotencyKey {
+		return false
+	}
+	return equalStringMaps(exp.ActionFlags, other.ActionFlags) &&
+		equalStringSliceMaps(exp.ActionFlagValues, other.ActionFlagValues) &&
+		equalStrings(exp.ActionPrograms, other.ActionPrograms) &&
+		equalStrings(exp.ActionCategories, other.ActionCategories) &&
+		equalStrings(exp.SecretFlags, other.SecretFlags) &&
+		equalStringMaps(exp.Labels, other.Labels) &&
+		equalStringMaps(exp.Annotations, other.Annotations)
+}
+
+// Clone returns the deep copy of the flag, the Validate callback of the validation and the schema are shared
+func (f *ExpFlag) Clone() *ExpFlag {
+	if f == nil {
+		return nil
+	}
+	cloned := *f
+	cloned.EnumValues = copyStrings(f.EnumValues)
+	cloned.Validation = f.Validation.clone()
+	cloned.Examples = copyExamples(f.Examples)
+	cloned.Translations = copyTranslations(f.Translations)
+	cloned.Aliases = copyStrings(f.Aliases)
+	return &cloned
+}
+
+// Equal returns true if the flags have the same fields except the Validate callback
+func (f *ExpFlag) Equal(other *ExpFlag) bool {
+	return reflect.DeepEqual(f.comparable(), other.comparable())
+}
+
+func (f *ExpFlag) comparable() *ExpFlag {
+	cloned := f.Clone()
+	if cloned != nil {
+		cloned.Validation = comparableValidation(cloned.Validation)
+	}
+	return cloned
+}
+
+func (v *FlagValidation) clone() *FlagValidation {
+	if v == nil {
+		return nil
+	}
+	cloned := *v
+	if v.Min != nil {
+		min := *v.Min
+		cloned.Min = &min
+	}
+	if v.Max != nil {
+		max := *v.Max
+		cloned.Max = &max
+	}
+	cloned.Validators = copyStrings(v.Validators)
+	return &cloned
+}
+
+// Clone returns the deep copy of the action, the executor is shared
+func (am *ActionModel) Clone() *ActionModel {
+	if am == nil {
+		return nil
+	}
+	cloned := *am
+	cloned.ActionAliases = copyStrings(am.ActionAliases)
+	cloned.ActionMatchers = copyFlags(am.ActionMatchers)
+	cloned.ActionFlags = copyFlags(am.ActionFlags)
+	cloned.ActionPrograms = copyStrings(am.ActionPrograms)
+	cloned.ActionCategories = copyStrings(am.ActionCategories)
+	cloned.ActionScopes = copyStrings(am.ActionScopes)
+	cloned.ActionPreconditions = copyStrings(am.ActionPreconditions)
+	if am.ActionRequirements != nil {
+		cloned.ActionRequirements = &ActionRequirements{
+			Commands:      copyStrings(am.ActionRequirements.Commands),
+			KernelModules: copyStrings(am.ActionRequirements.KernelModules),
+			Capabilities:  copyStrings(am.ActionRequirements.Capabilities),
+			KernelVersion: am.ActionRequirements.KernelVersion,
+			CgroupVersion: am.ActionRequirements.CgroupVersion,
+		}
+	}
+	cloned.ActionExamples = copyExamples(am.ActionExamples)
+	cloned.ActionTranslations = copyTranslations(am.ActionTranslations)
+	return &cloned
+}
+
+// Equal returns true if the actions have the same fields except the executor and the Validate callbacks
+func (am *ActionModel) Equal(other *ActionModel) bool {
+	return reflect.DeepEqual(am.comparable(), other.comparable())
+}
+
+func (am *ActionModel) comparable() *ActionModel {
+	cloned := am.Clone()
+	if cloned == nil {
+		return nil
+	}
+	cloned.executor = nil
+	comparableFlags(cloned.ActionMatchers)
+	comparableFlags(cloned.ActionFlags)
+	return cloned
+}
+
+// Clone returns the deep copy of the command including the actions and the sub models, the executors are shared
+func (ecm *ExpCommandModel) Clone() *ExpCommandModel {
+	if ecm == nil {
+		return nil
+	}
+	cloned := *ecm
+	if ecm.ExpActions != nil {
+		cloned.ExpActions = make([]ActionModel, len(ecm.ExpActions))
+		for i := range ecm.ExpActions {
+			cloned.ExpActions[i] = *ecm.ExpActions[i].Clone()
+		}
+	}
+	cloned.ExpFlags = copyFlags(ecm.ExpFlags)
+	cloned.ExpPrepareModel.PrepareFlags = copyFlags(ecm.ExpPrepareModel.PrepareFlags)
+	cloned.ExpSubTargets = copyStrings(ecm.ExpSubTargets)
+	if ecm.ExpFlagGroups != nil {
+		cloned.ExpFlagGroups = make([]FlagGroup, len(ecm.ExpFlagGroups))
+		for i, group := range ecm.ExpFlagGroups {
+			cloned.ExpFlagGroups[i] = FlagGroup{Type: group.Type, Flags: copyStrings(group.Flags)}
+		}
+	}
+	if ecm.ExpSubModels != nil {
+		cloned.ExpSubModels = make([]ExpCommandModel, len(ecm.ExpSubModels))
+		for i := range ecm.ExpSubModels {
+			cloned.ExpSubModels[i] = *ecm.ExpSubModels[i].Clone()
+		}
+	}
+	cloned.ExpTranslations = copyTranslations(ecm.ExpTranslations)
+	return &cloned
+}
+
+// Equal returns true if the commands have the same fields except the executors and the Validate callbacks
+func (ecm *ExpCommandModel) Equal(other *ExpCommandModel) bool {
+	return reflect.DeepEqual(ecm.comparable(), other.comparable())
+}
+
+func (ecm *ExpCommandModel) comparable() *ExpCommandModel {
+	if ecm == nil {
+		return nil
+	}
+	cloned := *ecm
+	cloned.ExpExecutor = nil
+	if ecm.ExpActions != nil {
+		cloned.ExpActions = make([]ActionModel, len(ecm.ExpActions))
+		for i := range ecm.ExpActions {
+			cloned.ExpActions[i] = *ecm.ExpActions[i].comparable()
+		}
+	}
+	cloned.ExpFlags = copyFlags(ecm.ExpFlags)
+	comparableFlags(cloned.ExpFlags)
+	cloned.ExpPrepareModel.PrepareFlags = copyFlags(ecm.ExpPrepareModel.PrepareFlags)
+	comparableFlags(cloned.ExpPrepareModel.PrepareFlags)
+	if ecm.ExpSubModels != nil {
+		cloned.ExpSubModels = make([]ExpCommandModel, len(ecm.ExpSubModels))
+		for i := range ecm.ExpSubModels {
+			cloned.ExpSubModels[i] = *ecm.ExpSubModels[i].comparable()
+		}
+	}
+	return &cloned
+}
+
+// Clone returns the deep copy of the models
+func (m *Models) Clone() *Models {
+	if m == nil {
+		return nil
+	}
+	cloned := *m
+	if m.Models != nil {
+		cloned.Models = make([]ExpCommandModel, len(m.Models))
+		for i := range m.Models {
+			cloned.Models[i] = *m.Models[i].Clone()
+		}
+	}
+	return &cloned
+}
+
+// Clone returns the copy of the response whose metadata, warnings, causes and field errors can be mutated
+// without affecting the origin. The result and the metadata values are shared.
+func (response *Response) Clone() *Response {
+	if response == nil {
+		return nil
+	}
+	cloned := *response
+	if response.Metadata != nil {
+		cloned.Metadata = make(map[string]interface{}, len(response.Metadata))
+		for k, v := range response.Metadata {
+			cloned.Metadata[k] = v
+		}
+	}
+	if response.Warnings != nil {
+		cloned.Warnings = append(make([]Warning, 0, len(response.Warnings)), response.Warnings...)
+	}
+	if response.Causes != nil {
+		cloned.Causes = append(make([]Cause, 0, len(response.Causes)), response.Causes...)
+	}
+	if response.FieldErrors != nil {
+		cloned.FieldErrors = append(make([]FieldError, 0, len(response.FieldErrors)), response.FieldErrors...)
+	}
+	return &cloned
+}
+
+// Equal returns true if the models have the same versions and the equal commands in the same order
+func (m *Models) Equal(other *Models) bool {
+	if m == nil || other == nil {
+		return m == other
+	}
+	if m.Version != other.Version || m.Kind != other.Kind || m.SpecVersion != other.SpecVersion ||
+		len(m.Models) != len(other.Models) {
+		return false
+	}
+	for i := range m.Models {
+		if !m.Models[i].Equal(&other.Models[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func copyFlags(flags []ExpFlag) []ExpFlag {
+	if flags == nil {
+		return nil
+	}
+	copied := make([]ExpFlag, len(flags))
+	for i := range flags {
+		copied[i] = *flags[i].Clone()
+	}
+	return copied
+}
+
+// comparableFlags removes the Validate callbacks of the copied flags in place
+func comparableFlags(flags []ExpFlag) {
+	for i := range flags {
+		flags[i].Validation = comparableValidation(flags[i].Validation)
+	}
+}
+
+func copyStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	return append(make([]string, 0, len(values)), values...)
+}
+
+func copyStringSliceMap(m map[string][]string) map[string][]string {
+	if m == nil {
+		return nil
+	}
+	copied := make(map[string][]string, len(m))
+	for k, v := range m {
+		copied[k] = copyStrings(v)
+	}
+	return copied
+}
+
+func copyExamples(examples []Example) []Example {
+	if examples == nil {
+		return nil
+	}
+	return append(make([]Example, 0, len(examples)), examples...)
+}
+
+func copyTranslations(translations map[string]Translation) map[string]Translation {
+	if translations == nil {
+		return nil
+	}
+	copied := make(map[string]Translation, len(translations))
+	for k, v := range translations {
+		copied[k] = v
+	}
+	return copied
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalStringMaps(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || v != w {
+			return false
+		}
+	}
+	return true
+}
+
+func equalStringSliceMaps(a, b map[string][]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || !equalStrings(v, w) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestExpModelClone(t *testing.T) {
+	model := &ExpModel{
+		Target:           "network",
+		ActionName:       "delay",
+		ActionFlags:      map[string]string{"time": "3000"},
+		ActionFlagValues: map[string][]string{"port": {"80", "443"}},
+		ActionPrograms:   []string{"tc"},
+		Labels:           map[string]string{"team": "payment"},
+	}
+	cloned := model.Clone()
+	if !model.Equal(cloned) {
+		t.Fatalf("Clone() = %+v, want equal to %+v", cloned, model)
+	}
+	cloned.ActionFlags["time"] = "1000"
+	cloned.ActionFlagValues["port"][0] = "8080"
+	cloned.ActionPrograms[0] = "iptables"
+	cloned.Labels["team"] = "order"
+	if model.ActionFlags["time"] != "3000" || model.ActionFlagValues["port"][0] != "80" ||
+		model.ActionPrograms[0] != "tc" || model.Labels["team"] != "payment" {
+		t.Errorf("the origin is mutated by the clone, %+v", model)
+	}
+	if model.Equal(cloned) {
+		t.Errorf("Equal() = true after the clone is mutated")
+	}
+	var empty *ExpModel
+	if empty.Clone() != nil || !empty.Equal(nil) || empty.Equal(model) {
+		t.Errorf("the nil model is not handled")
+	}
+}
+
+func TestExpModelEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b *ExpModel
+		want bool
+	}{
+		{name: "nil and empty maps", a: &ExpModel{Target: "cpu"}, b: &ExpModel{Target: "cpu", ActionFlags: map[string]string{}}, want: true},
+		{name: "different flag", a: &ExpModel{ActionFlags: map[string]string{"a": "1"}}, b: &ExpModel{ActionFlags: map[string]string{"a": "2"}}},
+		{name: "missing flag", a: &ExpModel{ActionFlags: map[string]string{"a": ""}}, b: &ExpModel{ActionFlags: map[string]string{"b": ""}}},
+		{name: "different scope", a: &ExpModel{Scope: "host"}, b: &ExpModel{Scope: "docker"}},
+		{name: "different values order", a: &ExpModel{ActionFlagValues: map[string][]string{"p": {"1", "2"}}},
+			b: &ExpModel{ActionFlagValues: map[string][]string{"p": {"2", "1"}}}},
+		{name: "different annotation", a: &ExpModel{Annotations: map[string]string{"owner": "a"}}, b: &ExpModel{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Equal(tt.b); got != tt.want {
+				t.Errorf("Equal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpCommandModelClone(t *testing.T) {
+	min := 1.0
+	command := &ExpCommandModel{
+		ExpName: "network",
+		ExpActions: []ActionModel{{
+			ActionName: "delay",
+			ActionFlags: []ExpFlag{{Name: "time", Validation: &FlagValidation{Min: &min,
+				Validate: func(ctx context.Context, flag ExpFlagSpec, value string, model *ExpModel) error {
+					return fmt.Errorf("illegal")
+				}}}},
+			ActionRequirements: &ActionRequirements{Commands: []string{"tc"}},
+		}},
+		ExpFlagGroups: []FlagGroup{{Type: FlagGroupExactlyOne, Flags: []string{"interface", "destination-ip"}}},
+		ExpSubModels:  []ExpCommandModel{{ExpName: "dns", ExpActions: []ActionModel{{ActionName: "drop"}}}},
+	}
+	cloned := command.Clone()
+	if !command.Equal(cloned) {
+		t.Fatalf("Clone() is not equal to the origin")
+	}
+	*cloned.ExpActions[0].ActionFlags[0].Validation.Min = 2
+	cloned.ExpActions[0].ActionRequirements.Commands[0] = "iptables"
+	cloned.ExpFlagGroups[0].Flags[0] = "device"
+	cloned.ExpSubModels[0].ExpActions[0].ActionName = "loss"
+	if min != 1 || command.ExpActions[0].ActionRequirements.Commands[0] != "tc" ||
+		command.ExpFlagGroups[0].Flags[0] != "interface" || command.ExpSubModels[0].ExpActions[0].ActionName != "drop" {
+		t.Errorf("the origin is mutated by the clone")
+	}
+	if command.Equal(cloned) {
+		t.Errorf("Equal() = true after the clone is mutated")
+	}
+	models := &Models{Version: "v1", Models: []ExpCommandModel{*command}}
+	if !models.Equal(models.Clone()) {
+		t.Errorf("Models.Clone() is not equal to the origin")
+	}
+}
+
+func TestResponseClone(t *testing.T) {
+	response := ReturnFail(ParameterLess, "time").SetMetadata("host", "a").
+		AddWarnings(Warning{Name: "device"}).AddCauses(Cause{Message: "exit status 1"}).
+		AddFieldErrors(FieldError{Field: "time"})
+	cloned := response.Clone()
+	if !reflect.DeepEqual(cloned, response) {
+		t.Fatalf("Clone() = %v, want %v", cloned, response)
+	}
+	cloned.SetMetadata("host", "b")
+	cloned.Warnings[0].Name = "interface"
+	cloned.Causes[0].Message = "killed"
+	cloned.FieldErrors[0].Field = "interface"
+	if response.Metadata["host"] != "a" || response.Warnings[0].Name != "device" ||
+		response.Causes[0].Message != "exit status 1" || response.FieldErrors[0].Field != "time" {
+		t.Errorf("Clone() shares the fields with the origin, origin = %v", response)
+	}
+	if (*Response)(nil).Clone() != nil {
+		t.Errorf("Clone() of nil is not nil")
+	}
+}
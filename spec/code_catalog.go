@@ -0,0 +1,95 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+// The categories of the response codes by the code range
+const (
+	CodeCategorySuccess     = "success"
+	CodeCategoryClient      = "client"
+	CodeCategoryEnvironment = "environment"
+	CodeCategoryExecution   = "execution"
+	CodeCategoryCustom      = "custom"
+	CodeCategoryUnknown     = "unknown"
+)
+
+// CodeCatalogEntry is the description of the registered code for the controllers and the docs tooling
+type CodeCatalogEntry struct {
+	Code     int32  `json:"code" yaml:"code"`
+	Status   string `json:"status" yaml:"status"`
+	Message  string `json:"message" yaml:"message"`
+	Category string `json:"category" yaml:"category"`
+	// HTTPStatus is the http status of the code by HTTPStatus
+	HTTPStatus int `json:"httpStatus" yaml:"httpStatus"`
+	// Messages are the message templates keyed by the locale registered by RegisterMessages
+	Messages map[string]string `json:"messages,omitempty" yaml:"messages,omitempty"`
+	// Params are the names of the message params in order, see MessageParams
+	Params []string `json:"params,omitempty" yaml:"params,omitempty"`
+	// Severity and Retryable are the classification of the code by ClassifyCode
+	Severity  string `json:"severity,omitempty" yaml:"severity,omitempty"`
+	Retryable bool   `json:"retryable,omitempty" yaml:"retryable,omitempty"`
+}
+
+// CodeCategory returns the category of the code range: success below 300, client errors such as the illegal
+// parameters in [40000, 50000), environment errors such as the missing commands in [50000, 60000),
+// execution errors in [60000, CustomCodeMin) and the custom codes of the plugins
+func CodeCategory(code int32) string {
+	switch {
+	case code >= 100 && code < 300:
+		return CodeCategorySuccess
+	case code >= 40000 && code < 50000:
+		return CodeCategoryClient
+	case code >= 50000 && code < 60000:
+		return CodeCategoryEnvironment
+	case code >= 60000 && code < CustomCodeMin:
+		return CodeCategoryExecution
+	case code >= CustomCodeMin && code <= CustomCodeMax:
+		return CodeCategoryCustom
+	}
+	return CodeCategoryUnknown
+}
+
+// CodeCatalog returns all the registered codes ordered by code, including the custom codes of the plugins
+func CodeCatalog() []CodeCatalogEntry {
+	catalogLock.RLock()
+	messages := make(map[int32]map[string]string)
+	for l, templates := range catalog {
+		for code, msg := range templates {
+			if messages[code] == nil {
+				messages[code] = make(map[string]string)
+			}
+			messages[code][l] = msg
+		}
+	}
+	catalogLock.RUnlock()
+
+	entries := make([]CodeCatalogEntry, 0)
+	for _, registered := range RegisteredCodes() {
+		classification := ClassifyCode(registered.Code)
+		entries = append(entries, CodeCatalogEntry{
+			Code:       registered.Code,
+			Status:     registered.Status,
+			Message:    registered.Msg,
+			Category:   CodeCategory(registered.Code),
+			HTTPStatus: HTTPStatus(registered.Code),
+			Messages:   messages[registered.Code],
+			Params:     MessageParams(registered.Code),
+			Severity:   classification.Severity,
+			Retryable:  classification.Retryable,
+		})
+	}
+	return entries
+}
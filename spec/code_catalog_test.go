@@ -0,0 +1,66 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestCodeCategory(t *testing.T) {
+	tests := []struct {
+		code int32
+		want string
+	}{
+		{OK.Code, CodeCategorySuccess},
+		{ParameterLess.Code, CodeCategoryClient},
+		{CommandTcNotFound.Code, CodeCategoryEnvironment},
+		{ExecTimeout.Code, CodeCategoryExecution},
+		{91000, CodeCategoryCustom},
+		{1, CodeCategoryUnknown},
+	}
+	for _, tt := range tests {
+		if got := CodeCategory(tt.code); got != tt.want {
+			t.Errorf("CodeCategory(%d) = %s, want %s", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestCodeCatalog(t *testing.T) {
+	entries := CodeCatalog()
+	if len(entries) != len(RegisteredCodes()) {
+		t.Fatalf("CodeCatalog() returns %d entries, want %d", len(entries), len(RegisteredCodes()))
+	}
+	for _, entry := range entries {
+		if entry.Code != ExecTimeout.Code {
+			continue
+		}
+		want := CodeCatalogEntry{
+			Code:       ExecTimeout.Code,
+			Status:     "ExecTimeout",
+			Message:    ExecTimeout.Msg,
+			Category:   CodeCategoryExecution,
+			HTTPStatus: http.StatusGatewayTimeout,
+		}
+		if entry.Status != want.Status || entry.Message != want.Message || entry.Category != want.Category ||
+			entry.HTTPStatus != want.HTTPStatus || entry.Messages[LocaleZhCN] != zhCNMessages[ExecTimeout.Code] {
+			t.Errorf("CodeCatalog() entry = %+v, want %+v with the zh-CN message", entry, want)
+		}
+		return
+	}
+	t.Errorf("CodeCatalog() does not contain ExecTimeout")
+}
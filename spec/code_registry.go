@@ -0,0 +1,195 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// The code range reserved for the downstream plugins
+const (
+	CustomCodeMin int32 = 90000
+	CustomCodeMax int32 = 99999
+)
+
+// RegisteredCode is the response code with its status name
+type RegisteredCode struct {
+	CodeType
+	Status string
+}
+
+var (
+	codeRegistry     = make(map[int32]RegisteredCode)
+	codeRegistryLock sync.RWMutex
+)
+
+func init() {
+	for status, codeType := range map[string]CodeType{
+		"IgnoreCode":                        IgnoreCode,
+		"OK":                                OK,
+		"ReturnOKDirectly":                  ReturnOKDirectly,
+		"Forbidden":                         Forbidden,
+		"ActionNotSupport":                  ActionNotSupport,
+		"ParameterLess":                     ParameterLess,
+		"ParameterLessOneOf":                ParameterLessOneOf,
+		"ParameterConflict":                 ParameterConflict,
+		"ParameterLessWith":                 ParameterLessWith,
+		"ParameterForbidden":                ParameterForbidden,
+		"ParameterIllegal":                  ParameterIllegal,
+		"ParameterInvalid":                  ParameterInvalid,
+		"ParameterInvalidProName":           ParameterInvalidProName,
+		"ParameterInvalidProIdNotByName":    ParameterInvalidProIdNotByName,
+		"ParameterInvalidCplusPort":         ParameterInvalidCplusPort,
+		"ParameterInvalidDbQuery":           ParameterInvalidDbQuery,
+		"ParameterInvalidCplusTarget":       ParameterInvalidCplusTarget,
+		"ParameterInvalidBladePathError":    ParameterInvalidBladePathError,
+		"ParameterInvalidNSNotOne":          ParameterInvalidNSNotOne,
+		"ParameterInvalidK8sPodQuery":       ParameterInvalidK8sPodQuery,
+		"ParameterInvalidK8sNodeQuery":      ParameterInvalidK8sNodeQuery,
+		"ParameterInvalidDockContainerId":   ParameterInvalidDockContainerId,
+		"ParameterInvalidDockContainerName": ParameterInvalidDockContainerName,
+		"ParameterInvalidTooManyProcess":    ParameterInvalidTooManyProcess,
+		"DeployChaosBladeFailed":            DeployChaosBladeFailed,
+		"ParameterRequestFailed":            ParameterRequestFailed,
+		"CommandIllegal":                    CommandIllegal,
+		"CommandNetworkExist":               CommandNetworkExist,
+		"CommandRejectedByPolicy":           CommandRejectedByPolicy,
+		"ChaosbladeFileNotFound":            ChaosbladeFileNotFound,
+		"CommandTasksetNotFound":            CommandTasksetNotFound,
+		"CommandMountNotFound":              CommandMountNotFound,
+		"CommandUmountNotFound":             CommandUmountNotFound,
+		"CommandTcNotFound":                 CommandTcNotFound,
+		"CommandIptablesNotFound":           CommandIptablesNotFound,
+		"CommandSedNotFound":                CommandSedNotFound,
+		"CommandCatNotFound":                CommandCatNotFound,
+		"CommandSsNotFound":                 CommandSsNotFound,
+		"CommandDdNotFound":                 CommandDdNotFound,
+		"CommandRmNotFound":                 CommandRmNotFound,
+		"CommandTouchNotFound":              CommandTouchNotFound,
+		"CommandMkdirNotFound":              CommandMkdirNotFound,
+		"CommandEchoNotFound":               CommandEchoNotFound,
+		"CommandKillNotFound":               CommandKillNotFound,
+		"CommandMvNotFound":                 CommandMvNotFound,
+		"CommandHeadNotFound":               CommandHeadNotFound,
+		"CommandGrepNotFound":               CommandGrepNotFound,
+		"CommandAwkNotFound":                CommandAwkNotFound,
+		"CommandTarNotFound":                CommandTarNotFound,
+		"CommandSystemctlNotFound":          CommandSystemctlNotFound,
+		"CommandNohupNotFound":              CommandNohupNotFound,
+		"EnvironmentNotSatisfied":           EnvironmentNotSatisfied,
+		"PreconditionNotSatisfied":          PreconditionNotSatisfied,
+		"ChaosbladeServerStarted":           ChaosbladeServerStarted,
+		"UnexpectedStatus":                  UnexpectedStatus,
+		"DockerExecNotFound":                DockerExecNotFound,
+		"DockerImagePullFailed":             DockerImagePullFailed,
+		"CriExecNotFound":                   CriExecNotFound,
+		"ImagePullFailed":                   ImagePullFailed,
+		"HandlerExecNotFound":               HandlerExecNotFound,
+		"CplusActionNotSupport":             CplusActionNotSupport,
+		"ContainerInContextNotFound":        ContainerInContextNotFound,
+		"PodNotReady":                       PodNotReady,
+		"ResultUnmarshalFailed":             ResultUnmarshalFailed,
+		"ResultMarshalFailed":               ResultMarshalFailed,
+		"GenerateUidFailed":                 GenerateUidFailed,
+		"ChaosbladeServiceStoped":           ChaosbladeServiceStoped,
+		"ProcessIdByNameFailed":             ProcessIdByNameFailed,
+		"ProcessJudgeExistFailed":           ProcessJudgeExistFailed,
+		"ProcessNotExist":                   ProcessNotExist,
+		"ProcessGetUsernameFailed":          ProcessGetUsernameFailed,
+		"ChannelNil":                        ChannelNil,
+		"SandboxGetPortFailed":              SandboxGetPortFailed,
+		"SandboxCreateTokenFailed":          SandboxCreateTokenFailed,
+		"FileCantGetLogFile":                FileCantGetLogFile,
+		"FileNotExist":                      FileNotExist,
+		"FileCantReadOrOpen":                FileCantReadOrOpen,
+		"BackfileExists":                    BackfileExists,
+		"DbQueryFailed":                     DbQueryFailed,
+		"K8sExecFailed":                     K8sExecFailed,
+		"DockerExecFailed":                  DockerExecFailed,
+		"OsCmdExecFailed":                   OsCmdExecFailed,
+		"HttpExecFailed":                    HttpExecFailed,
+		"GetIdentifierFailed":               GetIdentifierFailed,
+		"CreateContainerFailed":             CreateContainerFailed,
+		"ContainerExecFailed":               ContainerExecFailed,
+		"OsExecutorNotFound":                OsExecutorNotFound,
+		"ExecutorPanic":                     ExecutorPanic,
+		"ExecTimeout":                       ExecTimeout,
+		"ExecCanceled":                      ExecCanceled,
+		"ConcurrencyLimitExceeded":          ConcurrencyLimitExceeded,
+		"ChaosfsClientFailed":               ChaosfsClientFailed,
+		"ChaosfsInjectFailed":               ChaosfsInjectFailed,
+		"ChaosfsRecoverFailed":              ChaosfsRecoverFailed,
+		"SshExecFailed":                     SshExecFailed,
+		"SshExecNothing":                    SshExecNothing,
+		"SystemdNotFound":                   SystemdNotFound,
+		"DatabaseError":                     DatabaseError,
+		"DataNotFound":                      DataNotFound,
+		"BashPhthonNotFoundError":           BashPhthonNotFoundError,
+	} {
+		codeRegistry[codeType.Code] = RegisteredCode{CodeType: codeType, Status: status}
+	}
+}
+
+// RegisterResponseCode registers the custom code, the code must be in the reserved range
+// [CustomCodeMin, CustomCodeMax] and not registered yet. The registered code is understood by
+// GetCodeType and Decode. The message params are named by RegisterMessageParams.
+func RegisterResponseCode(code int32, status, msg string) (CodeType, error) {
+	if code < CustomCodeMin || code > CustomCodeMax {
+		return CodeType{}, fmt.Errorf("the code %d is out of the custom range [%d, %d]", code, CustomCodeMin, CustomCodeMax)
+	}
+	if status == "" {
+		return CodeType{}, fmt.Errorf("the status of code %d is empty", code)
+	}
+	codeRegistryLock.Lock()
+	defer codeRegistryLock.Unlock()
+	if registered, ok := codeRegistry[code]; ok {
+		return CodeType{}, fmt.Errorf("the code %d is already registered by %s", code, registered.Status)
+	}
+	codeType := CodeType{Code: code, Msg: msg}
+	codeRegistry[code] = RegisteredCode{CodeType: codeType, Status: status}
+	return codeType, nil
+}
+
+// GetCodeType returns the registered code type by the code
+func GetCodeType(code int32) (CodeType, bool) {
+	registered, ok := GetRegisteredCode(code)
+	return registered.CodeType, ok
+}
+
+// GetRegisteredCode returns the registered code with the status name by the code
+func GetRegisteredCode(code int32) (RegisteredCode, bool) {
+	codeRegistryLock.RLock()
+	defer codeRegistryLock.RUnlock()
+	registered, ok := codeRegistry[code]
+	return registered, ok
+}
+
+// RegisteredCodes returns all the registered codes ordered by code
+func RegisteredCodes() []RegisteredCode {
+	codeRegistryLock.RLock()
+	defer codeRegistryLock.RUnlock()
+	codes := make([]RegisteredCode, 0, len(codeRegistry))
+	for _, registered := range codeRegistry {
+		codes = append(codes, registered)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		return codes[i].Code < codes[j].Code
+	})
+	return codes
+}
@@ -0,0 +1,39 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import "testing"
+
+func TestRegisterResponseCode(t *testing.T) {
+	codeType, err := RegisterResponseCode(91001, "RedisConnectFailed", "`%s`: connect redis failed")
+	if err != nil {
+		t.Fatalf("RegisterResponseCode() error = %v", err)
+	}
+	if _, err := RegisterResponseCode(91001, "RedisAuthFailed", "auth failed"); err == nil {
+		t.Errorf("RegisterResponseCode() expected collision error")
+	}
+	if _, err := RegisterResponseCode(OK.Code, "MyOK", "ok"); err == nil {
+		t.Errorf("RegisterResponseCode() expected out of range error")
+	}
+	if got, ok := GetCodeType(91001); !ok || got != codeType {
+		t.Errorf("GetCodeType() = %v, %v", got, ok)
+	}
+	response := Decode(`{"code":91001,"success":false}`, nil)
+	if response.Err != codeType.Msg {
+		t.Errorf("Decode() err = %s, want %s", response.Err, codeType.Msg)
+	}
+}
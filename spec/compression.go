@@ -0,0 +1,164 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+)
+
+// CompressionGzip is the built-in compression algorithm of the response result, the others, such as zstd,
+// can be registered by RegisterCompressor
+const CompressionGzip = "gzip"
+
+// DefaultCompressionThreshold is the min size in bytes of the json encoded result to be compressed
+const DefaultCompressionThreshold = 64 * 1024
+
+// MaxDecompressedSize is the max size in bytes of the decompressed result, Decode decompresses the output of the
+// channels, so the compression bomb is rejected instead of exhausting the memory
+const MaxDecompressedSize = 64 * 1024 * 1024
+
+// Compressor compresses and decompresses the response result, Decompress should fail if the decompressed data
+// exceeds MaxDecompressedSize
+type Compressor interface {
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+var (
+	compressors     = map[string]Compressor{CompressionGzip: gzipCompressor{}}
+	compressorsLock sync.RWMutex
+)
+
+// RegisterCompressor registers the compressor of the algorithm, for example zstd
+func RegisterCompressor(algorithm string, compressor Compressor) {
+	compressorsLock.Lock()
+	defer compressorsLock.Unlock()
+	compressors[algorithm] = compressor
+}
+
+func getCompressor(algorithm string) (Compressor, error) {
+	compressorsLock.RLock()
+	defer compressorsLock.RUnlock()
+	compressor, ok := compressors[algorithm]
+	if !ok {
+		return nil, fmt.Errorf("unsupported compression algorithm %s", algorithm)
+	}
+	return compressor, nil
+}
+
+// Compress compresses the result by the algorithm if its json encoding is not less than the threshold,
+// and records the algorithm in the Encoding. The compressed result is encoded in base64 by json.
+func (response *Response) Compress(algorithm string, threshold int) error {
+	if response.Result == nil || response.Encoding != "" {
+		return nil
+	}
+	data, err := json.Marshal(response.Result)
+	if err != nil {
+		return err
+	}
+	if len(data) < threshold {
+		return nil
+	}
+	compressor, err := getCompressor(algorithm)
+	if err != nil {
+		return err
+	}
+	compressed, err := compressor.Compress(data)
+	if err != nil {
+		return err
+	}
+	response.Result = compressed
+	response.Encoding = algorithm
+	return nil
+}
+
+// Decompress restores the result compressed by Compress, Decode invokes it automatically
+func (response *Response) Decompress() error {
+	if response.Encoding == "" {
+		return nil
+	}
+	var data []byte
+	switch result := response.Result.(type) {
+	case []byte:
+		data = result
+	case string:
+		decoded, err := base64.StdEncoding.DecodeString(result)
+		if err != nil {
+			return err
+		}
+		data = decoded
+	default:
+		return fmt.Errorf("illegal compressed result type %T", response.Result)
+	}
+	compressor, err := getCompressor(response.Encoding)
+	if err != nil {
+		return err
+	}
+	decompressed, err := compressor.Decompress(data)
+	if err != nil {
+		return err
+	}
+	if len(decompressed) > MaxDecompressedSize {
+		return errDecompressedTooLarge
+	}
+	var result interface{}
+	if err := json.Unmarshal(decompressed, &result); err != nil {
+		return err
+	}
+	response.Result = result
+	response.Encoding = ""
+	return nil
+}
+
+var errDecompressedTooLarge = fmt.Errorf("the decompressed result exceeds %d bytes", MaxDecompressedSize)
+
+type gzipCompressor struct{}
+
+func (gzipCompressor) Compress(data []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	writer := gzip.NewWriter(buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gzipCompressor) Decompress(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	data, err = ioutil.ReadAll(io.LimitReader(reader, MaxDecompressedSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxDecompressedSize {
+		return nil, errDecompressedTooLarge
+	}
+	return data, nil
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+)
+
+func TestResponse_Compress(t *testing.T) {
+	output := strings.Repeat("chaosblade ", 1024)
+	response := ReturnSuccess(output)
+	if err := response.Compress(CompressionGzip, 1024); err != nil {
+		t.Fatalf("Compress() error = %v", err)
+	}
+	if response.Encoding != CompressionGzip {
+		t.Fatalf("Encoding = %s, want gzip", response.Encoding)
+	}
+	content := response.Print()
+	if len(content) >= len(output) {
+		t.Errorf("compressed size %d, want less than %d", len(content), len(output))
+	}
+	decoded := Decode(content, nil)
+	if decoded.Encoding != "" || decoded.Result != output {
+		t.Errorf("Decode() result is not decompressed, encoding = %s", decoded.Encoding)
+	}
+
+	small := ReturnSuccess("ok")
+	if err := small.Compress(CompressionGzip, DefaultCompressionThreshold); err != nil || small.Encoding != "" {
+		t.Errorf("Compress() = %v, encoding = %s, want not compressed", err, small.Encoding)
+	}
+	if err := ReturnSuccess(output).Compress("zstd", 0); err == nil {
+		t.Errorf("Compress() expected error for unregistered zstd")
+	}
+}
+
+func TestDecompressLimit(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer := gzip.NewWriter(buf)
+	// the zeros are compressed to about 64KB
+	zeros := make([]byte, 1024*1024)
+	for written := 0; written <= MaxDecompressedSize; written += len(zeros) {
+		writer.Write(zeros)
+	}
+	writer.Close()
+	response := &Response{Code: 200, Success: true, Result: buf.Bytes(), Encoding: CompressionGzip}
+	if err := response.Decompress(); err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Errorf("Decompress() error = %v, want exceeding the max size", err)
+	}
+}
+
+func TestEncodeResponseCompression(t *testing.T) {
+	output := strings.Repeat("chaosblade ", 1024)
+	response := ReturnSuccess(output)
+	for _, accept := range []string{ContentTypeJSON, ContentTypeProtobuf} {
+		data, contentType, err := EncodeResponse(response, accept, EncodeCompression(CompressionGzip, 1024))
+		if err != nil {
+			t.Fatalf("EncodeResponse() error = %v", err)
+		}
+		if len(data) >= len(output) {
+			t.Errorf("EncodeResponse(%s) size %d, want compressed", accept, len(data))
+		}
+		decoded, err := DecodeResponse(data, contentType)
+		if err != nil {
+			t.Fatalf("DecodeResponse() error = %v", err)
+		}
+		if decoded.Result != output || decoded.Encoding != "" {
+			t.Errorf("DecodeResponse(%s) is not decompressed, encoding = %s", accept, decoded.Encoding)
+		}
+	}
+	if response.Encoding != "" || response.Result != output {
+		t.Errorf("EncodeResponse() modified the response")
+	}
+}
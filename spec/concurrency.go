@@ -0,0 +1,116 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+var (
+	// activeExperiments are the uids of the running experiments keyed by the target and the action
+	activeExperiments     = make(map[string]map[string]struct{})
+	activeExperimentsLock sync.Mutex
+)
+
+func concurrencyKey(target, action string) string {
+	return target + ActionPathSeparator + action
+}
+
+// ConcurrencyMiddleware returns the middleware which limits the running experiments of the action to its
+// MaxConcurrency. The uid is tracked from the create until the successful destroy, the create which exceeds
+// the limit is rejected by the ConcurrencyLimitExceeded response without executing.
+func ConcurrencyMiddleware(action ExpActionCommandSpec) ExecutorMiddleware {
+	return ExecutorMiddlewareFunc(func(next ExecFunc) ExecFunc {
+		return func(uid string, ctx context.Context, model *ExpModel) *Response {
+			limit := ActionMaxConcurrencyOf(action)
+			if limit <= 0 {
+				return next(uid, ctx, model)
+			}
+			key := concurrencyKey(model.Target, action.Name())
+			if _, isDestroy := IsDestroy(ctx); isDestroy {
+				response := next(uid, ctx, model)
+				if response != nil && response.Success {
+					releaseActiveExperiment(key, uid)
+				}
+				return response
+			}
+			activeExperimentsLock.Lock()
+			uids, ok := activeExperiments[key]
+			if !ok {
+				uids = make(map[string]struct{})
+				activeExperiments[key] = uids
+			}
+			_, running := uids[uid]
+			if !running && len(uids) >= limit {
+				running := sortedUids(uids)
+				activeExperimentsLock.Unlock()
+				return ResponseFailWithFlags(ConcurrencyLimitExceeded, key, limit, running)
+			}
+			uids[uid] = struct{}{}
+			activeExperimentsLock.Unlock()
+
+			response := next(uid, ctx, model)
+			// the retried create of the running uid must not release the running experiment
+			if !running && (response == nil || !response.Success) {
+				releaseActiveExperiment(key, uid)
+			}
+			return response
+		}
+	})
+}
+
+// ActiveExperiments returns the sorted uids of the running experiments of the target action
+// tracked by ConcurrencyMiddleware
+func ActiveExperiments(target, action string) []string {
+	activeExperimentsLock.Lock()
+	defer activeExperimentsLock.Unlock()
+	return sortedUids(activeExperiments[concurrencyKey(target, action)])
+}
+
+// ReleaseExperiment stops tracking the uid, for the experiment which is recovered without the destroy
+// execution, for example the process of the experiment exited
+func ReleaseExperiment(uid string) {
+	activeExperimentsLock.Lock()
+	defer activeExperimentsLock.Unlock()
+	for _, uids := range activeExperiments {
+		delete(uids, uid)
+	}
+}
+
+// ResetActiveExperiments stops tracking all running experiments
+func ResetActiveExperiments() {
+	activeExperimentsLock.Lock()
+	defer activeExperimentsLock.Unlock()
+	activeExperiments = make(map[string]map[string]struct{})
+}
+
+func releaseActiveExperiment(key, uid string) {
+	activeExperimentsLock.Lock()
+	defer activeExperimentsLock.Unlock()
+	delete(activeExperiments[key], uid)
+}
+
+func sortedUids(uids map[string]struct{}) []string {
+	result := make([]string, 0, len(uids))
+	for uid := range uids {
+		result = append(result, uid)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestConcurrencyMiddleware(t *testing.T) {
+	defer ResetActiveExperiments()
+	action := &ActionModel{ActionName: "fullload", ActionMaxConcurrency: 1}
+	failed := false
+	executor := WithMiddlewares(&testExecutor{exec: func(uid string, ctx context.Context, model *ExpModel) *Response {
+		if failed {
+			return ResponseFailWithFlags(OsCmdExecFailed, "burn", "failed")
+		}
+		return Success()
+	}}, ConcurrencyMiddleware(action))
+	model := &ExpModel{Target: "cpu", ActionName: "fullload"}
+	destroyCtx := func(uid string) context.Context {
+		return SetDestroyFlag(context.Background(), uid)
+	}
+
+	failed = true
+	if response := executor.Exec("a", context.Background(), model); response.Success {
+		t.Fatalf("Exec() = %s, want failed", response.Print())
+	}
+	if uids := ActiveExperiments("cpu", "fullload"); len(uids) != 0 {
+		t.Fatalf("ActiveExperiments() = %v, want empty after the failed create", uids)
+	}
+	failed = false
+	if response := executor.Exec("a", context.Background(), model); !response.Success {
+		t.Fatalf("Exec() = %s, want success", response.Print())
+	}
+	response := executor.Exec("b", context.Background(), model)
+	if response.Code != ConcurrencyLimitExceeded.Code {
+		t.Fatalf("Exec() = %v, want ConcurrencyLimitExceeded", response)
+	}
+	if response := executor.Exec("a", context.Background(), model); !response.Success {
+		t.Errorf("Exec() of the running uid = %s, want success", response.Print())
+	}
+	failed = true
+	if response := executor.Exec("a", context.Background(), model); response.Success {
+		t.Errorf("Exec() = %s, want failed", response.Print())
+	}
+	if uids := ActiveExperiments("cpu", "fullload"); !reflect.DeepEqual(uids, []string{"a"}) {
+		t.Errorf("ActiveExperiments() = %v, want the running uid kept after the failed retry", uids)
+	}
+	failed = false
+	if response := executor.Exec("c", context.Background(), &ExpModel{Target: "mem", ActionName: "fullload"}); !response.Success {
+		t.Errorf("Exec() of the other target = %s, want success", response.Print())
+	}
+	if uids := ActiveExperiments("cpu", "fullload"); !reflect.DeepEqual(uids, []string{"a"}) {
+		t.Errorf("ActiveExperiments() = %v, want [a]", uids)
+	}
+	if response := executor.Exec("a", destroyCtx("a"), model); !response.Success {
+		t.Fatalf("destroy Exec() = %s, want success", response.Print())
+	}
+	if response := executor.Exec("b", context.Background(), model); !response.Success {
+		t.Errorf("Exec() after destroyed = %s, want success", response.Print())
+	}
+	ReleaseExperiment("b")
+	if uids := ActiveExperiments("cpu", "fullload"); len(uids) != 0 {
+		t.Errorf("ActiveExperiments() = %v, want empty after released", uids)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ConditionDestroy is the condition variable which is true when destroying the experiment
+const ConditionDestroy = "destroy"
+
+// conditionTerm is one comparison of the condition expression
+type conditionTerm struct {
+	name     string
+	operator string
+	value    string
+}
+
+// The operators of the condition terms, the empty operator means the flag is specified
+const (
+	conditionEquals    = "="
+	conditionNotEquals = "!="
+	conditionAbsent    = "!"
+	conditionPresent   = ""
+)
+
+// parseCondition parses the expression to the terms joined by || of the terms joined by &&. The terms are
+// name=value, name!=value, name for the specified flag and !name for the absent flag, for example
+// "protocol=tcp && local-port" or "destroy=true".
+func parseCondition(expr string) ([][]conditionTerm, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, fmt.Errorf("the condition is empty")
+	}
+	condition := make([][]conditionTerm, 0)
+	for _, or := range strings.Split(expr, "||") {
+		terms := make([]conditionTerm, 0)
+		for _, and := range strings.Split(or, "&&") {
+			term, err := parseConditionTerm(strings.TrimSpace(and))
+			if err != nil {
+				return nil, fmt.Errorf("illegal condition `%s`, %v", expr, err)
+			}
+			terms = append(terms, term)
+		}
+		condition = append(condition, terms)
+	}
+	return condition, nil
+}
+
+func parseConditionTerm(term string) (conditionTerm, error) {
+	var result conditionTerm
+	switch {
+	case strings.Contains(term, conditionNotEquals):
+		parts := strings.SplitN(term, conditionNotEquals, 2)
+		result = conditionTerm{name: parts[0], operator: conditionNotEquals, value: parts[1]}
+	case strings.Contains(term, conditionEquals):
+		parts := strings.SplitN(term, conditionEquals, 2)
+		result = conditionTerm{name: parts[0], operator: conditionEquals, value: parts[1]}
+	case strings.HasPrefix(term, conditionAbsent):
+		result = conditionTerm{name: term[1:], operator: conditionAbsent}
+	default:
+		result = conditionTerm{name: term, operator: conditionPresent}
+	}
+	result.name = strings.TrimSpace(result.name)
+	result.value = strings.TrimSpace(result.value)
+	if result.name == "" || strings.ContainsAny(result.name, " =!") {
+		return result, fmt.Errorf("illegal term `%s`", term)
+	}
+	return result, nil
+}
+
+// ValidateCondition checks the syntax of the condition expression
+func ValidateCondition(expr string) error {
+	_, err := parseCondition(expr)
+	return err
+}
+
+// EvaluateCondition returns true if the model flags satisfy the condition expression,
+// the destroy variable is true if isDestroy
+func EvaluateCondition(expr string, model *ExpModel, isDestroy bool) (bool, error) {
+	condition, err := parseCondition(expr)
+	if err != nil {
+		return false, err
+	}
+	for _, terms := range condition {
+		satisfied := true
+		for _, term := range terms {
+			if !term.evaluate(model, isDestroy) {
+				satisfied = false
+				break
+			}
+		}
+		if satisfied {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (t conditionTerm) evaluate(model *ExpModel, isDestroy bool) bool {
+	value, ok := model.flagValue(t.name)
+	if t.name == ConditionDestroy && !ok {
+		value, ok = strconv.FormatBool(isDestroy), true
+	}
+	switch t.operator {
+	case conditionEquals:
+		return ok && value == t.value
+	case conditionNotEquals:
+		return !ok || value != t.value
+	case conditionAbsent:
+		return !ok
+	}
+	return ok
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEvaluateCondition(t *testing.T) {
+	tests := []struct {
+		name      string
+		expr      string
+		flags     map[string]string
+		isDestroy bool
+		want      bool
+		wantErr   bool
+	}{
+		{name: "equals", expr: "protocol=tcp", flags: map[string]string{"protocol": "tcp"}, want: true},
+		{name: "not equals", expr: "protocol!=tcp", flags: map[string]string{"protocol": "udp"}, want: true},
+		{name: "not equals absent", expr: "protocol!=tcp", flags: map[string]string{}, want: true},
+		{name: "present", expr: "local-port", flags: map[string]string{"local-port": "80"}, want: true},
+		{name: "absent", expr: "!local-port", flags: map[string]string{"local-port": " "}, want: true},
+		{name: "and", expr: "protocol=tcp && local-port", flags: map[string]string{"protocol": "tcp"}, want: false},
+		{name: "or", expr: "protocol=udp || local-port && protocol=tcp",
+			flags: map[string]string{"protocol": "tcp", "local-port": "80"}, want: true},
+		{name: "destroy", expr: "destroy=true", flags: map[string]string{}, isDestroy: true, want: true},
+		{name: "not destroy", expr: "destroy=true", flags: map[string]string{}, want: false},
+		{name: "empty", expr: " ", wantErr: true},
+		{name: "illegal term", expr: "protocol=tcp && ", wantErr: true},
+		{name: "illegal name", expr: "=tcp", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EvaluateCondition(tt.expr, &ExpModel{ActionFlags: tt.flags}, tt.isDestroy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EvaluateCondition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("EvaluateCondition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateExpModelCondition(t *testing.T) {
+	action := &ActionModel{
+		ActionFlags: []ExpFlag{
+			{Name: "protocol"},
+			{Name: "local-port", RequiredIf: "protocol=tcp"},
+			{Name: "force", ForbiddenIf: "destroy=true"},
+		},
+	}
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		flags          map[string]string
+		wantCode       int32
+		wantConstraint string
+	}{
+		{name: "passed", ctx: context.Background(), flags: map[string]string{"protocol": "tcp", "local-port": "80"}},
+		{name: "not required", ctx: context.Background(), flags: map[string]string{"protocol": "udp"}},
+		{name: "required if", ctx: context.Background(), flags: map[string]string{"protocol": "tcp"},
+			wantCode: ParameterLessWith.Code, wantConstraint: ConstraintRequiredIf},
+		{name: "allowed", ctx: context.Background(), flags: map[string]string{"force": "true"}},
+		{name: "forbidden if", ctx: SetDestroyFlag(context.Background(), "uid"), flags: map[string]string{"force": "true"},
+			wantCode: ParameterForbidden.Code, wantConstraint: ConstraintForbiddenIf},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := ValidateExpModel(tt.ctx, action, &ExpModel{ActionFlags: tt.flags})
+			if tt.wantCode == 0 {
+				if response != nil {
+					t.Errorf("ValidateExpModel() = %s, want nil", response.Print())
+				}
+				return
+			}
+			if response == nil || response.Code != tt.wantCode {
+				t.Fatalf("ValidateExpModel() = %v, want code %d", response, tt.wantCode)
+			}
+			if len(response.FieldErrors) != 1 || response.FieldErrors[0].Constraint != tt.wantConstraint {
+				t.Errorf("FieldErrors = %v, want constraint %s", response.FieldErrors, tt.wantConstraint)
+			}
+		})
+	}
+}
@@ -15,4 +15,8 @@ const (
 	DefaultCGroupPath  = "/sys/fs/cgroup/"
 	Uid                = "uid"
 	YamlPathEnv        = "YAML_PATH"
+	LocaleEnv          = "CHAOSBLADE_LOCALE"
+	HostnameEnv        = "CHAOSBLADE_HOSTNAME"
+	HostIPsEnv         = "CHAOSBLADE_HOST_IPS"
+	HostInterfaceEnv   = "CHAOSBLADE_HOST_INTERFACE"
 )
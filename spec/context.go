@@ -0,0 +1,62 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import "context"
+
+// ContextKey is the type of the context keys defined by chaosblade. Using a dedicated type instead of
+// plain strings prevents collisions with the values set by other packages.
+type ContextKey string
+
+// String returns the name of the key
+func (k ContextKey) String() string {
+	return string(k)
+}
+
+// UidContextKey is the context key of the experiment uid
+const UidContextKey ContextKey = Uid
+
+// DestroyContextKey is the context key of the uid of the experiment to destroy
+const DestroyContextKey ContextKey = DestroyKey
+
+// ContextValue returns the value of the typed key in ctx. The values set with the plain string key by
+// earlier versions are still returned if the typed key is absent.
+func ContextValue(ctx context.Context, key ContextKey) interface{} {
+	if ctx == nil {
+		return nil
+	}
+	if value := ctx.Value(key); value != nil {
+		return value
+	}
+	return ctx.Value(string(key))
+}
+
+// ContextString returns the string value of the key in ctx, ok is false if the value is absent or not a string
+func ContextString(ctx context.Context, key ContextKey) (value string, ok bool) {
+	value, ok = ContextValue(ctx, key).(string)
+	return
+}
+
+// WithUID returns a copy of ctx carrying the experiment uid
+func WithUID(ctx context.Context, uid string) context.Context {
+	return context.WithValue(ctx, UidContextKey, uid)
+}
+
+// UIDFromContext returns the experiment uid in ctx
+func UIDFromContext(ctx context.Context) (string, bool) {
+	return ContextString(ctx, UidContextKey)
+}
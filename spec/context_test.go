@@ -0,0 +1,57 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"testing"
+)
+
+func TestUIDFromContext(t *testing.T) {
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		uid    string
+		wantOk bool
+	}{
+		{"typed key", WithUID(context.Background(), "abc"), "abc", true},
+		{"legacy string key", context.WithValue(context.Background(), "uid", "def"), "def", true},
+		{"absent", context.Background(), "", false},
+		{"not a string", context.WithValue(context.Background(), UidContextKey, 1), "", false},
+		{"typed key wins", WithUID(context.WithValue(context.Background(), "uid", "old"), "new"), "new", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uid, ok := UIDFromContext(tt.ctx)
+			if uid != tt.uid || ok != tt.wantOk {
+				t.Errorf("UIDFromContext() = %q, %v, want %q, %v", uid, ok, tt.uid, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestIsDestroy(t *testing.T) {
+	if _, ok := IsDestroy(context.Background()); ok {
+		t.Errorf("IsDestroy() = true for empty context")
+	}
+	if suid, ok := IsDestroy(SetDestroyFlag(context.Background(), "abc")); !ok || suid != "abc" {
+		t.Errorf("IsDestroy() = %q, %v, want abc, true", suid, ok)
+	}
+	if _, ok := IsDestroy(context.WithValue(context.Background(), DestroyKey, 1)); ok {
+		t.Errorf("IsDestroy() = true for non string value")
+	}
+}
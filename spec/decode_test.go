@@ -0,0 +1,108 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExtractJSON(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		want          string
+		wantRemainder string
+		wantOk        bool
+	}{
+		{name: "json only", content: `{"code":200}`, want: `{"code":200}`, wantOk: true},
+		{name: "log lines before", content: "level=info msg={start\n{\"code\":200,\"result\":{\"a\":1}}",
+			want: `{"code":200,"result":{"a":1}}`, wantRemainder: "level=info msg={start", wantOk: true},
+		{name: "last object", content: "{\"step\":1}\n{\"code\":200}\ndone",
+			want: `{"code":200}`, wantRemainder: "{\"step\":1}\ndone", wantOk: true},
+		{name: "braces in string", content: `{"code":200,"error":"}{"}`, want: `{"code":200,"error":"}{"}`, wantOk: true},
+		{name: "escaped quote in string", content: `{"code":200,"error":"\\\"}"}`, want: `{"code":200,"error":"\\\"}"}`, wantOk: true},
+		{name: "stray brace after", content: "{\"code\":200}\ndone}", want: `{"code":200}`, wantRemainder: "done}", wantOk: true},
+		{name: "no json", content: "exit status 1", wantRemainder: "exit status 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, remainder, ok := ExtractJSON(tt.content)
+			if got != tt.want || remainder != tt.wantRemainder || ok != tt.wantOk {
+				t.Errorf("ExtractJSON() = %q, %q, %v, want %q, %q, %v", got, remainder, ok, tt.want, tt.wantRemainder, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestDecodeWithOptions(t *testing.T) {
+	content := "time=\"2021\" level=warning msg=\"cgroup not found\"\n{\"code\":200,\"success\":true,\"result\":\"ok\"}\n"
+	tests := []struct {
+		name       string
+		content    string
+		options    DecodeOptions
+		wantCode   int32
+		wantOutput interface{}
+	}{
+		{name: "tolerant", content: content, wantCode: 200, wantOutput: `time="2021" level=warning msg="cgroup not found"`},
+		{name: "strict", content: content, options: DecodeOptions{Strict: true}, wantCode: ResultUnmarshalFailed.Code},
+		{name: "json without code", content: "log\n{\"a\":1}", wantCode: ResultUnmarshalFailed.Code},
+		{name: "json only", content: `{"code":200,"success":true}`, wantCode: 200},
+		{name: "json log after", content: "{\"code\":200,\"success\":true}\n{\"level\":\"info\"}",
+			wantCode: 200, wantOutput: `{"level":"info"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := DecodeWithOptions(tt.content, nil, tt.options)
+			if response.Code != tt.wantCode || response.Metadata[MetadataOutput] != tt.wantOutput {
+				t.Errorf("DecodeWithOptions() = %s, want code %d and output %v", response.Print(), tt.wantCode, tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestEndsWithJSON(t *testing.T) {
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{content: "log\n{\"code\":200}\n", want: true},
+		{content: "{\"code\":200}\n{\"level\":\"info\"}", want: true},
+		{content: "payload {\"code\":1} received", want: false},
+		{content: "{\"code\":200}\ndone", want: false},
+		{content: "exit status 1", want: false},
+	}
+	for _, tt := range tests {
+		if got := EndsWithJSON(tt.content); got != tt.want {
+			t.Errorf("EndsWithJSON(%q) = %t, want %t", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestExtractJSONLongOutput(t *testing.T) {
+	// decoding from every '{' of the log lines is quadratic for the long outputs
+	content := strings.Repeat("level=info msg={\"step\": {", 200000) + "\n{\"code\":200}"
+	start := time.Now()
+	got, _, ok := ExtractJSON(content)
+	if !ok || got != `{"code":200}` {
+		t.Errorf("ExtractJSON() = %q, %v, want the trailing object", got, ok)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("ExtractJSON() took %s", elapsed)
+	}
+}
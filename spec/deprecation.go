@@ -0,0 +1,69 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+// The warning types
+const (
+	WarningDeprecatedAction = "DeprecatedAction"
+	WarningDeprecatedFlag   = "DeprecatedFlag"
+)
+
+// Warning is the structured warning in the response, the experiment is executed anyway
+type Warning struct {
+	Type       string `json:"type"`
+	Name       string `json:"name"`
+	Message    string `json:"message,omitempty"`
+	ReplacedBy string `json:"replacedBy,omitempty"`
+}
+
+// AddWarnings appends the warnings to the response
+func (response *Response) AddWarnings(warnings ...Warning) *Response {
+	response.Warnings = append(response.Warnings, warnings...)
+	return response
+}
+
+// DeprecationWarnings returns the warnings if the action or the flags specified in the model are deprecated.
+// The framework appends them to the response of the experiment by Response.AddWarnings.
+func DeprecationWarnings(action ExpActionCommandSpec, model *ExpModel) []Warning {
+	warnings := make([]Warning, 0)
+	if ActionDeprecatedOf(action) != "" {
+		warnings = append(warnings, Warning{
+			Type:       WarningDeprecatedAction,
+			Name:       action.Name(),
+			Message:    ActionDeprecatedOf(action),
+			ReplacedBy: ActionReplacedByOf(action),
+		})
+	}
+	flags := make([]ExpFlagSpec, 0)
+	flags = append(flags, action.Matchers()...)
+	flags = append(flags, action.Flags()...)
+	for _, flag := range flags {
+		if FlagDeprecatedOf(flag) == "" {
+			continue
+		}
+		if _, ok := model.flagValue(flag.FlagName()); !ok {
+			continue
+		}
+		warnings = append(warnings, Warning{
+			Type:       WarningDeprecatedFlag,
+			Name:       flag.FlagName(),
+			Message:    FlagDeprecatedOf(flag),
+			ReplacedBy: FlagReplacedByOf(flag),
+		})
+	}
+	return warnings
+}
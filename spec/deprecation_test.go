@@ -0,0 +1,67 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestDeprecationWarnings(t *testing.T) {
+	action := &ActionModel{
+		ActionName:       "delay",
+		ActionDeprecated: "use latency instead",
+		ActionReplacedBy: "latency",
+		ActionFlags: []ExpFlag{
+			{Name: "time"},
+			{Name: "offset", Deprecated: "offset is ignored", ReplacedBy: "jitter"},
+			{Name: "percent", Deprecated: "percent is ignored"},
+		},
+	}
+	model := &ExpModel{ActionFlags: map[string]string{"time": "10", "offset": "5"}}
+	want := []Warning{
+		{Type: WarningDeprecatedAction, Name: "delay", Message: "use latency instead", ReplacedBy: "latency"},
+		{Type: WarningDeprecatedFlag, Name: "offset", Message: "offset is ignored", ReplacedBy: "jitter"},
+	}
+	warnings := DeprecationWarnings(action, model)
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("DeprecationWarnings() = %v, want %v", warnings, want)
+	}
+	response := ReturnSuccess(nil).AddWarnings(warnings...)
+	if len(response.Warnings) != 2 {
+		t.Errorf("AddWarnings() got %d warnings, want 2", len(response.Warnings))
+	}
+}
+
+func TestExecExperimentDeprecationWarnings(t *testing.T) {
+	defer ResetModelSpecs()
+	network := &ExpCommandModel{ExpName: "network", ExpActions: []ActionModel{{ActionName: "delay",
+		ActionFlags: []ExpFlag{{Name: "device", Deprecated: "use interface"}, {Name: "interface"}}}}}
+	if err := RegisterModelSpec(network); err != nil {
+		t.Fatalf("RegisterModelSpec() error = %v", err)
+	}
+	executor := &testExecutor{exec: func(uid string, ctx context.Context, model *ExpModel) *Response {
+		return ReturnSuccess(nil)
+	}}
+	model := &ExpModel{Target: "network", ActionName: "delay", ActionFlags: map[string]string{"device": "eth0"}}
+	response := ExecExperiment(executor, "uid", context.Background(), model)
+	want := []Warning{{Type: WarningDeprecatedFlag, Name: "device", Message: "use interface"}}
+	if !reflect.DeepEqual(response.Warnings, want) {
+		t.Errorf("ExecExperiment() warnings = %v, want %v", response.Warnings, want)
+	}
+}
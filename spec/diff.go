@@ -0,0 +1,235 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// The kinds of the spec difference
+const (
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffChanged = "changed"
+)
+
+// DiffEntry is one difference between two spec versions
+type DiffEntry struct {
+	Kind string `json:"kind"`
+	// Path is the target path, action and flag, for example k8s pod delete --names
+	Path   string `json:"path"`
+	Detail string `json:"detail,omitempty"`
+	// Breaking is true if the experiments created by the old spec may fail with the new spec
+	Breaking bool `json:"breaking"`
+}
+
+// ModelsDiff is the result of Diff
+type ModelsDiff struct {
+	Entries []DiffEntry `json:"entries"`
+}
+
+// HasBreakingChanges returns true if any difference is breaking
+func (d *ModelsDiff) HasBreakingChanges() bool {
+	for _, entry := range d.Entries {
+		if entry.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *ModelsDiff) add(kind, path, detail string, breaking bool) {
+	d.Entries = append(d.Entries, DiffEntry{Kind: kind, Path: path, Detail: detail, Breaking: breaking})
+}
+
+// Diff reports the added, removed and changed targets, actions and flags from the old models to the new models.
+// Removing targets, actions or flags, adding required flags and narrowing flag values are breaking changes.
+func Diff(oldModels, newModels *Models) *ModelsDiff {
+	diff := &ModelsDiff{Entries: make([]DiffEntry, 0)}
+	oldTargets, oldNames := indexTargets(oldModels)
+	newTargets, newNames := indexTargets(newModels)
+	for _, name := range oldNames {
+		newTarget, ok := newTargets[name]
+		if !ok {
+			diff.add(DiffRemoved, name, "", true)
+			continue
+		}
+		diffActions(diff, name, oldTargets[name], newTarget)
+	}
+	for _, name := range newNames {
+		if _, ok := oldTargets[name]; !ok {
+			diff.add(DiffAdded, name, "", false)
+		}
+	}
+	return diff
+}
+
+// indexTargets returns the targets including the nested sub targets by the scope and the path
+func indexTargets(models *Models) (map[string]*ExpCommandModel, []string) {
+	targets := make(map[string]*ExpCommandModel)
+	names := make([]string, 0)
+	var index func(prefix string, model *ExpCommandModel)
+	index = func(prefix string, model *ExpCommandModel) {
+		name := strings.TrimSpace(prefix + " " + model.ExpName)
+		targets[name] = model
+		names = append(names, name)
+		for idx := range model.ExpSubModels {
+			index(name, &model.ExpSubModels[idx])
+		}
+	}
+	if models != nil {
+		for idx := range models.Models {
+			model := &models.Models[idx]
+			index(model.ExpScope, model)
+		}
+	}
+	return targets, names
+}
+
+func diffActions(diff *ModelsDiff, target string, oldTarget, newTarget *ExpCommandModel) {
+	newActions := make(map[string]*ActionModel)
+	for idx := range newTarget.ExpActions {
+		newActions[newTarget.ExpActions[idx].ActionName] = &newTarget.ExpActions[idx]
+	}
+	oldActions := make(map[string]bool)
+	for idx := range oldTarget.ExpActions {
+		oldAction := &oldTarget.ExpActions[idx]
+		oldActions[oldAction.ActionName] = true
+		path := target + " " + oldAction.ActionName
+		newAction, ok := newActions[oldAction.ActionName]
+		if !ok {
+			diff.add(DiffRemoved, path, "", true)
+			continue
+		}
+		diffFlags(diff, path, concatFlags(oldTarget.ExpFlags, oldAction.ActionMatchers, oldAction.ActionFlags),
+			concatFlags(newTarget.ExpFlags, newAction.ActionMatchers, newAction.ActionFlags))
+	}
+	for _, action := range newTarget.ExpActions {
+		if !oldActions[action.ActionName] {
+			diff.add(DiffAdded, target+" "+action.ActionName, "", false)
+		}
+	}
+}
+
+func concatFlags(groups ...[]ExpFlag) []ExpFlag {
+	flags := make([]ExpFlag, 0)
+	for _, group := range groups {
+		flags = append(flags, group...)
+	}
+	return flags
+}
+
+func diffFlags(diff *ModelsDiff, action string, oldFlags, newFlags []ExpFlag) {
+	newIndex := make(map[string]*ExpFlag)
+	for idx := range newFlags {
+		if _, ok := newIndex[newFlags[idx].Name]; !ok {
+			newIndex[newFlags[idx].Name] = &newFlags[idx]
+		}
+	}
+	oldIndex := make(map[string]bool)
+	for idx := range oldFlags {
+		oldFlag := &oldFlags[idx]
+		if oldIndex[oldFlag.Name] {
+			continue
+		}
+		oldIndex[oldFlag.Name] = true
+		path := action + " --" + oldFlag.Name
+		newFlag, ok := newIndex[oldFlag.Name]
+		if !ok {
+			diff.add(DiffRemoved, path, "", true)
+			continue
+		}
+		diffFlag(diff, path, oldFlag, newFlag)
+	}
+	for idx := range newFlags {
+		flag := &newFlags[idx]
+		if oldIndex[flag.Name] {
+			continue
+		}
+		oldIndex[flag.Name] = true
+		detail := ""
+		if flag.Required {
+			detail = "required"
+		}
+		diff.add(DiffAdded, action+" --"+flag.Name, detail, flag.Required)
+	}
+}
+
+func diffFlag(diff *ModelsDiff, path string, oldFlag, newFlag *ExpFlag) {
+	if oldFlag.Type != newFlag.Type {
+		diff.add(DiffChanged, path, fmt.Sprintf("type `%s` -> `%s`", oldFlag.Type, newFlag.Type), true)
+	}
+	if oldFlag.NoArgs != newFlag.NoArgs {
+		diff.add(DiffChanged, path, fmt.Sprintf("noArgs %t -> %t", oldFlag.NoArgs, newFlag.NoArgs), true)
+	}
+	if oldFlag.Required != newFlag.Required {
+		diff.add(DiffChanged, path, fmt.Sprintf("required %t -> %t", oldFlag.Required, newFlag.Required), newFlag.Required)
+	}
+	if oldFlag.Default != newFlag.Default {
+		diff.add(DiffChanged, path, fmt.Sprintf("default `%s` -> `%s`", oldFlag.Default, newFlag.Default), false)
+	}
+	if !reflect.DeepEqual(oldFlag.EnumValues, newFlag.EnumValues) {
+		removed := len(oldFlag.EnumValues) == 0 && len(newFlag.EnumValues) > 0
+		for _, value := range oldFlag.EnumValues {
+			if checkEnumValue(newFlag.EnumValues, value) != nil {
+				removed = true
+			}
+		}
+		diff.add(DiffChanged, path, fmt.Sprintf("enumValues %v -> %v", oldFlag.EnumValues, newFlag.EnumValues), removed)
+	}
+	if !reflect.DeepEqual(comparableValidation(oldFlag.Validation), comparableValidation(newFlag.Validation)) {
+		// the old rules can not be applied onto the new ones if the new range is narrower
+		_, err := narrowValidation(newFlag.Validation, oldFlag.Validation)
+		diff.add(DiffChanged, path, "validation changed", err != nil || isNarrowed(oldFlag.Validation, newFlag.Validation))
+	}
+}
+
+// isNarrowed returns true if the new validation adds the rules which are not in the old one
+func isNarrowed(oldValidation, newValidation *FlagValidation) bool {
+	if newValidation == nil {
+		return false
+	}
+	if oldValidation == nil {
+		oldValidation = &FlagValidation{}
+	}
+	return (newValidation.Min != nil && oldValidation.Min == nil) ||
+		(newValidation.Max != nil && oldValidation.Max == nil) ||
+		(newValidation.Pattern != "" && oldValidation.Pattern == "") ||
+		(newValidation.NonEmpty && !oldValidation.NonEmpty) ||
+		hasNewValidator(oldValidation.Validators, newValidation.Validators)
+}
+
+func hasNewValidator(oldValidators, newValidators []string) bool {
+	for _, name := range newValidators {
+		if !containsString(oldValidators, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// comparableValidation returns the validation without the Validate callback, the funcs are never deep equal
+func comparableValidation(validation *FlagValidation) *FlagValidation {
+	if validation == nil || validation.Validate == nil {
+		return validation
+	}
+	copied := *validation
+	copied.Validate = nil
+	return &copied
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	max, newMax := 100.0, 80.0
+	oldModels := &Models{Models: []ExpCommandModel{
+		{ExpName: "cpu", ExpActions: []ActionModel{
+			{ActionName: "fullload", ActionFlags: []ExpFlag{
+				{Name: "cpu-percent", Default: "100", Validation: &FlagValidation{Max: &max}},
+				{Name: "cpu-count"},
+				{Name: "mode", Type: FlagTypeEnum, EnumValues: []string{"user", "sys"}},
+			}},
+			{ActionName: "burn"},
+		}},
+		{ExpName: "mem"},
+	}}
+	newModels := &Models{Models: []ExpCommandModel{
+		{ExpName: "cpu", ExpActions: []ActionModel{
+			{ActionName: "fullload", ActionFlags: []ExpFlag{
+				{Name: "cpu-percent", Default: "50", Validation: &FlagValidation{Max: &newMax}},
+				{Name: "mode", Type: FlagTypeEnum, EnumValues: []string{"user", "sys", "iowait"}},
+				{Name: "cpu-list", Required: true},
+			}},
+			{ActionName: "load"},
+		}},
+		{ExpName: "disk"},
+	}}
+	diff := Diff(oldModels, newModels)
+	want := []DiffEntry{
+		{Kind: DiffChanged, Path: "cpu fullload --cpu-percent", Detail: "default `100` -> `50`"},
+		{Kind: DiffChanged, Path: "cpu fullload --cpu-percent", Detail: "validation changed", Breaking: true},
+		{Kind: DiffRemoved, Path: "cpu fullload --cpu-count", Breaking: true},
+		{Kind: DiffChanged, Path: "cpu fullload --mode", Detail: "enumValues [user sys] -> [user sys iowait]"},
+		{Kind: DiffAdded, Path: "cpu fullload --cpu-list", Detail: "required", Breaking: true},
+		{Kind: DiffRemoved, Path: "cpu burn", Breaking: true},
+		{Kind: DiffAdded, Path: "cpu load"},
+		{Kind: DiffRemoved, Path: "mem", Breaking: true},
+		{Kind: DiffAdded, Path: "disk"},
+	}
+	if !reflect.DeepEqual(diff.Entries, want) {
+		t.Errorf("Diff() = %+v\nwant %+v", diff.Entries, want)
+	}
+	if !diff.HasBreakingChanges() {
+		t.Errorf("HasBreakingChanges() = false, want true")
+	}
+	if Diff(oldModels, oldModels).HasBreakingChanges() {
+		t.Errorf("HasBreakingChanges() = true for the same models")
+	}
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TimeoutFlag is the flag of the experiment duration, the experiment is destroyed automatically after it.
+// It's bounded by ExpActionCommandSpec.DefaultDuration and MaxDuration, while DefaultTimeout and MaxTimeout
+// are the deadline of one execution, see GetExecTimeout.
+const TimeoutFlag = "timeout"
+
+var (
+	// autoDestroys are the timers destroying the experiments keyed by the uid
+	autoDestroys     = make(map[string]*time.Timer)
+	autoDestroysLock sync.Mutex
+)
+
+// GetExperimentDuration returns the duration of the experiment, 0 means running until destroyed. The timeout flag
+// is used first, then the default duration of the action. The duration which exceeds the max duration of the action
+// is illegal, and the max duration is used if no duration is specified.
+func GetExperimentDuration(action ExpActionCommandSpec, model *ExpModel) (time.Duration, *Response) {
+	maxDuration := parseTimeout(action.Name(), ActionMaxDurationOf(action))
+	value, ok := model.flagValue(TimeoutFlag)
+	if !ok {
+		duration := parseTimeout(action.Name(), ActionDefaultDurationOf(action))
+		if maxDuration > 0 && (duration <= 0 || duration > maxDuration) {
+			duration = maxDuration
+		}
+		return duration, nil
+	}
+	duration, err := ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, ResponseFailWithFlags(ParameterIllegal, TimeoutFlag, value, fmt.Sprintf("illegal duration `%s`", value)).
+			AddFieldErrors(FieldError{Field: TimeoutFlag, Constraint: ConstraintType, Value: value})
+	}
+	if maxDuration > 0 && (duration == 0 || duration > maxDuration) {
+		return 0, ResponseFailWithFlags(ParameterIllegal, TimeoutFlag, value,
+			fmt.Sprintf("the duration must not exceed %v", maxDuration)).
+			AddFieldErrors(FieldError{Field: TimeoutFlag, Constraint: ConstraintMax, Value: value})
+	}
+	return duration, nil
+}
+
+// DurationMiddleware returns the middleware which destroys the created experiment after GetExperimentDuration,
+// so the executor does not need to wire the timer itself. The timer is stopped if the experiment is destroyed before.
+func DurationMiddleware(action ExpActionCommandSpec) ExecutorMiddleware {
+	return ExecutorMiddlewareFunc(func(next ExecFunc) ExecFunc {
+		return func(uid string, ctx context.Context, model *ExpModel) *Response {
+			if _, isDestroy := IsDestroy(ctx); isDestroy {
+				StopAutoDestroy(uid)
+				return next(uid, ctx, model)
+			}
+			duration, response := GetExperimentDuration(action, model)
+			if response != nil {
+				return response
+			}
+			response = next(uid, ctx, model)
+			if duration <= 0 || response == nil || !response.Success {
+				return response
+			}
+			destroyCtx := SetDestroyFlag(detachedContext{ctx}, uid)
+			autoDestroysLock.Lock()
+			defer autoDestroysLock.Unlock()
+			if timer, ok := autoDestroys[uid]; ok {
+				timer.Stop()
+			}
+			var timer *time.Timer
+			timer = time.AfterFunc(duration, func() {
+				autoDestroysLock.Lock()
+				if autoDestroys[uid] != timer {
+					autoDestroysLock.Unlock()
+					return
+				}
+				delete(autoDestroys, uid)
+				autoDestroysLock.Unlock()
+				if response := next(uid, destroyCtx, model); response != nil && !response.Success {
+					logrus.Warnf("destroy the %s experiment after %v failed, %s", uid, duration, response.Err)
+				}
+			})
+			autoDestroys[uid] = timer
+			return response
+		}
+	})
+}
+
+// StopAutoDestroy stops the timer destroying the experiment of the uid, it returns false if not found
+func StopAutoDestroy(uid string) bool {
+	autoDestroysLock.Lock()
+	defer autoDestroysLock.Unlock()
+	timer, ok := autoDestroys[uid]
+	if !ok {
+		return false
+	}
+	timer.Stop()
+	delete(autoDestroys, uid)
+	return true
+}
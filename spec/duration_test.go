@@ -0,0 +1,89 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGetExperimentDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		action   *ActionModel
+		flags    map[string]string
+		want     time.Duration
+		wantCode int32
+	}{
+		{name: "no duration", action: &ActionModel{}, flags: map[string]string{}},
+		{name: "timeout flag", action: &ActionModel{ActionDefaultDuration: "1m"}, flags: map[string]string{"timeout": "30"}, want: 30 * time.Second},
+		{name: "default", action: &ActionModel{ActionDefaultDuration: "1m"}, flags: map[string]string{}, want: time.Minute},
+		{name: "max as default", action: &ActionModel{ActionMaxDuration: "1h"}, flags: map[string]string{}, want: time.Hour},
+		{name: "default over max", action: &ActionModel{ActionDefaultDuration: "2h", ActionMaxDuration: "1h"}, flags: map[string]string{}, want: time.Hour},
+		{name: "exceed max", action: &ActionModel{ActionMaxDuration: "1h"}, flags: map[string]string{"timeout": "2h"}, wantCode: ParameterIllegal.Code},
+		{name: "illegal", action: &ActionModel{}, flags: map[string]string{"timeout": "forever"}, wantCode: ParameterIllegal.Code},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, response := GetExperimentDuration(tt.action, &ExpModel{ActionFlags: tt.flags})
+			if tt.wantCode != 0 {
+				if response == nil || response.Code != tt.wantCode {
+					t.Errorf("GetExperimentDuration() = %v, want code %d", response, tt.wantCode)
+				}
+				return
+			}
+			if response != nil || got != tt.want {
+				t.Errorf("GetExperimentDuration() = %v, %v, want %v", got, response, tt.want)
+			}
+		})
+	}
+}
+
+func TestDurationMiddleware(t *testing.T) {
+	destroyed := make(chan string, 2)
+	executor := WithMiddlewares(&testExecutor{exec: func(uid string, ctx context.Context, model *ExpModel) *Response {
+		if _, ok := IsDestroy(ctx); ok {
+			destroyed <- uid
+		}
+		return Success()
+	}}, DurationMiddleware(&ActionModel{ActionName: "delay", ActionMaxDuration: "1h"}))
+
+	if response := executor.Exec("a", context.Background(), &ExpModel{ActionFlags: map[string]string{"timeout": "10ms"}}); !response.Success {
+		t.Fatalf("Exec() = %s, want success", response.Print())
+	}
+	select {
+	case uid := <-destroyed:
+		if uid != "a" {
+			t.Errorf("destroyed %s, want a", uid)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("the experiment is not destroyed after the duration")
+	}
+	if StopAutoDestroy("a") {
+		t.Errorf("StopAutoDestroy() = true after destroyed automatically")
+	}
+
+	if response := executor.Exec("b", context.Background(), &ExpModel{ActionFlags: map[string]string{}}); !response.Success {
+		t.Fatalf("Exec() = %s, want success", response.Print())
+	}
+	executor.Exec("b", SetDestroyFlag(context.Background(), "b"), &ExpModel{})
+	<-destroyed
+	if StopAutoDestroy("b") {
+		t.Errorf("StopAutoDestroy() = true after destroyed by the user")
+	}
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"errors"
+)
+
+// Error makes the CodeType as the target of errors.Is, for example errors.Is(err, spec.ParameterLess)
+func (c CodeType) Error() string {
+	return c.Msg
+}
+
+// Is reports whether the response matches the target by code, the target can be CodeType or *Response
+func (response *Response) Is(target error) bool {
+	switch t := target.(type) {
+	case CodeType:
+		return response.Code == t.Code
+	case *CodeType:
+		return t != nil && response.Code == t.Code
+	case *Response:
+		return t != nil && response.Code == t.Code
+	}
+	return false
+}
+
+// AsResponse finds the first *Response in the err chain
+func AsResponse(err error) (*Response, bool) {
+	var response *Response
+	if errors.As(err, &response) && response != nil {
+		return response, true
+	}
+	return nil, false
+}
+
+// ResponseFromError returns the *Response in the err chain, the ParameterIllegal response of the
+// *FlagValueError in the chain, or the failed response of the code type with the err as the only flag.
+// It returns nil if err is nil.
+func ResponseFromError(err error, codeType CodeType) *Response {
+	if err == nil {
+		return nil
+	}
+	if response, ok := AsResponse(err); ok {
+		return response
+	}
+	var flagErr *FlagValueError
+	if errors.As(err, &flagErr) {
+		return flagErr.Response()
+	}
+	return ResponseFailWithFlags(codeType, err.Error())
+}
@@ -0,0 +1,42 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestResponse_ErrorSemantics(t *testing.T) {
+	var err error = ResponseFailWithFlags(ParameterLess, "pid")
+	wrapped := fmt.Errorf("create experiment failed: %w", err)
+
+	if !errors.Is(wrapped, ParameterLess) {
+		t.Errorf("errors.Is() expected to match by code")
+	}
+	if errors.Is(wrapped, ParameterIllegal) {
+		t.Errorf("errors.Is() unexpected match")
+	}
+	response, ok := AsResponse(wrapped)
+	if !ok || response.Code != ParameterLess.Code {
+		t.Errorf("AsResponse() = %v, %v", response, ok)
+	}
+	if got := ResponseFromError(errors.New("boom"), CommandIllegal); got.Code != CommandIllegal.Code {
+		t.Errorf("ResponseFromError() code = %d", got.Code)
+	}
+}
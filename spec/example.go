@@ -0,0 +1,50 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"strings"
+)
+
+// Example is the usage example of the action or the flag
+type Example struct {
+	// Command is the command line, for example blade create cpu load --cpu-percent 60
+	Command string `yaml:"command" json:"command"`
+	// Desc describes what the command does
+	Desc string `yaml:"desc,omitempty" json:"desc,omitempty"`
+}
+
+// FormatExamples returns the examples text for the cli help, the description is the comment line of the command
+func FormatExamples(examples []Example) string {
+	lines := make([]string, 0)
+	for _, example := range examples {
+		if example.Desc != "" {
+			lines = append(lines, "# "+example.Desc)
+		}
+		lines = append(lines, example.Command, "")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// ActionUsageExample returns the structured examples formatted by FormatExamples,
+// or the hand-maintained Example if no structured example
+func ActionUsageExample(action ExpActionCommandSpec) string {
+	if examples := ActionExamplesOf(action); len(examples) > 0 {
+		return FormatExamples(examples)
+	}
+	return action.Example()
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestActionUsageExample(t *testing.T) {
+	content := `action: load
+shortDesc: cpu load
+longDesc: cpu load
+example: blade create cpu load
+actionProcessHang: false
+examples:
+- command: blade create cpu load --cpu-percent 60
+  desc: load 60% of all cpu cores
+- command: blade create cpu load --cpu-list 0,1
+`
+	action := &ActionModel{}
+	if err := yaml.UnmarshalStrict([]byte(content), action); err != nil {
+		t.Fatalf("yaml.UnmarshalStrict() error = %v", err)
+	}
+	want := "# load 60% of all cpu cores\nblade create cpu load --cpu-percent 60\n\nblade create cpu load --cpu-list 0,1"
+	if got := ActionUsageExample(action); got != want {
+		t.Errorf("ActionUsageExample() = %q, want %q", got, want)
+	}
+	action.ActionExamples = nil
+	if got := ActionUsageExample(action); got != "blade create cpu load" {
+		t.Errorf("ActionUsageExample() = %q, want the example", got)
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"sync"
+)
+
+// PreExecHook is invoked before the executor creates or destroys the experiment, use IsDestroy to tell them apart.
+// The executor is skipped and the returned response is used if it is not nil.
+type PreExecHook func(uid string, ctx context.Context, model *ExpModel) *Response
+
+// PostExecHook is invoked after the executor creates or destroys the experiment with the response,
+// the hook can modify the response, for example adding metadata
+type PostExecHook func(uid string, ctx context.Context, model *ExpModel, response *Response)
+
+var (
+	preExecHooks  = make([]PreExecHook, 0)
+	postExecHooks = make([]PostExecHook, 0)
+	execHooksLock sync.RWMutex
+)
+
+// RegisterPreExecHook registers the hook invoked by ExecExperiment in the registration order
+func RegisterPreExecHook(hook PreExecHook) {
+	execHooksLock.Lock()
+	defer execHooksLock.Unlock()
+	preExecHooks = append(preExecHooks, hook)
+}
+
+// RegisterPostExecHook registers the hook invoked by ExecExperiment in the registration order
+func RegisterPostExecHook(hook PostExecHook) {
+	execHooksLock.Lock()
+	defer execHooksLock.Unlock()
+	postExecHooks = append(postExecHooks, hook)
+}
+
+// ResetExecHooks removes all registered hooks
+func ResetExecHooks() {
+	execHooksLock.Lock()
+	defer execHooksLock.Unlock()
+	preExecHooks = make([]PreExecHook, 0)
+	postExecHooks = make([]PostExecHook, 0)
+}
+
+func getExecHooks() ([]PreExecHook, []PostExecHook) {
+	execHooksLock.RLock()
+	defer execHooksLock.RUnlock()
+	return preExecHooks, postExecHooks
+}
+
+// ExecExperiment invokes the executor with the registered hooks. After the pre hooks, the flag templates are
+// expanded by ExpandFlagTemplates, then the model is validated by the registered action spec by
+// ValidateRegisteredAction, the requirements of the action are checked by the checker set by
+// SetRequirementsChecker, and the executor is validated by ValidateExecutor. The executor and the post hooks
+// receive the expanded copy of the model, and the deprecation warnings of the registered action are appended to the
+// response of the executor by DeprecationWarnings. The progress of the experiment is cleared after destroyed.
+// The post hooks are invoked even if a pre hook or the validation stops the execution, so the notifications
+// always see the final response. The secret flags of the registered action are marked by MarkSecretFlags before
+// the pre hooks, because the models decoded from json lose the marks. The secret flag values are carried by ctx
+// for the channels by WithSecrets, and are masked in the error messages of the response before the post hooks.
+func ExecExperiment(executor Executor, uid string, ctx context.Context, model *ExpModel) *Response {
+	path, action, registered := findRegisteredAction(model)
+	for _, target := range path {
+		MarkSecretFlags(target, action, model)
+	}
+	ctx = WithSecrets(ctx, model)
+	preHooks, postHooks := getExecHooks()
+	var response *Response
+	for _, hook := range preHooks {
+		if response = hook(uid, ctx, model); response != nil {
+			break
+		}
+	}
+	if response == nil {
+		var expanded *ExpModel
+		if expanded, response = ExpandFlagTemplates(uid, model); response == nil {
+			model = expanded
+			ctx = WithSecrets(ctx, model)
+		}
+	}
+	if response == nil {
+		response = ValidateRegisteredAction(ctx, model)
+	}
+	if response == nil && registered {
+		response = checkActionRequirements(ctx, action)
+	}
+	if response == nil {
+		response = ValidateExecutor(ctx, executor, model)
+	}
+	if response == nil {
+		response = executor.Exec(uid, ctx, model)
+		if registered && response != nil {
+			response.AddWarnings(DeprecationWarnings(action, model)...)
+		}
+		clearDestroyedProgress(uid, ctx, response)
+	}
+	maskResponseSecrets(model, response)
+	for _, hook := range postHooks {
+		hook(uid, ctx, model, response)
+	}
+	return response
+}
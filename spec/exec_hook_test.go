@@ -0,0 +1,204 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type testExecutor struct {
+	exec func(uid string, ctx context.Context, model *ExpModel) *Response
+}
+
+func (e *testExecutor) Name() string {
+	return "test"
+}
+
+func (e *testExecutor) Exec(uid string, ctx context.Context, model *ExpModel) *Response {
+	return e.exec(uid, ctx, model)
+}
+
+func (e *testExecutor) SetChannel(channel Channel) {
+}
+
+func TestExecExperiment(t *testing.T) {
+	defer ResetExecHooks()
+	executed := false
+	executor := &testExecutor{exec: func(uid string, ctx context.Context, model *ExpModel) *Response {
+		executed = true
+		return ReturnSuccess(uid)
+	}}
+	RegisterPreExecHook(func(uid string, ctx context.Context, model *ExpModel) *Response {
+		if _, ok := model.ActionFlags["force"]; !ok {
+			return ResponseFailWithFlags(ParameterLess, "force")
+		}
+		return nil
+	})
+	var post *Response
+	RegisterPostExecHook(func(uid string, ctx context.Context, model *ExpModel, response *Response) {
+		post = response
+		response.SetMetadata("hooked", true)
+	})
+
+	response := ExecExperiment(executor, "uid", context.Background(), &ExpModel{})
+	if executed || response.Code != ParameterLess.Code || post != response {
+		t.Errorf("ExecExperiment() = %v, executed = %t, want stopped by the pre hook", response, executed)
+	}
+	model := &ExpModel{ActionFlags: map[string]string{"force": "true"}}
+	response = ExecExperiment(executor, "uid", context.Background(), model)
+	if hooked, _ := response.GetMetadata("hooked"); !executed || !response.Success || hooked != true {
+		t.Errorf("ExecExperiment() = %v, executed = %t, want success with metadata", response, executed)
+	}
+}
+
+type validatedExecutor struct {
+	testExecutor
+}
+
+func (e *validatedExecutor) Validate(ctx context.Context, model *ExpModel) *Response {
+	if model.Target == "" {
+		return ResponseFailWithFlags(ParameterLess, "target")
+	}
+	return nil
+}
+
+func TestExecExperimentValidate(t *testing.T) {
+	executed := false
+	executor := &validatedExecutor{testExecutor{exec: func(uid string, ctx context.Context, model *ExpModel) *Response {
+		executed = true
+		return ReturnSuccess(uid)
+	}}}
+	wrapped := WithMiddlewares(executor, RecoverMiddleware("test"))
+	response := ExecExperiment(wrapped, "uid", context.Background(), &ExpModel{})
+	if executed || response.Code != ParameterLess.Code {
+		t.Errorf("ExecExperiment() = %v, executed = %t, want stopped by Validate", response, executed)
+	}
+	response = ExecExperiment(wrapped, "uid", context.Background(), &ExpModel{Target: "cpu"})
+	if !executed || !response.Success {
+		t.Errorf("ExecExperiment() = %v, executed = %t, want success", response, executed)
+	}
+}
+
+func TestExecExperimentValidateRegisteredAction(t *testing.T) {
+	defer ResetModelSpecs()
+	network := &ExpCommandModel{ExpName: "network", ExpActions: []ActionModel{{ActionName: "delay",
+		ActionFlags: []ExpFlag{{Name: "time", Required: true}, {Name: "interface", Default: "eth0"}}}}}
+	if err := RegisterModelSpec(network); err != nil {
+		t.Fatalf("RegisterModelSpec() error = %v", err)
+	}
+	executed := false
+	executor := &testExecutor{exec: func(uid string, ctx context.Context, model *ExpModel) *Response {
+		executed = true
+		return ReturnSuccess(model.ActionFlags["interface"])
+	}}
+	response := ExecExperiment(executor, "uid", context.Background(), &ExpModel{Target: "network", ActionName: "delay"})
+	if executed || response.Code != ParameterLess.Code {
+		t.Errorf("ExecExperiment() = %v, executed = %t, want stopped by the action spec", response, executed)
+	}
+	model := &ExpModel{Target: "network", ActionName: "delay", ActionFlags: map[string]string{"time": "3000"}}
+	response = ExecExperiment(executor, "uid", context.Background(), model)
+	if !executed || response.Result != "eth0" {
+		t.Errorf("ExecExperiment() = %v, executed = %t, want executed with the default flag", response, executed)
+	}
+}
+
+func TestExecExperimentFlagAlias(t *testing.T) {
+	defer ResetModelSpecs()
+	network := &ExpCommandModel{ExpName: "network", ExpActions: []ActionModel{{ActionName: "delay",
+		ActionFlags: []ExpFlag{{Name: "interface", Required: true, Aliases: []string{"device"}}}}}}
+	if err := RegisterModelSpec(network); err != nil {
+		t.Fatalf("RegisterModelSpec() error = %v", err)
+	}
+	executor := &testExecutor{exec: func(uid string, ctx context.Context, model *ExpModel) *Response {
+		return ReturnSuccess(model.ActionFlags["interface"])
+	}}
+	model := &ExpModel{Target: "network", ActionName: "delay", ActionFlags: map[string]string{"device": "eth0"}}
+	response := ExecExperiment(executor, "uid", context.Background(), model)
+	if !response.Success || response.Result != "eth0" {
+		t.Errorf("ExecExperiment() = %v, want executed with the flag specified by the alias", response)
+	}
+}
+
+func TestExecExperimentMarkSecretFlags(t *testing.T) {
+	defer ResetModelSpecs()
+	mysql := &ExpCommandModel{ExpName: "mysql", ExpActions: []ActionModel{{ActionName: "delay",
+		ActionFlags: []ExpFlag{{Name: "password", Secret: true}}}}}
+	if err := RegisterModelSpec(mysql); err != nil {
+		t.Fatalf("RegisterModelSpec() error = %v", err)
+	}
+	executor := &testExecutor{exec: func(uid string, ctx context.Context, model *ExpModel) *Response {
+		return ResponseFailWithFlags(CommandIllegal, "mysql -p"+model.ActionFlags["password"])
+	}}
+	model := &ExpModel{Target: "mysql", ActionName: "delay", ActionFlags: map[string]string{"password": "p@ss"}}
+	response := ExecExperiment(executor, "uid", context.Background(), model)
+	if strings.Contains(response.Err, "p@ss") || !strings.Contains(response.Err, SecretMask) {
+		t.Errorf("ExecExperiment() err = %s, want the secret masked", response.Err)
+	}
+}
+
+func TestExecExperimentSubTarget(t *testing.T) {
+	defer ResetModelSpecs()
+	if err := RegisterModelSpec(newK8sModel()); err != nil {
+		t.Fatalf("RegisterModelSpec() error = %v", err)
+	}
+	executed := false
+	executor := &testExecutor{exec: func(uid string, ctx context.Context, model *ExpModel) *Response {
+		executed = true
+		return ReturnSuccess(model.ActionFlags["namespace"])
+	}}
+	for _, target := range []string{"k8s pod network", "k8s/pod/network"} {
+		executed = false
+		response := ExecExperiment(executor, "uid", context.Background(), &ExpModel{Target: target, ActionName: "latency"})
+		if executed || response.Code != ParameterLess.Code {
+			t.Errorf("ExecExperiment(%s) = %v, want stopped by the inherited required namespace", target, response)
+		}
+		model := &ExpModel{Target: target, ActionName: "delay", ActionFlags: map[string]string{"namespace": "chaos"}}
+		response = ExecExperiment(executor, "uid", context.Background(), model)
+		if !executed || response.Result != "chaos" {
+			t.Errorf("ExecExperiment(%s) = %v, want executed", target, response)
+		}
+	}
+}
+
+func TestExecExperimentCheckRequirements(t *testing.T) {
+	defer ResetModelSpecs()
+	defer SetRequirementsChecker(nil)
+	network := &ExpCommandModel{ExpName: "network", ExpActions: []ActionModel{{ActionName: "delay",
+		ActionRequirements: &ActionRequirements{Commands: []string{"tc"}}}}}
+	if err := RegisterModelSpec(network); err != nil {
+		t.Fatalf("RegisterModelSpec() error = %v", err)
+	}
+	executed := false
+	executor := &testExecutor{exec: func(uid string, ctx context.Context, model *ExpModel) *Response {
+		executed = true
+		return ReturnSuccess(nil)
+	}}
+	model := &ExpModel{Target: "network", ActionName: "delay"}
+	if response := ExecExperiment(executor, "uid", context.Background(), model); !executed || !response.Success {
+		t.Errorf("ExecExperiment() = %v, want executed without the requirements checker", response)
+	}
+	SetRequirementsChecker(func(ctx context.Context, requirements *ActionRequirements) *Response {
+		return ResponseFailWithFlags(EnvironmentNotSatisfied, requirements)
+	})
+	executed = false
+	response := ExecExperiment(executor, "uid", context.Background(), model)
+	if executed || response.Code != EnvironmentNotSatisfied.Code {
+		t.Errorf("ExecExperiment() = %v, executed = %t, want stopped by the requirements", response, executed)
+	}
+}
@@ -40,6 +40,9 @@ type ExpModel struct {
 	// ActionFlags is the experiment action flags, for example time and offset
 	ActionFlags map[string]string `json:"flags,omitempty"`
 
+	// ActionFlagValues are the values of the repeated flags, the values are also joined by comma in ActionFlags
+	ActionFlagValues map[string][]string `json:"flagValues,omitempty"`
+
 	// Programs
 	ActionPrograms []string `json:"programs,omitempty"`
 
@@ -47,6 +50,18 @@ type ExpModel struct {
 	ActionCategories []string `json:"categories,omitempty"`
 
 	ActionProcessHang bool     `yaml:"actionProcessHang"`
+
+	// IdempotencyKey identifies the create request, the uid is used if empty
+	IdempotencyKey string `json:"idempotencyKey,omitempty"`
+
+	// SecretFlags are the names of the flags whose values are masked by Masked and String
+	SecretFlags []string `json:"-"`
+
+	// Labels are the identifying metadata to select the experiments, such as team=payment
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are the free-form metadata not used to select the experiments, such as the owner and the ticket id
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
 // ExpExecutor defines the ExpExecutor interface
@@ -54,7 +69,7 @@ type Executor interface {
 	// Name is used to identify the ExpExecutor
 	Name() string
 
-	// Exec is used to execute the experiment
+	// Exec is used to execute the experiment, it must return as soon as possible after the ctx is canceled
 	Exec(uid string, ctx context.Context, model *ExpModel) *Response
 
 	// SetChannel
@@ -75,14 +90,10 @@ func (exp *ExpModel) GetFlags() string {
 const UnknownUid = "unknown"
 
 func SetDestroyFlag(ctx context.Context, suid string) context.Context {
-	return context.WithValue(ctx, DestroyKey, suid)
+	return context.WithValue(ctx, DestroyContextKey, suid)
 }
 
 // IsDestroy command
 func IsDestroy(ctx context.Context) (string, bool) {
-	suid := ctx.Value(DestroyKey)
-	if suid == nil {
-		return "", false
-	}
-	return suid.(string), true
+	return ContextString(ctx, DestroyContextKey)
 }
@@ -0,0 +1,134 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Logger is the logger injected into the executors, the uid in ctx is attached to the entries
+type Logger interface {
+	Debugf(ctx context.Context, format string, a ...interface{})
+	Infof(ctx context.Context, format string, a ...interface{})
+	Warnf(ctx context.Context, format string, a ...interface{})
+	Errorf(ctx context.Context, format string, a ...interface{})
+}
+
+// ExecutorConfig is the configuration injected into the executors
+type ExecutorConfig map[string]string
+
+// Get returns the value of the key, or the defaultValue if the key is absent
+func (c ExecutorConfig) Get(key, defaultValue string) string {
+	if value, ok := c[key]; ok {
+		return value
+	}
+	return defaultValue
+}
+
+// ExecutorDeps contains the dependencies which the framework injects into the executor
+type ExecutorDeps struct {
+	// Channel is used to run the commands, it is required
+	Channel Channel
+	// Logger is the logrus based logger if nil
+	Logger Logger
+	// Config is empty if nil
+	Config ExecutorConfig
+}
+
+// ExecutorConstructor creates the executor with the injected dependencies, for example
+//
+//	func NewExecutor(deps spec.ExecutorDeps) spec.Executor {
+//		return &NetworkExecutor{BaseExecutor: spec.NewBaseExecutor(deps)}
+//	}
+type ExecutorConstructor func(deps ExecutorDeps) Executor
+
+// NewExecutor checks the dependencies, fills the optional ones and invokes the constructor.
+// It returns the ChannelNil response instead of creating an executor without channel.
+func NewExecutor(constructor ExecutorConstructor, deps ExecutorDeps) (Executor, *Response) {
+	if deps.Channel == nil {
+		return nil, ResponseFailWithFlags(ChannelNil)
+	}
+	if deps.Logger == nil {
+		deps.Logger = defaultLogger{}
+	}
+	if deps.Config == nil {
+		deps.Config = ExecutorConfig{}
+	}
+	return constructor(deps), nil
+}
+
+// BaseExecutor holds the injected dependencies, it is embedded by the executors created by NewExecutor
+type BaseExecutor struct {
+	deps ExecutorDeps
+}
+
+// NewBaseExecutor returns the base executor holding the dependencies
+func NewBaseExecutor(deps ExecutorDeps) BaseExecutor {
+	return BaseExecutor{deps: deps}
+}
+
+// Channel returns the injected channel
+func (b *BaseExecutor) Channel() Channel {
+	return b.deps.Channel
+}
+
+// Logger returns the injected logger
+func (b *BaseExecutor) Logger() Logger {
+	if b.deps.Logger == nil {
+		return defaultLogger{}
+	}
+	return b.deps.Logger
+}
+
+// Config returns the injected configuration
+func (b *BaseExecutor) Config() ExecutorConfig {
+	return b.deps.Config
+}
+
+// SetChannel implements the Executor interface for compatibility, the nil channel is ignored
+// so the injected channel is never reset.
+func (b *BaseExecutor) SetChannel(channel Channel) {
+	if channel != nil {
+		b.deps.Channel = channel
+	}
+}
+
+// defaultLogger writes the entries by logrus
+type defaultLogger struct{}
+
+func (defaultLogger) entry(ctx context.Context) *logrus.Entry {
+	uid, _ := UIDFromContext(ctx)
+	return logrus.WithField("uid", uid)
+}
+
+func (l defaultLogger) Debugf(ctx context.Context, format string, a ...interface{}) {
+	l.entry(ctx).Debugf(format, a...)
+}
+
+func (l defaultLogger) Infof(ctx context.Context, format string, a ...interface{}) {
+	l.entry(ctx).Infof(format, a...)
+}
+
+func (l defaultLogger) Warnf(ctx context.Context, format string, a ...interface{}) {
+	l.entry(ctx).Warnf(format, a...)
+}
+
+func (l defaultLogger) Errorf(ctx context.Context, format string, a ...interface{}) {
+	l.entry(ctx).Errorf(format, a...)
+}
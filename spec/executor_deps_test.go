@@ -0,0 +1,80 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"testing"
+)
+
+type stubChannel struct {
+	Channel
+	name string
+}
+
+func (c *stubChannel) Name() string {
+	return c.name
+}
+
+type depsExecutor struct {
+	BaseExecutor
+}
+
+func (e *depsExecutor) Name() string {
+	return "deps"
+}
+
+func (e *depsExecutor) Exec(uid string, ctx context.Context, model *ExpModel) *Response {
+	e.Logger().Infof(ctx, "exec %s by %s", model.ActionName, e.Channel().Name())
+	return ReturnSuccess(e.Config().Get("interface", "eth0"))
+}
+
+func newDepsExecutor(deps ExecutorDeps) Executor {
+	return &depsExecutor{BaseExecutor: NewBaseExecutor(deps)}
+}
+
+func TestNewExecutor(t *testing.T) {
+	if _, response := NewExecutor(newDepsExecutor, ExecutorDeps{}); response == nil || response.Code != ChannelNil.Code {
+		t.Fatalf("NewExecutor() without channel = %v, want ChannelNil", response)
+	}
+
+	executor, response := NewExecutor(newDepsExecutor, ExecutorDeps{Channel: &stubChannel{name: "local"}})
+	if response != nil {
+		t.Fatalf("NewExecutor() = %v", response)
+	}
+	ctx := WithUID(context.Background(), "abc")
+	if response := executor.Exec("abc", ctx, &ExpModel{ActionName: "delay"}); response.Result != "eth0" {
+		t.Errorf("Exec() result = %v, want eth0", response.Result)
+	}
+
+	executor.SetChannel(nil)
+	if name := executor.(*depsExecutor).Channel().Name(); name != "local" {
+		t.Errorf("Channel() after SetChannel(nil) = %s, want local", name)
+	}
+	executor.SetChannel(&stubChannel{name: "nsexec"})
+	if name := executor.(*depsExecutor).Channel().Name(); name != "nsexec" {
+		t.Errorf("Channel() after SetChannel() = %s, want nsexec", name)
+	}
+
+	executor, _ = NewExecutor(newDepsExecutor, ExecutorDeps{
+		Channel: &stubChannel{name: "local"},
+		Config:  ExecutorConfig{"interface": "eth1"},
+	})
+	if response := executor.Exec("abc", ctx, &ExpModel{ActionName: "delay"}); response.Result != "eth1" {
+		t.Errorf("Exec() result = %v, want eth1", response.Result)
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/sirupsen/logrus"
+)
+
+// The metadata keys populated by FailWithCause
+const (
+	// MetadataContext is the key-value context of the failure
+	MetadataContext = "context"
+	// MetadataStack is the stack trace where the failure is reported, it is captured in the debug mode only
+	MetadataStack = "stack"
+)
+
+// FailWithCause returns the failed response of the code for the unexpected error of the executor. The message is
+// rendered by the code template with the kv pairs as the named params and the cause as the err param, such as
+// FailWithCause(spec.OsCmdExecFailed, err, "command", cmd). The cause chain is kept in the Causes, the kv pairs
+// are in the MetadataContext and the stack trace is in the MetadataStack if the debug log level is enabled.
+func FailWithCause(codeType CodeType, cause error, kv ...interface{}) *Response {
+	context := contextFromKeyValues(kv)
+	params := make(map[string]interface{}, len(context)+1)
+	for key, value := range context {
+		params[key] = value
+	}
+	if _, ok := params["err"]; !ok && cause != nil {
+		params["err"] = cause.Error()
+		if response, ok := cause.(*Response); ok {
+			params["err"] = response.Err
+		}
+	}
+	msg, _ := codeType.Render(defaultLocale(), params)
+	response := (&Response{Code: codeType.Code, Success: false, Err: msg}).classify()
+	response.AddCauses(CausesFromError(cause, "")...)
+	if len(context) > 0 {
+		response.SetMetadata(MetadataContext, context)
+	}
+	if logrus.IsLevelEnabled(logrus.DebugLevel) {
+		response.SetMetadata(MetadataStack, string(debug.Stack()))
+	}
+	return response
+}
+
+// contextFromKeyValues converts the alternating keys and values to the map, the value of the last key
+// is nil if missing and the keys are formatted by fmt.Sprint if they are not strings
+func contextFromKeyValues(kv []interface{}) map[string]interface{} {
+	context := make(map[string]interface{}, len(kv)/2)
+	for idx := 0; idx < len(kv); idx += 2 {
+		key, ok := kv[idx].(string)
+		if !ok {
+			key = fmt.Sprint(kv[idx])
+		}
+		var value interface{}
+		if idx+1 < len(kv) {
+			value = kv[idx+1]
+		}
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+		context[key] = value
+	}
+	return context
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestFailWithCause(t *testing.T) {
+	response := FailWithCause(OsCmdExecFailed, ResponseFailWithFlags(CommandTcNotFound),
+		"command", "tc qdisc add", "device", "eth0")
+	if response.Success || response.Code != OsCmdExecFailed.Code {
+		t.Fatalf("FailWithCause() = %v, want OsCmdExecFailed", response)
+	}
+	if want := "`tc qdisc add`: cmd exec failed, err: `tc`: command not found"; response.Err != want {
+		t.Errorf("Err = %q, want %q", response.Err, want)
+	}
+	if !errors.Is(response, CommandTcNotFound) {
+		t.Errorf("errors.Is(response, CommandTcNotFound) = false, want the code in the cause chain")
+	}
+	context, _ := response.GetMetadata(MetadataContext)
+	if context.(map[string]interface{})["device"] != "eth0" {
+		t.Errorf("context = %v, want the device", context)
+	}
+	if _, ok := response.GetMetadata(MetadataStack); ok {
+		t.Errorf("the stack is captured, want it in the debug mode only")
+	}
+	wrapped := FailWithCause(OsCmdExecFailed, fmt.Errorf("run tc failed, %w", ResponseFailWithFlags(CommandTcNotFound)),
+		"command", "tc")
+	if !errors.Is(wrapped, CommandTcNotFound) {
+		t.Errorf("errors.Is(wrapped, CommandTcNotFound) = false, want the code of the wrapped response")
+	}
+}
+
+func TestFailWithCauseStack(t *testing.T) {
+	level := logrus.GetLevel()
+	defer logrus.SetLevel(level)
+	logrus.SetLevel(logrus.DebugLevel)
+	response := FailWithCause(ExecutorPanic, errors.New("boom"), "action", "delay", "odd")
+	if stack, _ := response.GetMetadata(MetadataStack); !strings.Contains(fmt.Sprint(stack), "TestFailWithCauseStack") {
+		t.Errorf("stack = %v, want the caller", stack)
+	}
+	if response.Err != "`delay`: executor panic, err: boom" {
+		t.Errorf("Err = %q", response.Err)
+	}
+	context, _ := response.GetMetadata(MetadataContext)
+	if value, ok := context.(map[string]interface{})["odd"]; !ok || value != nil {
+		t.Errorf("context = %v, want the odd key with nil value", context)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import "errors"
+
+// The constraints of the field errors, the flag group constraints are the FlagGroup types
+const (
+	ConstraintRequired = "required"
+	ConstraintType     = "type"
+	ConstraintEnum     = "enum"
+	ConstraintNonEmpty = "nonEmpty"
+	ConstraintPattern  = "pattern"
+	ConstraintMin      = "min"
+	ConstraintMax      = "max"
+	ConstraintRepeated = "repeated"
+	// ConstraintSchema is the constraint of the json type flag value violating the flag schema
+	ConstraintSchema = "schema"
+	// ConstraintValidator is the constraint of the FlagValidation.Validate callback and the named validators
+	ConstraintValidator = "validator"
+	// ConstraintRequiredIf and ConstraintForbiddenIf are the violated conditions of the flag,
+	// the message of the field error is the condition expression
+	ConstraintRequiredIf  = "requiredIf"
+	ConstraintForbiddenIf = "forbiddenIf"
+)
+
+// FieldError is the structured detail of the illegal parameter, so the UI can highlight the flag
+type FieldError struct {
+	Field      string `json:"field"`
+	Constraint string `json:"constraint"`
+	// Value is the provided value, it is SecretMask if the flag is secret
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// AddFieldErrors appends the field errors to the response
+func (response *Response) AddFieldErrors(fieldErrors ...FieldError) *Response {
+	response.FieldErrors = append(response.FieldErrors, fieldErrors...)
+	return response
+}
+
+// constraintError is the error of the flag value with the violated constraint
+type constraintError struct {
+	constraint string
+	err        error
+}
+
+func (e *constraintError) Error() string {
+	return e.err.Error()
+}
+
+func (e *constraintError) Unwrap() error {
+	return e.err
+}
+
+// newFieldError returns the field error of the flag value, the constraint is taken from the err if present
+func newFieldError(flag ExpFlagSpec, value, constraint string, err error) FieldError {
+	var ce *constraintError
+	if errors.As(err, &ce) {
+		constraint = ce.constraint
+	}
+	if FlagSecretOf(flag) && value != "" {
+		value = SecretMask
+	}
+	fieldError := FieldError{Field: flag.FlagName(), Constraint: constraint, Value: value}
+	if err != nil {
+		fieldError.Message = err.Error()
+	}
+	return fieldError
+}
+
+// parameterIllegal returns the ParameterIllegal response with the field error of the flag value
+func parameterIllegal(flag ExpFlagSpec, value string, err error) *Response {
+	shown := value
+	if FlagSecretOf(flag) && value != "" {
+		shown = SecretMask
+	}
+	return ResponseFailWithFlags(ParameterIllegal, flag.FlagName(), shown, err).
+		AddFieldErrors(newFieldError(flag, value, ConstraintType, err))
+}
+
+// groupFieldErrors returns the field errors of the flags violating the flag group
+func groupFieldErrors(constraint string, names []string) []FieldError {
+	fieldErrors := make([]FieldError, 0, len(names))
+	for _, name := range names {
+		fieldErrors = append(fieldErrors, FieldError{Field: name, Constraint: constraint})
+	}
+	return fieldErrors
+}
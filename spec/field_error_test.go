@@ -0,0 +1,88 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestValidateExpModel_FieldErrors(t *testing.T) {
+	max := 100.0
+	action := &ActionModel{
+		ActionFlags: []ExpFlag{
+			{Name: "time", Type: FlagTypeInt, Required: true, Validation: &FlagValidation{Max: &max}},
+			{Name: "mode", Type: FlagTypeEnum, EnumValues: []string{"burn", "ram"}},
+			{Name: "password", Secret: true, Validation: &FlagValidation{Pattern: "^[a-z]+$"}},
+		},
+	}
+	tests := []struct {
+		name  string
+		flags map[string]string
+		want  FieldError
+	}{
+		{"required", map[string]string{}, FieldError{Field: "time", Constraint: ConstraintRequired}},
+		{"type", map[string]string{"time": "abc"}, FieldError{Field: "time", Constraint: ConstraintType, Value: "abc"}},
+		{"max", map[string]string{"time": "200"}, FieldError{Field: "time", Constraint: ConstraintMax, Value: "200"}},
+		{"enum", map[string]string{"time": "1", "mode": "fill"}, FieldError{Field: "mode", Constraint: ConstraintEnum, Value: "fill"}},
+		{"secret", map[string]string{"time": "1", "password": "P@ss"}, FieldError{Field: "password", Constraint: ConstraintPattern, Value: SecretMask}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := ValidateExpModel(context.Background(), action, &ExpModel{ActionFlags: tt.flags})
+			if response == nil || len(response.FieldErrors) != 1 {
+				t.Fatalf("ValidateExpModel() = %v, want one field error", response)
+			}
+			got := response.FieldErrors[0]
+			got.Message = ""
+			if got != tt.want {
+				t.Errorf("FieldErrors[0] = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateFlagGroups_FieldErrors(t *testing.T) {
+	groups := []FlagGroup{{Type: FlagGroupExactlyOne, Flags: []string{"pid", "process"}}}
+	response := ValidateFlagGroups(groups, &ExpModel{ActionFlags: map[string]string{"pid": "1", "process": "java"}})
+	want := []FieldError{
+		{Field: "pid", Constraint: FlagGroupExactlyOne},
+		{Field: "process", Constraint: FlagGroupExactlyOne},
+	}
+	if response == nil || !reflect.DeepEqual(response.FieldErrors, want) {
+		t.Errorf("ValidateFlagGroups() = %v, want field errors %v", response, want)
+	}
+}
+
+func TestFlagValueError_Response(t *testing.T) {
+	model := &ExpModel{ActionFlags: map[string]string{"mode": "fill"}}
+	_, err := model.GetEnumFlag("mode", []string{"burn", "ram"}, "burn")
+	response := ResponseFromError(err, OsCmdExecFailed)
+	if len(response.FieldErrors) != 1 || response.FieldErrors[0].Constraint != ConstraintEnum ||
+		response.FieldErrors[0].Value != "fill" {
+		t.Errorf("ResponseFromError() = %v, want the enum field error", response)
+	}
+	data, err := MarshalResponseProto(response)
+	if err != nil {
+		t.Fatalf("MarshalResponseProto() error = %v", err)
+	}
+	decoded, err := UnmarshalResponseProto(data)
+	if err != nil || !reflect.DeepEqual(decoded.FieldErrors, response.FieldErrors) {
+		t.Errorf("UnmarshalResponseProto() = %v, %v", decoded, err)
+	}
+}
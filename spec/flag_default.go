@@ -0,0 +1,85 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+// FlagConfigProvider returns the configured value of the key
+type FlagConfigProvider func(key string) (string, bool)
+
+var (
+	flagConfigProvider     FlagConfigProvider
+	flagConfigProviderLock sync.RWMutex
+)
+
+// SetFlagConfigProvider sets the provider used to look up the flag config keys, nil disables the config lookup
+func SetFlagConfigProvider(provider FlagConfigProvider) {
+	flagConfigProviderLock.Lock()
+	defer flagConfigProviderLock.Unlock()
+	flagConfigProvider = provider
+}
+
+func getFlagConfigProvider() FlagConfigProvider {
+	flagConfigProviderLock.RLock()
+	defer flagConfigProviderLock.RUnlock()
+	return flagConfigProvider
+}
+
+// ResolveFlagValue returns the flag value by the precedence: explicit flag > env > config > Default.
+// The bool result is false if the value is not found in any of them.
+func ResolveFlagValue(flag ExpFlagSpec, flags map[string]string) (string, bool) {
+	if value := strings.TrimSpace(flags[flag.FlagName()]); value != "" {
+		return value, true
+	}
+	if envVar := FlagEnvVarOf(flag); envVar != "" {
+		if value := strings.TrimSpace(os.Getenv(envVar)); value != "" {
+			return value, true
+		}
+	}
+	if configKey := FlagConfigKeyOf(flag); configKey != "" {
+		if provider := getFlagConfigProvider(); provider != nil {
+			if value, ok := provider(configKey); ok && strings.TrimSpace(value) != "" {
+				return strings.TrimSpace(value), true
+			}
+		}
+	}
+	if value := flag.FlagDefault(); value != "" {
+		return value, true
+	}
+	return "", false
+}
+
+// ApplyFlagDefaults sets the absent matchers and flags of the model by ResolveFlagValue,
+// it should be invoked before validating the model.
+func ApplyFlagDefaults(action ExpActionCommandSpec, model *ExpModel) {
+	applyFlagDefaults(actionFlagSpecList(action), model)
+}
+
+func applyFlagDefaults(flags []ExpFlagSpec, model *ExpModel) {
+	if model.ActionFlags == nil {
+		model.ActionFlags = make(map[string]string)
+	}
+	for _, flag := range flags {
+		if value, ok := ResolveFlagValue(flag, model.ActionFlags); ok {
+			model.ActionFlags[flag.FlagName()] = value
+		}
+	}
+}
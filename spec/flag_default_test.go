@@ -0,0 +1,84 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"testing"
+)
+
+func TestResolveFlagValue(t *testing.T) {
+	flag := &ExpFlag{Name: "interface", Default: "eth0", EnvVar: "BLADE_TEST_INTERFACE", ConfigKey: "network.interface"}
+	config := map[string]string{}
+	SetFlagConfigProvider(func(key string) (string, bool) {
+		value, ok := config[key]
+		return value, ok
+	})
+	defer SetFlagConfigProvider(nil)
+
+	tests := []struct {
+		name   string
+		flags  map[string]string
+		env    string
+		config string
+		want   string
+	}{
+		{name: "explicit flag", flags: map[string]string{"interface": "eth3"}, env: "eth2", config: "eth1", want: "eth3"},
+		{name: "env", flags: map[string]string{"interface": " "}, env: "eth2", config: "eth1", want: "eth2"},
+		{name: "config", config: " eth1 ", want: "eth1"},
+		{name: "default", want: "eth0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BLADE_TEST_INTERFACE", tt.env)
+			config["network.interface"] = tt.config
+			if got, ok := ResolveFlagValue(flag, tt.flags); !ok || got != tt.want {
+				t.Errorf("ResolveFlagValue() = %q, %t, want %q", got, ok, tt.want)
+			}
+		})
+	}
+	if got, ok := ResolveFlagValue(&ExpFlag{Name: "timeout"}, nil); ok {
+		t.Errorf("ResolveFlagValue() of the absent flag = %q, want not found", got)
+	}
+}
+
+func TestApplyFlagDefaults(t *testing.T) {
+	t.Setenv("BLADE_TEST_TIME", "3000")
+	action := &ActionModel{
+		ActionMatchers: []ExpFlag{{Name: "interface", Default: "eth0"}},
+		ActionFlags:    []ExpFlag{{Name: "time", Required: true, EnvVar: "BLADE_TEST_TIME"}, {Name: "offset"}},
+	}
+	model, err := NewExpModelBuilder().Target("network").Action("delay").ActionSpec(action).Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v, want the required flag resolved from the env", err)
+	}
+	if model.ActionFlags["interface"] != "eth0" || model.ActionFlags["time"] != "3000" {
+		t.Errorf("Build() flags = %v, want the default and env values", model.ActionFlags)
+	}
+	if _, ok := model.ActionFlags["offset"]; ok {
+		t.Errorf("the unresolved flag is set")
+	}
+
+	command := &ExpCommandModel{}
+	validated := &ExpModel{ActionFlags: map[string]string{"interface": "eth1"}}
+	if response := ValidateExpCommand(context.Background(), command, action, validated); response != nil {
+		t.Fatalf("ValidateExpCommand() = %s", response.Print())
+	}
+	if validated.ActionFlags["interface"] != "eth1" || validated.ActionFlags["time"] != "3000" {
+		t.Errorf("ValidateExpCommand() flags = %v", validated.ActionFlags)
+	}
+}
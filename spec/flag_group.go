@@ -0,0 +1,140 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"strings"
+)
+
+// The types of the flag group
+const (
+	// FlagGroupExactlyOne requires exactly one of the flags, for example --pid and --process
+	FlagGroupExactlyOne = "exactlyOne"
+	// FlagGroupMutuallyExclusive allows at most one of the flags
+	FlagGroupMutuallyExclusive = "mutuallyExclusive"
+	// FlagGroupRequiredTogether requires all of the flags if any of them is specified
+	FlagGroupRequiredTogether = "requiredTogether"
+	// FlagGroupRequires requires the other flags if the first flag is specified,
+	// for example --local-port requires --protocol
+	FlagGroupRequires = "requires"
+)
+
+// FlagGroup defines the constraint between the flags
+type FlagGroup struct {
+	Type  string   `yaml:"type"`
+	Flags []string `yaml:"flags,flow"`
+}
+
+// ValidateExpCommand validates the model scope by the action scopes, and the model flags by the action flags
+// and the flag groups of the command, it returns nil if passed. The absent flags are resolved by
+// ApplyFlagDefaults first, so the executor gets the same values as validated.
+func ValidateExpCommand(ctx context.Context, command ExpModelCommandSpec, action ExpActionCommandSpec,
+	model *ExpModel) *Response {
+	if response := ValidateActionScope(action, model); response != nil {
+		return response
+	}
+	ApplyFlagDefaults(action, model)
+	if response := ValidateExpModel(ctx, action, model); response != nil {
+		return response
+	}
+	return ValidateFlagGroups(command.FlagGroups(), model)
+}
+
+// ValidateModelPath is like ValidateExpCommand, but the flags inherited from the targets of the path, such as
+// the namespace flag of the k8s root, are resolved by the aliases, defaulted and validated with the action flags,
+// and the flag groups of the sub target are checked. The action flag overrides the inherited one of the same name.
+func ValidateModelPath(ctx context.Context, path ModelPath, action ExpActionCommandSpec, model *ExpModel) *Response {
+	flags := pathFlagSpecList(path, action)
+	if response := resolveFlagAliases(flags, model); response != nil {
+		return response
+	}
+	if response := ValidateActionScope(action, model); response != nil {
+		return response
+	}
+	applyFlagDefaults(flags, model)
+	if response := validateExpFlags(ctx, flags, model); response != nil {
+		return response
+	}
+	return ValidateFlagGroups(path.Last().FlagGroups(), model)
+}
+
+// actionFlagSpecList returns the matchers and the flags of the action
+func actionFlagSpecList(action ExpActionCommandSpec) []ExpFlagSpec {
+	flags := make([]ExpFlagSpec, 0)
+	flags = append(flags, action.Matchers()...)
+	return append(flags, action.Flags()...)
+}
+
+// pathFlagSpecList returns the matchers and the flags of the action, followed by the flags inherited down the
+// path which are not defined by the action
+func pathFlagSpecList(path ModelPath, action ExpActionCommandSpec) []ExpFlagSpec {
+	flags := actionFlagSpecList(action)
+	names := make(map[string]bool, len(flags))
+	for _, flag := range flags {
+		names[flag.FlagName()] = true
+	}
+	for _, flag := range path.Flags() {
+		if !names[flag.FlagName()] {
+			flags = append(flags, flag)
+		}
+	}
+	return flags
+}
+
+// ValidateFlagGroups checks the model flags by the groups, it returns nil if passed
+func ValidateFlagGroups(groups []FlagGroup, model *ExpModel) *Response {
+	for _, group := range groups {
+		specified := make([]string, 0)
+		missing := make([]string, 0)
+		for _, name := range group.Flags {
+			if _, ok := model.flagValue(name); ok {
+				specified = append(specified, name)
+			} else {
+				missing = append(missing, name)
+			}
+		}
+		names := strings.Join(group.Flags, "|")
+		switch group.Type {
+		case FlagGroupExactlyOne:
+			if len(specified) == 0 {
+				return ResponseFailWithFlags(ParameterLessOneOf, names).AddFieldErrors(groupFieldErrors(group.Type, group.Flags)...)
+			}
+			if len(specified) > 1 {
+				return ResponseFailWithFlags(ParameterConflict, names).AddFieldErrors(groupFieldErrors(group.Type, specified)...)
+			}
+		case FlagGroupMutuallyExclusive:
+			if len(specified) > 1 {
+				return ResponseFailWithFlags(ParameterConflict, names).AddFieldErrors(groupFieldErrors(group.Type, specified)...)
+			}
+		case FlagGroupRequiredTogether:
+			if len(specified) > 0 && len(missing) > 0 {
+				return ResponseFailWithFlags(ParameterLessWith, strings.Join(missing, "|"), strings.Join(specified, "|")).
+					AddFieldErrors(groupFieldErrors(group.Type, missing)...)
+			}
+		case FlagGroupRequires:
+			if len(group.Flags) == 0 || len(specified) == 0 || specified[0] != group.Flags[0] {
+				continue
+			}
+			if len(missing) > 0 {
+				return ResponseFailWithFlags(ParameterLessWith, strings.Join(missing, "|"), group.Flags[0]).
+					AddFieldErrors(groupFieldErrors(group.Type, missing)...)
+			}
+		}
+	}
+	return nil
+}
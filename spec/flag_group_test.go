@@ -0,0 +1,51 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import "testing"
+
+func TestValidateFlagGroups(t *testing.T) {
+	groups := []FlagGroup{
+		{Type: FlagGroupExactlyOne, Flags: []string{"pid", "process"}},
+		{Type: FlagGroupRequires, Flags: []string{"local-port", "protocol"}},
+	}
+	tests := []struct {
+		name     string
+		flags    map[string]string
+		wantCode int32
+	}{
+		{name: "passed", flags: map[string]string{"pid": "1", "local-port": "80", "protocol": "tcp"}},
+		{name: "none of exactly one", flags: map[string]string{}, wantCode: ParameterLessOneOf.Code},
+		{name: "both of exactly one", flags: map[string]string{"pid": "1", "process": "java"}, wantCode: ParameterConflict.Code},
+		{name: "requires", flags: map[string]string{"pid": "1", "local-port": "80"}, wantCode: ParameterLessWith.Code},
+		{name: "required flag only", flags: map[string]string{"pid": "1", "protocol": "tcp"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := ValidateFlagGroups(groups, &ExpModel{ActionFlags: tt.flags})
+			if tt.wantCode == 0 {
+				if response != nil {
+					t.Errorf("ValidateFlagGroups() = %s, want nil", response.Print())
+				}
+				return
+			}
+			if response == nil || response.Code != tt.wantCode {
+				t.Errorf("ValidateFlagGroups() = %v, want code %d", response, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ParseJSONValue decodes the json or yaml value of the json type flag, the value must be an object or an array.
+// The objects are decoded to map[string]interface{} and the arrays to []interface{} like encoding/json.
+func ParseJSONValue(value string) (interface{}, error) {
+	var result interface{}
+	if err := yaml.Unmarshal([]byte(value), &result); err != nil {
+		return nil, fmt.Errorf("illegal json or yaml value, %v", err)
+	}
+	result, err := normalizeYAMLValue(result)
+	if err != nil {
+		return nil, err
+	}
+	switch result.(type) {
+	case map[string]interface{}, []interface{}:
+		return result, nil
+	}
+	return nil, fmt.Errorf("the value must be an object or an array")
+}
+
+// normalizeYAMLValue converts the yaml maps with the interface keys to the json compatible maps
+func normalizeYAMLValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			normalized, err := normalizeYAMLValue(item)
+			if err != nil {
+				return nil, err
+			}
+			result[fmt.Sprint(key)] = normalized
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			normalized, err := normalizeYAMLValue(item)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = normalized
+		}
+		return result, nil
+	}
+	return value, nil
+}
+
+// GetJSONFlag decodes the json type flag value into the out, which is the pointer of the struct or the slice
+// provided by the executor. The out is not changed if the flag is absent, so it can hold the default value.
+func (exp *ExpModel) GetJSONFlag(name string, out interface{}) error {
+	value, ok := exp.flagValue(name)
+	if !ok {
+		return nil
+	}
+	result, err := ParseJSONValue(value)
+	if err != nil {
+		return &FlagValueError{Flag: name, Value: value, Err: err}
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return &FlagValueError{Flag: name, Value: value, Err: err}
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return &FlagValueError{Flag: name, Value: value, Err: err}
+	}
+	return nil
+}
+
+// ValidateJSONValue validates the decoded json value by the schema. The type, enum, minimum, maximum, minLength,
+// pattern, properties, required, items and the schema combinations are supported, the other keywords are ignored.
+func ValidateJSONValue(schema *JSONSchema, value interface{}) error {
+	return validateJSONValue(schema, value, "")
+}
+
+func validateJSONValue(schema *JSONSchema, value interface{}, path string) error {
+	if schema == nil {
+		return nil
+	}
+	if schema.Type != "" && !isJSONType(schema.Type, value) {
+		return jsonValueError(path, "must be %s", schema.Type)
+	}
+	if len(schema.Enum) > 0 && !containsString(schema.Enum, fmt.Sprint(value)) {
+		return jsonValueError(path, "must be one of %s", strings.Join(schema.Enum, ", "))
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range schema.Required {
+			if _, ok := v[name]; !ok {
+				return jsonValueError(joinJSONPath(path, name), "is required")
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if err := validateJSONValue(schema.Properties[name], v[name], joinJSONPath(path, name)); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			if err := validateJSONValue(schema.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case string:
+		if schema.MinLength != nil && len(v) < *schema.MinLength {
+			return jsonValueError(path, "must be at least %d characters", *schema.MinLength)
+		}
+		if schema.Pattern != "" {
+			matched, err := regexp.MatchString(schema.Pattern, v)
+			if err != nil {
+				return jsonValueError(path, "illegal pattern `%s`, %v", schema.Pattern, err)
+			}
+			if !matched {
+				return jsonValueError(path, "does not match `%s`", schema.Pattern)
+			}
+		}
+	default:
+		if number, err := numericValue(v); err == nil {
+			if schema.Minimum != nil && number < *schema.Minimum {
+				return jsonValueError(path, "must be greater than or equal to %v", *schema.Minimum)
+			}
+			if schema.Maximum != nil && number > *schema.Maximum {
+				return jsonValueError(path, "must be less than or equal to %v", *schema.Maximum)
+			}
+		}
+	}
+	return validateJSONCombinations(schema, value, path)
+}
+
+func validateJSONCombinations(schema *JSONSchema, value interface{}, path string) error {
+	for _, sub := range schema.AllOf {
+		if err := validateJSONValue(sub, value, path); err != nil {
+			return err
+		}
+	}
+	if len(schema.AnyOf) > 0 && countJSONMatches(schema.AnyOf, value, path) == 0 {
+		return jsonValueError(path, "must match any of the schemas")
+	}
+	if len(schema.OneOf) > 0 && countJSONMatches(schema.OneOf, value, path) != 1 {
+		return jsonValueError(path, "must match exactly one of the schemas")
+	}
+	if schema.Not != nil && validateJSONValue(schema.Not, value, path) == nil {
+		return jsonValueError(path, "must not match the schema")
+	}
+	return nil
+}
+
+func countJSONMatches(schemas []*JSONSchema, value interface{}, path string) int {
+	count := 0
+	for _, sub := range schemas {
+		if validateJSONValue(sub, value, path) == nil {
+			count++
+		}
+	}
+	return count
+}
+
+func isJSONType(schemaType string, value interface{}) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return schemaType == "object"
+	case []interface{}:
+		return schemaType == "array"
+	case string:
+		return schemaType == "string"
+	case bool:
+		return schemaType == "boolean"
+	case int, int64:
+		return schemaType == "integer" || schemaType == "number"
+	case float64:
+		return schemaType == "number" || (schemaType == "integer" && v == float64(int64(v)))
+	case nil:
+		return schemaType == "null"
+	}
+	return false
+}
+
+func joinJSONPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func jsonValueError(path, format string, args ...interface{}) error {
+	if path == "" {
+		path = "the value"
+	}
+	return fmt.Errorf("%s %s", path, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"testing"
+)
+
+type httpRule struct {
+	Path   string `json:"path"`
+	Status int    `json:"status"`
+}
+
+func newHTTPRulesFlag() *ExpFlag {
+	min, max := 100.0, 599.0
+	return &ExpFlag{Name: "rules", Type: FlagTypeJSON, Schema: &JSONSchema{
+		Type: "array",
+		Items: &JSONSchema{
+			Type:     "object",
+			Required: []string{"path"},
+			Properties: map[string]*JSONSchema{
+				"path":   {Type: "string", Pattern: "^/"},
+				"status": {Type: "integer", Minimum: &min, Maximum: &max},
+			},
+		},
+	}}
+}
+
+func TestValidateJSONFlag(t *testing.T) {
+	flag := newHTTPRulesFlag()
+	tests := []struct {
+		name       string
+		value      string
+		constraint string
+	}{
+		{name: "json", value: `[{"path": "/api", "status": 500}]`},
+		{name: "yaml", value: "- path: /api\n  status: 503\n"},
+		{name: "illegal json", value: `[{"path": `, constraint: ConstraintType},
+		{name: "scalar", value: `abc`, constraint: ConstraintType},
+		{name: "not array", value: `{"path": "/api"}`, constraint: ConstraintSchema},
+		{name: "required", value: `[{"status": 500}]`, constraint: ConstraintSchema},
+		{name: "pattern", value: `[{"path": "api"}]`, constraint: ConstraintSchema},
+		{name: "maximum", value: `[{"path": "/api", "status": 600}]`, constraint: ConstraintSchema},
+		{name: "not integer", value: `[{"path": "/api", "status": 500.5}]`, constraint: ConstraintSchema},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := ValidateExpModel(context.Background(), &ActionModel{ActionFlags: []ExpFlag{*flag}},
+				&ExpModel{ActionFlags: map[string]string{"rules": tt.value}})
+			if tt.constraint == "" {
+				if response != nil {
+					t.Errorf("ValidateExpModel() = %s, want nil", response.Print())
+				}
+				return
+			}
+			if response == nil || response.FieldErrors[0].Constraint != tt.constraint {
+				t.Errorf("ValidateExpModel() = %v, want constraint %s", response, tt.constraint)
+			}
+		})
+	}
+}
+
+func TestGetJSONFlag(t *testing.T) {
+	model := &ExpModel{ActionFlags: map[string]string{"rules": "- path: /api\n  status: 503\n", "illegal": "{"}}
+	var rules []httpRule
+	if err := model.GetJSONFlag("rules", &rules); err != nil {
+		t.Fatalf("GetJSONFlag() error = %v", err)
+	}
+	if len(rules) != 1 || rules[0].Path != "/api" || rules[0].Status != 503 {
+		t.Errorf("GetJSONFlag() = %+v", rules)
+	}
+	defaults := []httpRule{{Path: "/"}}
+	if err := model.GetJSONFlag("absent", &defaults); err != nil || len(defaults) != 1 {
+		t.Errorf("GetJSONFlag() = %+v, %v, want the default value", defaults, err)
+	}
+	if err := model.GetJSONFlag("illegal", &rules); err == nil {
+		t.Errorf("GetJSONFlag() error = nil, want illegal value error")
+	}
+}
+
+func TestJSONFlagSchema(t *testing.T) {
+	schema := flagSchema(newHTTPRulesFlag())
+	if schema.Type != "array" || schema.Items == nil || schema.Items.Properties["path"] == nil {
+		t.Errorf("flagSchema() = %+v, want the flag schema", schema)
+	}
+	if schema := flagSchema(&ExpFlag{Name: "rules", Type: FlagTypeJSON}); schema.Type != "" {
+		t.Errorf("flagSchema() type = %s, want any type", schema.Type)
+	}
+}
@@ -0,0 +1,343 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The value types of the flag
+const (
+	FlagTypeString   = "string"
+	FlagTypeInt      = "int"
+	FlagTypeFloat    = "float"
+	FlagTypeBool     = "bool"
+	FlagTypeDuration = "duration"
+	FlagTypeSize     = "size"
+	FlagTypePercent  = "percent"
+	FlagTypeEnum     = "enum"
+	// FlagTypeJSON is the structured value in json or yaml, such as a list of http rules, see GetJSONFlag
+	FlagTypeJSON = "json"
+)
+
+var sizeUnits = map[string]int64{
+	"":  1,
+	"B": 1,
+	"K": 1 << 10,
+	"M": 1 << 20,
+	"G": 1 << 30,
+	"T": 1 << 40,
+}
+
+// FlagValueError is the error of the illegal flag value, its message is the ParameterIllegal message
+type FlagValueError struct {
+	Flag  string
+	Value string
+	Err   error
+}
+
+func (e *FlagValueError) Error() string {
+	return ParameterIllegal.Sprintf(e.Flag, e.Value, e.Err)
+}
+
+func (e *FlagValueError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes errors.Is(err, spec.ParameterIllegal) true
+func (e *FlagValueError) Is(target error) bool {
+	return (&Response{Code: ParameterIllegal.Code}).Is(target)
+}
+
+// Response returns the ParameterIllegal response of the error with the field error
+func (e *FlagValueError) Response() *Response {
+	return ResponseFailWithFlags(ParameterIllegal, e.Flag, e.Value, e.Err).
+		AddFieldErrors(newFieldError(&ExpFlag{Name: e.Flag}, e.Value, ConstraintType, e.Err))
+}
+
+// ParseFlagValue parses the value by the flag type, the result type is string, int, float64, bool,
+// time.Duration, int64 for size, float64 for percent or the decoded object or array for json.
+// The error is *FlagValueError.
+func ParseFlagValue(flag ExpFlagSpec, value string) (interface{}, error) {
+	result, err := parseFlagValue(flag, value)
+	if err != nil {
+		if FlagTypeOf(flag) == FlagTypeEnum {
+			err = &constraintError{ConstraintEnum, err}
+		}
+		return nil, &FlagValueError{Flag: flag.FlagName(), Value: value, Err: err}
+	}
+	return result, nil
+}
+
+func parseFlagValue(flag ExpFlagSpec, value string) (interface{}, error) {
+	var result interface{}
+	var err error
+	flagType := FlagTypeOf(flag)
+	if flagType == "" && flag.FlagNoArgs() {
+		flagType = FlagTypeBool
+	}
+	switch flagType {
+	case FlagTypeInt:
+		result, err = strconv.Atoi(value)
+	case FlagTypeFloat:
+		result, err = strconv.ParseFloat(value, 64)
+	case FlagTypeBool:
+		result, err = strconv.ParseBool(value)
+	case FlagTypeDuration:
+		result, err = ParseDuration(value)
+	case FlagTypeSize:
+		result, err = ParseSize(value)
+	case FlagTypePercent:
+		result, err = ParsePercent(value)
+	case FlagTypeEnum:
+		result, err = value, checkEnumValue(FlagEnumValuesOf(flag), value)
+	case FlagTypeJSON:
+		result, err = ParseJSONValue(value)
+	default:
+		result = value
+	}
+	return result, err
+}
+
+func checkEnumValue(enumValues []string, value string) error {
+	for _, v := range enumValues {
+		if v == value {
+			return nil
+		}
+	}
+	return fmt.Errorf("the value must be one of %s", strings.Join(enumValues, ", "))
+}
+
+// ParseDuration parses the duration value, such as 10s, 1m30s or 2d12h. The integer value without unit is seconds,
+// and d is the unit of 24 hours which time.ParseDuration does not support.
+func ParseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	original := value
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	var days time.Duration
+	if idx := strings.Index(value, "d"); idx > 0 {
+		number, err := strconv.ParseFloat(value[:idx], 64)
+		if err != nil || number < 0 {
+			return 0, illegalDurationError(original)
+		}
+		days, value = time.Duration(number*float64(24*time.Hour)), value[idx+1:]
+		if value == "" {
+			return days, nil
+		}
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || (days > 0 && duration < 0) {
+		return 0, illegalDurationError(original)
+	}
+	return days + duration, nil
+}
+
+func illegalDurationError(value string) error {
+	return fmt.Errorf("illegal duration `%s`, must be like 30, 30s, 1m30s or 2d12h", value)
+}
+
+// FormatDuration formats the duration in the units up to days, such as 2d12h, 1h30m or 500ms, the result
+// can be parsed by ParseDuration
+func FormatDuration(duration time.Duration) string {
+	sign := ""
+	if duration < 0 {
+		sign, duration = "-", -duration
+	}
+	day := 24 * time.Hour
+	days, rest := duration/day, duration%day
+	result := ""
+	if days > 0 {
+		result = fmt.Sprintf("%dd", days)
+	}
+	if rest > 0 || days == 0 {
+		// trim the zero minutes and seconds, such as 1h0m0s to 1h
+		text := rest.String()
+		if strings.HasSuffix(text, "m0s") {
+			text = strings.TrimSuffix(text, "0s")
+		}
+		if strings.HasSuffix(text, "h0m") {
+			text = strings.TrimSuffix(text, "0m")
+		}
+		result += text
+	}
+	return sign + result
+}
+
+// ParseSize parses the size value to bytes, such as 512, 100K, 10MB, 1Gi. The units are based on 1024.
+func ParseSize(value string) (int64, error) {
+	original := value
+	value = strings.ToUpper(strings.TrimSpace(value))
+	idx := strings.IndexFunc(value, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	number, unit := value, ""
+	if idx >= 0 {
+		number, unit = value[:idx], strings.TrimSpace(value[idx:])
+	}
+	unit = strings.TrimSuffix(strings.TrimSuffix(unit, "B"), "I")
+	multiple, ok := sizeUnits[unit]
+	if !ok || number == "" {
+		return 0, illegalSizeError(original)
+	}
+	size, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, illegalSizeError(original)
+	}
+	return int64(size * float64(multiple)), nil
+}
+
+func illegalSizeError(value string) error {
+	return fmt.Errorf("illegal size `%s`, must be like 512, 100K, 10MB or 1Gi", value)
+}
+
+// FormatSize formats the bytes in the largest unit based on 1024 with at most one decimal, such as 512B,
+// 1.5KB or 10MB, the result can be parsed by ParseSize
+func FormatSize(size int64) string {
+	units := []string{"TB", "GB", "MB", "KB"}
+	for idx, unit := range units {
+		multiple := sizeUnits[unit[:1]]
+		if size >= multiple || -size >= multiple {
+			value := strconv.FormatFloat(float64(size)/float64(multiple), 'f', 1, 64)
+			return strings.TrimSuffix(value, ".0") + units[idx]
+		}
+	}
+	return fmt.Sprintf("%dB", size)
+}
+
+// ParsePercent parses the percentage value in [0, 100], such as 80, 80% or 12.5%
+func ParsePercent(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	number := strings.TrimSpace(strings.TrimSuffix(value, "%"))
+	percent, err := strconv.ParseFloat(number, 64)
+	if err != nil || number == "" {
+		return 0, fmt.Errorf("illegal percent `%s`", value)
+	}
+	if percent < 0 || percent > 100 {
+		return 0, fmt.Errorf("the percent `%s` must be in [0, 100]", value)
+	}
+	return percent, nil
+}
+
+func (exp *ExpModel) flagValue(name string) (string, bool) {
+	value, ok := exp.ActionFlags[name]
+	value = strings.TrimSpace(value)
+	return value, ok && value != ""
+}
+
+// GetStringFlag returns the flag value, or the default value if the flag is absent
+func (exp *ExpModel) GetStringFlag(name, defaultValue string) string {
+	if value, ok := exp.flagValue(name); ok {
+		return value
+	}
+	return defaultValue
+}
+
+// GetIntFlag returns the int flag value, or the default value if the flag is absent
+func (exp *ExpModel) GetIntFlag(name string, defaultValue int) (int, error) {
+	value, ok := exp.flagValue(name)
+	if !ok {
+		return defaultValue, nil
+	}
+	result, err := ParseFlagValue(&ExpFlag{Name: name, Type: FlagTypeInt}, value)
+	if err != nil {
+		return defaultValue, err
+	}
+	return result.(int), nil
+}
+
+// GetFloatFlag returns the float flag value, or the default value if the flag is absent
+func (exp *ExpModel) GetFloatFlag(name string, defaultValue float64) (float64, error) {
+	value, ok := exp.flagValue(name)
+	if !ok {
+		return defaultValue, nil
+	}
+	result, err := ParseFlagValue(&ExpFlag{Name: name, Type: FlagTypeFloat}, value)
+	if err != nil {
+		return defaultValue, err
+	}
+	return result.(float64), nil
+}
+
+// GetBoolFlag returns the bool flag value, or the default value if the flag is absent
+func (exp *ExpModel) GetBoolFlag(name string, defaultValue bool) (bool, error) {
+	value, ok := exp.flagValue(name)
+	if !ok {
+		return defaultValue, nil
+	}
+	result, err := ParseFlagValue(&ExpFlag{Name: name, Type: FlagTypeBool}, value)
+	if err != nil {
+		return defaultValue, err
+	}
+	return result.(bool), nil
+}
+
+// GetDurationFlag returns the duration flag value, or the default value if the flag is absent
+func (exp *ExpModel) GetDurationFlag(name string, defaultValue time.Duration) (time.Duration, error) {
+	value, ok := exp.flagValue(name)
+	if !ok {
+		return defaultValue, nil
+	}
+	result, err := ParseFlagValue(&ExpFlag{Name: name, Type: FlagTypeDuration}, value)
+	if err != nil {
+		return defaultValue, err
+	}
+	return result.(time.Duration), nil
+}
+
+// GetSizeFlag returns the size flag value in bytes, or the default value if the flag is absent
+func (exp *ExpModel) GetSizeFlag(name string, defaultValue int64) (int64, error) {
+	value, ok := exp.flagValue(name)
+	if !ok {
+		return defaultValue, nil
+	}
+	result, err := ParseFlagValue(&ExpFlag{Name: name, Type: FlagTypeSize}, value)
+	if err != nil {
+		return defaultValue, err
+	}
+	return result.(int64), nil
+}
+
+// GetPercentFlag returns the percent flag value in [0, 100], or the default value if the flag is absent
+func (exp *ExpModel) GetPercentFlag(name string, defaultValue float64) (float64, error) {
+	value, ok := exp.flagValue(name)
+	if !ok {
+		return defaultValue, nil
+	}
+	result, err := ParseFlagValue(&ExpFlag{Name: name, Type: FlagTypePercent}, value)
+	if err != nil {
+		return defaultValue, err
+	}
+	return result.(float64), nil
+}
+
+// GetEnumFlag returns the flag value if it's one of the enum values, or the default value if the flag is absent
+func (exp *ExpModel) GetEnumFlag(name string, enumValues []string, defaultValue string) (string, error) {
+	value, ok := exp.flagValue(name)
+	if !ok {
+		return defaultValue, nil
+	}
+	result, err := ParseFlagValue(&ExpFlag{Name: name, Type: FlagTypeEnum, EnumValues: enumValues}, value)
+	if err != nil {
+		return defaultValue, err
+	}
+	return result.(string), nil
+}
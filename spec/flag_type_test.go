@@ -0,0 +1,212 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{value: "512", want: 512},
+		{value: "512B", want: 512},
+		{value: "100K", want: 100 << 10},
+		{value: "10MB", want: 10 << 20},
+		{value: "1Gi", want: 1 << 30},
+		{value: "1.5k", want: 1536},
+		{value: "1GiB", want: 1 << 30},
+		{value: "-5MB", wantErr: true},
+		{value: "MB", wantErr: true},
+		{value: "10X", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseSize(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseSize() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseSize() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePercent(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    float64
+		wantErr bool
+	}{
+		{value: "80", want: 80},
+		{value: "80%", want: 80},
+		{value: " 12.5 % ", want: 12.5},
+		{value: "0", want: 0},
+		{value: "%", wantErr: true},
+		{value: "101%", wantErr: true},
+		{value: "-1", wantErr: true},
+		{value: "abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParsePercent(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParsePercent() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParsePercent() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpModel_TypedFlags(t *testing.T) {
+	model := &ExpModel{ActionFlags: map[string]string{
+		"cpu-percent": "80",
+		"timeout":     "30",
+		"delay":       "1m",
+		"mode":        "fill",
+		"bad":         "abc",
+		"load":        "75%",
+		"interval":    "100ms",
+	}}
+	if got, err := model.GetIntFlag("cpu-percent", 100); err != nil || got != 80 {
+		t.Errorf("GetIntFlag() = %v, %v", got, err)
+	}
+	if got, err := model.GetIntFlag("cpu-count", 1); err != nil || got != 1 {
+		t.Errorf("GetIntFlag() default = %v, %v", got, err)
+	}
+	if _, err := model.GetIntFlag("bad", 0); err == nil {
+		t.Errorf("GetIntFlag() expected error for illegal value")
+	}
+	if got, err := model.GetDurationFlag("timeout", 0); err != nil || got != 30*time.Second {
+		t.Errorf("GetDurationFlag() = %v, %v", got, err)
+	}
+	if got, err := model.GetDurationFlag("delay", 0); err != nil || got != time.Minute {
+		t.Errorf("GetDurationFlag() = %v, %v", got, err)
+	}
+	if _, err := model.GetEnumFlag("mode", []string{"burn", "ram"}, "burn"); err == nil {
+		t.Errorf("GetEnumFlag() expected error for value out of enum")
+	}
+	if got, err := model.GetDurationFlag("interval", 0); err != nil || got != 100*time.Millisecond {
+		t.Errorf("GetDurationFlag() = %v, %v", got, err)
+	}
+	if got, err := model.GetPercentFlag("load", 100); err != nil || got != 75 {
+		t.Errorf("GetPercentFlag() = %v, %v", got, err)
+	}
+	_, err := model.GetPercentFlag("bad", 100)
+	if !errors.Is(err, ParameterIllegal) {
+		t.Fatalf("GetPercentFlag() error = %v, want ParameterIllegal", err)
+	}
+	response := ResponseFromError(err, OsCmdExecFailed)
+	if response.Code != ParameterIllegal.Code || response.Err != err.Error() {
+		t.Errorf("ResponseFromError() = %v, want the ParameterIllegal response", response)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "30", want: 30 * time.Second},
+		{value: "1m30s", want: 90 * time.Second},
+		{value: "2d", want: 48 * time.Hour},
+		{value: "1d12h", want: 36 * time.Hour},
+		{value: "0.5d", want: 12 * time.Hour},
+		{value: "-1d", wantErr: true},
+		{value: "1d-1h", wantErr: true},
+		{value: "d", wantErr: true},
+		{value: "10x", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseDuration(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDuration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), tt.value) {
+				t.Errorf("ParseDuration() error = %v, want the value in the message", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDuration() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		want     string
+	}{
+		{500 * time.Millisecond, "500ms"},
+		{0, "0s"},
+		{90 * time.Minute, "1h30m"},
+		{time.Hour, "1h"},
+		{30 * time.Minute, "30m"},
+		{48 * time.Hour, "2d"},
+		{36*time.Hour + 30*time.Second, "1d12h0m30s"},
+		{-25 * time.Hour, "-1d1h"},
+	}
+	for _, tt := range tests {
+		got := FormatDuration(tt.duration)
+		if got != tt.want {
+			t.Errorf("FormatDuration(%v) = %s, want %s", tt.duration, got, tt.want)
+		}
+		if tt.duration >= 0 {
+			if parsed, err := ParseDuration(got); err != nil || parsed != tt.duration {
+				t.Errorf("ParseDuration(%s) = %v, %v, want %v", got, parsed, err, tt.duration)
+			}
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		size int64
+		want string
+	}{
+		{0, "0B"},
+		{512, "512B"},
+		{1536, "1.5KB"},
+		{10 << 20, "10MB"},
+		{1 << 30, "1GB"},
+		{3 << 40, "3TB"},
+		{-2048, "-2KB"},
+	}
+	for _, tt := range tests {
+		if got := FormatSize(tt.size); got != tt.want {
+			t.Errorf("FormatSize(%d) = %s, want %s", tt.size, got, tt.want)
+		}
+		if tt.size >= 0 {
+			if parsed, err := ParseSize(FormatSize(tt.size)); err != nil || parsed != tt.size {
+				t.Errorf("ParseSize(%s) = %d, %v, want %d", FormatSize(tt.size), parsed, err, tt.size)
+			}
+		}
+	}
+}
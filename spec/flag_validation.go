@@ -0,0 +1,188 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// FlagValidation defines the rules of the flag value
+type FlagValidation struct {
+	// Min is the min numeric value. The value of duration type is compared in seconds and size type in bytes
+	Min *float64 `yaml:"min,omitempty"`
+
+	// Max is the max numeric value, compared like Min
+	Max *float64 `yaml:"max,omitempty"`
+
+	// Pattern is the regular expression that the value must match
+	Pattern string `yaml:"pattern,omitempty"`
+
+	// NonEmpty is true if the value can not be empty when the flag is specified
+	NonEmpty bool `yaml:"nonEmpty,omitempty"`
+
+	// Validators are the names of the validators registered by RegisterFlagValidator, they are invoked
+	// in order after the rules above are passed
+	Validators []string `yaml:"validators,flow,omitempty"`
+
+	// Validate is the validator set by the code-defined spec, it is invoked before the named validators
+	Validate FlagValidator `yaml:"-"`
+}
+
+// ValidateExpModel validates the model flags by the matchers and flags of the action, it returns nil if passed.
+// The required flags are checked by FlagRequiredWhenDestroyed if ctx is the destroy context.
+// It is invoked before calling the executor, so the executor need not to validate the flags by hand.
+// The validators of the flag, for example the pid must exist, are invoked after the value passes the rules.
+func ValidateExpModel(ctx context.Context, action ExpActionCommandSpec, model *ExpModel) *Response {
+	return validateExpFlags(ctx, actionFlagSpecList(action), model)
+}
+
+func validateExpFlags(ctx context.Context, flags []ExpFlagSpec, model *ExpModel) *Response {
+	_, isDestroy := IsDestroy(ctx)
+	for _, flag := range flags {
+		value, ok := model.ActionFlags[flag.FlagName()]
+		if !ok || value == "" {
+			required := flag.FlagRequired()
+			if isDestroy {
+				required = flag.FlagRequiredWhenDestroyed()
+			}
+			if required {
+				return ResponseFailWithFlags(ParameterLess, flag.FlagName()).
+					AddFieldErrors(FieldError{Field: flag.FlagName(), Constraint: ConstraintRequired})
+			}
+			if response := checkFlagCondition(flag, FlagRequiredIfOf(flag), ParameterLessWith, ConstraintRequiredIf, model, isDestroy); response != nil {
+				return response
+			}
+			if validation := FlagValidationOf(flag); ok && validation != nil && validation.NonEmpty {
+				return parameterIllegal(flag, value, &constraintError{ConstraintNonEmpty, fmt.Errorf("the value can not be empty")})
+			}
+			continue
+		}
+		if response := checkFlagCondition(flag, FlagForbiddenIfOf(flag), ParameterForbidden, ConstraintForbiddenIf, model, isDestroy); response != nil {
+			return response
+		}
+		if values := model.ActionFlagValues[flag.FlagName()]; len(values) > 1 && !FlagRepeatedOf(flag) {
+			return parameterIllegal(flag, value, &constraintError{ConstraintRepeated, fmt.Errorf("the flag can not be repeated")})
+		}
+		if FlagRepeatedOf(flag) {
+			for _, v := range model.GetStringSliceFlag(flag.FlagName()) {
+				if err := ValidateFlagValue(flag, v); err != nil {
+					return parameterIllegal(flag, v, err)
+				}
+				if err := runFlagValidators(ctx, flag, v, model); err != nil {
+					return parameterIllegal(flag, v, err)
+				}
+			}
+			continue
+		}
+		if err := ValidateFlagValue(flag, value); err != nil {
+			return parameterIllegal(flag, value, err)
+		}
+		if err := runFlagValidators(ctx, flag, value, model); err != nil {
+			return parameterIllegal(flag, value, err)
+		}
+	}
+	return nil
+}
+
+// checkFlagCondition returns the failed response with the code if the condition of the flag is satisfied,
+// the condition is RequiredIf if the flag is absent, or ForbiddenIf if the flag is specified
+func checkFlagCondition(flag ExpFlagSpec, condition string, code CodeType, constraint string,
+	model *ExpModel, isDestroy bool) *Response {
+	if condition == "" {
+		return nil
+	}
+	satisfied, err := EvaluateCondition(condition, model, isDestroy)
+	if err != nil {
+		return ResponseFailWithFlags(ParameterIllegal, flag.FlagName(), condition, err)
+	}
+	if !satisfied {
+		return nil
+	}
+	return ResponseFailWithFlags(code, flag.FlagName(), condition).
+		AddFieldErrors(FieldError{Field: flag.FlagName(), Constraint: constraint, Message: condition})
+}
+
+// ValidateFlagValue checks the value by the flag type, the enum values and the validation rules
+func ValidateFlagValue(flag ExpFlagSpec, value string) error {
+	result, err := parseFlagValue(flag, value)
+	if err != nil {
+		if FlagTypeOf(flag) == FlagTypeEnum {
+			return &constraintError{ConstraintEnum, err}
+		}
+		return &constraintError{ConstraintType, err}
+	}
+	if FlagTypeOf(flag) != FlagTypeEnum && len(FlagEnumValuesOf(flag)) > 0 {
+		if err := checkEnumValue(FlagEnumValuesOf(flag), value); err != nil {
+			return &constraintError{ConstraintEnum, err}
+		}
+	}
+	if schema := FlagSchemaOf(flag); schema != nil && FlagTypeOf(flag) == FlagTypeJSON {
+		if err := ValidateJSONValue(schema, result); err != nil {
+			return &constraintError{ConstraintSchema, err}
+		}
+	}
+	validation := FlagValidationOf(flag)
+	if validation == nil {
+		return nil
+	}
+	if validation.NonEmpty && value == "" {
+		return &constraintError{ConstraintNonEmpty, fmt.Errorf("the value can not be empty")}
+	}
+	if validation.Pattern != "" {
+		matched, err := regexp.MatchString(validation.Pattern, value)
+		if err != nil {
+			return &constraintError{ConstraintPattern, fmt.Errorf("illegal pattern `%s`, %v", validation.Pattern, err)}
+		}
+		if !matched {
+			return &constraintError{ConstraintPattern, fmt.Errorf("the value does not match `%s`", validation.Pattern)}
+		}
+	}
+	if validation.Min == nil && validation.Max == nil {
+		return nil
+	}
+	number, err := numericValue(result)
+	if err != nil {
+		return &constraintError{ConstraintType, err}
+	}
+	if validation.Min != nil && number < *validation.Min {
+		return &constraintError{ConstraintMin, fmt.Errorf("the value must be greater than or equal to %v", *validation.Min)}
+	}
+	if validation.Max != nil && number > *validation.Max {
+		return &constraintError{ConstraintMax, fmt.Errorf("the value must be less than or equal to %v", *validation.Max)}
+	}
+	return nil
+}
+
+func numericValue(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case time.Duration:
+		return v.Seconds(), nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	}
+	return 0, fmt.Errorf("the value is not a number")
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"testing"
+)
+
+func TestValidateExpModel(t *testing.T) {
+	min, max := 0.0, 100.0
+	action := &ActionModel{
+		ActionFlags: []ExpFlag{
+			{Name: "cpu-percent", Type: FlagTypeInt, Required: true, Validation: &FlagValidation{Min: &min, Max: &max}},
+			{Name: "interface", Validation: &FlagValidation{Pattern: `^eth\d+$`, NonEmpty: true}},
+		},
+	}
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		flags    map[string]string
+		wantCode int32
+	}{
+		{name: "passed", ctx: context.Background(), flags: map[string]string{"cpu-percent": "80", "interface": "eth0"}},
+		{name: "required", ctx: context.Background(), flags: map[string]string{}, wantCode: ParameterLess.Code},
+		{name: "not required when destroyed", ctx: SetDestroyFlag(context.Background(), "uid"), flags: map[string]string{}},
+		{name: "not int", ctx: context.Background(), flags: map[string]string{"cpu-percent": "8o"}, wantCode: ParameterIllegal.Code},
+		{name: "over max", ctx: context.Background(), flags: map[string]string{"cpu-percent": "101"}, wantCode: ParameterIllegal.Code},
+		{name: "pattern", ctx: context.Background(), flags: map[string]string{"cpu-percent": "1", "interface": "lo"}, wantCode: ParameterIllegal.Code},
+		{name: "empty", ctx: context.Background(), flags: map[string]string{"cpu-percent": "1", "interface": ""}, wantCode: ParameterIllegal.Code},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := ValidateExpModel(tt.ctx, action, &ExpModel{ActionFlags: tt.flags})
+			if tt.wantCode == 0 {
+				if response != nil {
+					t.Errorf("ValidateExpModel() = %s, want nil", response.Print())
+				}
+				return
+			}
+			if response == nil || response.Code != tt.wantCode {
+				t.Errorf("ValidateExpModel() = %v, want code %d", response, tt.wantCode)
+			}
+		})
+	}
+}
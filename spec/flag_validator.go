@@ -0,0 +1,94 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// FlagValidator checks the flag value against the environment, for example the pid must exist on this host.
+// The model is the whole experiment model, so the validator can check the value with other flags.
+type FlagValidator func(ctx context.Context, flag ExpFlagSpec, value string, model *ExpModel) error
+
+var (
+	flagValidators     = make(map[string]FlagValidator)
+	flagValidatorsLock sync.RWMutex
+)
+
+// RegisterFlagValidator registers the validator by the name, which is referenced by FlagValidation.Validators
+// in the yaml spec. The validator registered with the same name is replaced.
+func RegisterFlagValidator(name string, validator FlagValidator) {
+	flagValidatorsLock.Lock()
+	defer flagValidatorsLock.Unlock()
+	flagValidators[name] = validator
+}
+
+// GetFlagValidator returns the validator registered by the name
+func GetFlagValidator(name string) (FlagValidator, bool) {
+	flagValidatorsLock.RLock()
+	defer flagValidatorsLock.RUnlock()
+	validator, ok := flagValidators[name]
+	return validator, ok
+}
+
+// FlagValidatorNames returns the names of all the registered validators in order
+func FlagValidatorNames() []string {
+	flagValidatorsLock.RLock()
+	defer flagValidatorsLock.RUnlock()
+	names := make([]string, 0, len(flagValidators))
+	for name := range flagValidators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// runFlagValidators invokes the Validate callback and the named validators of the flag in order,
+// it returns the first error. The unregistered validator name is an error too.
+func runFlagValidators(ctx context.Context, flag ExpFlagSpec, value string, model *ExpModel) error {
+	validation := FlagValidationOf(flag)
+	if validation == nil {
+		return nil
+	}
+	if validation.Validate != nil {
+		if err := validation.Validate(ctx, flag, value, model); err != nil {
+			return &constraintError{ConstraintValidator, err}
+		}
+	}
+	for _, name := range validation.Validators {
+		validator, ok := GetFlagValidator(name)
+		if !ok {
+			return &constraintError{ConstraintValidator, fmt.Errorf("the validator `%s` is not registered", name)}
+		}
+		if err := validator(ctx, flag, value, model); err != nil {
+			return &constraintError{ConstraintValidator, fmt.Errorf("%s: %v", name, err)}
+		}
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestFlagValidators(t *testing.T) {
+	RegisterFlagValidator("test-pid-exists", func(ctx context.Context, flag ExpFlagSpec, value string, model *ExpModel) error {
+		if value != "1" {
+			return fmt.Errorf("the process %s does not exist", value)
+		}
+		return nil
+	})
+	action := &ActionModel{
+		ActionFlags: []ExpFlag{
+			{Name: "pid", Type: FlagTypeInt, Validation: &FlagValidation{Validators: []string{"test-pid-exists"}}},
+			{Name: "unknown", Validation: &FlagValidation{Validators: []string{"test-not-registered"}}},
+			{Name: "port", Validation: &FlagValidation{Validate: func(ctx context.Context, flag ExpFlagSpec, value string, model *ExpModel) error {
+				if model.ActionFlags["pid"] == "" {
+					return fmt.Errorf("the pid is required by the port")
+				}
+				return nil
+			}}},
+		},
+	}
+	tests := []struct {
+		name       string
+		flags      map[string]string
+		constraint string
+	}{
+		{name: "passed", flags: map[string]string{"pid": "1", "port": "80"}},
+		{name: "named validator failed", flags: map[string]string{"pid": "2"}, constraint: ConstraintValidator},
+		{name: "not registered", flags: map[string]string{"unknown": "value"}, constraint: ConstraintValidator},
+		{name: "callback failed", flags: map[string]string{"port": "80"}, constraint: ConstraintValidator},
+		{name: "type checked first", flags: map[string]string{"pid": "x"}, constraint: ConstraintType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := ValidateExpModel(context.Background(), action, &ExpModel{ActionFlags: tt.flags})
+			if tt.constraint == "" {
+				if response != nil {
+					t.Errorf("ValidateExpModel() = %s, want nil", response.Print())
+				}
+				return
+			}
+			if response == nil || response.Code != ParameterIllegal.Code {
+				t.Fatalf("ValidateExpModel() = %v, want ParameterIllegal", response)
+			}
+			if response.FieldErrors[0].Constraint != tt.constraint {
+				t.Errorf("Constraint = %s, want %s", response.FieldErrors[0].Constraint, tt.constraint)
+			}
+		})
+	}
+	if _, ok := GetFlagValidator("test-pid-exists"); !ok {
+		t.Errorf("GetFlagValidator() not found")
+	}
+}
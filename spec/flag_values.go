@@ -0,0 +1,78 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"strings"
+)
+
+// FlagValueSeparator joins the values of the repeated flag in ActionFlags for the executors reading the flag
+// as one string, it splits the value of ActionFlags if the flag is not in ActionFlagValues
+const FlagValueSeparator = ","
+
+// GetStringSliceFlag returns the values of the repeated flag, nil if the flag is absent. The value of ActionFlags
+// is split by FlagValueSeparator if the flag is not in ActionFlagValues, for the models created by the old clients.
+func (exp *ExpModel) GetStringSliceFlag(name string) []string {
+	if values, ok := exp.ActionFlagValues[name]; ok {
+		return values
+	}
+	value, ok := exp.flagValue(name)
+	if !ok {
+		return nil
+	}
+	values := make([]string, 0)
+	for _, v := range strings.Split(value, FlagValueSeparator) {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// GetIntSliceFlag returns the int values of the repeated flag, nil if the flag is absent
+func (exp *ExpModel) GetIntSliceFlag(name string) ([]int, error) {
+	values := exp.GetStringSliceFlag(name)
+	if values == nil {
+		return nil, nil
+	}
+	result := make([]int, 0, len(values))
+	for _, value := range values {
+		v, err := ParseFlagValue(&ExpFlag{Name: name, Type: FlagTypeInt}, strings.TrimSpace(value))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, v.(int))
+	}
+	return result, nil
+}
+
+// SetFlagValues sets the values of the repeated flag
+func (exp *ExpModel) SetFlagValues(name string, values []string) {
+	if exp.ActionFlags == nil {
+		exp.ActionFlags = make(map[string]string)
+	}
+	if exp.ActionFlagValues == nil {
+		exp.ActionFlagValues = make(map[string][]string)
+	}
+	exp.ActionFlagValues[name] = append([]string{}, values...)
+	exp.ActionFlags[name] = strings.Join(values, FlagValueSeparator)
+}
+
+// AddFlagValue appends the value to the repeated flag
+func (exp *ExpModel) AddFlagValue(name, value string) {
+	exp.SetFlagValues(name, append(exp.GetStringSliceFlag(name), value))
+}
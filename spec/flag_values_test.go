@@ -0,0 +1,121 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetStringSliceFlag(t *testing.T) {
+	tests := []struct {
+		name  string
+		flag  string
+		model *ExpModel
+		want  []string
+	}{
+		{name: "absent", flag: "exclude-port", model: &ExpModel{}},
+		{name: "comma joined", flag: "exclude-port", model: &ExpModel{ActionFlags: map[string]string{"exclude-port": "22, 80"}},
+			want: []string{"22", "80"}},
+		{name: "repeated", flag: "header", model: &ExpModel{
+			ActionFlags:      map[string]string{"header": "a,b,c"},
+			ActionFlagValues: map[string][]string{"header": {"a,b", "c"}},
+		}, want: []string{"a,b", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.model.GetStringSliceFlag(tt.flag); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetStringSliceFlag() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddFlagValue(t *testing.T) {
+	model := &ExpModel{}
+	model.AddFlagValue("exclude-port", "22")
+	model.AddFlagValue("exclude-port", "80")
+	if got := model.ActionFlags["exclude-port"]; got != "22,80" {
+		t.Errorf("ActionFlags = %s, want 22,80", got)
+	}
+	ports, err := model.GetIntSliceFlag("exclude-port")
+	if err != nil || !reflect.DeepEqual(ports, []int{22, 80}) {
+		t.Errorf("GetIntSliceFlag() = %v, %v, want [22 80]", ports, err)
+	}
+	model.AddFlagValue("exclude-port", "http")
+	if _, err := model.GetIntSliceFlag("exclude-port"); err == nil {
+		t.Errorf("GetIntSliceFlag() error = nil, want illegal value")
+	}
+}
+
+func TestValidateRepeatedFlag(t *testing.T) {
+	action := &ActionModel{ActionFlags: []ExpFlag{
+		{Name: "exclude-port", Type: FlagTypeInt, Repeated: true},
+		{Name: "interface"},
+	}}
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int32
+	}{
+		{name: "repeated", args: []string{"--exclude-port=22", "--exclude-port=80"}},
+		{name: "comma joined", args: []string{"--exclude-port=22,80"}},
+		{name: "illegal value", args: []string{"--exclude-port=22", "--exclude-port=http"}, wantCode: ParameterIllegal.Code},
+		{name: "not repeated", args: []string{"--interface=eth0", "--interface=eth1"}, wantCode: ParameterIllegal.Code},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := ArgsToModel("network", "drop", tt.args)
+			if err != nil {
+				t.Fatalf("ArgsToModel() error = %v", err)
+			}
+			response := ValidateExpModel(context.Background(), action, model)
+			if tt.wantCode == 0 {
+				if response != nil {
+					t.Errorf("ValidateExpModel() = %s, want nil", response.Print())
+				}
+				return
+			}
+			if response == nil || response.Code != tt.wantCode {
+				t.Errorf("ValidateExpModel() = %v, want code %d", response, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestRepeatedFlagSerialization(t *testing.T) {
+	model := &ExpModel{Target: "network", ActionName: "drop", SecretFlags: []string{"token"}}
+	model.SetFlagValues("header", []string{"a,b", "c"})
+	model.SetFlagValues("token", []string{"s3cret", "p@ss"})
+
+	parsed, err := ArgsToModel(model.Target, model.ActionName, ModelToArgs(model))
+	if err != nil || !reflect.DeepEqual(parsed.ActionFlagValues, model.ActionFlagValues) {
+		t.Errorf("ArgsToModel(ModelToArgs()) = %v, %v, want %v", parsed, err, model.ActionFlagValues)
+	}
+	decoded, err := UnmarshalExpModelProto(MarshalExpModelProto(model))
+	if err != nil || !reflect.DeepEqual(decoded.ActionFlagValues, model.ActionFlagValues) {
+		t.Errorf("UnmarshalExpModelProto() = %v, %v, want %v", decoded, err, model.ActionFlagValues)
+	}
+	if text := model.String(); strings.Contains(text, "s3cret") || strings.Contains(text, "p@ss") {
+		t.Errorf("String() = %s, want the secret values masked", text)
+	}
+	if text := model.MaskSecrets("curl -H p@ss"); text != "curl -H "+SecretMask {
+		t.Errorf("MaskSecrets() = %s, want the secret value masked", text)
+	}
+}
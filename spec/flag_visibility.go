@@ -0,0 +1,63 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+// The flag groups of the help, the flags without group are in FlagGroupDefault
+const (
+	FlagGroupDefault  = ""
+	FlagGroupAdvanced = "advanced"
+)
+
+// FlagHelpGroup is the flags of the same group in the help
+type FlagHelpGroup struct {
+	Name  string
+	Flags []ExpFlagSpec
+}
+
+// VisibleFlags returns the flags which are not hidden
+func VisibleFlags(flags []ExpFlagSpec) []ExpFlagSpec {
+	visible := make([]ExpFlagSpec, 0, len(flags))
+	for _, flag := range flags {
+		if !FlagHiddenOf(flag) {
+			visible = append(visible, flag)
+		}
+	}
+	return visible
+}
+
+// GroupFlags groups the flags for the help, the default group is the first and the others are in the order of
+// their first flags. The hidden flags are excluded unless showHidden, for example blade help --all.
+func GroupFlags(flags []ExpFlagSpec, showHidden bool) []FlagHelpGroup {
+	if !showHidden {
+		flags = VisibleFlags(flags)
+	}
+	groups := []FlagHelpGroup{{Name: FlagGroupDefault}}
+	indexes := map[string]int{FlagGroupDefault: 0}
+	for _, flag := range flags {
+		idx, ok := indexes[FlagGroupOf(flag)]
+		if !ok {
+			idx = len(groups)
+			indexes[FlagGroupOf(flag)] = idx
+			groups = append(groups, FlagHelpGroup{Name: FlagGroupOf(flag)})
+		}
+		groups[idx].Flags = append(groups[idx].Flags, flag)
+	}
+	if len(groups[0].Flags) == 0 {
+		groups = groups[1:]
+	}
+	return groups
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroupFlags(t *testing.T) {
+	flags := []ExpFlagSpec{
+		&ExpFlag{Name: "debug", Group: "debug", Hidden: true},
+		&ExpFlag{Name: "cgroup-root", Group: FlagGroupAdvanced},
+		&ExpFlag{Name: "cpu-percent"},
+		&ExpFlag{Name: "cpu-index", Group: FlagGroupAdvanced},
+		&ExpFlag{Name: "timeout"},
+	}
+	names := func(groups []FlagHelpGroup) map[string][]string {
+		result := make(map[string][]string)
+		order := make([]string, 0)
+		for _, group := range groups {
+			order = append(order, group.Name)
+			for _, flag := range group.Flags {
+				result[group.Name] = append(result[group.Name], flag.FlagName())
+			}
+		}
+		result["order"] = order
+		return result
+	}
+	tests := []struct {
+		name       string
+		flags      []ExpFlagSpec
+		showHidden bool
+		want       map[string][]string
+	}{
+		{name: "default", flags: flags, want: map[string][]string{
+			"order":           {FlagGroupDefault, FlagGroupAdvanced},
+			FlagGroupDefault:  {"cpu-percent", "timeout"},
+			FlagGroupAdvanced: {"cgroup-root", "cpu-index"},
+		}},
+		{name: "show hidden", flags: flags, showHidden: true, want: map[string][]string{
+			"order":           {FlagGroupDefault, "debug", FlagGroupAdvanced},
+			FlagGroupDefault:  {"cpu-percent", "timeout"},
+			"debug":           {"debug"},
+			FlagGroupAdvanced: {"cgroup-root", "cpu-index"},
+		}},
+		{name: "no default group", flags: flags[:2], want: map[string][]string{
+			"order":           {FlagGroupAdvanced},
+			FlagGroupAdvanced: {"cgroup-root"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := names(GroupFlags(tt.flags, tt.showHidden)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GroupFlags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActionJSONSchemaHiddenFlag(t *testing.T) {
+	action := &ActionModel{ActionName: "fullload", ActionFlags: []ExpFlag{
+		{Name: "cpu-percent"},
+		{Name: "debug", Hidden: true},
+	}}
+	schema := ActionJSONSchema(&ExpCommandModel{ExpName: "cpu"}, action)
+	if _, ok := schema.Properties["debug"]; ok {
+		t.Errorf("ActionJSONSchema() contains the hidden flag")
+	}
+	if _, ok := schema.Properties["cpu-percent"]; !ok {
+		t.Errorf("ActionJSONSchema() does not contain the visible flag")
+	}
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+var (
+	// httpStatuses contains the codes whose http status differs from the status of their code range
+	httpStatuses = map[int32]int{
+		Forbidden.Code:                         http.StatusForbidden,
+		ActionNotSupport.Code:                  http.StatusBadRequest,
+		ParameterInvalidProName.Code:           http.StatusNotFound,
+		ParameterInvalidDbQuery.Code:           http.StatusNotFound,
+		ParameterInvalidK8sPodQuery.Code:       http.StatusNotFound,
+		ParameterInvalidK8sNodeQuery.Code:      http.StatusNotFound,
+		ParameterInvalidDockContainerId.Code:   http.StatusNotFound,
+		ParameterInvalidDockContainerName.Code: http.StatusNotFound,
+		CommandRejectedByPolicy.Code:           http.StatusForbidden,
+		PreconditionNotSatisfied.Code:          http.StatusPreconditionFailed,
+		ChaosbladeServerStarted.Code:           http.StatusConflict,
+		UnexpectedStatus.Code:                  http.StatusConflict,
+		ContainerInContextNotFound.Code:        http.StatusNotFound,
+		PodNotReady.Code:                       http.StatusServiceUnavailable,
+		ChaosbladeServiceStoped.Code:           http.StatusServiceUnavailable,
+		ProcessNotExist.Code:                   http.StatusNotFound,
+		FileNotExist.Code:                      http.StatusNotFound,
+		BackfileExists.Code:                    http.StatusConflict,
+		ExecTimeout.Code:                       http.StatusGatewayTimeout,
+		ConcurrencyLimitExceeded.Code:          http.StatusTooManyRequests,
+		DataNotFound.Code:                      http.StatusNotFound,
+	}
+	httpStatusLock sync.RWMutex
+)
+
+// RegisterHTTPStatus sets the http status of the code, it is used by the plugins to map their custom codes
+func RegisterHTTPStatus(code int32, status int) error {
+	if status < 100 || status > 599 {
+		return fmt.Errorf("illegal http status %d of code %d", status, code)
+	}
+	httpStatusLock.Lock()
+	defer httpStatusLock.Unlock()
+	httpStatuses[code] = status
+	return nil
+}
+
+// HTTPStatus returns the http status of the response code. The success codes map to 200, the parameter
+// codes map to 400, the codes of missing experiments, processes, files or containers map to 404 and
+// the others, such as the execution failures, map to 500.
+func HTTPStatus(code int32) int {
+	httpStatusLock.RLock()
+	status, ok := httpStatuses[code]
+	httpStatusLock.RUnlock()
+	if ok {
+		return status
+	}
+	switch {
+	case code < 300:
+		return http.StatusOK
+	case code >= ParameterLess.Code && code < ChaosbladeFileNotFound.Code:
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// HTTPStatus returns the http status of the response
+func (response *Response) HTTPStatus() int {
+	return HTTPStatus(response.Code)
+}
+
+// CodesForHTTPStatus returns the registered codes mapped to the http status ordered by code,
+// it is the reverse mapping of HTTPStatus.
+func CodesForHTTPStatus(status int) []CodeType {
+	codes := make([]CodeType, 0)
+	for _, registered := range RegisteredCodes() {
+		if HTTPStatus(registered.Code) == status {
+			codes = append(codes, registered.CodeType)
+		}
+	}
+	return codes
+}
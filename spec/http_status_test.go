@@ -0,0 +1,81 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		codeType CodeType
+		want     int
+	}{
+		{OK, http.StatusOK},
+		{ReturnOKDirectly, http.StatusOK},
+		{IgnoreCode, http.StatusOK},
+		{Forbidden, http.StatusForbidden},
+		{ParameterLess, http.StatusBadRequest},
+		{ParameterIllegal, http.StatusBadRequest},
+		{CommandIllegal, http.StatusBadRequest},
+		{ParameterInvalidK8sPodQuery, http.StatusNotFound},
+		{FileNotExist, http.StatusNotFound},
+		{DataNotFound, http.StatusNotFound},
+		{UnexpectedStatus, http.StatusConflict},
+		{ExecTimeout, http.StatusGatewayTimeout},
+		{OsCmdExecFailed, http.StatusInternalServerError},
+		{CommandTcNotFound, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		if got := HTTPStatus(tt.codeType.Code); got != tt.want {
+			t.Errorf("HTTPStatus(%d) = %d, want %d", tt.codeType.Code, got, tt.want)
+		}
+	}
+	if got := ResponseFailWithFlags(ParameterLess, "pid").HTTPStatus(); got != http.StatusBadRequest {
+		t.Errorf("Response.HTTPStatus() = %d, want %d", got, http.StatusBadRequest)
+	}
+}
+
+func TestRegisterHTTPStatus(t *testing.T) {
+	codeType, err := RegisterResponseCode(91101, "RedisKeyNotFound", "`%s`: redis key not found")
+	if err != nil {
+		t.Fatalf("RegisterResponseCode() error = %v", err)
+	}
+	if got := HTTPStatus(codeType.Code); got != http.StatusInternalServerError {
+		t.Errorf("HTTPStatus() = %d, want %d", got, http.StatusInternalServerError)
+	}
+	if err := RegisterHTTPStatus(codeType.Code, 999); err == nil {
+		t.Errorf("RegisterHTTPStatus() expected illegal status error")
+	}
+	if err := RegisterHTTPStatus(codeType.Code, http.StatusNotFound); err != nil {
+		t.Fatalf("RegisterHTTPStatus() error = %v", err)
+	}
+	if got := HTTPStatus(codeType.Code); got != http.StatusNotFound {
+		t.Errorf("HTTPStatus() = %d, want %d", got, http.StatusNotFound)
+	}
+	found := false
+	for _, code := range CodesForHTTPStatus(http.StatusNotFound) {
+		if HTTPStatus(code.Code) != http.StatusNotFound {
+			t.Errorf("CodesForHTTPStatus() contains %d mapped to %d", code.Code, HTTPStatus(code.Code))
+		}
+		found = found || code == codeType
+	}
+	if !found {
+		t.Errorf("CodesForHTTPStatus() does not contain the registered code %d", codeType.Code)
+	}
+}
@@ -0,0 +1,164 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	LocaleEnUS = "en-US"
+	LocaleZhCN = "zh-CN"
+
+	// LocaleKey is the context key of the locale of the response messages
+	LocaleKey ContextKey = "locale"
+)
+
+var (
+	locale      = LocaleEnUS
+	catalog     = map[string]map[int32]string{LocaleZhCN: zhCNMessages}
+	catalogLock sync.RWMutex
+)
+
+func init() {
+	if l := os.Getenv(LocaleEnv); l != "" {
+		SetLocale(l)
+	}
+}
+
+// SetLocale sets the default locale of the response messages, such as zh-CN or zh_CN
+func SetLocale(l string) {
+	catalogLock.Lock()
+	defer catalogLock.Unlock()
+	locale = normalizeLocale(l)
+}
+
+// GetLocale returns the locale in ctx, or the default locale if ctx does not contain it
+func GetLocale(ctx context.Context) string {
+	if l, ok := ctx.Value(LocaleKey).(string); ok && l != "" {
+		return normalizeLocale(l)
+	}
+	return defaultLocale()
+}
+
+func defaultLocale() string {
+	catalogLock.RLock()
+	defer catalogLock.RUnlock()
+	return locale
+}
+
+// WithLocale returns a copy of ctx with the locale of the response messages
+func WithLocale(ctx context.Context, l string) context.Context {
+	return context.WithValue(ctx, LocaleKey, l)
+}
+
+// RegisterMessages adds the message templates of the codes for the locale, the templates must keep the same
+// format verbs as the en-US messages. The en-US messages are the CodeType Msg, use RegisterMessageTemplate
+// to override them.
+func RegisterMessages(l string, messages map[int32]string) {
+	l = normalizeLocale(l)
+	catalogLock.Lock()
+	defer catalogLock.Unlock()
+	if _, ok := catalog[l]; !ok {
+		catalog[l] = make(map[int32]string)
+	}
+	for code, msg := range messages {
+		catalog[l][code] = msg
+	}
+}
+
+// Localize returns the message template of the code in the locale, or the en-US message if not found
+func (c CodeType) Localize(l string) string {
+	l = normalizeLocale(l)
+	if l == LocaleEnUS {
+		return c.Msg
+	}
+	catalogLock.RLock()
+	defer catalogLock.RUnlock()
+	if msg, ok := catalog[l][c.Code]; ok {
+		return msg
+	}
+	return c.Msg
+}
+
+func (c CodeType) localMsg() string {
+	return c.Localize(defaultLocale())
+}
+
+// ResponseFailWithContext is like ResponseFailWithFlags, but the message is localized by the locale in ctx
+func ResponseFailWithContext(ctx context.Context, codeType CodeType, flags ...interface{}) *Response {
+	return (&Response{Code: codeType.Code, Success: false, Err: codeType.renderFlags(GetLocale(ctx), flags)}).classify()
+}
+
+// normalizeLocale converts the locale to the language-REGION format, such as zh_CN.UTF-8 to zh-CN
+func normalizeLocale(l string) string {
+	l = strings.TrimSpace(l)
+	if idx := strings.IndexAny(l, ".@"); idx >= 0 {
+		l = l[:idx]
+	}
+	parts := strings.Split(strings.ReplaceAll(l, "_", "-"), "-")
+	if len(parts) != 2 {
+		return LocaleEnUS
+	}
+	return strings.ToLower(parts[0]) + "-" + strings.ToUpper(parts[1])
+}
+
+var zhCNMessages = map[int32]string{
+	OK.Code:                             "成功",
+	Forbidden.Code:                      "禁止访问：必须使用 root 用户",
+	ActionNotSupport.Code:               "`%s`：不支持该操作",
+	ParameterLess.Code:                  "缺少参数：`%s`",
+	ParameterLessOneOf.Code:             "缺少参数，`%s` 中必须指定一个",
+	ParameterConflict.Code:              "参数冲突，`%s` 只能指定一个",
+	ParameterLessWith.Code:              "缺少参数：指定 `%[2]s` 时必须指定 `%[1]s`",
+	ParameterForbidden.Code:             "禁止的参数：`%[2]s` 时不允许指定 `%[1]s`",
+	ParameterIllegal.Code:               "`%s` 参数值非法：`%s`。%v",
+	ParameterInvalid.Code:               "`%s` 参数值无效：`%s`。%v",
+	ParameterInvalidProName.Code:        "参数 `%s` 无效，未找到 `%s` 进程",
+	ParameterInvalidTooManyProcess.Code: "参数 process 无效，找到的 `%s` 进程过多",
+	ParameterRequestFailed.Code:         "获取请求参数失败",
+	CommandIllegal.Code:                 "非法命令，错误：%v",
+	CommandRejectedByPolicy.Code:        "`%s`：命令被策略拒绝，%v",
+	ChaosbladeFileNotFound.Code:         "`%s`：未找到 chaosblade 文件",
+	EnvironmentNotSatisfied.Code:        "环境不满足要求，缺少 %s",
+	PreconditionNotSatisfied.Code:       "前置条件不满足：%s",
+	ChaosbladeServerStarted.Code:        "chaosblade 已启动，如需停止请执行 blade server stop 命令",
+	UnexpectedStatus.Code:               "非预期的状态，期望状态：`%s`，实际状态：`%s`，请稍候！",
+	ResultUnmarshalFailed.Code:          "`%s`：执行结果反序列化失败，错误：%v",
+	ResultMarshalFailed.Code:            "`%v`：执行结果序列化失败，错误：%v",
+	GenerateUidFailed.Code:              "生成实验 uid 失败，错误：%v",
+	ChaosbladeServiceStoped.Code:        "chaosblade 服务已停止",
+	ProcessIdByNameFailed.Code:          "`%s`：根据名称获取进程 id 失败，错误：%v",
+	ProcessJudgeExistFailed.Code:        "`%s`：判断进程是否存在失败，错误：%v",
+	ProcessNotExist.Code:                "`%s`：进程不存在",
+	ProcessGetUsernameFailed.Code:       "`%s`：根据进程 id 获取用户名失败，错误：%v",
+	ChannelNil.Code:                     "channel 为空",
+	FileCantGetLogFile.Code:             "无法获取日志文件",
+	FileNotExist.Code:                   "`%s`：不存在",
+	FileCantReadOrOpen.Code:             "`%s`：无法读取或打开",
+	BackfileExists.Code:                 "`%s`：备份文件已存在，可能有其他实验正在运行",
+	OsCmdExecFailed.Code:                "`%s`：命令执行失败，错误：%v",
+	OsExecutorNotFound.Code:             "`%s`：未找到 os 执行器",
+	ExecutorPanic.Code:                  "`%s`：执行器异常，错误：%v",
+	ExecTimeout.Code:                    "`%s`：执行超时，超时时间：%v",
+	ExecCanceled.Code:                   "`%s`：执行已取消",
+	ConcurrencyLimitExceeded.Code:       "`%s`：超过并发上限 %d，运行中的实验：%v",
+	DataNotFound.Code:                   "未找到 `%s` 记录，如果是 k8s 实验，请添加 --target k8s 参数重试",
+}
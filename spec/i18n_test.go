@@ -0,0 +1,41 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"testing"
+)
+
+func TestResponseFailWithContext(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   string
+	}{
+		{locale: "", want: "less parameter: `pid`"},
+		{locale: "zh_CN.UTF-8", want: "缺少参数：`pid`"},
+		{locale: "ja-JP", want: "less parameter: `pid`"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			response := ResponseFailWithContext(WithLocale(context.Background(), tt.locale), ParameterLess, "pid")
+			if response.Err != tt.want || response.Code != ParameterLess.Code {
+				t.Errorf("ResponseFailWithContext() = %s, want %s", response.Err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// The default bounds of the memory IdempotencyStore
+const (
+	DefaultIdempotencyTTL        = 24 * time.Hour
+	DefaultIdempotencyMaxEntries = 10000
+)
+
+// IdempotencyStore saves the responses of the created experiments by the idempotency keys
+type IdempotencyStore interface {
+	// Get returns the saved response of the key, the caller may mutate it without affecting the saved one
+	Get(key string) (*Response, bool)
+
+	// Put saves the response of the key, the later mutations of the response don't affect the saved one
+	Put(key string, response *Response)
+
+	// Delete removes the response of the key, it's invoked after the experiment destroyed
+	Delete(key string)
+}
+
+// IdempotencyStoreOption customizes the memory IdempotencyStore
+type IdempotencyStoreOption func(store *memoryIdempotencyStore)
+
+// IdempotencyTTL sets how long the response is kept, the key can be created again after it expired
+func IdempotencyTTL(ttl time.Duration) IdempotencyStoreOption {
+	return func(store *memoryIdempotencyStore) {
+		store.ttl = ttl
+	}
+}
+
+// IdempotencyMaxEntries sets the max number of the responses kept, the oldest one is evicted if exceeded
+func IdempotencyMaxEntries(maxEntries int) IdempotencyStoreOption {
+	return func(store *memoryIdempotencyStore) {
+		store.maxEntries = maxEntries
+	}
+}
+
+// memoryIdempotencyStore is the IdempotencyStore in memory
+type memoryIdempotencyStore struct {
+	lock       sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	responses  map[string]idempotencyEntry
+	now        func() time.Time
+}
+
+type idempotencyEntry struct {
+	response *Response
+	created  time.Time
+}
+
+// NewMemoryIdempotencyStore returns the IdempotencyStore in memory, the responses are kept for
+// DefaultIdempotencyTTL and at most DefaultIdempotencyMaxEntries responses are kept by default
+func NewMemoryIdempotencyStore(options ...IdempotencyStoreOption) IdempotencyStore {
+	store := &memoryIdempotencyStore{
+		ttl:        DefaultIdempotencyTTL,
+		maxEntries: DefaultIdempotencyMaxEntries,
+		responses:  make(map[string]idempotencyEntry),
+		now:        time.Now,
+	}
+	for _, option := range options {
+		option(store)
+	}
+	return store
+}
+
+func (s *memoryIdempotencyStore) Get(key string) (*Response, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	entry, ok := s.responses[key]
+	if !ok {
+		return nil, false
+	}
+	if s.expired(entry) {
+		delete(s.responses, key)
+		return nil, false
+	}
+	return entry.response.Clone(), true
+}
+
+func (s *memoryIdempotencyStore) Put(key string, response *Response) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for k, entry := range s.responses {
+		if s.expired(entry) {
+			delete(s.responses, k)
+		}
+	}
+	if _, exists := s.responses[key]; !exists && s.maxEntries > 0 {
+		for len(s.responses) >= s.maxEntries {
+			s.evictOldest()
+		}
+	}
+	s.responses[key] = idempotencyEntry{response: response.Clone(), created: s.now()}
+}
+
+func (s *memoryIdempotencyStore) Delete(key string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.responses, key)
+}
+
+func (s *memoryIdempotencyStore) expired(entry idempotencyEntry) bool {
+	return s.ttl > 0 && s.now().Sub(entry.created) > s.ttl
+}
+
+func (s *memoryIdempotencyStore) evictOldest() {
+	oldestKey, oldest := "", time.Time{}
+	for key, entry := range s.responses {
+		if oldestKey == "" || entry.created.Before(oldest) {
+			oldestKey, oldest = key, entry.created
+		}
+	}
+	delete(s.responses, oldestKey)
+}
+
+// keyedMutex locks by the key, the lock of the key is released when no one holds or waits for it
+type keyedMutex struct {
+	lock  sync.Mutex
+	locks map[string]*refMutex
+}
+
+type refMutex struct {
+	sync.Mutex
+	refs int
+}
+
+func (m *keyedMutex) Lock(key string) func() {
+	m.lock.Lock()
+	if m.locks == nil {
+		m.locks = make(map[string]*refMutex)
+	}
+	mutex, ok := m.locks[key]
+	if !ok {
+		mutex = &refMutex{}
+		m.locks[key] = mutex
+	}
+	mutex.refs++
+	m.lock.Unlock()
+
+	mutex.Lock()
+	return func() {
+		mutex.Unlock()
+		m.lock.Lock()
+		defer m.lock.Unlock()
+		mutex.refs--
+		if mutex.refs == 0 {
+			delete(m.locks, key)
+		}
+	}
+}
+
+// IdempotencyMiddleware returns the middleware which returns the prior response if the create request with
+// the same idempotency key or uid succeeded, so the retries of the controllers don't inject twice.
+// The failed responses are not saved, and the concurrent requests with the same key are executed one by one.
+// The response is removed after the experiment destroyed successfully, so the key can be created again.
+func IdempotencyMiddleware(store IdempotencyStore) ExecutorMiddleware {
+	var locks keyedMutex
+	// keys are the idempotency keys of the created experiments by the uid, the destroy request carries the uid
+	var keys sync.Map
+	return ExecutorMiddlewareFunc(func(next ExecFunc) ExecFunc {
+		return func(uid string, ctx context.Context, model *ExpModel) *Response {
+			if destroyUid, isDestroy := IsDestroy(ctx); isDestroy {
+				if destroyUid == "" {
+					destroyUid = uid
+				}
+				response := next(uid, ctx, model)
+				if response != nil && response.Success {
+					key := model.IdempotencyKey
+					if value, ok := keys.LoadAndDelete(destroyUid); ok && key == "" {
+						key = value.(string)
+					}
+					if key == "" {
+						key = destroyUid
+					}
+					unlock := locks.Lock(key)
+					store.Delete(key)
+					unlock()
+				}
+				return response
+			}
+			key := model.IdempotencyKey
+			if key == "" {
+				key = uid
+			}
+			if key == "" {
+				return next(uid, ctx, model)
+			}
+			unlock := locks.Lock(key)
+			defer unlock()
+			if response, ok := store.Get(key); ok {
+				return response.Clone()
+			}
+			response := next(uid, ctx, model)
+			if response != nil && response.Success {
+				store.Put(key, response.Clone())
+				if uid != "" && uid != key {
+					keys.Store(uid, key)
+				}
+			}
+			return response
+		}
+	})
+}
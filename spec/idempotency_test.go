@@ -0,0 +1,121 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	count := 0
+	executor := &testExecutor{exec: func(uid string, ctx context.Context, model *ExpModel) *Response {
+		count++
+		if model.ActionFlags["fail"] == "true" {
+			return ResponseFailWithFlags(ParameterLess, "fail")
+		}
+		return ReturnSuccess(uid)
+	}}
+	wrapped := WithMiddlewares(executor, IdempotencyMiddleware(NewMemoryIdempotencyStore()))
+	model := &ExpModel{IdempotencyKey: "token"}
+	first := wrapped.Exec("uid-1", context.Background(), model)
+	second := wrapped.Exec("uid-2", context.Background(), model)
+	if count != 1 || !reflect.DeepEqual(second, first) {
+		t.Errorf("Exec() count = %d, second = %v, want the prior response", count, second)
+	}
+	// the callers mutate the returned responses, such as adding the metadata and the warnings
+	first.SetMetadata("host", "a").AddWarnings(Warning{Name: "first"})
+	second.SetMetadata("host", "b")
+	third := wrapped.Exec("uid-2", context.Background(), model)
+	if third.Metadata["host"] != nil || len(third.Warnings) != 0 {
+		t.Errorf("Exec() = %v, want the saved response not mutated by the callers", third)
+	}
+	failed := &ExpModel{ActionFlags: map[string]string{"fail": "true"}}
+	wrapped.Exec("uid-3", context.Background(), failed)
+	wrapped.Exec("uid-3", context.Background(), failed)
+	if count != 3 {
+		t.Errorf("Exec() count = %d, want failed responses not saved", count)
+	}
+	wrapped.Exec("uid-1", SetDestroyFlag(context.Background(), "uid-1"), model)
+	if count != 4 {
+		t.Errorf("Exec() count = %d, want destroy executed", count)
+	}
+	// the key is removed after destroyed, so the experiment is created again
+	wrapped.Exec("uid-5", context.Background(), model)
+	if count != 5 {
+		t.Errorf("Exec() count = %d, want created again after destroyed", count)
+	}
+}
+
+func TestMemoryIdempotencyStore(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryIdempotencyStore(IdempotencyTTL(time.Hour), IdempotencyMaxEntries(2)).(*memoryIdempotencyStore)
+	store.now = func() time.Time { return now }
+	store.Put("a", Success())
+	now = now.Add(time.Minute)
+	store.Put("b", Success())
+	now = now.Add(time.Minute)
+	store.Put("c", Success())
+	if _, ok := store.Get("a"); ok {
+		t.Errorf("the oldest response is not evicted")
+	}
+	if _, ok := store.Get("c"); !ok {
+		t.Errorf("the response of c is not saved")
+	}
+	now = now.Add(2 * time.Hour)
+	if _, ok := store.Get("b"); ok {
+		t.Errorf("the expired response is returned")
+	}
+	store.Put("d", Success())
+	if len(store.responses) != 1 {
+		t.Errorf("the expired responses are not evicted, %d left", len(store.responses))
+	}
+	saved := Success().SetMetadata("host", "a")
+	store.Put("d", saved)
+	saved.SetMetadata("host", "b").AddWarnings(Warning{Name: "put"})
+	got, _ := store.Get("d")
+	got.SetMetadata("host", "c")
+	if got, _ = store.Get("d"); got.Metadata["host"] != "a" || len(got.Warnings) != 0 {
+		t.Errorf("Get() = %v, want the saved response not shared", got)
+	}
+	store.Delete("d")
+	if _, ok := store.Get("d"); ok {
+		t.Errorf("the deleted response is returned")
+	}
+}
+
+func TestKeyedMutex(t *testing.T) {
+	var locks keyedMutex
+	unlock := locks.Lock("a")
+	acquired := make(chan struct{})
+	go func() {
+		locks.Lock("a")()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatalf("the lock of the same key is acquired twice")
+	case <-time.After(20 * time.Millisecond):
+	}
+	unlock()
+	<-acquired
+	if len(locks.locks) != 0 {
+		t.Errorf("the released locks are kept, %d left", len(locks.locks))
+	}
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+// JSONSchemaDraft is the JSON Schema version of the action schema
+const JSONSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// ActionJSONSchema returns the JSON Schema of the action flags, including the target flags and the action matchers.
+// The property types, enums, defaults and validation rules come from the flag specs and the flag groups
+// of the target are converted to the schema combinations, so web UIs can render and validate the experiment form.
+// The hidden flags are not in the properties, but are allowed by the schema.
+func ActionJSONSchema(target ExpModelCommandSpec, action ExpActionCommandSpec) *JSONSchema {
+	schema := &JSONSchema{
+		Schema:      JSONSchemaDraft,
+		Title:       target.Name() + " " + action.Name(),
+		Type:        "object",
+		Description: action.ShortDesc(),
+		Deprecated:  ActionDeprecatedOf(action) != "",
+		Properties:  make(map[string]*JSONSchema),
+	}
+	for _, flag := range VisibleFlags(actionFlagSpecs(target.Flags(), action)) {
+		schema.Properties[flag.FlagName()] = flagSchema(flag)
+		if flag.FlagRequired() {
+			schema.Required = append(schema.Required, flag.FlagName())
+		}
+	}
+	for _, group := range target.FlagGroups() {
+		addFlagGroupSchema(schema, group)
+	}
+	return schema
+}
+
+// addFlagGroupSchema converts the flag group constraint to the schema
+func addFlagGroupSchema(schema *JSONSchema, group FlagGroup) {
+	if len(group.Flags) < 2 {
+		return
+	}
+	if schema.Dependencies == nil {
+		schema.Dependencies = make(map[string]interface{})
+	}
+	switch group.Type {
+	case FlagGroupExactlyOne:
+		oneOf := make([]*JSONSchema, 0)
+		for _, name := range group.Flags {
+			oneOf = append(oneOf, &JSONSchema{Required: []string{name}})
+		}
+		schema.AllOf = append(schema.AllOf, &JSONSchema{OneOf: oneOf})
+	case FlagGroupMutuallyExclusive:
+		for _, name := range group.Flags {
+			others := make([]*JSONSchema, 0)
+			for _, other := range otherFlags(group.Flags, name) {
+				others = append(others, &JSONSchema{Required: []string{other}})
+			}
+			addDependency(schema, name, &JSONSchema{Not: &JSONSchema{AnyOf: others}})
+		}
+	case FlagGroupRequiredTogether:
+		for _, name := range group.Flags {
+			addDependency(schema, name, otherFlags(group.Flags, name))
+		}
+	case FlagGroupRequires:
+		addDependency(schema, group.Flags[0], group.Flags[1:])
+	}
+}
+
+// addDependency adds the dependency of the flag, the dependencies of the flag in several groups are merged by allOf
+func addDependency(schema *JSONSchema, name string, dependency interface{}) {
+	existing, ok := schema.Dependencies[name]
+	if !ok {
+		schema.Dependencies[name] = dependency
+		return
+	}
+	merged, ok := existing.(*JSONSchema)
+	if !ok || !isAllOfSchema(merged) {
+		merged = &JSONSchema{AllOf: []*JSONSchema{dependencySchema(existing)}}
+	}
+	merged.AllOf = append(merged.AllOf, dependencySchema(dependency))
+	schema.Dependencies[name] = merged
+}
+
+// dependencySchema returns the schema form of the dependency, the property names are converted to required
+func dependencySchema(dependency interface{}) *JSONSchema {
+	if names, ok := dependency.([]string); ok {
+		return &JSONSchema{Required: names}
+	}
+	return dependency.(*JSONSchema)
+}
+
+// isAllOfSchema returns true if the schema is the allOf merged by addDependency
+func isAllOfSchema(schema *JSONSchema) bool {
+	return len(schema.AllOf) > 0 && schema.Not == nil && len(schema.Required) == 0 && len(schema.OneOf) == 0 &&
+		len(schema.AnyOf) == 0
+}
+
+func otherFlags(flags []string, name string) []string {
+	others := make([]string, 0)
+	for _, flag := range flags {
+		if flag != name {
+			others = append(others, flag)
+		}
+	}
+	return others
+}
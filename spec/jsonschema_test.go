@@ -0,0 +1,86 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestActionJSONSchema(t *testing.T) {
+	target := &ExpCommandModel{
+		ExpName:  "process",
+		ExpFlags: []ExpFlag{{Name: "timeout", Type: FlagTypeInt}},
+		ExpFlagGroups: []FlagGroup{
+			{Type: FlagGroupExactlyOne, Flags: []string{"pid", "process"}},
+			{Type: FlagGroupRequires, Flags: []string{"local-port", "protocol"}},
+		},
+	}
+	action := &ActionModel{
+		ActionName: "kill",
+		ActionFlags: []ExpFlag{
+			{Name: "signal", Type: FlagTypeEnum, EnumValues: []string{"9", "15"}, Required: true},
+			{Name: "pid"}, {Name: "process"}, {Name: "local-port"}, {Name: "protocol"},
+		},
+	}
+	schema := ActionJSONSchema(target, action)
+	if schema.Title != "process kill" || len(schema.Properties) != 6 {
+		t.Errorf("ActionJSONSchema() = %+v", schema)
+	}
+	if !reflect.DeepEqual(schema.Required, []string{"signal"}) {
+		t.Errorf("required = %v, want [signal]", schema.Required)
+	}
+	if schema.Properties["timeout"].Type != "integer" {
+		t.Errorf("timeout type = %s, want integer", schema.Properties["timeout"].Type)
+	}
+	if len(schema.AllOf) != 1 || len(schema.AllOf[0].OneOf) != 2 {
+		t.Errorf("allOf = %v, want the exactlyOne group", schema.AllOf)
+	}
+	if !reflect.DeepEqual(schema.Dependencies["local-port"], []string{"protocol"}) {
+		t.Errorf("dependencies = %v", schema.Dependencies)
+	}
+	bytes, err := json.Marshal(schema)
+	if err != nil || !strings.Contains(string(bytes), `"$schema":"`+JSONSchemaDraft+`"`) {
+		t.Errorf("json.Marshal() = %s, %v", bytes, err)
+	}
+}
+
+func TestActionJSONSchemaMergedDependencies(t *testing.T) {
+	target := &ExpCommandModel{
+		ExpName: "network",
+		ExpFlagGroups: []FlagGroup{
+			{Type: FlagGroupRequires, Flags: []string{"local-port", "protocol"}},
+			{Type: FlagGroupMutuallyExclusive, Flags: []string{"local-port", "remote-port"}},
+			{Type: FlagGroupRequiredTogether, Flags: []string{"local-port", "interface"}},
+		},
+	}
+	action := &ActionModel{ActionName: "delay"}
+	schema := ActionJSONSchema(target, action)
+	dependency, ok := schema.Dependencies["local-port"].(*JSONSchema)
+	if !ok || len(dependency.AllOf) != 3 {
+		t.Fatalf("dependencies[local-port] = %+v, want allOf of 3 groups", schema.Dependencies["local-port"])
+	}
+	if !reflect.DeepEqual(dependency.AllOf[0].Required, []string{"protocol"}) || dependency.AllOf[1].Not == nil ||
+		!reflect.DeepEqual(dependency.AllOf[2].Required, []string{"interface"}) {
+		t.Errorf("dependencies[local-port] allOf = %+v", dependency.AllOf)
+	}
+	if !reflect.DeepEqual(schema.Dependencies["interface"], []string{"local-port"}) {
+		t.Errorf("dependencies[interface] = %v", schema.Dependencies["interface"])
+	}
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+// SetLabel sets the label of the model, the labels are kept by the serialization and visible to the hooks
+func (exp *ExpModel) SetLabel(key, value string) {
+	if exp.Labels == nil {
+		exp.Labels = make(map[string]string)
+	}
+	exp.Labels[key] = value
+}
+
+// SetAnnotation sets the annotation of the model
+func (exp *ExpModel) SetAnnotation(key, value string) {
+	if exp.Annotations == nil {
+		exp.Annotations = make(map[string]string)
+	}
+	exp.Annotations[key] = value
+}
+
+// MatchLabels returns true if the model has all the labels of the selector, the empty selector matches all
+func (exp *ExpModel) MatchLabels(selector map[string]string) bool {
+	for key, value := range selector {
+		if v, ok := exp.Labels[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+func copyStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	copied := make(map[string]string, len(m))
+	for key, value := range m {
+		copied[key] = value
+	}
+	return copied
+}
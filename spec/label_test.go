@@ -0,0 +1,78 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestExpModelLabels(t *testing.T) {
+	model, err := NewExpModelBuilder().Target("cpu").Action("fullload").
+		Label("team", "payment").Label("env", "staging").
+		Annotation("owner", "alice").Annotation("ticket", "CHAOS-42").Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if _, err := NewExpModelBuilder().Target("cpu").Action("fullload").Label(" ", "x").Build(); err == nil {
+		t.Errorf("Build() error = nil, want the empty label key error")
+	}
+
+	var fromJSON ExpModel
+	bytes, _ := json.Marshal(model)
+	if err := json.Unmarshal(bytes, &fromJSON); err != nil || !reflect.DeepEqual(fromJSON.Labels, model.Labels) ||
+		!reflect.DeepEqual(fromJSON.Annotations, model.Annotations) {
+		t.Errorf("json round trip = %v, %v, want %v", fromJSON, err, model)
+	}
+	fromProto, err := UnmarshalExpModelProto(MarshalExpModelProto(model))
+	if err != nil || !reflect.DeepEqual(fromProto.Labels, model.Labels) || !reflect.DeepEqual(fromProto.Annotations, model.Annotations) {
+		t.Errorf("proto round trip = %v, %v, want %v", fromProto, err, model)
+	}
+
+	tests := []struct {
+		selector map[string]string
+		want     bool
+	}{
+		{selector: nil, want: true},
+		{selector: map[string]string{"team": "payment"}, want: true},
+		{selector: map[string]string{"team": "payment", "env": "prod"}, want: false},
+		{selector: map[string]string{"owner": "alice"}, want: false},
+	}
+	for _, tt := range tests {
+		if got := model.MatchLabels(tt.selector); got != tt.want {
+			t.Errorf("MatchLabels(%v) = %v, want %v", tt.selector, got, tt.want)
+		}
+	}
+}
+
+func TestExpModelLabelsInHooks(t *testing.T) {
+	defer ResetExecHooks()
+	var owner string
+	RegisterPostExecHook(func(uid string, ctx context.Context, model *ExpModel, response *Response) {
+		owner = model.Annotations["owner"]
+	})
+	model := &ExpModel{Target: "cpu", ActionName: "fullload"}
+	model.SetAnnotation("owner", "alice")
+	ExecExperiment(&testExecutor{exec: func(uid string, ctx context.Context, model *ExpModel) *Response {
+		return Success()
+	}}, "uid", context.Background(), model)
+	if owner != "alice" {
+		t.Errorf("the post hook got the owner %s, want alice", owner)
+	}
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// The match operators of the matcher flag
+const (
+	// MatchOperatorEquals matches if the actual value equals the flag value
+	MatchOperatorEquals = "equals"
+	// MatchOperatorRegex matches if the actual value matches the regular expression, for example ^java.*order-service
+	MatchOperatorRegex = "regex"
+	// MatchOperatorIn matches if the actual value is one of the comma separated flag values
+	MatchOperatorIn = "in"
+	// MatchOperatorNot matches if the actual value does not equal the flag value
+	MatchOperatorNot = "not"
+	// MatchOperatorPrefix matches if the actual value starts with the flag value
+	MatchOperatorPrefix = "prefix"
+)
+
+// MatchFlagValue returns true if the actual value matches the flag value by the operator of the matcher flag,
+// so the target selection works consistently across plugins
+func MatchFlagValue(flag ExpFlagSpec, value, actual string) (bool, error) {
+	matched, err := Match(FlagOperatorOf(flag), value, actual)
+	if err != nil {
+		return false, fmt.Errorf(ParameterIllegal.Sprintf(flag.FlagName(), value, err))
+	}
+	return matched, nil
+}
+
+// Match returns true if the actual value matches the expected value by the operator, equals is used if empty
+func Match(operator, expected, actual string) (bool, error) {
+	switch operator {
+	case "", MatchOperatorEquals:
+		return actual == expected, nil
+	case MatchOperatorRegex:
+		re, err := regexp.Compile(expected)
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(actual), nil
+	case MatchOperatorIn:
+		for _, value := range strings.Split(expected, ",") {
+			if strings.TrimSpace(value) == actual {
+				return true, nil
+			}
+		}
+		return false, nil
+	case MatchOperatorNot:
+		return actual != expected, nil
+	case MatchOperatorPrefix:
+		return strings.HasPrefix(actual, expected), nil
+	}
+	return false, fmt.Errorf("unknown match operator `%s`", operator)
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		operator string
+		expected string
+		actual   string
+		want     bool
+		wantErr  bool
+	}{
+		{"", "java", "java", true, false},
+		{MatchOperatorEquals, "java", "javac", false, false},
+		{MatchOperatorRegex, "^java.*order-service", "java -jar order-service.jar", true, false},
+		{MatchOperatorRegex, "^java.*order-service", "python order-service", false, false},
+		{MatchOperatorRegex, "(", "java", false, true},
+		{MatchOperatorIn, "nginx, java", "java", true, false},
+		{MatchOperatorIn, "nginx,java", "python", false, false},
+		{MatchOperatorNot, "java", "python", true, false},
+		{MatchOperatorPrefix, "order-", "order-service", true, false},
+		{"contains", "a", "a", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.operator+"/"+tt.expected, func(t *testing.T) {
+			got, err := Match(tt.operator, tt.expected, tt.actual)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("Match() = %v, %v, want %v, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+	flag := &ExpFlag{Name: "process", Operator: MatchOperatorRegex}
+	if _, err := MatchFlagValue(flag, "[", "java"); err == nil {
+		t.Errorf("MatchFlagValue() expected error for illegal regex")
+	}
+}
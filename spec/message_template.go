@@ -0,0 +1,321 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MessageTemplate is the message of the code with the named placeholders, such as
+// "`{flag}`: illegal value `{value}`", the placeholder names are the params of the code.
+// The literal braces are escaped by doubling them, such as "{{" and "}}".
+type MessageTemplate string
+
+// MissingParamsError is returned by Render if the params of the placeholders are not provided,
+// the placeholders are kept in the rendered message
+type MissingParamsError struct {
+	Code   int32
+	Params []string
+}
+
+func (e *MissingParamsError) Error() string {
+	return fmt.Sprintf("missing the params %s of the code %d message", strings.Join(e.Params, ", "), e.Code)
+}
+
+var placeholderRegexp = regexp.MustCompile(`\{\{|\}\}|\{([A-Za-z][A-Za-z0-9_]*)\}`)
+
+var (
+	// messageParams are the names of the positional flags of ResponseFailWithFlags by code
+	messageParams = map[int32][]string{
+		ActionNotSupport.Code:                  {"action"},
+		ParameterLess.Code:                     {"flag"},
+		ParameterLessOneOf.Code:                {"flags"},
+		ParameterConflict.Code:                 {"flags"},
+		ParameterLessWith.Code:                 {"flag", "dependency"},
+		ParameterForbidden.Code:                {"flag", "condition"},
+		ParameterIllegal.Code:                  {"flag", "value", "err"},
+		ParameterInvalid.Code:                  {"flag", "value", "err"},
+		ParameterInvalidProName.Code:           {"flag", "process"},
+		ParameterInvalidProIdNotByName.Code:    {"process", "pid"},
+		ParameterInvalidCplusPort.Code:         {"port"},
+		ParameterInvalidDbQuery.Code:           {"flag"},
+		ParameterInvalidCplusTarget.Code:       {"target"},
+		ParameterInvalidBladePathError.Code:    {"flag", "path", "err"},
+		ParameterInvalidNSNotOne.Code:          {"flag"},
+		ParameterInvalidK8sPodQuery.Code:       {"flag"},
+		ParameterInvalidK8sNodeQuery.Code:      {"flag"},
+		ParameterInvalidDockContainerId.Code:   {"flag"},
+		ParameterInvalidDockContainerName.Code: {"flag"},
+		ParameterInvalidTooManyProcess.Code:    {"process"},
+		DeployChaosBladeFailed.Code:            {"path", "err"},
+		CommandIllegal.Code:                    {"err"},
+		CommandRejectedByPolicy.Code:           {"command", "err"},
+		ChaosbladeFileNotFound.Code:            {"file"},
+		EnvironmentNotSatisfied.Code:           {"requirements"},
+		PreconditionNotSatisfied.Code:          {"preconditions"},
+		UnexpectedStatus.Code:                  {"expected", "actual"},
+		DockerExecNotFound.Code:                {"name"},
+		DockerImagePullFailed.Code:             {"err"},
+		CriExecNotFound.Code:                   {"name"},
+		ImagePullFailed.Code:                   {"image", "err"},
+		HandlerExecNotFound.Code:               {"name"},
+		CplusActionNotSupport.Code:             {"action"},
+		PodNotReady.Code:                       {"pod"},
+		ResultUnmarshalFailed.Code:             {"result", "err"},
+		ResultMarshalFailed.Code:               {"result", "err"},
+		GenerateUidFailed.Code:                 {"err"},
+		ProcessIdByNameFailed.Code:             {"process", "err"},
+		ProcessJudgeExistFailed.Code:           {"pid", "err"},
+		ProcessNotExist.Code:                   {"pid"},
+		ProcessGetUsernameFailed.Code:          {"pid", "err"},
+		SandboxGetPortFailed.Code:              {"err"},
+		SandboxCreateTokenFailed.Code:          {"err"},
+		FileNotExist.Code:                      {"file"},
+		FileCantReadOrOpen.Code:                {"file"},
+		BackfileExists.Code:                    {"file"},
+		DbQueryFailed.Code:                     {"query", "err"},
+		K8sExecFailed.Code:                     {"command", "err"},
+		DockerExecFailed.Code:                  {"command", "err"},
+		OsCmdExecFailed.Code:                   {"command", "err"},
+		HttpExecFailed.Code:                    {"command", "err"},
+		GetIdentifierFailed.Code:               {"err"},
+		CreateContainerFailed.Code:             {"err"},
+		ContainerExecFailed.Code:               {"command", "err"},
+		OsExecutorNotFound.Code:                {"name"},
+		ExecutorPanic.Code:                     {"action", "err"},
+		ExecTimeout.Code:                       {"action", "timeout"},
+		ExecCanceled.Code:                      {"action"},
+		ConcurrencyLimitExceeded.Code:          {"action", "limit", "uids"},
+		ChaosfsClientFailed.Code:               {"pod", "err"},
+		ChaosfsInjectFailed.Code:               {"pod", "request", "err"},
+		ChaosfsRecoverFailed.Code:              {"pod", "err"},
+		SshExecFailed.Code:                     {"result", "err"},
+		SystemdNotFound.Code:                   {"name", "err"},
+		DatabaseError.Code:                     {"name", "err"},
+		DataNotFound.Code:                      {"name"},
+		BashPhthonNotFoundError.Code:           {"name", "err"},
+	}
+	// messageTemplates are the overridden templates by code and locale
+	messageTemplates = make(map[int32]map[string]MessageTemplate)
+	templatesLock    sync.RWMutex
+)
+
+// RegisterMessageParams sets the param names of the code message, the positional flags of ResponseFailWithFlags
+// are the params in order. The codes without the param names use arg1, arg2 and so on.
+func RegisterMessageParams(code int32, params ...string) {
+	templatesLock.Lock()
+	defer templatesLock.Unlock()
+	messageParams[code] = append([]string{}, params...)
+}
+
+// MessageParams returns the param names of the code message
+func MessageParams(code int32) []string {
+	templatesLock.RLock()
+	defer templatesLock.RUnlock()
+	return append([]string{}, messageParams[code]...)
+}
+
+// RegisterMessageTemplate overrides the message of the code in the locale by the named template, the placeholders
+// must be the params of the code, for example "{flag} has the illegal value {value}: {err}".
+func RegisterMessageTemplate(code int32, l string, template MessageTemplate) error {
+	params := MessageParams(code)
+	for _, name := range template.Placeholders() {
+		if !containsString(params, name) {
+			return fmt.Errorf("unknown placeholder {%s} of the code %d message, the params are %v", name, code, params)
+		}
+	}
+	l = normalizeLocale(l)
+	templatesLock.Lock()
+	defer templatesLock.Unlock()
+	if messageTemplates[code] == nil {
+		messageTemplates[code] = make(map[string]MessageTemplate)
+	}
+	messageTemplates[code][l] = template
+	return nil
+}
+
+// Template returns the named template of the code message in the locale. The template registered by
+// RegisterMessageTemplate is preferred, otherwise it is converted from the localized printf style message.
+func (c CodeType) Template(l string) MessageTemplate {
+	if template, ok := c.registeredTemplate(l); ok {
+		return template
+	}
+	return convertPrintfTemplate(c.Localize(l), MessageParams(c.Code))
+}
+
+// registeredTemplate returns the template registered by RegisterMessageTemplate in the locale
+func (c CodeType) registeredTemplate(l string) (MessageTemplate, bool) {
+	templatesLock.RLock()
+	defer templatesLock.RUnlock()
+	template, ok := messageTemplates[c.Code][normalizeLocale(l)]
+	return template, ok
+}
+
+// Render returns the message of the code in the locale with the params, it returns *MissingParamsError
+// with the rendered message if some params of the placeholders are not provided. The localized printf style
+// message is formatted by fmt.Sprintf with the params in order if all provided, so the verbs such as %03d
+// and %q are kept.
+func (c CodeType) Render(l string, params map[string]interface{}) (string, error) {
+	if template, ok := c.registeredTemplate(l); ok {
+		return template.render(c.Code, params)
+	}
+	format := c.Localize(l)
+	names := MessageParams(c.Code)
+	template := convertPrintfTemplate(format, names)
+	if args, ok := template.printfArgs(names, params); ok {
+		return fmt.Sprintf(format, args...), nil
+	}
+	return template.render(c.Code, params)
+}
+
+// renderFlags renders the message with the positional flags of ResponseFailWithFlags. The localized printf style
+// message is formatted by fmt.Sprintf, and it's returned as is without the flags like before the templates.
+// The registered template falls back to the printf style message if the flags are not enough for it, so the
+// placeholders are never returned.
+func (c CodeType) renderFlags(l string, flags []interface{}) string {
+	if len(flags) == 0 {
+		return c.Localize(l)
+	}
+	if template, ok := c.registeredTemplate(l); ok {
+		names := MessageParams(c.Code)
+		params := make(map[string]interface{}, len(flags))
+		for idx, flag := range flags {
+			params[paramName(names, idx)] = flag
+		}
+		if msg, err := template.render(c.Code, params); err == nil {
+			return msg
+		}
+	}
+	return fmt.Sprintf(c.Localize(l), flags...)
+}
+
+// Placeholders returns the placeholder names in the template in order
+func (t MessageTemplate) Placeholders() []string {
+	names := make([]string, 0)
+	for _, match := range placeholderRegexp.FindAllStringSubmatch(string(t), -1) {
+		if match[1] != "" && !containsString(names, match[1]) {
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+func (t MessageTemplate) render(code int32, params map[string]interface{}) (string, error) {
+	missing := make([]string, 0)
+	msg := placeholderRegexp.ReplaceAllStringFunc(string(t), func(placeholder string) string {
+		if placeholder == "{{" || placeholder == "}}" {
+			return placeholder[:1]
+		}
+		name := placeholder[1 : len(placeholder)-1]
+		value, ok := params[name]
+		if !ok {
+			if !containsString(missing, name) {
+				missing = append(missing, name)
+			}
+			return placeholder
+		}
+		return fmt.Sprint(value)
+	})
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return msg, &MissingParamsError{Code: code, Params: missing}
+	}
+	return msg, nil
+}
+
+// printfArgs returns the params in the order of the printf style message converted to the template,
+// false is returned if some params of the placeholders are not provided
+func (t MessageTemplate) printfArgs(names []string, params map[string]interface{}) ([]interface{}, bool) {
+	count := 0
+	for _, placeholder := range t.Placeholders() {
+		if idx := paramIndex(names, placeholder); idx >= count {
+			count = idx + 1
+		}
+	}
+	args := make([]interface{}, count)
+	for idx := range args {
+		value, ok := params[paramName(names, idx)]
+		if !ok {
+			return nil, false
+		}
+		args[idx] = value
+	}
+	return args, true
+}
+
+// convertPrintfTemplate replaces the format verbs by the placeholders of the params, the explicit argument
+// indexes such as %[2]s are supported. The literal braces are escaped.
+func convertPrintfTemplate(format string, params []string) MessageTemplate {
+	var builder strings.Builder
+	argIdx := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] == '{' || format[i] == '}' {
+			builder.WriteByte(format[i])
+			builder.WriteByte(format[i])
+			continue
+		}
+		if format[i] != '%' || i+1 == len(format) {
+			builder.WriteByte(format[i])
+			continue
+		}
+		i++
+		if format[i] == '%' {
+			builder.WriteByte('%')
+			continue
+		}
+		if format[i] == '[' {
+			if end := strings.IndexByte(format[i:], ']'); end > 0 {
+				if n, err := strconv.Atoi(format[i+1 : i+end]); err == nil && n > 0 {
+					argIdx = n - 1
+				}
+				i += end + 1
+			}
+		}
+		for i < len(format) && strings.IndexByte("+-# 0123456789.", format[i]) >= 0 {
+			i++
+		}
+		builder.WriteString("{" + paramName(params, argIdx) + "}")
+		argIdx++
+	}
+	return MessageTemplate(builder.String())
+}
+
+func paramName(params []string, idx int) string {
+	if idx < len(params) {
+		return params[idx]
+	}
+	return "arg" + strconv.Itoa(idx+1)
+}
+
+// paramIndex is the reverse of paramName, -1 is returned if the name is not the param
+func paramIndex(params []string, name string) int {
+	for idx, param := range params {
+		if param == name {
+			return idx
+		}
+	}
+	if n, err := strconv.Atoi(strings.TrimPrefix(name, "arg")); err == nil && strings.HasPrefix(name, "arg") &&
+		n > len(params) {
+		return n - 1
+	}
+	return -1
+}
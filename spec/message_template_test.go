@@ -0,0 +1,148 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinMessageParams(t *testing.T) {
+	for _, registered := range RegisteredCodes() {
+		if CodeCategory(registered.Code) == CodeCategoryCustom {
+			continue
+		}
+		params := MessageParams(registered.Code)
+		for _, l := range []string{LocaleEnUS, LocaleZhCN} {
+			template := registered.Template(l)
+			if strings.Contains(string(template), "{arg") {
+				t.Errorf("%s: the %s template %q has unnamed params %v", registered.Status, l, template, params)
+			}
+			if placeholders := template.Placeholders(); len(placeholders) != len(params) {
+				t.Errorf("%s: the %s template %q placeholders = %v, want %v", registered.Status, l, template, placeholders, params)
+			}
+		}
+	}
+}
+
+func TestConvertPrintfTemplate(t *testing.T) {
+	tests := []struct {
+		format string
+		params []string
+		want   MessageTemplate
+	}{
+		{format: "`%s`: not exist", params: []string{"file"}, want: "`{file}`: not exist"},
+		{format: "need %[2]s when %[1]s", params: []string{"flag", "dependency"}, want: "need {dependency} when {flag}"},
+		{format: "%-5d%% of %v", params: nil, want: "{arg1}% of {arg2}"},
+		{format: "no verbs", want: "no verbs"},
+		{format: "`%s`: unknown field {name}", params: []string{"flag"}, want: "`{flag}`: unknown field {{name}}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if got := convertPrintfTemplate(tt.format, tt.params); got != tt.want {
+				t.Errorf("convertPrintfTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderMessage(t *testing.T) {
+	msg, err := ParameterIllegal.Render(LocaleEnUS, map[string]interface{}{"flag": "timeout", "value": "1x",
+		"err": errors.New("illegal duration")})
+	if err != nil || msg != "illegal `timeout` parameter value: `1x`. illegal duration" {
+		t.Errorf("Render() = %q, %v", msg, err)
+	}
+	msg, err = ParameterIllegal.Render(LocaleEnUS, map[string]interface{}{"flag": "timeout"})
+	var missing *MissingParamsError
+	if !errors.As(err, &missing) || !reflect.DeepEqual(missing.Params, []string{"err", "value"}) {
+		t.Errorf("Render() error = %v, want missing err and value", err)
+	}
+	if msg != "illegal `timeout` parameter value: `{value}`. {err}" {
+		t.Errorf("Render() = %q, want the placeholders kept", msg)
+	}
+	if msg := ResponseFailWithParams(ParameterLess, map[string]interface{}{"flag": "pid"}).Err; msg != "less parameter: `pid`" {
+		t.Errorf("ResponseFailWithParams() = %q", msg)
+	}
+	zh := ResponseFailWithContext(WithLocale(context.Background(), LocaleZhCN), ParameterLessWith, "pid", "process").Err
+	if zh != "缺少参数：指定 `process` 时必须指定 `pid`" {
+		t.Errorf("ResponseFailWithContext() = %q", zh)
+	}
+}
+
+func TestRegisterMessageTemplate(t *testing.T) {
+	code, err := RegisterResponseCode(99101, "TestTemplateCode", "`%s`: quota exceeded, %v")
+	if err != nil {
+		t.Fatalf("RegisterResponseCode() error = %v", err)
+	}
+	if msg := ResponseFailWithFlags(code, "disk", 10).Err; msg != "`disk`: quota exceeded, 10" {
+		t.Errorf("ResponseFailWithFlags() = %q", msg)
+	}
+	RegisterMessageParams(code.Code, "resource", "quota")
+	if err := RegisterMessageTemplate(code.Code, "en_US", "the {resource} quota {quota} exceeded"); err != nil {
+		t.Fatalf("RegisterMessageTemplate() error = %v", err)
+	}
+	if msg := ResponseFailWithFlags(code, "disk", 10).Err; msg != "the disk quota 10 exceeded" {
+		t.Errorf("ResponseFailWithFlags() = %q, want the overridden template", msg)
+	}
+	if msg := code.Sprintf("disk", 10); msg != "the disk quota 10 exceeded" {
+		t.Errorf("Sprintf() = %q, want the overridden template", msg)
+	}
+	if err := RegisterMessageTemplate(code.Code, LocaleEnUS, "{unknown} exceeded"); err == nil {
+		t.Errorf("RegisterMessageTemplate() error = nil, want unknown placeholder error")
+	}
+}
+
+func TestRenderPrintfVerbs(t *testing.T) {
+	code, err := RegisterResponseCode(99102, "TestVerbsCode", "%03d items of %q, mask %x, field {name}")
+	if err != nil {
+		t.Fatalf("RegisterResponseCode() error = %v", err)
+	}
+	want := `007 items of "disk", mask ff, field {name}`
+	if msg := ResponseFailWithFlags(code, 7, "disk", 255).Err; msg != want {
+		t.Errorf("ResponseFailWithFlags() = %q, want %q", msg, want)
+	}
+	msg, err := code.Render(LocaleEnUS, map[string]interface{}{"arg1": 7, "arg2": "disk", "arg3": 255})
+	if err != nil || msg != want {
+		t.Errorf("Render() = %q, %v, want %q", msg, err, want)
+	}
+	if placeholders := code.Template(LocaleEnUS).Placeholders(); !reflect.DeepEqual(placeholders, []string{"arg1", "arg2", "arg3"}) {
+		t.Errorf("Placeholders() = %v, want the literal braces skipped", placeholders)
+	}
+}
+
+func TestRenderWithoutFlags(t *testing.T) {
+	if msg := ResponseFailWithFlags(ParameterLess).Err; msg != ParameterLess.Msg {
+		t.Errorf("ResponseFailWithFlags() = %q, want the message as is", msg)
+	}
+	code, err := RegisterResponseCode(99103, "TestNoFlagsCode", "`%s`: not ready")
+	if err != nil {
+		t.Fatalf("RegisterResponseCode() error = %v", err)
+	}
+	RegisterMessageParams(code.Code, "pod")
+	if err := RegisterMessageTemplate(code.Code, LocaleEnUS, "the {pod} is not ready, {{retry}}"); err != nil {
+		t.Fatalf("RegisterMessageTemplate() error = %v", err)
+	}
+	if msg := ResponseFailWithFlags(code, "nginx").Err; msg != "the nginx is not ready, {retry}" {
+		t.Errorf("ResponseFailWithFlags() = %q, want the escaped braces rendered", msg)
+	}
+	if msg := ResponseFailWithFlags(code).Err; strings.Contains(msg, "{pod}") {
+		t.Errorf("ResponseFailWithFlags() = %q, want no placeholders", msg)
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"runtime/debug"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ExecFunc is the func signature of Executor.Exec
+type ExecFunc func(uid string, ctx context.Context, model *ExpModel) *Response
+
+// ExecutorMiddleware wraps the Exec of the executor, for example timeout enforcement, panic recovery, metrics and audit
+type ExecutorMiddleware interface {
+	// Wrap returns the func which invokes the next func
+	Wrap(next ExecFunc) ExecFunc
+}
+
+// ExecutorMiddlewareFunc is the adapter to use the func as the middleware
+type ExecutorMiddlewareFunc func(next ExecFunc) ExecFunc
+
+func (f ExecutorMiddlewareFunc) Wrap(next ExecFunc) ExecFunc {
+	return f(next)
+}
+
+// ChainMiddlewares composes the middlewares into one, the first middleware is the outermost
+func ChainMiddlewares(middlewares ...ExecutorMiddleware) ExecutorMiddleware {
+	return ExecutorMiddlewareFunc(func(next ExecFunc) ExecFunc {
+		for idx := len(middlewares) - 1; idx >= 0; idx-- {
+			next = middlewares[idx].Wrap(next)
+		}
+		return next
+	})
+}
+
+// middlewareExecutor is the executor whose Exec is wrapped by the middlewares
+type middlewareExecutor struct {
+	Executor
+	exec ExecFunc
+}
+
+func (e *middlewareExecutor) Exec(uid string, ctx context.Context, model *ExpModel) *Response {
+	return e.exec(uid, ctx, model)
+}
+
+// Validate delegates to the wrapped executor, the middlewares are not invoked
+func (e *middlewareExecutor) Validate(ctx context.Context, model *ExpModel) *Response {
+	return ValidateExecutor(ctx, e.Executor, model)
+}
+
+// WithMiddlewares returns the executor which invokes the Exec of the executor through the middlewares,
+// the first middleware is the outermost. Name and SetChannel are delegated to the executor.
+func WithMiddlewares(executor Executor, middlewares ...ExecutorMiddleware) Executor {
+	if len(middlewares) == 0 {
+		return executor
+	}
+	return &middlewareExecutor{
+		Executor: executor,
+		exec:     ChainMiddlewares(middlewares...).Wrap(executor.Exec),
+	}
+}
+
+// RecoverMiddleware returns the middleware which converts the panic of the executor to the ExecutorPanic response
+func RecoverMiddleware(executorName string) ExecutorMiddleware {
+	return ExecutorMiddlewareFunc(func(next ExecFunc) ExecFunc {
+		return func(uid string, ctx context.Context, model *ExpModel) (response *Response) {
+			defer func() {
+				if err := recover(); err != nil {
+					logrus.Errorf("executor %s panic, uid: %s, err: %v, stack: %s", executorName, uid, err, debug.Stack())
+					response = ResponseFailWithFlags(ExecutorPanic, executorName, err)
+				}
+			}()
+			return next(uid, ctx, model)
+		}
+	})
+}
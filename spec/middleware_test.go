@@ -0,0 +1,60 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestWithMiddlewares(t *testing.T) {
+	calls := make([]string, 0)
+	trace := func(name string) ExecutorMiddleware {
+		return ExecutorMiddlewareFunc(func(next ExecFunc) ExecFunc {
+			return func(uid string, ctx context.Context, model *ExpModel) *Response {
+				calls = append(calls, name+".before")
+				response := next(uid, ctx, model)
+				calls = append(calls, name+".after")
+				return response
+			}
+		})
+	}
+	executor := &testExecutor{exec: func(uid string, ctx context.Context, model *ExpModel) *Response {
+		calls = append(calls, "exec")
+		return ReturnSuccess(uid)
+	}}
+	wrapped := WithMiddlewares(executor, trace("outer"), trace("inner"))
+	response := wrapped.Exec("uid", context.Background(), &ExpModel{})
+	want := []string{"outer.before", "inner.before", "exec", "inner.after", "outer.after"}
+	if !response.Success || !reflect.DeepEqual(calls, want) {
+		t.Errorf("Exec() calls = %v, want %v", calls, want)
+	}
+	if wrapped.Name() != executor.Name() {
+		t.Errorf("Name() = %s, want %s", wrapped.Name(), executor.Name())
+	}
+}
+
+func TestRecoverMiddleware(t *testing.T) {
+	executor := &testExecutor{exec: func(uid string, ctx context.Context, model *ExpModel) *Response {
+		panic("boom")
+	}}
+	response := WithMiddlewares(executor, RecoverMiddleware("test")).Exec("uid", context.Background(), &ExpModel{})
+	if response == nil || response.Code != ExecutorPanic.Code {
+		t.Errorf("Exec() = %v, want ExecutorPanic", response)
+	}
+}
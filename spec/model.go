@@ -43,9 +43,19 @@ type ExpModelCommandSpec interface {
 
 	// SetFlags
 	SetFlags(flags []ExpFlagSpec)
+
+	// FlagGroups returns the constraints between the flags
+	FlagGroups() []FlagGroup
+
+	// SubModels returns the nested sub targets, which inherit the flags of the command
+	SubModels() []ExpModelCommandSpec
+
+	// Translations returns the label and the descriptions of the command keyed by locale
+	Translations() map[string]Translation
 }
 
-// ExpActionCommandSpec defines the action command interface for the experimental plugin
+// ExpActionCommandSpec defines the action command interface for the experimental plugin, the optional accessors,
+// such as Scopes, are defined by the interfaces in optional_spec.go
 type ExpActionCommandSpec interface {
 	// Name returns the action name
 	Name() string
@@ -93,6 +103,8 @@ type ExpActionCommandSpec interface {
 	ProcessHang() bool
 }
 
+// ExpFlagSpec defines the flag interface, the optional accessors, such as FlagType, are defined by the
+// interfaces in optional_spec.go
 type ExpFlagSpec interface {
 	// FlagName returns the flag FlagName
 	FlagName() string
@@ -126,6 +138,63 @@ type ExpFlag struct {
 
 	// default value
 	Default string `yaml:"default,omitempty"`
+
+	// Type is the value type, string is used if empty
+	Type string `yaml:"type,omitempty"`
+
+	// EnumValues are the allowed values if the type is enum
+	EnumValues []string `yaml:"enumValues,flow,omitempty"`
+
+	// Validation is the rules of the flag value
+	Validation *FlagValidation `yaml:"validation,omitempty"`
+
+	// EnvVar is the environment variable name of the flag value
+	EnvVar string `yaml:"envVar,omitempty"`
+
+	// ConfigKey is the config key of the flag value
+	ConfigKey string `yaml:"configKey,omitempty"`
+
+	// Deprecated is the deprecation message
+	Deprecated string `yaml:"deprecated,omitempty"`
+
+	// ReplacedBy is the flag name which replaces the deprecated flag
+	ReplacedBy string `yaml:"replacedBy,omitempty"`
+
+	// Operator is the match operator of the matcher flag
+	Operator string `yaml:"operator,omitempty"`
+
+	// Examples are the usage examples of the flag
+	Examples []Example `yaml:"examples,omitempty"`
+
+	// Secret means the flag value is sensitive, such as the database password
+	Secret bool `yaml:"secret,omitempty"`
+
+	// Label is the display name of the flag
+	Label string `yaml:"label,omitempty"`
+
+	// Translations are the label and the description keyed by locale
+	Translations map[string]Translation `yaml:"translations,omitempty"`
+
+	// RequiredIf is the condition expression which makes the flag required
+	RequiredIf string `yaml:"requiredIf,omitempty"`
+
+	// ForbiddenIf is the condition expression which makes the flag not allowed
+	ForbiddenIf string `yaml:"forbiddenIf,omitempty"`
+
+	// Repeated is true if the flag can be specified multiple times, such as --exclude-port 22 --exclude-port 80
+	Repeated bool `yaml:"repeated,omitempty"`
+
+	// Hidden is true for the internal flags, such as the nsexec toggles and the debug knobs
+	Hidden bool `yaml:"hidden,omitempty"`
+
+	// Group is the help group of the flag, such as advanced
+	Group string `yaml:"group,omitempty"`
+
+	// Aliases are the old names kept for compatibility after the flag is renamed
+	Aliases []string `yaml:"aliases,flow,omitempty"`
+
+	// Schema validates the value of the json type flag, such as the list of the http rules
+	Schema *JSONSchema `yaml:"schema,omitempty"`
 }
 
 func (f *ExpFlag) FlagName() string {
@@ -152,11 +221,90 @@ func (f *ExpFlag) FlagDefault() string {
 	return f.Default
 }
 
+func (f *ExpFlag) FlagType() string {
+	return f.Type
+}
+
+func (f *ExpFlag) FlagEnumValues() []string {
+	return f.EnumValues
+}
+
+func (f *ExpFlag) FlagValidation() *FlagValidation {
+	return f.Validation
+}
+
+func (f *ExpFlag) FlagEnvVar() string {
+	return f.EnvVar
+}
+
+func (f *ExpFlag) FlagConfigKey() string {
+	return f.ConfigKey
+}
+
+func (f *ExpFlag) FlagDeprecated() string {
+	return f.Deprecated
+}
+
+func (f *ExpFlag) FlagReplacedBy() string {
+	return f.ReplacedBy
+}
+
+func (f *ExpFlag) FlagOperator() string {
+	return f.Operator
+}
+
+func (f *ExpFlag) FlagExamples() []Example {
+	return f.Examples
+}
+
+func (f *ExpFlag) FlagSecret() bool {
+	return f.Secret
+}
+
+func (f *ExpFlag) FlagLabel() string {
+	return f.Label
+}
+
+func (f *ExpFlag) FlagTranslations() map[string]Translation {
+	return f.Translations
+}
+
+func (f *ExpFlag) FlagRequiredIf() string {
+	return f.RequiredIf
+}
+
+func (f *ExpFlag) FlagForbiddenIf() string {
+	return f.ForbiddenIf
+}
+
+func (f *ExpFlag) FlagRepeated() bool {
+	return f.Repeated
+}
+
+func (f *ExpFlag) FlagHidden() bool {
+	return f.Hidden
+}
+
+func (f *ExpFlag) FlagGroup() string {
+	return f.Group
+}
+
+func (f *ExpFlag) FlagAliases() []string {
+	return f.Aliases
+}
+
+func (f *ExpFlag) FlagSchema() *JSONSchema {
+	return f.Schema
+}
+
 // BaseExpModelCommandSpec defines the common struct of the implementation of ExpModelCommandSpec
 type BaseExpModelCommandSpec struct {
-	ExpScope   string
-	ExpActions []ExpActionCommandSpec
-	ExpFlags   []ExpFlagSpec
+	ExpScope        string
+	ExpActions      []ExpActionCommandSpec
+	ExpFlags        []ExpFlagSpec
+	ExpFlagGroups   []FlagGroup
+	ExpSubModels    []ExpModelCommandSpec
+	ExpTranslations map[string]Translation
 }
 
 // Scope default value is "" means localhost
@@ -176,6 +324,18 @@ func (b *BaseExpModelCommandSpec) SetFlags(flags []ExpFlagSpec) {
 	b.ExpFlags = flags
 }
 
+func (b *BaseExpModelCommandSpec) FlagGroups() []FlagGroup {
+	return b.ExpFlagGroups
+}
+
+func (b *BaseExpModelCommandSpec) SubModels() []ExpModelCommandSpec {
+	return b.ExpSubModels
+}
+
+func (b *BaseExpModelCommandSpec) Translations() map[string]Translation {
+	return b.ExpTranslations
+}
+
 // BaseExpActionCommandSpec defines the common struct of the implementation of ExpActionCommandSpec
 type BaseExpActionCommandSpec struct {
 	ActionMatchers    []ExpFlagSpec
@@ -186,6 +346,20 @@ type BaseExpActionCommandSpec struct {
 	ActionPrograms    []string
 	ActionCategories  []string
 	ActionProcessHang bool
+	ActionDeprecated  string
+	ActionReplacedBy  string
+	// ActionDefaultTimeout and ActionMaxTimeout are the duration strings parsed by ParseDuration
+	ActionDefaultTimeout string
+	ActionMaxTimeout     string
+	ActionMaxConcurrency int
+	// ActionDefaultDuration and ActionMaxDuration are the duration strings of the experiment, not the execution
+	ActionDefaultDuration string
+	ActionMaxDuration     string
+	ActionScopes          []string
+	ActionPreconditions   []string
+	ActionRequirements    *ActionRequirements
+	ActionExamples        []Example
+	ActionTranslations    map[string]Translation
 }
 
 func (b *BaseExpActionCommandSpec) Matchers() []ExpFlagSpec {
@@ -232,19 +406,80 @@ func (b *BaseExpActionCommandSpec) ProcessHang() bool {
 	return b.ActionProcessHang
 }
 
+func (b *BaseExpActionCommandSpec) Deprecated() string {
+	return b.ActionDeprecated
+}
+
+func (b *BaseExpActionCommandSpec) ReplacedBy() string {
+	return b.ActionReplacedBy
+}
+
+func (b *BaseExpActionCommandSpec) DefaultTimeout() string {
+	return b.ActionDefaultTimeout
+}
+
+func (b *BaseExpActionCommandSpec) MaxTimeout() string {
+	return b.ActionMaxTimeout
+}
+
+func (b *BaseExpActionCommandSpec) MaxConcurrency() int {
+	return b.ActionMaxConcurrency
+}
+
+func (b *BaseExpActionCommandSpec) DefaultDuration() string {
+	return b.ActionDefaultDuration
+}
+
+func (b *BaseExpActionCommandSpec) MaxDuration() string {
+	return b.ActionMaxDuration
+}
+
+func (b *BaseExpActionCommandSpec) Scopes() []string {
+	return b.ActionScopes
+}
+
+func (b *BaseExpActionCommandSpec) Preconditions() []string {
+	return b.ActionPreconditions
+}
+
+func (b *BaseExpActionCommandSpec) Requirements() *ActionRequirements {
+	return b.ActionRequirements
+}
+
+func (b *BaseExpActionCommandSpec) Examples() []Example {
+	return b.ActionExamples
+}
+
+func (b *BaseExpActionCommandSpec) Translations() map[string]Translation {
+	return b.ActionTranslations
+}
+
 // ActionModel for yaml file
 type ActionModel struct {
-	ActionName        string    `yaml:"action"`
-	ActionAliases     []string  `yaml:"aliases,flow,omitempty"`
-	ActionShortDesc   string    `yaml:"shortDesc"`
-	ActionLongDesc    string    `yaml:"longDesc"`
-	ActionMatchers    []ExpFlag `yaml:"matchers,omitempty"`
-	ActionFlags       []ExpFlag `yaml:"flags,omitempty"`
-	ActionExample     string    `yaml:"example"`
-	executor          Executor
-	ActionPrograms    []string `yaml:"programs,omitempty"`
-	ActionCategories  []string `yaml:"categories,omitempty"`
-	ActionProcessHang bool     `yaml:"actionProcessHang"`
+	ActionName            string    `yaml:"action"`
+	ActionAliases         []string  `yaml:"aliases,flow,omitempty"`
+	ActionShortDesc       string    `yaml:"shortDesc"`
+	ActionLongDesc        string    `yaml:"longDesc"`
+	ActionMatchers        []ExpFlag `yaml:"matchers,omitempty"`
+	ActionFlags           []ExpFlag `yaml:"flags,omitempty"`
+	ActionExample         string    `yaml:"example"`
+	executor              Executor
+	ActionPrograms        []string            `yaml:"programs,omitempty"`
+	ActionCategories      []string            `yaml:"categories,omitempty"`
+	ActionProcessHang     bool                `yaml:"actionProcessHang"`
+	ActionDeprecated      string              `yaml:"deprecated,omitempty"`
+	ActionReplacedBy      string              `yaml:"replacedBy,omitempty"`
+	ActionDefaultTimeout  string              `yaml:"defaultTimeout,omitempty"`
+	ActionMaxTimeout      string              `yaml:"maxTimeout,omitempty"`
+	ActionMaxConcurrency  int                 `yaml:"maxConcurrency,omitempty"`
+	ActionDefaultDuration string              `yaml:"defaultDuration,omitempty"`
+	ActionMaxDuration     string              `yaml:"maxDuration,omitempty"`
+	ActionScopes          []string            `yaml:"scopes,flow,omitempty"`
+	ActionPreconditions   []string            `yaml:"preconditions,omitempty"`
+	ActionRequirements    *ActionRequirements `yaml:"requirements,omitempty"`
+	ActionExamples        []Example           `yaml:"examples,omitempty"`
+	// ActionTranslations are the label and the descriptions keyed by locale
+	ActionTranslations map[string]Translation `yaml:"translations,omitempty"`
 }
 
 func (am *ActionModel) Programs() []string {
@@ -315,6 +550,54 @@ func (am *ActionModel) ProcessHang() bool {
 	return am.ActionProcessHang
 }
 
+func (am *ActionModel) Deprecated() string {
+	return am.ActionDeprecated
+}
+
+func (am *ActionModel) ReplacedBy() string {
+	return am.ActionReplacedBy
+}
+
+func (am *ActionModel) DefaultTimeout() string {
+	return am.ActionDefaultTimeout
+}
+
+func (am *ActionModel) MaxTimeout() string {
+	return am.ActionMaxTimeout
+}
+
+func (am *ActionModel) MaxConcurrency() int {
+	return am.ActionMaxConcurrency
+}
+
+func (am *ActionModel) DefaultDuration() string {
+	return am.ActionDefaultDuration
+}
+
+func (am *ActionModel) MaxDuration() string {
+	return am.ActionMaxDuration
+}
+
+func (am *ActionModel) Scopes() []string {
+	return am.ActionScopes
+}
+
+func (am *ActionModel) Preconditions() []string {
+	return am.ActionPreconditions
+}
+
+func (am *ActionModel) Requirements() *ActionRequirements {
+	return am.ActionRequirements
+}
+
+func (am *ActionModel) Examples() []Example {
+	return am.ActionExamples
+}
+
+func (am *ActionModel) Translations() map[string]Translation {
+	return am.ActionTranslations
+}
+
 type ExpPrepareModel struct {
 	PrepareType     string    `yaml:"type"`
 	PrepareFlags    []ExpFlag `yaml:"flags"`
@@ -331,6 +614,11 @@ type ExpCommandModel struct {
 	ExpScope        string          `yaml:"scope"`
 	ExpPrepareModel ExpPrepareModel `yaml:"prepare,omitempty"`
 	ExpSubTargets   []string        `yaml:"subTargets,flow,omitempty"`
+	ExpFlagGroups   []FlagGroup     `yaml:"flagGroups,omitempty"`
+	// ExpSubModels are the nested sub targets, the actions of them contain the inherited flags
+	ExpSubModels []ExpCommandModel `yaml:"subModels,omitempty"`
+	// ExpTranslations are the label and the descriptions keyed by locale
+	ExpTranslations map[string]Translation `yaml:"translations,omitempty"`
 }
 
 func (ecm *ExpCommandModel) Scope() string {
@@ -368,6 +656,22 @@ func (ecm *ExpCommandModel) Flags() []ExpFlagSpec {
 	return flags
 }
 
+func (ecm *ExpCommandModel) FlagGroups() []FlagGroup {
+	return ecm.ExpFlagGroups
+}
+
+func (ecm *ExpCommandModel) Translations() map[string]Translation {
+	return ecm.ExpTranslations
+}
+
+func (ecm *ExpCommandModel) SubModels() []ExpModelCommandSpec {
+	specs := make([]ExpModelCommandSpec, 0)
+	for idx := range ecm.ExpSubModels {
+		specs = append(specs, &ecm.ExpSubModels[idx])
+	}
+	return specs
+}
+
 func (ecm *ExpCommandModel) SetFlags(flags []ExpFlagSpec) {
 	expFlags := make([]ExpFlag, 0)
 	for idx := range flags {
@@ -377,9 +681,11 @@ func (ecm *ExpCommandModel) SetFlags(flags []ExpFlagSpec) {
 }
 
 type Models struct {
-	Version string            `yaml:"version"`
-	Kind    string            `yaml:"kind"`
-	Models  []ExpCommandModel `yaml:"items"`
+	Version string `yaml:"version"`
+	Kind    string `yaml:"kind"`
+	// SpecVersion is the chaosblade-spec-go version which the models are created by
+	SpecVersion string            `yaml:"specVersion,omitempty"`
+	Models      []ExpCommandModel `yaml:"items"`
 }
 
 type Empty struct{}
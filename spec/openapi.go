@@ -0,0 +1,307 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"reflect"
+	"strings"
+)
+
+// OpenAPIVersion is the OpenAPI specification version of the generated document
+const OpenAPIVersion = "3.0.3"
+
+// OpenAPIDocument is the OpenAPI 3 document of the experiment models
+type OpenAPIDocument struct {
+	OpenAPI    string                      `json:"openapi" yaml:"openapi"`
+	Info       OpenAPIInfo                 `json:"info" yaml:"info"`
+	Paths      map[string]*OpenAPIPathItem `json:"paths" yaml:"paths"`
+	Components *OpenAPIComponents          `json:"components,omitempty" yaml:"components,omitempty"`
+}
+
+// OpenAPIInfo is the metadata of the document
+type OpenAPIInfo struct {
+	Title   string `json:"title" yaml:"title"`
+	Version string `json:"version" yaml:"version"`
+}
+
+// OpenAPIPathItem contains the operations of one path, the experiment is created by post
+type OpenAPIPathItem struct {
+	Post *OpenAPIOperation `json:"post,omitempty" yaml:"post,omitempty"`
+}
+
+// OpenAPIOperation is the operation of one target action
+type OpenAPIOperation struct {
+	OperationID string                      `json:"operationId" yaml:"operationId"`
+	Summary     string                      `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description string                      `json:"description,omitempty" yaml:"description,omitempty"`
+	Tags        []string                    `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Deprecated  bool                        `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	Parameters  []*OpenAPIParameter         `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses" yaml:"responses"`
+}
+
+// OpenAPIParameter is the operation parameter converted from the flag
+type OpenAPIParameter struct {
+	Name        string      `json:"name" yaml:"name"`
+	In          string      `json:"in" yaml:"in"`
+	Description string      `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool        `json:"required,omitempty" yaml:"required,omitempty"`
+	Deprecated  bool        `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	Schema      *JSONSchema `json:"schema" yaml:"schema"`
+}
+
+// OpenAPIResponse is the operation response
+type OpenAPIResponse struct {
+	Description string                       `json:"description" yaml:"description"`
+	Content     map[string]*OpenAPIMediaType `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+// OpenAPIMediaType is the schema of the response content
+type OpenAPIMediaType struct {
+	Schema *JSONSchema `json:"schema" yaml:"schema"`
+}
+
+// OpenAPIComponents contains the reusable schemas
+type OpenAPIComponents struct {
+	Schemas map[string]*JSONSchema `json:"schemas,omitempty" yaml:"schemas,omitempty"`
+}
+
+// JSONSchema is the subset of JSON Schema used by the OpenAPI document
+type JSONSchema struct {
+	Schema      string                 `json:"$schema,omitempty" yaml:"$schema,omitempty"`
+	Ref         string                 `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Title       string                 `json:"title,omitempty" yaml:"title,omitempty"`
+	Type        string                 `json:"type,omitempty" yaml:"type,omitempty"`
+	Format      string                 `json:"format,omitempty" yaml:"format,omitempty"`
+	Description string                 `json:"description,omitempty" yaml:"description,omitempty"`
+	Enum        []string               `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Default     interface{}            `json:"default,omitempty" yaml:"default,omitempty"`
+	Minimum     *float64               `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	Maximum     *float64               `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	MinLength   *int                   `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	Pattern     string                 `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Deprecated  bool                   `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	Properties  map[string]*JSONSchema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Items       *JSONSchema            `json:"items,omitempty" yaml:"items,omitempty"`
+	Required    []string               `json:"required,omitempty" yaml:"required,omitempty"`
+	// Dependencies values are the required property names or the schema when the key property is present
+	Dependencies map[string]interface{} `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+	OneOf        []*JSONSchema          `json:"oneOf,omitempty" yaml:"oneOf,omitempty"`
+	AnyOf        []*JSONSchema          `json:"anyOf,omitempty" yaml:"anyOf,omitempty"`
+	AllOf        []*JSONSchema          `json:"allOf,omitempty" yaml:"allOf,omitempty"`
+	Not          *JSONSchema            `json:"not,omitempty" yaml:"not,omitempty"`
+}
+
+// GenerateOpenAPI converts the experiment models to the OpenAPI document. Each target action is one path
+// like the blade create command, /{scope}/{target}/{sub targets...}/{action}, the host scope is omitted.
+// The inherited target flags, action matchers and action flags are the query parameters.
+func GenerateOpenAPI(title, version string, models ...ExpModelCommandSpec) *OpenAPIDocument {
+	document := &OpenAPIDocument{
+		OpenAPI: OpenAPIVersion,
+		Info:    OpenAPIInfo{Title: title, Version: version},
+		Paths:   make(map[string]*OpenAPIPathItem),
+		Components: &OpenAPIComponents{
+			Schemas: map[string]*JSONSchema{"Response": responseSchema()},
+		},
+	}
+	for _, model := range models {
+		WalkModels(model, func(path ModelPath) error {
+			for _, action := range ActionsForScope(path.Last(), model.Scope()) {
+				segments := make([]string, 0)
+				if model.Scope() != "" && model.Scope() != "host" {
+					segments = append(segments, model.Scope())
+				}
+				segments = append(segments, path.Names()...)
+				segments = append(segments, action.Name())
+				document.Paths["/"+strings.Join(segments, "/")] = &OpenAPIPathItem{
+					Post: actionOperation(segments, path, action),
+				}
+			}
+			return nil
+		})
+	}
+	return document
+}
+
+func actionOperation(segments []string, path ModelPath, action ExpActionCommandSpec) *OpenAPIOperation {
+	operation := &OpenAPIOperation{
+		OperationID: "create_" + strings.Join(segments, "_"),
+		Summary:     action.ShortDesc(),
+		Description: action.LongDesc(),
+		Tags:        []string{path[0].Name()},
+		Deprecated:  ActionDeprecatedOf(action) != "",
+		Parameters:  make([]*OpenAPIParameter, 0),
+		Responses: map[string]*OpenAPIResponse{
+			"200": {
+				Description: "the experiment response",
+				Content: map[string]*OpenAPIMediaType{
+					ContentTypeJSON: {Schema: &JSONSchema{Ref: "#/components/schemas/Response"}},
+				},
+			},
+		},
+	}
+	for _, flag := range actionFlagSpecs(path.Flags(), action) {
+		operation.Parameters = append(operation.Parameters, &OpenAPIParameter{
+			Name:        flag.FlagName(),
+			In:          "query",
+			Description: flag.FlagDesc(),
+			Required:    flag.FlagRequired(),
+			Deprecated:  FlagDeprecatedOf(flag) != "",
+			Schema:      flagSchema(flag),
+		})
+	}
+	return operation
+}
+
+// actionFlagSpecs returns the target flags, action matchers and action flags, the first one wins if duplicated
+func actionFlagSpecs(targetFlags []ExpFlagSpec, action ExpActionCommandSpec) []ExpFlagSpec {
+	flags := make([]ExpFlagSpec, 0)
+	names := make(map[string]bool)
+	for _, group := range [][]ExpFlagSpec{targetFlags, action.Matchers(), action.Flags()} {
+		for _, flag := range group {
+			if names[flag.FlagName()] {
+				continue
+			}
+			names[flag.FlagName()] = true
+			flags = append(flags, flag)
+		}
+	}
+	return flags
+}
+
+// flagSchema returns the value schema of the flag
+func flagSchema(flag ExpFlagSpec) *JSONSchema {
+	schema := &JSONSchema{Type: "string", Description: flag.FlagDesc(), Deprecated: FlagDeprecatedOf(flag) != ""}
+	flagType := FlagTypeOf(flag)
+	if flagType == "" && flag.FlagNoArgs() {
+		flagType = FlagTypeBool
+	}
+	switch flagType {
+	case FlagTypeInt:
+		schema.Type = "integer"
+	case FlagTypeFloat:
+		schema.Type = "number"
+	case FlagTypeBool:
+		schema.Type = "boolean"
+	case FlagTypeDuration, FlagTypeSize, FlagTypePercent:
+		schema.Format = flagType
+	case FlagTypeEnum:
+		schema.Enum = FlagEnumValuesOf(flag)
+	case FlagTypeJSON:
+		schema.Type = ""
+		if jsonSchema := FlagSchemaOf(flag); jsonSchema != nil {
+			copied := *jsonSchema
+			copied.Description, copied.Deprecated = schema.Description, schema.Deprecated
+			schema = &copied
+		}
+	}
+	if FlagSecretOf(flag) && schema.Type == "string" && schema.Format == "" {
+		schema.Format = "password"
+	}
+	if flag.FlagDefault() != "" {
+		schema.Default = flag.FlagDefault()
+		switch schema.Type {
+		case "integer", "number", "boolean", "object", "array", "":
+			if value, err := parseFlagValue(flag, flag.FlagDefault()); err == nil {
+				schema.Default = value
+			}
+		}
+	}
+	if validation := FlagValidationOf(flag); validation != nil {
+		if schema.Type == "integer" || schema.Type == "number" {
+			schema.Minimum = validation.Min
+			schema.Maximum = validation.Max
+		}
+		schema.Pattern = validation.Pattern
+		if validation.NonEmpty && schema.Type == "string" {
+			minLength := 1
+			schema.MinLength = &minLength
+		}
+	}
+	if FlagRepeatedOf(flag) {
+		schema.Description, schema.Deprecated = "", false
+		return &JSONSchema{Type: "array", Description: flag.FlagDesc(), Deprecated: FlagDeprecatedOf(flag) != "", Items: schema}
+	}
+	return schema
+}
+
+// responseSchema generates the schema of Response from its json fields, so the new fields are documented
+func responseSchema() *JSONSchema {
+	schema := typeSchema(reflect.TypeOf(Response{}))
+	schema.Properties["result"].Description = "the result of the experiment, the uid is returned if created"
+	return schema
+}
+
+// typeSchema returns the schema of the json encoding of the type, the struct fields without omitempty are required
+func typeSchema(t reflect.Type) *JSONSchema {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeSchema(t.Elem())
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &JSONSchema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &JSONSchema{Type: "array", Items: typeSchema(t.Elem())}
+	case reflect.Map:
+		return &JSONSchema{Type: "object"}
+	case reflect.Struct:
+		schema := &JSONSchema{Type: "object", Properties: make(map[string]*JSONSchema)}
+		for idx := 0; idx < t.NumField(); idx++ {
+			field := t.Field(idx)
+			name, omitEmpty, ok := jsonFieldName(field)
+			if !ok {
+				continue
+			}
+			schema.Properties[name] = typeSchema(field.Type)
+			if !omitEmpty {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+		return schema
+	}
+	// any value, such as interface{}
+	return &JSONSchema{}
+}
+
+// jsonFieldName returns the json name of the exported struct field, ok is false if it's not encoded
+func jsonFieldName(field reflect.StructField) (name string, omitEmpty bool, ok bool) {
+	if field.PkgPath != "" {
+		return "", false, false
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty, true
+}
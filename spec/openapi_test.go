@@ -0,0 +1,94 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestGenerateOpenAPI(t *testing.T) {
+	min := 0.0
+	model := &ExpCommandModel{
+		ExpName:  "cpu",
+		ExpScope: "docker",
+		ExpActions: []ActionModel{{
+			ActionName:      "fullload",
+			ActionShortDesc: "cpu load",
+			ActionMatchers:  []ExpFlag{{Name: "container-id", Required: true}},
+			ActionFlags: []ExpFlag{
+				{Name: "cpu-count", Type: FlagTypeInt, Default: "1", Validation: &FlagValidation{Min: &min}},
+				{Name: "mode", Type: FlagTypeEnum, EnumValues: []string{"user", "sys"}},
+				{Name: "container-id"},
+			},
+		}},
+	}
+	document := GenerateOpenAPI("chaosblade", "1.0.0", model)
+	item, ok := document.Paths["/docker/cpu/fullload"]
+	if !ok || item.Post == nil {
+		t.Fatalf("GenerateOpenAPI() paths = %v, want /docker/cpu/fullload", document.Paths)
+	}
+	operation := item.Post
+	if operation.OperationID != "create_docker_cpu_fullload" || operation.Summary != "cpu load" {
+		t.Errorf("operation = %+v", operation)
+	}
+	if len(operation.Parameters) != 3 {
+		t.Fatalf("parameters count = %d, want 3", len(operation.Parameters))
+	}
+	if p := operation.Parameters[0]; p.Name != "container-id" || !p.Required || p.In != "query" {
+		t.Errorf("parameter[0] = %+v", p)
+	}
+	schema := operation.Parameters[1].Schema
+	if schema.Type != "integer" || schema.Default != 1 || schema.Minimum == nil || *schema.Minimum != 0 {
+		t.Errorf("cpu-count schema = %+v", schema)
+	}
+	if schema := operation.Parameters[2].Schema; len(schema.Enum) != 2 {
+		t.Errorf("mode schema = %+v", schema)
+	}
+	if _, err := json.Marshal(document); err != nil {
+		t.Errorf("json.Marshal() error = %v", err)
+	}
+}
+
+func TestResponseSchema(t *testing.T) {
+	schema := responseSchema()
+	responseType := reflect.TypeOf(Response{})
+	for idx := 0; idx < responseType.NumField(); idx++ {
+		name, _, ok := jsonFieldName(responseType.Field(idx))
+		if ok && schema.Properties[name] == nil {
+			t.Errorf("responseSchema() has no property of the field %s", responseType.Field(idx).Name)
+		}
+	}
+	if !reflect.DeepEqual(schema.Required, []string{"code", "success"}) {
+		t.Errorf("responseSchema() required = %v", schema.Required)
+	}
+	if retryable := schema.Properties["retryable"]; retryable.Type != "boolean" {
+		t.Errorf("responseSchema() retryable = %+v", retryable)
+	}
+	causes := schema.Properties["causes"]
+	if causes.Type != "array" || causes.Items.Properties["origin"] == nil ||
+		!reflect.DeepEqual(causes.Items.Required, []string{"message"}) {
+		t.Errorf("responseSchema() causes = %+v", causes)
+	}
+	if fieldErrors := schema.Properties["fieldErrors"]; fieldErrors.Items.Properties["constraint"] == nil {
+		t.Errorf("responseSchema() fieldErrors = %+v", fieldErrors)
+	}
+	if result := schema.Properties["result"]; result.Type != "" || result.Description == "" {
+		t.Errorf("responseSchema() result = %+v", result)
+	}
+}
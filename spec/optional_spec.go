@@ -0,0 +1,427 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+// The interfaces below are the optional accessors of ExpFlagSpec and ExpActionCommandSpec. They are kept out of
+// the two interfaces so the implementations outside this package still compile, ExpFlag and the action specs of
+// this package implement all of them. The accessors are read by the ...Of functions, which check the interface
+// by type assertion and return the zero value if the spec doesn't implement it.
+
+// FlagTypeSpec is implemented by the flag spec which declares the value type
+type FlagTypeSpec interface {
+	// FlagType returns the value type of the flag, such as FlagTypeInt. Empty means string, or bool if FlagNoArgs
+	FlagType() string
+	// FlagEnumValues returns the allowed values of the enum type flag
+	FlagEnumValues() []string
+}
+
+// FlagValidationSpec is implemented by the flag spec which declares the validation rules
+type FlagValidationSpec interface {
+	// FlagValidation returns the validation rules of the flag value, nil means no rules
+	FlagValidation() *FlagValidation
+}
+
+// FlagSchemaSpec is implemented by the json type flag spec which declares the JSON Schema
+type FlagSchemaSpec interface {
+	// FlagSchema returns the JSON Schema of the json type flag value, nil means any object or array
+	FlagSchema() *JSONSchema
+}
+
+// FlagSourceSpec is implemented by the flag spec which reads the value from the environment or the config
+type FlagSourceSpec interface {
+	// FlagEnvVar returns the environment variable used as the value when the flag is absent
+	FlagEnvVar() string
+	// FlagConfigKey returns the config key used as the value when the flag and the environment variable are absent
+	FlagConfigKey() string
+}
+
+// FlagDeprecationSpec is implemented by the flag spec which can be deprecated
+type FlagDeprecationSpec interface {
+	// FlagDeprecated returns the deprecation message, empty means the flag is not deprecated
+	FlagDeprecated() string
+	// FlagReplacedBy returns the flag which replaces the deprecated flag
+	FlagReplacedBy() string
+}
+
+// FlagOperatorSpec is implemented by the matcher flag spec which declares the match operator
+type FlagOperatorSpec interface {
+	// FlagOperator returns the match operator of the matcher flag, equals is used if empty
+	FlagOperator() string
+}
+
+// FlagExamplesSpec is implemented by the flag spec which declares the usage examples
+type FlagExamplesSpec interface {
+	// FlagExamples returns the usage examples of the flag
+	FlagExamples() []Example
+}
+
+// FlagSecretSpec is implemented by the flag spec which can be sensitive
+type FlagSecretSpec interface {
+	// FlagSecret returns true if the flag value is sensitive and must be masked in logs and records
+	FlagSecret() bool
+}
+
+// FlagTranslationSpec is implemented by the flag spec which declares the display name and the translations
+type FlagTranslationSpec interface {
+	// FlagLabel returns the display name of the flag, empty means the flag name is used
+	FlagLabel() string
+	// FlagTranslations returns the label and the description of the flag keyed by locale
+	FlagTranslations() map[string]Translation
+}
+
+// FlagConditionSpec is implemented by the flag spec which is required or forbidden by conditions
+type FlagConditionSpec interface {
+	// FlagRequiredIf returns the condition expression which makes the flag required, such as protocol=tcp
+	FlagRequiredIf() string
+	// FlagForbiddenIf returns the condition expression which makes the flag not allowed, such as destroy=true
+	FlagForbiddenIf() string
+}
+
+// FlagRepeatedSpec is implemented by the flag spec which can be specified multiple times
+type FlagRepeatedSpec interface {
+	// FlagRepeated returns true if the flag can be specified multiple times, the values are got by
+	// ExpModel.GetStringSliceFlag
+	FlagRepeated() bool
+}
+
+// FlagVisibilitySpec is implemented by the flag spec which declares how it's listed in the help
+type FlagVisibilitySpec interface {
+	// FlagHidden returns true if the flag is kept out of the help and the UI listings, it still works if specified
+	FlagHidden() bool
+	// FlagGroup returns the group of the flag in the help, such as advanced, empty means the default group
+	FlagGroup() string
+}
+
+// FlagAliasesSpec is implemented by the flag spec which keeps the old names after renamed
+type FlagAliasesSpec interface {
+	// FlagAliases returns the old names of the flag, they are resolved to the flag name by ResolveFlagAliases
+	FlagAliases() []string
+}
+
+// ActionDeprecationSpec is implemented by the action spec which can be deprecated
+type ActionDeprecationSpec interface {
+	// Deprecated returns the deprecation message, empty means the action is not deprecated
+	Deprecated() string
+	// ReplacedBy returns the action which replaces the deprecated action
+	ReplacedBy() string
+}
+
+// ActionTimeoutSpec is implemented by the action spec which limits the deadline of one execution
+type ActionTimeoutSpec interface {
+	// DefaultTimeout returns the default deadline of one execution, such as 30s, empty means no timeout.
+	// It's the deadline of the executor call applied by TimeoutMiddleware, not the --timeout flag, which is the
+	// experiment duration governed by DefaultDuration and MaxDuration.
+	DefaultTimeout() string
+	// MaxTimeout returns the max deadline of one execution, empty means no limit. See DefaultTimeout.
+	MaxTimeout() string
+}
+
+// ActionDurationSpec is implemented by the action spec which limits how long the experiment lasts
+type ActionDurationSpec interface {
+	// DefaultDuration returns the experiment duration if the --timeout flag (TimeoutFlag) is absent, empty means
+	// running until destroyed. Despite the flag name, it's how long the experiment lasts, not the execution
+	// deadline governed by DefaultTimeout and MaxTimeout.
+	DefaultDuration() string
+	// MaxDuration returns the max experiment duration, the --timeout flag which exceeds it is illegal and the
+	// experiment is destroyed automatically after it, empty means no limit
+	MaxDuration() string
+}
+
+// ActionConcurrencySpec is implemented by the action spec which limits the running experiments
+type ActionConcurrencySpec interface {
+	// MaxConcurrency returns the max running experiments of the action on the host, 0 means no limit
+	MaxConcurrency() int
+}
+
+// ActionScopesSpec is implemented by the action spec which is only applicable to some scopes
+type ActionScopesSpec interface {
+	// Scopes returns the execution scopes which the action is applicable to, such as host and docker,
+	// empty means all scopes
+	Scopes() []string
+}
+
+// ActionPreconditionsSpec is implemented by the action spec which declares the preconditions
+type ActionPreconditionsSpec interface {
+	// Preconditions returns the precondition expressions evaluated before injecting, such as diskFree({path}) > 1G
+	Preconditions() []string
+}
+
+// ActionRequirementsSpec is implemented by the action spec which declares the environment requirements
+type ActionRequirementsSpec interface {
+	// Requirements returns the environment requirements checked before executing, nil means no requirement
+	Requirements() *ActionRequirements
+}
+
+// ActionExamplesSpec is implemented by the action spec which declares the structured usage examples
+type ActionExamplesSpec interface {
+	// Examples returns the structured usage examples of the action
+	Examples() []Example
+}
+
+// ActionTranslationSpec is implemented by the action spec which declares the translations
+type ActionTranslationSpec interface {
+	// Translations returns the label and the descriptions of the action keyed by locale
+	Translations() map[string]Translation
+}
+
+// FlagTypeOf returns the value type of the flag, see FlagTypeSpec
+func FlagTypeOf(flag ExpFlagSpec) string {
+	if f, ok := flag.(FlagTypeSpec); ok {
+		return f.FlagType()
+	}
+	return ""
+}
+
+// FlagEnumValuesOf returns the allowed values of the enum type flag, see FlagTypeSpec
+func FlagEnumValuesOf(flag ExpFlagSpec) []string {
+	if f, ok := flag.(FlagTypeSpec); ok {
+		return f.FlagEnumValues()
+	}
+	return nil
+}
+
+// FlagValidationOf returns the validation rules of the flag value, see FlagValidationSpec
+func FlagValidationOf(flag ExpFlagSpec) *FlagValidation {
+	if f, ok := flag.(FlagValidationSpec); ok {
+		return f.FlagValidation()
+	}
+	return nil
+}
+
+// FlagSchemaOf returns the JSON Schema of the json type flag value, see FlagSchemaSpec
+func FlagSchemaOf(flag ExpFlagSpec) *JSONSchema {
+	if f, ok := flag.(FlagSchemaSpec); ok {
+		return f.FlagSchema()
+	}
+	return nil
+}
+
+// FlagEnvVarOf returns the environment variable of the flag value, see FlagSourceSpec
+func FlagEnvVarOf(flag ExpFlagSpec) string {
+	if f, ok := flag.(FlagSourceSpec); ok {
+		return f.FlagEnvVar()
+	}
+	return ""
+}
+
+// FlagConfigKeyOf returns the config key of the flag value, see FlagSourceSpec
+func FlagConfigKeyOf(flag ExpFlagSpec) string {
+	if f, ok := flag.(FlagSourceSpec); ok {
+		return f.FlagConfigKey()
+	}
+	return ""
+}
+
+// FlagDeprecatedOf returns the deprecation message of the flag, see FlagDeprecationSpec
+func FlagDeprecatedOf(flag ExpFlagSpec) string {
+	if f, ok := flag.(FlagDeprecationSpec); ok {
+		return f.FlagDeprecated()
+	}
+	return ""
+}
+
+// FlagReplacedByOf returns the flag which replaces the deprecated flag, see FlagDeprecationSpec
+func FlagReplacedByOf(flag ExpFlagSpec) string {
+	if f, ok := flag.(FlagDeprecationSpec); ok {
+		return f.FlagReplacedBy()
+	}
+	return ""
+}
+
+// FlagOperatorOf returns the match operator of the matcher flag, see FlagOperatorSpec
+func FlagOperatorOf(flag ExpFlagSpec) string {
+	if f, ok := flag.(FlagOperatorSpec); ok {
+		return f.FlagOperator()
+	}
+	return ""
+}
+
+// FlagExamplesOf returns the usage examples of the flag, see FlagExamplesSpec
+func FlagExamplesOf(flag ExpFlagSpec) []Example {
+	if f, ok := flag.(FlagExamplesSpec); ok {
+		return f.FlagExamples()
+	}
+	return nil
+}
+
+// FlagSecretOf returns true if the flag value is sensitive, see FlagSecretSpec
+func FlagSecretOf(flag ExpFlagSpec) bool {
+	if f, ok := flag.(FlagSecretSpec); ok {
+		return f.FlagSecret()
+	}
+	return false
+}
+
+// FlagLabelOf returns the display name of the flag, see FlagTranslationSpec
+func FlagLabelOf(flag ExpFlagSpec) string {
+	if f, ok := flag.(FlagTranslationSpec); ok {
+		return f.FlagLabel()
+	}
+	return ""
+}
+
+// FlagTranslationsOf returns the translations of the flag keyed by locale, see FlagTranslationSpec
+func FlagTranslationsOf(flag ExpFlagSpec) map[string]Translation {
+	if f, ok := flag.(FlagTranslationSpec); ok {
+		return f.FlagTranslations()
+	}
+	return nil
+}
+
+// FlagRequiredIfOf returns the condition expression which makes the flag required, see FlagConditionSpec
+func FlagRequiredIfOf(flag ExpFlagSpec) string {
+	if f, ok := flag.(FlagConditionSpec); ok {
+		return f.FlagRequiredIf()
+	}
+	return ""
+}
+
+// FlagForbiddenIfOf returns the condition expression which makes the flag not allowed, see FlagConditionSpec
+func FlagForbiddenIfOf(flag ExpFlagSpec) string {
+	if f, ok := flag.(FlagConditionSpec); ok {
+		return f.FlagForbiddenIf()
+	}
+	return ""
+}
+
+// FlagRepeatedOf returns true if the flag can be specified multiple times, see FlagRepeatedSpec
+func FlagRepeatedOf(flag ExpFlagSpec) bool {
+	if f, ok := flag.(FlagRepeatedSpec); ok {
+		return f.FlagRepeated()
+	}
+	return false
+}
+
+// FlagHiddenOf returns true if the flag is kept out of the help, see FlagVisibilitySpec
+func FlagHiddenOf(flag ExpFlagSpec) bool {
+	if f, ok := flag.(FlagVisibilitySpec); ok {
+		return f.FlagHidden()
+	}
+	return false
+}
+
+// FlagGroupOf returns the help group of the flag, see FlagVisibilitySpec
+func FlagGroupOf(flag ExpFlagSpec) string {
+	if f, ok := flag.(FlagVisibilitySpec); ok {
+		return f.FlagGroup()
+	}
+	return ""
+}
+
+// FlagAliasesOf returns the old names of the flag, see FlagAliasesSpec
+func FlagAliasesOf(flag ExpFlagSpec) []string {
+	if f, ok := flag.(FlagAliasesSpec); ok {
+		return f.FlagAliases()
+	}
+	return nil
+}
+
+// ActionDeprecatedOf returns the deprecation message of the action, see ActionDeprecationSpec
+func ActionDeprecatedOf(action ExpActionCommandSpec) string {
+	if a, ok := action.(ActionDeprecationSpec); ok {
+		return a.Deprecated()
+	}
+	return ""
+}
+
+// ActionReplacedByOf returns the action which replaces the deprecated action, see ActionDeprecationSpec
+func ActionReplacedByOf(action ExpActionCommandSpec) string {
+	if a, ok := action.(ActionDeprecationSpec); ok {
+		return a.ReplacedBy()
+	}
+	return ""
+}
+
+// ActionDefaultTimeoutOf returns the default deadline of one execution, see ActionTimeoutSpec
+func ActionDefaultTimeoutOf(action ExpActionCommandSpec) string {
+	if a, ok := action.(ActionTimeoutSpec); ok {
+		return a.DefaultTimeout()
+	}
+	return ""
+}
+
+// ActionMaxTimeoutOf returns the max deadline of one execution, see ActionTimeoutSpec
+func ActionMaxTimeoutOf(action ExpActionCommandSpec) string {
+	if a, ok := action.(ActionTimeoutSpec); ok {
+		return a.MaxTimeout()
+	}
+	return ""
+}
+
+// ActionDefaultDurationOf returns the experiment duration if the --timeout flag is absent, see ActionDurationSpec
+func ActionDefaultDurationOf(action ExpActionCommandSpec) string {
+	if a, ok := action.(ActionDurationSpec); ok {
+		return a.DefaultDuration()
+	}
+	return ""
+}
+
+// ActionMaxDurationOf returns the max experiment duration, see ActionDurationSpec
+func ActionMaxDurationOf(action ExpActionCommandSpec) string {
+	if a, ok := action.(ActionDurationSpec); ok {
+		return a.MaxDuration()
+	}
+	return ""
+}
+
+// ActionMaxConcurrencyOf returns the max running experiments of the action, see ActionConcurrencySpec
+func ActionMaxConcurrencyOf(action ExpActionCommandSpec) int {
+	if a, ok := action.(ActionConcurrencySpec); ok {
+		return a.MaxConcurrency()
+	}
+	return 0
+}
+
+// ActionScopesOf returns the execution scopes which the action is applicable to, see ActionScopesSpec
+func ActionScopesOf(action ExpActionCommandSpec) []string {
+	if a, ok := action.(ActionScopesSpec); ok {
+		return a.Scopes()
+	}
+	return nil
+}
+
+// ActionPreconditionsOf returns the precondition expressions of the action, see ActionPreconditionsSpec
+func ActionPreconditionsOf(action ExpActionCommandSpec) []string {
+	if a, ok := action.(ActionPreconditionsSpec); ok {
+		return a.Preconditions()
+	}
+	return nil
+}
+
+// ActionRequirementsOf returns the environment requirements of the action, see ActionRequirementsSpec
+func ActionRequirementsOf(action ExpActionCommandSpec) *ActionRequirements {
+	if a, ok := action.(ActionRequirementsSpec); ok {
+		return a.Requirements()
+	}
+	return nil
+}
+
+// ActionExamplesOf returns the structured usage examples of the action, see ActionExamplesSpec
+func ActionExamplesOf(action ExpActionCommandSpec) []Example {
+	if a, ok := action.(ActionExamplesSpec); ok {
+		return a.Examples()
+	}
+	return nil
+}
+
+// ActionTranslationsOf returns the translations of the action keyed by locale, see ActionTranslationSpec
+func ActionTranslationsOf(action ExpActionCommandSpec) map[string]Translation {
+	if a, ok := action.(ActionTranslationSpec); ok {
+		return a.Translations()
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"testing"
+)
+
+// baseFlag only implements ExpFlagSpec, like the flags implemented outside this package
+type baseFlag struct {
+	ExpFlagSpec
+}
+
+// baseAction only implements ExpActionCommandSpec, like the actions implemented outside this package
+type baseAction struct {
+	ExpActionCommandSpec
+}
+
+func TestOptionalFlagSpec(t *testing.T) {
+	full := &ExpFlag{Name: "port", Type: FlagTypeInt, Repeated: true, Secret: true, Aliases: []string{"p"}}
+	if FlagTypeOf(full) != FlagTypeInt || !FlagRepeatedOf(full) || !FlagSecretOf(full) || len(FlagAliasesOf(full)) != 1 {
+		t.Errorf("the optional accessors of ExpFlag are not read")
+	}
+	flag := baseFlag{full}
+	if FlagTypeOf(flag) != "" || FlagRepeatedOf(flag) || FlagSecretOf(flag) || FlagAliasesOf(flag) != nil {
+		t.Errorf("the optional accessors of the base flag must be the zero values")
+	}
+	if err := ValidateFlagValue(flag, "abc"); err != nil {
+		t.Errorf("ValidateFlagValue() of the base flag error = %v", err)
+	}
+}
+
+func TestOptionalActionSpec(t *testing.T) {
+	full := &ActionModel{ActionName: "fullload", ActionScopes: []string{ScopeHost}, ActionMaxConcurrency: 1}
+	if len(ActionScopesOf(full)) != 1 || ActionMaxConcurrencyOf(full) != 1 {
+		t.Errorf("the optional accessors of ActionModel are not read")
+	}
+	action := baseAction{full}
+	if ActionScopesOf(action) != nil || ActionMaxConcurrencyOf(action) != 0 || ActionRequirementsOf(action) != nil {
+		t.Errorf("the optional accessors of the base action must be the zero values")
+	}
+	if !ActionSupportsScope(action, ScopeDocker) {
+		t.Errorf("ActionSupportsScope() of the base action must be true")
+	}
+}
@@ -0,0 +1,201 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ModelOverrides is the user-provided fragment overlaid onto the registered models to customize
+// the plugin behavior, for example changing defaults, hiding actions and tightening allowed values
+type ModelOverrides struct {
+	Items []TargetOverride `yaml:"items"`
+}
+
+// TargetOverride overrides the target, the nested sub target is specified by the path like "k8s pod network"
+type TargetOverride struct {
+	Target  string           `yaml:"target"`
+	Flags   []FlagOverride   `yaml:"flags,omitempty"`
+	Actions []ActionOverride `yaml:"actions,omitempty"`
+}
+
+// ActionOverride overrides the action, the action is removed if hidden
+type ActionOverride struct {
+	Action   string         `yaml:"action"`
+	Hidden   bool           `yaml:"hidden,omitempty"`
+	Matchers []FlagOverride `yaml:"matchers,omitempty"`
+	Flags    []FlagOverride `yaml:"flags,omitempty"`
+}
+
+// FlagOverride overrides the flag, the nil or empty fields are not changed. The enum values must be the subset
+// of the original ones and the validation range can only be narrowed.
+type FlagOverride struct {
+	Name       string          `yaml:"name"`
+	Default    *string         `yaml:"default,omitempty"`
+	Required   *bool           `yaml:"required,omitempty"`
+	EnumValues []string        `yaml:"enumValues,flow,omitempty"`
+	Validation *FlagValidation `yaml:"validation,omitempty"`
+}
+
+// ApplyOverrides overlays the overrides onto the models, it returns error if the target, action or flag
+// is not found or the allowed values are loosened, and the models may be partially changed in this case
+func ApplyOverrides(models *Models, overrides *ModelOverrides) error {
+	for _, targetOverride := range overrides.Items {
+		target := findCommandModel(models, strings.Fields(targetOverride.Target))
+		if target == nil {
+			return fmt.Errorf("override target `%s` not found", targetOverride.Target)
+		}
+		if err := applyFlagOverrides(target.ExpFlags, targetOverride.Flags); err != nil {
+			return fmt.Errorf("override target `%s` failed, %v", targetOverride.Target, err)
+		}
+		for _, actionOverride := range targetOverride.Actions {
+			if err := applyActionOverride(target, actionOverride); err != nil {
+				return fmt.Errorf("override target `%s` failed, %v", targetOverride.Target, err)
+			}
+		}
+	}
+	return nil
+}
+
+func findCommandModel(models *Models, path []string) *ExpCommandModel {
+	if len(path) == 0 {
+		return nil
+	}
+	candidates := models.Models
+	var found *ExpCommandModel
+	for _, name := range path {
+		found = nil
+		for idx := range candidates {
+			if candidates[idx].ExpName == name {
+				found = &candidates[idx]
+				break
+			}
+		}
+		if found == nil {
+			return nil
+		}
+		candidates = found.ExpSubModels
+	}
+	return found
+}
+
+func applyActionOverride(target *ExpCommandModel, override ActionOverride) error {
+	for idx := range target.ExpActions {
+		action := &target.ExpActions[idx]
+		if action.ActionName != override.Action {
+			continue
+		}
+		if override.Hidden {
+			target.ExpActions = append(target.ExpActions[:idx], target.ExpActions[idx+1:]...)
+			return nil
+		}
+		if err := applyFlagOverrides(action.ActionMatchers, override.Matchers); err != nil {
+			return fmt.Errorf("action `%s`: %v", override.Action, err)
+		}
+		if err := applyFlagOverrides(action.ActionFlags, override.Flags); err != nil {
+			return fmt.Errorf("action `%s`: %v", override.Action, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("action `%s` not found", override.Action)
+}
+
+func applyFlagOverrides(flags []ExpFlag, overrides []FlagOverride) error {
+	for _, override := range overrides {
+		var flag *ExpFlag
+		for idx := range flags {
+			if flags[idx].Name == override.Name {
+				flag = &flags[idx]
+				break
+			}
+		}
+		if flag == nil {
+			return fmt.Errorf("flag `%s` not found", override.Name)
+		}
+		if err := applyFlagOverride(flag, override); err != nil {
+			return fmt.Errorf("flag `%s`: %v", override.Name, err)
+		}
+	}
+	return nil
+}
+
+func applyFlagOverride(flag *ExpFlag, override FlagOverride) error {
+	if len(override.EnumValues) > 0 {
+		if len(flag.EnumValues) > 0 {
+			for _, value := range override.EnumValues {
+				if err := checkEnumValue(flag.EnumValues, value); err != nil {
+					return fmt.Errorf("can not loosen the enum values, %v", err)
+				}
+			}
+		}
+		flag.EnumValues = override.EnumValues
+	}
+	if override.Validation != nil {
+		validation, err := narrowValidation(flag.Validation, override.Validation)
+		if err != nil {
+			return err
+		}
+		flag.Validation = validation
+	}
+	if override.Required != nil {
+		flag.Required = *override.Required
+	}
+	if override.Default != nil {
+		if *override.Default != "" {
+			if err := ValidateFlagValue(flag, *override.Default); err != nil {
+				return fmt.Errorf("illegal default value, %v", err)
+			}
+		}
+		flag.Default = *override.Default
+	}
+	return nil
+}
+
+// narrowValidation returns the validation overridden, the range can only be narrowed
+func narrowValidation(origin, override *FlagValidation) (*FlagValidation, error) {
+	validation := &FlagValidation{}
+	if origin != nil {
+		*validation = *origin
+	}
+	if override.Min != nil {
+		if validation.Min != nil && *override.Min < *validation.Min {
+			return nil, fmt.Errorf("can not loosen the min value %v to %v", *validation.Min, *override.Min)
+		}
+		validation.Min = override.Min
+	}
+	if override.Max != nil {
+		if validation.Max != nil && *override.Max > *validation.Max {
+			return nil, fmt.Errorf("can not loosen the max value %v to %v", *validation.Max, *override.Max)
+		}
+		validation.Max = override.Max
+	}
+	if override.Pattern != "" {
+		if validation.Pattern != "" && validation.Pattern != override.Pattern {
+			return nil, fmt.Errorf("can not replace the pattern %s", validation.Pattern)
+		}
+		validation.Pattern = override.Pattern
+	}
+	validation.NonEmpty = validation.NonEmpty || override.NonEmpty
+	validation.Validators = append([]string{}, validation.Validators...)
+	for _, name := range override.Validators {
+		if !containsString(validation.Validators, name) {
+			validation.Validators = append(validation.Validators, name)
+		}
+	}
+	return validation, nil
+}
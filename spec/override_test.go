@@ -0,0 +1,89 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newOverrideModels() *Models {
+	max := 100.0
+	return &Models{Models: []ExpCommandModel{{
+		ExpName: "cpu",
+		ExpActions: []ActionModel{
+			{ActionName: "fullload", ActionFlags: []ExpFlag{
+				{Name: "cpu-percent", Type: FlagTypeInt, Default: "100", Validation: &FlagValidation{Max: &max}},
+				{Name: "mode", Type: FlagTypeEnum, EnumValues: []string{"user", "sys", "iowait"}},
+			}},
+			{ActionName: "burn"},
+		},
+	}}}
+}
+
+func TestApplyOverrides(t *testing.T) {
+	defaultPercent, max := "50", 80.0
+	models := newOverrideModels()
+	err := ApplyOverrides(models, &ModelOverrides{Items: []TargetOverride{{
+		Target: "cpu",
+		Actions: []ActionOverride{
+			{Action: "burn", Hidden: true},
+			{Action: "fullload", Flags: []FlagOverride{
+				{Name: "cpu-percent", Default: &defaultPercent, Validation: &FlagValidation{Max: &max}},
+				{Name: "mode", EnumValues: []string{"user"}},
+			}},
+		},
+	}}})
+	if err != nil {
+		t.Fatalf("ApplyOverrides() error = %v", err)
+	}
+	actions := models.Models[0].ExpActions
+	if len(actions) != 1 || actions[0].ActionName != "fullload" {
+		t.Fatalf("actions = %v, want burn hidden", actions)
+	}
+	percent := actions[0].ActionFlags[0]
+	if percent.Default != "50" || *percent.Validation.Max != 80 {
+		t.Errorf("cpu-percent = %+v, want default 50 and max 80", percent)
+	}
+	if !reflect.DeepEqual(actions[0].ActionFlags[1].EnumValues, []string{"user"}) {
+		t.Errorf("mode enum values = %v, want [user]", actions[0].ActionFlags[1].EnumValues)
+	}
+}
+
+func TestApplyOverridesRejected(t *testing.T) {
+	loose, illegal := 120.0, "90"
+	tests := []struct {
+		name     string
+		override TargetOverride
+	}{
+		{"unknown target", TargetOverride{Target: "mem"}},
+		{"unknown action", TargetOverride{Target: "cpu", Actions: []ActionOverride{{Action: "load"}}}},
+		{"loosen max", TargetOverride{Target: "cpu", Actions: []ActionOverride{{Action: "fullload",
+			Flags: []FlagOverride{{Name: "cpu-percent", Validation: &FlagValidation{Max: &loose}}}}}}},
+		{"loosen enum", TargetOverride{Target: "cpu", Actions: []ActionOverride{{Action: "fullload",
+			Flags: []FlagOverride{{Name: "mode", EnumValues: []string{"steal"}}}}}}},
+		{"illegal default", TargetOverride{Target: "cpu", Actions: []ActionOverride{{Action: "fullload",
+			Flags: []FlagOverride{{Name: "mode", Default: &illegal}}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ApplyOverrides(newOverrideModels(), &ModelOverrides{Items: []TargetOverride{tt.override}}); err == nil {
+				t.Errorf("ApplyOverrides() expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DefaultPageLimit is the page size if the limit is not positive
+const DefaultPageLimit = 100
+
+const pageTokenPrefix = "offset:"
+
+// Page is the paginated result of the list commands
+type Page struct {
+	// Items is the slice of the current page
+	Items interface{} `json:"items"`
+	// Total is the count of all items
+	Total int `json:"total"`
+	// NextToken is used to get the next page, empty means the last page
+	NextToken string `json:"nextToken,omitempty"`
+}
+
+// NewPage returns the page of the items started from the offset, it is used by the data sources paginated
+// by themselves, for example database queries
+func NewPage(items interface{}, total, offset int) *Page {
+	page := &Page{Items: items, Total: total}
+	if next := offset + reflect.ValueOf(items).Len(); next < total {
+		page.NextToken = EncodePageToken(next)
+	}
+	return page
+}
+
+// Paginate returns the page of the items slice by the limit and the token of the previous page
+func Paginate(items interface{}, limit int, token string) (*Page, error) {
+	value := reflect.ValueOf(items)
+	if value.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("the items to paginate must be slice, but %T", items)
+	}
+	offset, err := DecodePageToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = DefaultPageLimit
+	}
+	total := value.Len()
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return NewPage(value.Slice(offset, end).Interface(), total, offset), nil
+}
+
+// EncodePageToken returns the opaque token of the offset
+func EncodePageToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(pageTokenPrefix + strconv.Itoa(offset)))
+}
+
+// DecodePageToken returns the offset of the token, empty token means the first page
+func DecodePageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	bytes, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil && strings.HasPrefix(string(bytes), pageTokenPrefix) {
+		offset, err := strconv.Atoi(strings.TrimPrefix(string(bytes), pageTokenPrefix))
+		if err == nil && offset >= 0 {
+			return offset, nil
+		}
+	}
+	return 0, fmt.Errorf(ParameterIllegal.Sprintf("nextToken", token, "illegal page token"))
+}
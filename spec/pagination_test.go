@@ -0,0 +1,52 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e"}
+	pages := make([][]string, 0)
+	token := ""
+	for {
+		page, err := Paginate(items, 2, token)
+		if err != nil {
+			t.Fatalf("Paginate() error = %v", err)
+		}
+		if page.Total != len(items) {
+			t.Errorf("Total = %d, want %d", page.Total, len(items))
+		}
+		pages = append(pages, page.Items.([]string))
+		if page.NextToken == "" {
+			break
+		}
+		token = page.NextToken
+	}
+	want := [][]string{{"a", "b"}, {"c", "d"}, {"e"}}
+	if !reflect.DeepEqual(pages, want) {
+		t.Errorf("pages = %v, want %v", pages, want)
+	}
+	if _, err := Paginate(items, 2, "illegal"); err == nil {
+		t.Errorf("Paginate() expected error for illegal token")
+	}
+	if _, err := Paginate("abc", 2, ""); err == nil {
+		t.Errorf("Paginate() expected error for non-slice items")
+	}
+}
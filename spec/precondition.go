@@ -0,0 +1,292 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The statuses of the precondition results
+const (
+	PreconditionPassed  = "passed"
+	PreconditionFailed  = "failed"
+	PreconditionSkipped = "skipped"
+	PreconditionError   = "error"
+)
+
+// PreconditionCheck is the function of the precondition expression, such as processExists and diskFree
+type PreconditionCheck struct {
+	// Type is the flag type of the result, the value compared with is parsed by it, such as size for 1G.
+	// The bool check can not be compared.
+	Type string
+	// Args is the number of the args
+	Args int
+	// Eval returns the result of the args by the channel, it is bool, int, int64 or float64
+	Eval func(ctx context.Context, channel Channel, args []string) (interface{}, error)
+}
+
+// Precondition is the parsed expression like check(arg, ...) [op value], the arg like {name} is the flag value
+type Precondition struct {
+	Expression string
+	Check      string
+	Args       []string
+	Operator   string
+	Value      string
+}
+
+// PreconditionResult is the result of one precondition
+type PreconditionResult struct {
+	Expression string `json:"expression"`
+	Status     string `json:"status"`
+	// Actual is the result of the check
+	Actual  string `json:"actual,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// PreconditionReport is the result of the PreconditionNotSatisfied response
+type PreconditionReport struct {
+	Results []PreconditionResult `json:"results"`
+}
+
+// Failed returns the results which are failed or error
+func (r *PreconditionReport) Failed() []PreconditionResult {
+	failed := make([]PreconditionResult, 0)
+	for _, result := range r.Results {
+		if result.Status == PreconditionFailed || result.Status == PreconditionError {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+var preconditionPattern = regexp.MustCompile(`^\s*([A-Za-z][A-Za-z0-9_]*)\s*\(([^()]*)\)\s*(?:(>=|<=|==|!=|>|<)\s*(\S+))?\s*$`)
+
+var flagRefPattern = regexp.MustCompile(`^\{([A-Za-z0-9_-]+)\}$`)
+
+var (
+	preconditionChecks = map[string]PreconditionCheck{
+		"processExists": {Type: FlagTypeBool, Args: 1, Eval: func(ctx context.Context, channel Channel, args []string) (interface{}, error) {
+			pids, err := channel.GetPidsByProcessName(args[0], ctx)
+			return len(pids) > 0, err
+		}},
+		"pidExists": {Type: FlagTypeBool, Args: 1, Eval: func(ctx context.Context, channel Channel, args []string) (interface{}, error) {
+			return channel.ProcessExists(args[0])
+		}},
+		"portListening": {Type: FlagTypeBool, Args: 1, Eval: func(ctx context.Context, channel Channel, args []string) (interface{}, error) {
+			pids, err := channel.GetPidsByLocalPort(ctx, args[0])
+			return len(pids) > 0, err
+		}},
+		"commandExists": {Type: FlagTypeBool, Args: 1, Eval: func(ctx context.Context, channel Channel, args []string) (interface{}, error) {
+			return channel.IsCommandAvailable(ctx, args[0]), nil
+		}},
+		"diskFree": {Type: FlagTypeSize, Args: 1, Eval: diskFree},
+	}
+	preconditionChecksLock sync.RWMutex
+)
+
+// RegisterPreconditionCheck registers the check used in the precondition expressions
+func RegisterPreconditionCheck(name string, check PreconditionCheck) {
+	preconditionChecksLock.Lock()
+	defer preconditionChecksLock.Unlock()
+	preconditionChecks[name] = check
+}
+
+func getPreconditionCheck(name string) (PreconditionCheck, bool) {
+	preconditionChecksLock.RLock()
+	defer preconditionChecksLock.RUnlock()
+	check, ok := preconditionChecks[name]
+	return check, ok
+}
+
+// ParsePrecondition parses the expression, such as processExists({process}), portListening(8080) and
+// diskFree({path}) > 1G. The built-in checks are processExists, pidExists, portListening, commandExists
+// and diskFree, the others are registered by RegisterPreconditionCheck.
+func ParsePrecondition(expression string) (*Precondition, error) {
+	matches := preconditionPattern.FindStringSubmatch(expression)
+	if matches == nil {
+		return nil, fmt.Errorf("illegal precondition `%s`, the format is check(arg, ...) [op value]", expression)
+	}
+	check, ok := getPreconditionCheck(matches[1])
+	if !ok {
+		return nil, fmt.Errorf("unknown precondition check `%s`", matches[1])
+	}
+	precondition := &Precondition{Expression: strings.TrimSpace(expression), Check: matches[1],
+		Args: make([]string, 0), Operator: matches[3], Value: matches[4]}
+	if args := strings.TrimSpace(matches[2]); args != "" {
+		for _, arg := range strings.Split(args, ",") {
+			precondition.Args = append(precondition.Args, strings.TrimSpace(arg))
+		}
+	}
+	if len(precondition.Args) != check.Args {
+		return nil, fmt.Errorf("the `%s` check requires %d args", precondition.Check, check.Args)
+	}
+	if check.Type == FlagTypeBool && precondition.Operator != "" {
+		return nil, fmt.Errorf("the `%s` check can not be compared", precondition.Check)
+	}
+	if check.Type != FlagTypeBool && precondition.Operator == "" {
+		return nil, fmt.Errorf("the `%s` check must be compared with a value", precondition.Check)
+	}
+	if precondition.Operator != "" {
+		if _, err := parseFlagValue(&ExpFlag{Type: check.Type}, precondition.Value); err != nil {
+			return nil, fmt.Errorf("illegal value `%s` of the `%s` check, %v", precondition.Value, precondition.Check, err)
+		}
+	}
+	return precondition, nil
+}
+
+// Evaluate evaluates the precondition by the channel, the flag args are taken from the model.
+// The precondition is skipped if any flag arg is absent.
+func (p *Precondition) Evaluate(ctx context.Context, channel Channel, model *ExpModel) PreconditionResult {
+	result := PreconditionResult{Expression: p.Expression}
+	check, ok := getPreconditionCheck(p.Check)
+	if !ok {
+		result.Status, result.Message = PreconditionError, fmt.Sprintf("unknown precondition check `%s`", p.Check)
+		return result
+	}
+	args := make([]string, len(p.Args))
+	for idx, arg := range p.Args {
+		args[idx] = arg
+		if matches := flagRefPattern.FindStringSubmatch(arg); matches != nil {
+			value, ok := model.flagValue(matches[1])
+			if !ok {
+				result.Status, result.Message = PreconditionSkipped, fmt.Sprintf("the %s flag is absent", matches[1])
+				return result
+			}
+			args[idx] = value
+		}
+	}
+	actual, err := check.Eval(ctx, channel, args)
+	if err != nil {
+		result.Status, result.Message = PreconditionError, err.Error()
+		return result
+	}
+	result.Actual = fmt.Sprint(actual)
+	if satisfied, ok := actual.(bool); ok && p.Operator == "" {
+		result.Status = PreconditionFailed
+		if satisfied {
+			result.Status = PreconditionPassed
+		}
+		return result
+	}
+	satisfied, err := comparePrecondition(actual, p.Operator, p.Value, check.Type)
+	if err != nil {
+		result.Status, result.Message = PreconditionError, err.Error()
+		return result
+	}
+	result.Status = PreconditionFailed
+	if satisfied {
+		result.Status = PreconditionPassed
+	}
+	return result
+}
+
+func comparePrecondition(actual interface{}, operator, value, valueType string) (bool, error) {
+	expected, err := parseFlagValue(&ExpFlag{Type: valueType}, value)
+	if err != nil {
+		return false, err
+	}
+	a, err := numericValue(actual)
+	if err != nil {
+		return false, err
+	}
+	e, err := numericValue(expected)
+	if err != nil {
+		return false, err
+	}
+	switch operator {
+	case ">":
+		return a > e, nil
+	case ">=":
+		return a >= e, nil
+	case "<":
+		return a < e, nil
+	case "<=":
+		return a <= e, nil
+	case "==":
+		return a == e, nil
+	case "!=":
+		return a != e, nil
+	}
+	return false, fmt.Errorf("unknown operator `%s`", operator)
+}
+
+// EvaluatePreconditions evaluates all the preconditions of the action, it returns nil if none is failed,
+// otherwise the PreconditionNotSatisfied response whose result is the *PreconditionReport.
+func EvaluatePreconditions(ctx context.Context, channel Channel, action ExpActionCommandSpec, model *ExpModel) *Response {
+	if len(ActionPreconditionsOf(action)) == 0 {
+		return nil
+	}
+	report := &PreconditionReport{Results: make([]PreconditionResult, 0)}
+	for _, expression := range ActionPreconditionsOf(action) {
+		precondition, err := ParsePrecondition(expression)
+		if err != nil {
+			report.Results = append(report.Results, PreconditionResult{Expression: expression,
+				Status: PreconditionError, Message: err.Error()})
+			continue
+		}
+		report.Results = append(report.Results, precondition.Evaluate(ctx, channel, model))
+	}
+	failed := report.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	expressions := make([]string, 0, len(failed))
+	for _, result := range failed {
+		expressions = append(expressions, result.Expression)
+	}
+	response := ResponseFailWithFlags(PreconditionNotSatisfied, strings.Join(expressions, ", "))
+	response.Result = report
+	return response
+}
+
+// PreconditionMiddleware returns the middleware which evaluates the preconditions of the action by the channel
+// before injecting, the destroy requests are not checked
+func PreconditionMiddleware(channel Channel, action ExpActionCommandSpec) ExecutorMiddleware {
+	return ExecutorMiddlewareFunc(func(next ExecFunc) ExecFunc {
+		return func(uid string, ctx context.Context, model *ExpModel) *Response {
+			if _, isDestroy := IsDestroy(ctx); !isDestroy {
+				if response := EvaluatePreconditions(ctx, channel, action, model); response != nil {
+					return response
+				}
+			}
+			return next(uid, ctx, model)
+		}
+	})
+}
+
+// diskFree returns the available bytes of the file system which the path is on by df
+func diskFree(ctx context.Context, channel Channel, args []string) (interface{}, error) {
+	response := channel.Run(ctx, "df", "-Pk "+QuoteArgs(args[:1]))
+	if !response.Success {
+		return nil, fmt.Errorf("df %s failed, %s", args[0], response.Err)
+	}
+	lines := strings.Split(strings.TrimSpace(fmt.Sprint(response.Result)), "\n")
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(lines) < 2 || len(fields) < 4 {
+		return nil, fmt.Errorf("unexpected df output, %v", response.Result)
+	}
+	available, err := strconv.ParseInt(fields[3], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected df output, %v", response.Result)
+	}
+	return available * 1024, nil
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"testing"
+)
+
+// preconditionChannel is the channel of the precondition tests, only the used methods are implemented
+type preconditionChannel struct {
+	Channel
+	processes map[string]bool
+	ports     map[string]bool
+	df        string
+}
+
+func (c *preconditionChannel) GetPidsByProcessName(processName string, ctx context.Context) ([]string, error) {
+	if c.processes[processName] {
+		return []string{"1"}, nil
+	}
+	return nil, nil
+}
+
+func (c *preconditionChannel) GetPidsByLocalPort(ctx context.Context, localPort string) ([]string, error) {
+	if c.ports[localPort] {
+		return []string{"1"}, nil
+	}
+	return nil, nil
+}
+
+func (c *preconditionChannel) Run(ctx context.Context, script, args string) *Response {
+	return ReturnSuccess(c.df)
+}
+
+func TestParsePrecondition(t *testing.T) {
+	tests := []struct {
+		expression string
+		wantErr    bool
+	}{
+		{expression: "processExists({process})"},
+		{expression: " diskFree( /data ) >= 1G "},
+		{expression: "processExists", wantErr: true},
+		{expression: "unknown(a)", wantErr: true},
+		{expression: "processExists(a, b)", wantErr: true},
+		{expression: "processExists(a) == 1", wantErr: true},
+		{expression: "diskFree(/data)", wantErr: true},
+		{expression: "diskFree(/data) > lots", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			if _, err := ParsePrecondition(tt.expression); (err != nil) != tt.wantErr {
+				t.Errorf("ParsePrecondition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvaluatePreconditions(t *testing.T) {
+	channel := &preconditionChannel{
+		processes: map[string]bool{"nginx": true},
+		ports:     map[string]bool{"80": true},
+		df: "Filesystem     1024-blocks    Used Available Capacity Mounted on\n" +
+			"/dev/vda1         41152736 9000000   2097152      22% /\n",
+	}
+	action := &ActionModel{ActionPreconditions: []string{
+		"processExists({process})", "portListening({port})", "diskFree({path}) > 1G",
+	}}
+	tests := []struct {
+		name     string
+		flags    map[string]string
+		statuses []string
+	}{
+		{name: "passed", flags: map[string]string{"process": "nginx", "port": "80", "path": "/"}},
+		{name: "skipped", flags: map[string]string{}},
+		{name: "failed", flags: map[string]string{"process": "redis", "port": "80", "path": "/"},
+			statuses: []string{PreconditionFailed, PreconditionPassed, PreconditionPassed}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := EvaluatePreconditions(context.Background(), channel, action, &ExpModel{ActionFlags: tt.flags})
+			if tt.statuses == nil {
+				if response != nil {
+					t.Errorf("EvaluatePreconditions() = %s, want nil", response.Print())
+				}
+				return
+			}
+			if response == nil || response.Code != PreconditionNotSatisfied.Code {
+				t.Fatalf("EvaluatePreconditions() = %v, want PreconditionNotSatisfied", response)
+			}
+			report := response.Result.(*PreconditionReport)
+			for idx, status := range tt.statuses {
+				if report.Results[idx].Status != status {
+					t.Errorf("the status of %s = %s, want %s", report.Results[idx].Expression, report.Results[idx].Status, status)
+				}
+			}
+		})
+	}
+	channel.df = "Filesystem 1024-blocks Used Available Capacity Mounted on\n/dev/vda1 100 99 1 99% /\n"
+	response := EvaluatePreconditions(context.Background(), channel, &ActionModel{ActionPreconditions: []string{"diskFree(/) > 1G"}},
+		&ExpModel{})
+	if response == nil || response.Result.(*PreconditionReport).Results[0].Actual != "1024" {
+		t.Errorf("EvaluatePreconditions() = %v, want diskFree failed with 1024 bytes", response)
+	}
+}
+
+func TestPreconditionMiddleware(t *testing.T) {
+	channel := &preconditionChannel{}
+	action := &ActionModel{ActionPreconditions: []string{"processExists(nginx)"}}
+	executed := false
+	executor := WithMiddlewares(&testExecutor{exec: func(uid string, ctx context.Context, model *ExpModel) *Response {
+		executed = true
+		return ReturnSuccess(nil)
+	}}, PreconditionMiddleware(channel, action))
+	if response := executor.Exec("uid", context.Background(), &ExpModel{}); response.Code != PreconditionNotSatisfied.Code || executed {
+		t.Errorf("Exec() = %v, executed %v, want PreconditionNotSatisfied", response, executed)
+	}
+	if response := executor.Exec("uid", SetDestroyFlag(context.Background(), "uid"), &ExpModel{}); !response.Success {
+		t.Errorf("Exec() = %v, want the destroy executed", response)
+	}
+}
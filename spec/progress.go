@@ -0,0 +1,97 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Progress is the execution progress of the long-running experiment
+type Progress struct {
+	Uid string `json:"uid"`
+	// Percentage is from 0 to 100
+	Percentage int    `json:"percentage"`
+	Phase      string `json:"phase,omitempty"`
+	Message    string `json:"message,omitempty"`
+	UpdateTime string `json:"updateTime"`
+}
+
+var (
+	progresses     = make(map[string]Progress)
+	progressesLock sync.RWMutex
+)
+
+// ReportProgress publishes the progress of the experiment by the uid, the percentage is limited from 0 to 100
+func ReportProgress(uid string, percentage int, phase, message string) {
+	if percentage < 0 {
+		percentage = 0
+	} else if percentage > 100 {
+		percentage = 100
+	}
+	progressesLock.Lock()
+	defer progressesLock.Unlock()
+	progresses[uid] = Progress{
+		Uid:        uid,
+		Percentage: percentage,
+		Phase:      phase,
+		Message:    message,
+		UpdateTime: time.Now().Format(time.RFC3339Nano),
+	}
+}
+
+// GetProgress returns the latest progress of the experiment
+func GetProgress(uid string) (*Progress, bool) {
+	progressesLock.RLock()
+	defer progressesLock.RUnlock()
+	progress, ok := progresses[uid]
+	if !ok {
+		return nil, false
+	}
+	return &progress, true
+}
+
+// ClearProgress removes the progress of the experiment, it's invoked by ExecExperiment after the experiment is
+// destroyed successfully
+func ClearProgress(uid string) {
+	progressesLock.Lock()
+	defer progressesLock.Unlock()
+	delete(progresses, uid)
+}
+
+// clearDestroyedProgress removes the progress of the experiment if it is destroyed successfully, so the progresses
+// of the finished experiments are not kept forever
+func clearDestroyedProgress(uid string, ctx context.Context, response *Response) {
+	destroyUid, isDestroy := IsDestroy(ctx)
+	if !isDestroy || response == nil || !response.Success {
+		return
+	}
+	if destroyUid == "" {
+		destroyUid = uid
+	}
+	ClearProgress(destroyUid)
+}
+
+// SetProgress embeds the progress of the experiment into the response metadata if reported,
+// so the status responses carry the progress to the UIs
+func (response *Response) SetProgress(uid string) *Response {
+	if progress, ok := GetProgress(uid); ok {
+		response.SetMetadata(MetadataProgress, progress)
+	}
+	return response
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"testing"
+)
+
+func TestReportProgress(t *testing.T) {
+	defer ClearProgress("uid")
+	if _, ok := GetProgress("uid"); ok {
+		t.Fatalf("GetProgress() found, want not reported")
+	}
+	ReportProgress("uid", 120, "inject", "loading cpu")
+	progress, ok := GetProgress("uid")
+	if !ok || progress.Percentage != 100 || progress.Phase != "inject" || progress.Message != "loading cpu" {
+		t.Errorf("GetProgress() = %+v, want 100%% inject", progress)
+	}
+	response := ReturnSuccess("running").SetProgress("uid")
+	if value, _ := response.GetMetadata(MetadataProgress); value.(*Progress).Percentage != 100 {
+		t.Errorf("SetProgress() metadata = %v", response.Metadata)
+	}
+	ClearProgress("uid")
+	if response := ReturnSuccess("destroyed").SetProgress("uid"); response.Metadata != nil {
+		t.Errorf("SetProgress() metadata = %v, want nil after cleared", response.Metadata)
+	}
+}
+
+func TestExecExperimentClearProgress(t *testing.T) {
+	defer ClearProgress("uid")
+	success := true
+	executor := &testExecutor{exec: func(uid string, ctx context.Context, model *ExpModel) *Response {
+		if !success {
+			return ResponseFailWithFlags(OsCmdExecFailed, "tc", "exit status 1")
+		}
+		return ReturnSuccess(nil)
+	}}
+	ReportProgress("uid", 50, "running", "")
+	ExecExperiment(executor, "uid", context.Background(), &ExpModel{Target: "network"})
+	if _, ok := GetProgress("uid"); !ok {
+		t.Fatalf("GetProgress() not found, want kept after created")
+	}
+	success = false
+	ExecExperiment(executor, "uid", SetDestroyFlag(context.Background(), "uid"), &ExpModel{Target: "network"})
+	if _, ok := GetProgress("uid"); !ok {
+		t.Fatalf("GetProgress() not found, want kept after the destroy failed")
+	}
+	success = true
+	ExecExperiment(executor, "", SetDestroyFlag(context.Background(), "uid"), &ExpModel{Target: "network"})
+	if _, ok := GetProgress("uid"); ok {
+		t.Errorf("GetProgress() found, want cleared after destroyed")
+	}
+}
@@ -0,0 +1,638 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The content types supported by the response encoding
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+)
+
+// The protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// The field numbers of the messages in spec.proto. The codec below is maintained by hand instead of generated by
+// protoc, so the module doesn't depend on the protobuf runtime. The numbers must be kept in sync with spec.proto,
+// which is checked by TestProtoFieldNumbers.
+const (
+	protoResponseCode        = 1
+	protoResponseSuccess     = 2
+	protoResponseError       = 3
+	protoResponseResult      = 4
+	protoResponseMetadata    = 5
+	protoResponseEncoding    = 6
+	protoResponseCauses      = 7
+	protoResponseFieldErrors = 8
+	protoResponseSeverity    = 9
+	protoResponseRetryable   = 10
+	protoResponseWarnings    = 11
+
+	protoCauseCode    = 1
+	protoCauseMessage = 2
+	protoCauseOrigin  = 3
+
+	protoWarningType       = 1
+	protoWarningName       = 2
+	protoWarningMessage    = 3
+	protoWarningReplacedBy = 4
+
+	protoFieldErrorField      = 1
+	protoFieldErrorConstraint = 2
+	protoFieldErrorValue      = 3
+	protoFieldErrorMessage    = 4
+
+	protoModelTarget         = 1
+	protoModelScope          = 2
+	protoModelAction         = 3
+	protoModelFlags          = 4
+	protoModelPrograms       = 5
+	protoModelCategories     = 6
+	protoModelProcessHang    = 7
+	protoModelIdempotencyKey = 8
+	protoModelFlagValues     = 9
+	protoModelLabels         = 10
+	protoModelAnnotations    = 11
+
+	protoFlagValuesName   = 1
+	protoFlagValuesValues = 2
+)
+
+// MarshalResponseProto encodes the response by the Response message in spec.proto
+func MarshalResponseProto(response *Response) ([]byte, error) {
+	e := &protoEncoder{}
+	e.varint(protoResponseCode, uint64(int64(response.Code)))
+	e.bool(protoResponseSuccess, response.Success)
+	e.string(protoResponseError, response.Err)
+	if response.Result != nil {
+		bytes, err := json.Marshal(response.Result)
+		if err != nil {
+			return nil, err
+		}
+		e.bytes(protoResponseResult, bytes)
+	}
+	for _, key := range sortedKeys(response.Metadata) {
+		bytes, err := json.Marshal(response.Metadata[key])
+		if err != nil {
+			return nil, err
+		}
+		e.mapEntry(protoResponseMetadata, key, bytes)
+	}
+	e.string(protoResponseEncoding, response.Encoding)
+	for _, cause := range response.Causes {
+		c := &protoEncoder{}
+		c.varint(protoCauseCode, uint64(int64(cause.Code)))
+		c.string(protoCauseMessage, cause.Message)
+		c.string(protoCauseOrigin, cause.Origin)
+		e.bytes(protoResponseCauses, c.buf)
+	}
+	for _, fieldError := range response.FieldErrors {
+		f := &protoEncoder{}
+		f.string(protoFieldErrorField, fieldError.Field)
+		f.string(protoFieldErrorConstraint, fieldError.Constraint)
+		f.string(protoFieldErrorValue, fieldError.Value)
+		f.string(protoFieldErrorMessage, fieldError.Message)
+		e.bytes(protoResponseFieldErrors, f.buf)
+	}
+	e.string(protoResponseSeverity, response.Severity)
+	e.bool(protoResponseRetryable, response.Retryable)
+	for _, warning := range response.Warnings {
+		w := &protoEncoder{}
+		w.string(protoWarningType, warning.Type)
+		w.string(protoWarningName, warning.Name)
+		w.string(protoWarningMessage, warning.Message)
+		w.string(protoWarningReplacedBy, warning.ReplacedBy)
+		e.bytes(protoResponseWarnings, w.buf)
+	}
+	return e.buf, nil
+}
+
+// UnmarshalResponseProto decodes the Response message in spec.proto
+func UnmarshalResponseProto(data []byte) (*Response, error) {
+	response := &Response{}
+	err := decodeProto(data, func(field int, wireType int, d *protoDecoder) error {
+		switch field {
+		case protoResponseCode:
+			v, err := d.uvarint()
+			response.Code = int32(v)
+			return err
+		case protoResponseSuccess:
+			v, err := d.uvarint()
+			response.Success = v != 0
+			return err
+		case protoResponseError:
+			v, err := d.bytes()
+			response.Err = string(v)
+			return err
+		case protoResponseResult:
+			v, err := d.bytes()
+			if err != nil {
+				return err
+			}
+			return json.Unmarshal(v, &response.Result)
+		case protoResponseMetadata:
+			key, value, err := d.mapEntry()
+			if err != nil {
+				return err
+			}
+			var v interface{}
+			if err := json.Unmarshal(value, &v); err != nil {
+				return err
+			}
+			response.SetMetadata(key, v)
+			return nil
+		case protoResponseEncoding:
+			v, err := d.bytes()
+			response.Encoding = string(v)
+			return err
+		case protoResponseCauses:
+			v, err := d.bytes()
+			if err != nil {
+				return err
+			}
+			cause, err := unmarshalCauseProto(v)
+			if err != nil {
+				return err
+			}
+			response.AddCauses(cause)
+			return nil
+		case protoResponseFieldErrors:
+			v, err := d.bytes()
+			if err != nil {
+				return err
+			}
+			fieldError, err := unmarshalFieldErrorProto(v)
+			if err != nil {
+				return err
+			}
+			response.AddFieldErrors(fieldError)
+			return nil
+		case protoResponseSeverity:
+			v, err := d.bytes()
+			response.Severity = string(v)
+			return err
+		case protoResponseRetryable:
+			v, err := d.uvarint()
+			response.Retryable = v != 0
+			return err
+		case protoResponseWarnings:
+			v, err := d.bytes()
+			if err != nil {
+				return err
+			}
+			warning, err := unmarshalWarningProto(v)
+			if err != nil {
+				return err
+			}
+			response.AddWarnings(warning)
+			return nil
+		}
+		return d.skip(wireType)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func unmarshalCauseProto(data []byte) (Cause, error) {
+	cause := Cause{}
+	err := decodeProto(data, func(field int, wireType int, d *protoDecoder) error {
+		switch field {
+		case protoCauseCode:
+			v, err := d.uvarint()
+			cause.Code = int32(v)
+			return err
+		case protoCauseMessage:
+			v, err := d.bytes()
+			cause.Message = string(v)
+			return err
+		case protoCauseOrigin:
+			v, err := d.bytes()
+			cause.Origin = string(v)
+			return err
+		}
+		return d.skip(wireType)
+	})
+	return cause, err
+}
+
+func unmarshalFieldErrorProto(data []byte) (FieldError, error) {
+	fieldError := FieldError{}
+	err := decodeProto(data, func(field int, wireType int, d *protoDecoder) error {
+		var target *string
+		switch field {
+		case protoFieldErrorField:
+			target = &fieldError.Field
+		case protoFieldErrorConstraint:
+			target = &fieldError.Constraint
+		case protoFieldErrorValue:
+			target = &fieldError.Value
+		case protoFieldErrorMessage:
+			target = &fieldError.Message
+		default:
+			return d.skip(wireType)
+		}
+		v, err := d.bytes()
+		*target = string(v)
+		return err
+	})
+	return fieldError, err
+}
+
+func unmarshalWarningProto(data []byte) (Warning, error) {
+	warning := Warning{}
+	err := decodeProto(data, func(field int, wireType int, d *protoDecoder) error {
+		var target *string
+		switch field {
+		case protoWarningType:
+			target = &warning.Type
+		case protoWarningName:
+			target = &warning.Name
+		case protoWarningMessage:
+			target = &warning.Message
+		case protoWarningReplacedBy:
+			target = &warning.ReplacedBy
+		default:
+			return d.skip(wireType)
+		}
+		v, err := d.bytes()
+		*target = string(v)
+		return err
+	})
+	return warning, err
+}
+
+// MarshalExpModelProto encodes the model by the ExpModel message in spec.proto
+func MarshalExpModelProto(model *ExpModel) []byte {
+	e := &protoEncoder{}
+	e.string(protoModelTarget, model.Target)
+	e.string(protoModelScope, model.Scope)
+	e.string(protoModelAction, model.ActionName)
+	keys := make([]string, 0, len(model.ActionFlags))
+	for key := range model.ActionFlags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		e.mapEntry(protoModelFlags, key, []byte(model.ActionFlags[key]))
+	}
+	for _, program := range model.ActionPrograms {
+		e.bytes(protoModelPrograms, []byte(program))
+	}
+	for _, category := range model.ActionCategories {
+		e.bytes(protoModelCategories, []byte(category))
+	}
+	e.bool(protoModelProcessHang, model.ActionProcessHang)
+	e.string(protoModelIdempotencyKey, model.IdempotencyKey)
+	keys = keys[:0]
+	for key := range model.ActionFlagValues {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		entry := &protoEncoder{}
+		entry.string(protoFlagValuesName, key)
+		for _, value := range model.ActionFlagValues[key] {
+			entry.bytes(protoFlagValuesValues, []byte(value))
+		}
+		e.bytes(protoModelFlagValues, entry.buf)
+	}
+	for _, metadata := range []struct {
+		field  int
+		values map[string]string
+	}{{protoModelLabels, model.Labels}, {protoModelAnnotations, model.Annotations}} {
+		keys = keys[:0]
+		for key := range metadata.values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			e.mapEntry(metadata.field, key, []byte(metadata.values[key]))
+		}
+	}
+	return e.buf
+}
+
+// UnmarshalExpModelProto decodes the ExpModel message in spec.proto
+func UnmarshalExpModelProto(data []byte) (*ExpModel, error) {
+	model := &ExpModel{ActionFlags: make(map[string]string)}
+	err := decodeProto(data, func(field int, wireType int, d *protoDecoder) error {
+		switch field {
+		case protoModelTarget, protoModelScope, protoModelAction, protoModelPrograms, protoModelCategories,
+			protoModelIdempotencyKey:
+			v, err := d.bytes()
+			if err != nil {
+				return err
+			}
+			switch field {
+			case protoModelTarget:
+				model.Target = string(v)
+			case protoModelScope:
+				model.Scope = string(v)
+			case protoModelAction:
+				model.ActionName = string(v)
+			case protoModelPrograms:
+				model.ActionPrograms = append(model.ActionPrograms, string(v))
+			case protoModelCategories:
+				model.ActionCategories = append(model.ActionCategories, string(v))
+			case protoModelIdempotencyKey:
+				model.IdempotencyKey = string(v)
+			}
+			return nil
+		case protoModelFlags:
+			key, value, err := d.mapEntry()
+			model.ActionFlags[key] = string(value)
+			return err
+		case protoModelProcessHang:
+			v, err := d.uvarint()
+			model.ActionProcessHang = v != 0
+			return err
+		case protoModelFlagValues:
+			entry, err := d.bytes()
+			if err != nil {
+				return err
+			}
+			return unmarshalFlagValuesProto(entry, model)
+		case protoModelLabels:
+			key, value, err := d.mapEntry()
+			model.SetLabel(key, string(value))
+			return err
+		case protoModelAnnotations:
+			key, value, err := d.mapEntry()
+			model.SetAnnotation(key, string(value))
+			return err
+		}
+		return d.skip(wireType)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func unmarshalFlagValuesProto(data []byte, model *ExpModel) error {
+	var name string
+	values := make([]string, 0)
+	err := decodeProto(data, func(field int, wireType int, d *protoDecoder) error {
+		switch field {
+		case protoFlagValuesName, protoFlagValuesValues:
+			v, err := d.bytes()
+			if field == protoFlagValuesName {
+				name = string(v)
+			} else {
+				values = append(values, string(v))
+			}
+			return err
+		}
+		return d.skip(wireType)
+	})
+	if err != nil {
+		return err
+	}
+	if model.ActionFlagValues == nil {
+		model.ActionFlagValues = make(map[string][]string)
+	}
+	model.ActionFlagValues[name] = values
+	return nil
+}
+
+// NegotiateContentType returns the first supported content type in the accept header, json by default.
+// The quality values are ignored.
+func NegotiateContentType(accept string) string {
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType := strings.TrimSpace(strings.SplitN(mediaRange, ";", 2)[0])
+		switch mediaType {
+		case ContentTypeProtobuf, "application/protobuf":
+			return ContentTypeProtobuf
+		case ContentTypeJSON:
+			return ContentTypeJSON
+		}
+	}
+	return ContentTypeJSON
+}
+
+// EncodeOption customizes the EncodeResponse
+type EncodeOption func(options *encodeOptions)
+
+type encodeOptions struct {
+	compression string
+	threshold   int
+}
+
+// EncodeCompression compresses the result by the algorithm if its json encoding is not less than the threshold,
+// see Response.Compress. DecodeResponse and Decode decompress it transparently.
+func EncodeCompression(algorithm string, threshold int) EncodeOption {
+	return func(options *encodeOptions) {
+		options.compression = algorithm
+		options.threshold = threshold
+	}
+}
+
+// EncodeResponse encodes the response by the content type negotiated from the accept header,
+// it returns the bytes and the content type
+func EncodeResponse(response *Response, accept string, options ...EncodeOption) ([]byte, string, error) {
+	encodeOptions := &encodeOptions{}
+	for _, option := range options {
+		option(encodeOptions)
+	}
+	if encodeOptions.compression != "" {
+		// the response of the caller is not modified
+		compressed := *response
+		if err := compressed.Compress(encodeOptions.compression, encodeOptions.threshold); err != nil {
+			return nil, "", err
+		}
+		response = &compressed
+	}
+	contentType := NegotiateContentType(accept)
+	if contentType == ContentTypeProtobuf {
+		bytes, err := MarshalResponseProto(response)
+		return bytes, contentType, err
+	}
+	bytes, err := json.Marshal(response)
+	return bytes, contentType, err
+}
+
+// DecodeResponse decodes the response bytes of the content type, the compressed result is decompressed
+func DecodeResponse(data []byte, contentType string) (*Response, error) {
+	var response *Response
+	if NegotiateContentType(contentType) == ContentTypeProtobuf {
+		decoded, err := UnmarshalResponseProto(data)
+		if err != nil {
+			return nil, err
+		}
+		response = decoded
+	} else {
+		response = &Response{}
+		if err := json.Unmarshal(data, response); err != nil {
+			return nil, err
+		}
+	}
+	if err := response.Decompress(); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type protoEncoder struct {
+	buf []byte
+}
+
+func (e *protoEncoder) tag(field, wireType int) {
+	e.buf = binary.AppendUvarint(e.buf, uint64(field<<3|wireType))
+}
+
+// varint writes the non-zero value, zero is the default value in proto3
+func (e *protoEncoder) varint(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+	e.tag(field, wireVarint)
+	e.buf = binary.AppendUvarint(e.buf, v)
+}
+
+func (e *protoEncoder) bool(field int, v bool) {
+	if v {
+		e.varint(field, 1)
+	}
+}
+
+func (e *protoEncoder) string(field int, v string) {
+	if v != "" {
+		e.bytes(field, []byte(v))
+	}
+}
+
+func (e *protoEncoder) bytes(field int, v []byte) {
+	e.tag(field, wireBytes)
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+func (e *protoEncoder) mapEntry(field int, key string, value []byte) {
+	entry := &protoEncoder{}
+	entry.string(1, key)
+	entry.bytes(2, value)
+	e.bytes(field, entry.buf)
+}
+
+type protoDecoder struct {
+	buf []byte
+}
+
+func decodeProto(data []byte, handle func(field int, wireType int, d *protoDecoder) error) error {
+	d := &protoDecoder{buf: data}
+	for len(d.buf) > 0 {
+		tag, err := d.uvarint()
+		if err != nil {
+			return err
+		}
+		if err := handle(int(tag>>3), int(tag&7), d); err != nil {
+			return fmt.Errorf("decode protobuf field %d failed, %v", tag>>3, err)
+		}
+	}
+	return nil
+}
+
+func (d *protoDecoder) uvarint() (uint64, error) {
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		return 0, fmt.Errorf("illegal varint")
+	}
+	d.buf = d.buf[n:]
+	return v, nil
+}
+
+func (d *protoDecoder) bytes() ([]byte, error) {
+	length, err := d.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(d.buf)) < length {
+		return nil, fmt.Errorf("unexpected end of data")
+	}
+	v := d.buf[:length]
+	d.buf = d.buf[length:]
+	return v, nil
+}
+
+func (d *protoDecoder) mapEntry() (string, []byte, error) {
+	entry, err := d.bytes()
+	if err != nil {
+		return "", nil, err
+	}
+	var key string
+	var value []byte
+	err = decodeProto(entry, func(field int, wireType int, ed *protoDecoder) error {
+		switch field {
+		case 1:
+			v, err := ed.bytes()
+			key = string(v)
+			return err
+		case 2:
+			v, err := ed.bytes()
+			value = v
+			return err
+		}
+		return ed.skip(wireType)
+	})
+	return key, value, err
+}
+
+func (d *protoDecoder) skip(wireType int) error {
+	var size int
+	switch wireType {
+	case wireVarint:
+		_, err := d.uvarint()
+		return err
+	case wireBytes:
+		_, err := d.bytes()
+		return err
+	case wireFixed64:
+		size = 8
+	case wireFixed32:
+		size = 4
+	default:
+		return fmt.Errorf("unsupported wire type %d", wireType)
+	}
+	if len(d.buf) < size {
+		return fmt.Errorf("unexpected end of data")
+	}
+	d.buf = d.buf[size:]
+	return nil
+}
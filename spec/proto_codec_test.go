@@ -0,0 +1,261 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestMarshalResponseProto(t *testing.T) {
+	got, err := MarshalResponseProto(Success())
+	if err != nil {
+		t.Fatalf("MarshalResponseProto() error = %v", err)
+	}
+	// code = 200, success = true
+	if want := []byte{0x08, 0xc8, 0x01, 0x10, 0x01}; !bytes.Equal(got, want) {
+		t.Errorf("MarshalResponseProto() = %x, want %x", got, want)
+	}
+
+	response := ReturnSuccess("done").SetMetadata(MetadataChannel, "local")
+	data, err := MarshalResponseProto(response)
+	if err != nil {
+		t.Fatalf("MarshalResponseProto() error = %v", err)
+	}
+	decoded, err := UnmarshalResponseProto(data)
+	if err != nil {
+		t.Fatalf("UnmarshalResponseProto() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded, response) {
+		t.Errorf("UnmarshalResponseProto() = %v, want %v", decoded, response)
+	}
+
+	response = ResponseFailWithFlags(FileNotExist, "/tmp/a").
+		AddCauses(Cause{Code: OsCmdExecFailed.Code, Message: "exit status 1", Origin: "local"}, Cause{Message: "no such file"})
+	data, err = MarshalResponseProto(response)
+	if err != nil {
+		t.Fatalf("MarshalResponseProto() error = %v", err)
+	}
+	decoded, err = UnmarshalResponseProto(data)
+	if err != nil {
+		t.Fatalf("UnmarshalResponseProto() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded, response) {
+		t.Errorf("UnmarshalResponseProto() = %v, want %v", decoded, response)
+	}
+}
+
+func TestMarshalResponseProtoWarnings(t *testing.T) {
+	response := ReturnSuccess(nil).AddWarnings(
+		Warning{Type: WarningDeprecatedAction, Name: "delay", Message: "use latency", ReplacedBy: "latency"},
+		Warning{Type: WarningDeprecatedFlag, Name: "time"})
+	data, err := MarshalResponseProto(response)
+	if err != nil {
+		t.Fatalf("MarshalResponseProto() error = %v", err)
+	}
+	decoded, err := UnmarshalResponseProto(data)
+	if err != nil {
+		t.Fatalf("UnmarshalResponseProto() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded.Warnings, response.Warnings) {
+		t.Errorf("UnmarshalResponseProto() warnings = %v, want %v", decoded.Warnings, response.Warnings)
+	}
+}
+
+func TestMarshalExpModelProto(t *testing.T) {
+	model := &ExpModel{
+		Target:         "network",
+		ActionName:     "delay",
+		ActionFlags:    map[string]string{"time": "3000", "interface": "eth0"},
+		ActionPrograms: []string{"chaos_os"},
+		IdempotencyKey: "token",
+	}
+	decoded, err := UnmarshalExpModelProto(MarshalExpModelProto(model))
+	if err != nil {
+		t.Fatalf("UnmarshalExpModelProto() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded, model) {
+		t.Errorf("UnmarshalExpModelProto() = %v, want %v", decoded, model)
+	}
+}
+
+// fillFields sets every json encoded field of the struct to a non-zero value
+func fillFields(v reflect.Value) {
+	for idx := 0; idx < v.NumField(); idx++ {
+		if _, _, ok := jsonFieldName(v.Type().Field(idx)); ok {
+			fillValue(v.Field(idx), v.Type().Field(idx).Name)
+		}
+	}
+}
+
+func fillValue(v reflect.Value, name string) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(name)
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		v.SetInt(7)
+	case reflect.Interface:
+		v.Set(reflect.ValueOf(name))
+	case reflect.Struct:
+		fillFields(v)
+	case reflect.Slice:
+		slice := reflect.MakeSlice(v.Type(), 1, 1)
+		fillValue(slice.Index(0), name)
+		v.Set(slice)
+	case reflect.Map:
+		value := reflect.New(v.Type().Elem()).Elem()
+		fillValue(value, name)
+		m := reflect.MakeMap(v.Type())
+		m.SetMapIndex(reflect.ValueOf("key"), value)
+		v.Set(m)
+	}
+}
+
+// TestProtoFieldMappings fails if a json encoded field of Response or ExpModel is lost by the proto codec,
+// the new field must be added to spec.proto and proto_codec.go
+func TestProtoFieldMappings(t *testing.T) {
+	response := &Response{}
+	fillFields(reflect.ValueOf(response).Elem())
+	data, err := MarshalResponseProto(response)
+	if err != nil {
+		t.Fatalf("MarshalResponseProto() error = %v", err)
+	}
+	decodedResponse, err := UnmarshalResponseProto(data)
+	if err != nil {
+		t.Fatalf("UnmarshalResponseProto() error = %v", err)
+	}
+	assertFieldsEqual(t, reflect.ValueOf(response).Elem(), reflect.ValueOf(decodedResponse).Elem())
+
+	model := &ExpModel{}
+	fillFields(reflect.ValueOf(model).Elem())
+	// the values of the repeated flags are also joined in the flags
+	model.ActionFlags = map[string]string{"key": "ActionFlagValues"}
+	decodedModel, err := UnmarshalExpModelProto(MarshalExpModelProto(model))
+	if err != nil {
+		t.Fatalf("UnmarshalExpModelProto() error = %v", err)
+	}
+	assertFieldsEqual(t, reflect.ValueOf(model).Elem(), reflect.ValueOf(decodedModel).Elem())
+}
+
+func assertFieldsEqual(t *testing.T, want, got reflect.Value) {
+	for idx := 0; idx < want.NumField(); idx++ {
+		field := want.Type().Field(idx)
+		if _, _, ok := jsonFieldName(field); !ok {
+			continue
+		}
+		if !reflect.DeepEqual(want.Field(idx).Interface(), got.Field(idx).Interface()) {
+			t.Errorf("%s.%s has no proto mapping, got %v, want %v", want.Type().Name(), field.Name,
+				got.Field(idx).Interface(), want.Field(idx).Interface())
+		}
+	}
+}
+
+func TestNegotiateContentType(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{accept: "", want: ContentTypeJSON},
+		{accept: "application/x-protobuf", want: ContentTypeProtobuf},
+		{accept: "application/x-protobuf;q=0.9, application/json", want: ContentTypeProtobuf},
+		{accept: "text/html, application/json", want: ContentTypeJSON},
+	}
+	for _, tt := range tests {
+		if got := NegotiateContentType(tt.accept); got != tt.want {
+			t.Errorf("NegotiateContentType(%s) = %s, want %s", tt.accept, got, tt.want)
+		}
+	}
+}
+
+var (
+	protoMessageRegexp = regexp.MustCompile(`(?s)message\s+(\w+)\s*\{(.*?)\n\}`)
+	protoFieldRegexp   = regexp.MustCompile(`(?m)^\s*(?:repeated\s+)?(?:map<[^>]+>|\w+)\s+(\w+)\s*=\s*(\d+)\s*;`)
+)
+
+// TestProtoFieldNumbers checks the field numbers used by the hand-maintained codec against spec.proto,
+// every field of the messages in spec.proto must be mapped
+func TestProtoFieldNumbers(t *testing.T) {
+	content, err := os.ReadFile("spec.proto")
+	if err != nil {
+		t.Fatalf("read spec.proto error = %v", err)
+	}
+	fields := make(map[string]int)
+	for _, message := range protoMessageRegexp.FindAllStringSubmatch(string(content), -1) {
+		for _, field := range protoFieldRegexp.FindAllStringSubmatch(message[2], -1) {
+			number, _ := strconv.Atoi(field[2])
+			fields[message[1]+"."+field[1]] = number
+		}
+	}
+	codec := map[string]int{
+		"Response.code":                protoResponseCode,
+		"Response.success":             protoResponseSuccess,
+		"Response.error":               protoResponseError,
+		"Response.result_json":         protoResponseResult,
+		"Response.metadata_json":       protoResponseMetadata,
+		"Response.encoding":            protoResponseEncoding,
+		"Response.causes":              protoResponseCauses,
+		"Response.field_errors":        protoResponseFieldErrors,
+		"Response.severity":            protoResponseSeverity,
+		"Response.retryable":           protoResponseRetryable,
+		"Response.warnings":            protoResponseWarnings,
+		"Cause.code":                   protoCauseCode,
+		"Cause.message":                protoCauseMessage,
+		"Cause.origin":                 protoCauseOrigin,
+		"Warning.type":                 protoWarningType,
+		"Warning.name":                 protoWarningName,
+		"Warning.message":              protoWarningMessage,
+		"Warning.replaced_by":          protoWarningReplacedBy,
+		"FieldError.field":             protoFieldErrorField,
+		"FieldError.constraint":        protoFieldErrorConstraint,
+		"FieldError.value":             protoFieldErrorValue,
+		"FieldError.message":           protoFieldErrorMessage,
+		"ExpModel.target":              protoModelTarget,
+		"ExpModel.scope":               protoModelScope,
+		"ExpModel.action":              protoModelAction,
+		"ExpModel.flags":               protoModelFlags,
+		"ExpModel.programs":            protoModelPrograms,
+		"ExpModel.categories":          protoModelCategories,
+		"ExpModel.action_process_hang": protoModelProcessHang,
+		"ExpModel.idempotency_key":     protoModelIdempotencyKey,
+		"ExpModel.flag_values":         protoModelFlagValues,
+		"ExpModel.labels":              protoModelLabels,
+		"ExpModel.annotations":         protoModelAnnotations,
+		"FlagValues.name":              protoFlagValuesName,
+		"FlagValues.values":            protoFlagValuesValues,
+	}
+	if len(fields) == 0 {
+		t.Fatalf("no fields parsed from spec.proto")
+	}
+	for name, number := range fields {
+		if codecNumber, ok := codec[name]; !ok {
+			t.Errorf("the field %s = %d of spec.proto is not mapped by the codec", name, number)
+		} else if codecNumber != number {
+			t.Errorf("the field %s = %d of spec.proto, but the codec uses %d", name, number, codecNumber)
+		}
+	}
+	for name := range codec {
+		if _, ok := fields[name]; !ok {
+			t.Errorf("the field %s of the codec is not in spec.proto", name)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ActionPathSeparator separates the target names and the action name in the action path, such as network/delay
+const ActionPathSeparator = "/"
+
+// ResolvedAction is the spec and the executor resolved by the action path
+type ResolvedAction struct {
+	// Path is the target path from the registered root to the sub target which contains the action
+	Path     ModelPath
+	Action   ExpActionCommandSpec
+	Executor Executor
+}
+
+var (
+	modelSpecs         = make(map[string]ExpModelCommandSpec)
+	modelSpecWarnings  = make(map[string][]string)
+	modelCompatibility *Compatibility
+	modelSpecsLock     sync.RWMutex
+)
+
+// SetCompatibility sets the compatibility evaluated by RegisterModelSpec, nil disables the check
+func SetCompatibility(compatibility *Compatibility) {
+	modelSpecsLock.Lock()
+	defer modelSpecsLock.Unlock()
+	modelCompatibility = compatibility
+}
+
+// RegisterModelSpec registers the target spec tree of the plugin, the target name must be unique.
+// The compatibility set by SetCompatibility is evaluated here, the incompatible spec is rejected and
+// the deprecation warnings can be got by ModelSpecWarnings.
+func RegisterModelSpec(modelSpec ExpModelCommandSpec) error {
+	if modelSpec == nil || modelSpec.Name() == "" {
+		return fmt.Errorf("the model spec or its name is empty")
+	}
+	modelSpecsLock.Lock()
+	defer modelSpecsLock.Unlock()
+	if _, ok := modelSpecs[modelSpec.Name()]; ok {
+		return fmt.Errorf("the model spec %s is already registered", modelSpec.Name())
+	}
+	var warnings []string
+	if modelCompatibility != nil {
+		var err error
+		warnings, err = modelCompatibility.Check(compatibilityModels(modelSpec))
+		if err != nil {
+			return err
+		}
+	}
+	modelSpecs[modelSpec.Name()] = modelSpec
+	modelSpecWarnings[modelSpec.Name()] = warnings
+	return nil
+}
+
+// ModelSpecWarnings returns the compatibility warnings of the registered target spec
+func ModelSpecWarnings(name string) []string {
+	modelSpecsLock.RLock()
+	defer modelSpecsLock.RUnlock()
+	return modelSpecWarnings[name]
+}
+
+// compatibilityModels returns the models checked by Compatibility, the spec registered in process is
+// compiled against this package, so its spec version is SpecVersion
+func compatibilityModels(modelSpec ExpModelCommandSpec) *Models {
+	models := &Models{Kind: modelSpec.Name(), SpecVersion: SpecVersion, Models: make([]ExpCommandModel, 0)}
+	WalkModels(modelSpec, func(path ModelPath) error {
+		model := ExpCommandModel{ExpName: path.Last().Name(), ExpActions: make([]ActionModel, 0)}
+		for _, action := range path.Last().Actions() {
+			model.ExpActions = append(model.ExpActions, ActionModel{ActionName: action.Name()})
+		}
+		models.Models = append(models.Models, model)
+		return nil
+	})
+	return models
+}
+
+// GetModelSpec returns the registered target spec by the name
+func GetModelSpec(name string) (ExpModelCommandSpec, bool) {
+	modelSpecsLock.RLock()
+	defer modelSpecsLock.RUnlock()
+	modelSpec, ok := modelSpecs[name]
+	return modelSpec, ok
+}
+
+// RegisteredModelSpecs returns all the registered target specs ordered by name
+func RegisteredModelSpecs() []ExpModelCommandSpec {
+	modelSpecsLock.RLock()
+	defer modelSpecsLock.RUnlock()
+	specs := make([]ExpModelCommandSpec, 0, len(modelSpecs))
+	for _, modelSpec := range modelSpecs {
+		specs = append(specs, modelSpec)
+	}
+	sort.Slice(specs, func(i, j int) bool {
+		return specs[i].Name() < specs[j].Name()
+	})
+	return specs
+}
+
+// ResetModelSpecs removes all the registered target specs
+func ResetModelSpecs() {
+	modelSpecsLock.Lock()
+	defer modelSpecsLock.Unlock()
+	modelSpecs = make(map[string]ExpModelCommandSpec)
+	modelSpecWarnings = make(map[string][]string)
+}
+
+// ResolveAction returns the spec and the executor by the action path, such as network/delay or
+// k8s/pod/network/delay. The action aliases are supported.
+func ResolveAction(actionPath string) (*ResolvedAction, *Response) {
+	names := make([]string, 0)
+	for _, name := range strings.Split(strings.Trim(actionPath, ActionPathSeparator), ActionPathSeparator) {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) < 2 {
+		return nil, ResponseFailWithFlags(ActionNotSupport, actionPath)
+	}
+	root, ok := GetModelSpec(names[0])
+	if !ok {
+		return nil, ResponseFailWithFlags(ActionNotSupport, actionPath)
+	}
+	path, action, ok := FindAction(root, names[1:]...)
+	if !ok {
+		return nil, ResponseFailWithFlags(ActionNotSupport, actionPath)
+	}
+	executor := action.Executor()
+	if executor == nil {
+		return nil, ResponseFailWithFlags(OsExecutorNotFound, actionPath)
+	}
+	return &ResolvedAction{Path: path, Action: action, Executor: executor}, nil
+}
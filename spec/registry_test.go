@@ -0,0 +1,95 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"testing"
+)
+
+func TestResolveAction(t *testing.T) {
+	defer ResetModelSpecs()
+	k8s := newK8sModel()
+	if err := RegisterModelSpec(k8s); err != nil {
+		t.Fatalf("RegisterModelSpec() error = %v", err)
+	}
+	if err := RegisterModelSpec(newK8sModel()); err == nil {
+		t.Errorf("RegisterModelSpec() expected duplicate error")
+	}
+	network := &ExpCommandModel{ExpName: "network", ExpActions: []ActionModel{{ActionName: "loss"}}}
+	if err := RegisterModelSpec(network); err != nil {
+		t.Fatalf("RegisterModelSpec() error = %v", err)
+	}
+	if specs := RegisteredModelSpecs(); len(specs) != 2 || specs[0].Name() != "k8s" {
+		t.Errorf("RegisteredModelSpecs() = %v", specs)
+	}
+
+	if _, response := ResolveAction("k8s/pod/network/delay"); response == nil || response.Code != OsExecutorNotFound.Code {
+		t.Errorf("ResolveAction() without executor = %v, want OsExecutorNotFound", response)
+	}
+	_, action, _ := FindAction(k8s, "pod", "network", "delay")
+	executor := &testExecutor{exec: func(uid string, ctx context.Context, model *ExpModel) *Response {
+		return Success()
+	}}
+	action.SetExecutor(executor)
+
+	resolved, response := ResolveAction("/k8s/pod/network/latency")
+	if response != nil {
+		t.Fatalf("ResolveAction() = %v", response)
+	}
+	if resolved.Action.Name() != "delay" || resolved.Path.String() != "k8s pod network" || resolved.Executor != executor {
+		t.Errorf("ResolveAction() = %+v", resolved)
+	}
+	for _, path := range []string{"", "k8s", "docker/delay", "network/delay", "k8s/pod/delay"} {
+		if _, response := ResolveAction(path); response == nil || response.Code != ActionNotSupport.Code {
+			t.Errorf("ResolveAction(%q) = %v, want ActionNotSupport", path, response)
+		}
+	}
+}
+
+func TestRegisterModelSpecCompatibility(t *testing.T) {
+	defer ResetModelSpecs()
+	defer SetCompatibility(nil)
+	SetCompatibility(&Compatibility{MinSpecVersion: "99.0.0"})
+	network := &ExpCommandModel{ExpName: "network", ExpActions: []ActionModel{{ActionName: "dns"}}}
+	if err := RegisterModelSpec(network); err == nil {
+		t.Errorf("RegisterModelSpec() expected incompatible error")
+	}
+	if _, ok := GetModelSpec("network"); ok {
+		t.Errorf("GetModelSpec() the incompatible spec is registered")
+	}
+
+	SetCompatibility(&Compatibility{
+		MinSpecVersion:    "1.7.0",
+		DeprecatedActions: map[string]string{"pod.delete": "use pod fail instead"},
+	})
+	if err := RegisterModelSpec(network); err != nil {
+		t.Fatalf("RegisterModelSpec() error = %v", err)
+	}
+	if warnings := ModelSpecWarnings("network"); len(warnings) != 0 {
+		t.Errorf("ModelSpecWarnings(network) = %v, want none", warnings)
+	}
+	k8s := &ExpCommandModel{ExpName: "k8s", ExpSubModels: []ExpCommandModel{
+		{ExpName: "pod", ExpActions: []ActionModel{{ActionName: "delete"}}},
+	}}
+	if err := RegisterModelSpec(k8s); err != nil {
+		t.Fatalf("RegisterModelSpec() error = %v", err)
+	}
+	if warnings := ModelSpecWarnings("k8s"); len(warnings) != 1 {
+		t.Errorf("ModelSpecWarnings(k8s) = %v, want 1 warning", warnings)
+	}
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// The cgroup versions of ActionRequirements
+const (
+	CgroupV1 = "v1"
+	CgroupV2 = "v2"
+)
+
+// ActionRequirements declares the environment the action depends on. It is also the result of
+// the EnvironmentNotSatisfied response, which contains the missing items only.
+type ActionRequirements struct {
+	// Commands are the required binaries, for example tc and iptables
+	Commands []string `yaml:"commands,flow,omitempty" json:"commands,omitempty"`
+
+	// KernelModules are the required kernel modules, for example sch_netem
+	KernelModules []string `yaml:"kernelModules,flow,omitempty" json:"kernelModules,omitempty"`
+
+	// Capabilities are the required linux capabilities of the current process, for example CAP_NET_ADMIN
+	Capabilities []string `yaml:"capabilities,flow,omitempty" json:"capabilities,omitempty"`
+
+	// KernelVersion is the min kernel version, for example 4.9, or the constraint of the kernel version,
+	// for example ">=4.9, <6.0", see VersionConstraint
+	KernelVersion string `yaml:"kernelVersion,omitempty" json:"kernelVersion,omitempty"`
+
+	// CgroupVersion is the required cgroup version, v1 or v2, empty means both
+	CgroupVersion string `yaml:"cgroupVersion,omitempty" json:"cgroupVersion,omitempty"`
+}
+
+// RequirementsChecker checks the requirements of the action, it returns nil if satisfied, otherwise the
+// EnvironmentNotSatisfied response
+type RequirementsChecker func(ctx context.Context, requirements *ActionRequirements) *Response
+
+var (
+	requirementsChecker     RequirementsChecker
+	requirementsCheckerLock sync.RWMutex
+)
+
+// SetRequirementsChecker sets the checker invoked by ExecExperiment before the executor, nil disables the check.
+// The requirements are not checked by default, because the spec package can't check the commands without a
+// channel, use channel.RequirementsChecker for the local host.
+func SetRequirementsChecker(checker RequirementsChecker) {
+	requirementsCheckerLock.Lock()
+	defer requirementsCheckerLock.Unlock()
+	requirementsChecker = checker
+}
+
+func getRequirementsChecker() RequirementsChecker {
+	requirementsCheckerLock.RLock()
+	defer requirementsCheckerLock.RUnlock()
+	return requirementsChecker
+}
+
+// checkActionRequirements checks the requirements of the action by the checker set by SetRequirementsChecker
+func checkActionRequirements(ctx context.Context, action ExpActionCommandSpec) *Response {
+	checker := getRequirementsChecker()
+	if checker == nil || ActionRequirementsOf(action).IsEmpty() {
+		return nil
+	}
+	return checker(ctx, ActionRequirementsOf(action))
+}
+
+// IsEmpty returns true if nothing is required
+func (r *ActionRequirements) IsEmpty() bool {
+	return r == nil || (len(r.Commands)+len(r.KernelModules)+len(r.Capabilities) == 0 &&
+		r.KernelVersion == "" && r.CgroupVersion == "")
+}
+
+func (r *ActionRequirements) String() string {
+	items := make([]string, 0)
+	if len(r.Commands) > 0 {
+		items = append(items, "commands: "+strings.Join(r.Commands, ","))
+	}
+	if len(r.KernelModules) > 0 {
+		items = append(items, "kernel modules: "+strings.Join(r.KernelModules, ","))
+	}
+	if len(r.Capabilities) > 0 {
+		items = append(items, "capabilities: "+strings.Join(r.Capabilities, ","))
+	}
+	if r.KernelVersion != "" {
+		if constraint, err := r.KernelVersionConstraint(); err == nil {
+			items = append(items, "kernel version: "+constraint.String())
+		} else {
+			items = append(items, "kernel version: "+r.KernelVersion)
+		}
+	}
+	if r.CgroupVersion != "" {
+		items = append(items, "cgroup version: "+r.CgroupVersion)
+	}
+	return strings.Join(items, "; ")
+}
+
+// KernelVersionConstraint returns the constraint of the kernel version, the plain version is the min version
+func (r *ActionRequirements) KernelVersionConstraint() (*VersionConstraint, error) {
+	constraint := strings.TrimSpace(r.KernelVersion)
+	if constraint != "" && (constraint[0] == 'v' || constraint[0] >= '0' && constraint[0] <= '9') &&
+		!strings.ContainsAny(constraint, ",|") {
+		constraint = ">=" + constraint
+	}
+	return ParseVersionConstraint(constraint)
+}
@@ -35,6 +35,10 @@ var (
 	Forbidden                         = CodeType{43000, "Forbidden: must be root"}
 	ActionNotSupport                  = CodeType{44000, "`%s`: action not supported"}
 	ParameterLess                     = CodeType{45000, "less parameter: `%s`"}
+	ParameterLessOneOf                = CodeType{45001, "less parameter, one of `%s` is required"}
+	ParameterConflict                 = CodeType{45002, "conflicting parameters, only one of `%s` can be specified"}
+	ParameterLessWith                 = CodeType{45003, "less parameter: `%s` is required when `%s` is specified"}
+	ParameterForbidden                = CodeType{45004, "forbidden parameter: `%s` is not allowed when `%s`"}
 	ParameterIllegal                  = CodeType{46000, "illegal `%s` parameter value: `%s`. %v"}
 	ParameterInvalid                  = CodeType{47000, "invalid `%s` parameter value: `%s`. %v"}
 	ParameterInvalidProName           = CodeType{47001, "invalid parameter `%s`, `%s` process not found"}
@@ -53,6 +57,7 @@ var (
 	ParameterRequestFailed            = CodeType{48000, "get request parameter failed"}
 	CommandIllegal                    = CodeType{49000, "illegal command, err: %v"}
 	CommandNetworkExist               = CodeType{49001, "network tc exec failed! RTNETLINK answers: File exists"}
+	CommandRejectedByPolicy           = CodeType{49002, "`%s`: command rejected by policy, %v"}
 	ChaosbladeFileNotFound            = CodeType{51000, "`%s`: chaosblade file not found"}
 	CommandTasksetNotFound            = CodeType{52000, "`taskset`: command not found"}
 	CommandMountNotFound              = CodeType{52001, "`mount`: command not found"}
@@ -75,6 +80,8 @@ var (
 	CommandTarNotFound                = CodeType{52018, "`tar`: command not found"}
 	CommandSystemctlNotFound          = CodeType{52019, "`systemctl`: command not found"}
 	CommandNohupNotFound              = CodeType{52020, "`nohup`: command not found"}
+	EnvironmentNotSatisfied           = CodeType{52100, "environment not satisfied, missing %s"}
+	PreconditionNotSatisfied          = CodeType{52101, "precondition not satisfied: %s"}
 	ChaosbladeServerStarted           = CodeType{53000, "the chaosblade has been started. If you want to stop it, you can execute blade server stop command"}
 	UnexpectedStatus                  = CodeType{54000, "unexpected status, expected status: `%s`, but the real status: `%s`, please wait!"}
 	DockerExecNotFound                = CodeType{55000, "`%s`: the docker exec not found"}
@@ -109,6 +116,10 @@ var (
 	CreateContainerFailed             = CodeType{63066, "create container failed, err: %v"}
 	ContainerExecFailed               = CodeType{63067, "`%s`: container exec failed, err: %v"}
 	OsExecutorNotFound                = CodeType{63070, "`%s`: os executor not found"}
+	ExecutorPanic                     = CodeType{63071, "`%s`: executor panic, err: %v"}
+	ExecTimeout                       = CodeType{63072, "`%s`: execution timeout after %v"}
+	ExecCanceled                      = CodeType{63073, "`%s`: execution canceled"}
+	ConcurrencyLimitExceeded          = CodeType{63074, "`%s`: the concurrency limit %d exceeded, running experiments: %v"}
 	ChaosfsClientFailed               = CodeType{64000, "init chaosfs client failed in pod %v, err: %v"}
 	ChaosfsInjectFailed               = CodeType{64001, "inject io exception in pod %s failed, request %v, err: %v"}
 	ChaosfsRecoverFailed              = CodeType{64002, "recover io exception failed in pod  %v, err: %v"}
@@ -121,14 +132,55 @@ var (
 )
 
 func (c CodeType) Sprintf(values ...interface{}) string {
-	return fmt.Sprintf(c.Msg, values...)
+	return c.renderFlags(defaultLocale(), values)
 }
 
 type Response struct {
-	Code    int32       `json:"code"`
-	Success bool        `json:"success"`
-	Err     string      `json:"error,omitempty"`
-	Result  interface{} `json:"result,omitempty"`
+	Code     int32                  `json:"code"`
+	Success  bool                   `json:"success"`
+	Err      string                 `json:"error,omitempty"`
+	Result   interface{}            `json:"result,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Warnings []Warning              `json:"warnings,omitempty"`
+	// Causes is the failure chain from the nearest to the innermost layer
+	Causes []Cause `json:"causes,omitempty"`
+	// FieldErrors are the details of the illegal parameters
+	FieldErrors []FieldError `json:"fieldErrors,omitempty"`
+	// Encoding is the compression algorithm of the result, the result is not compressed if empty
+	Encoding string `json:"encoding,omitempty"`
+	// Severity and Retryable are the classification of the failed response code, see ClassifyCode
+	Severity  string `json:"severity,omitempty"`
+	Retryable bool   `json:"retryable,omitempty"`
+}
+
+// The metadata keys populated by the framework
+const (
+	MetadataHost       = "host"
+	MetadataHostIP     = "hostIp"
+	MetadataChannel    = "channel"
+	MetadataStartTime  = "startTime"
+	MetadataEndTime    = "endTime"
+	MetadataDurationMs = "durationMs"
+	// MetadataTruncated is true if the command output in the response is truncated by the channel
+	MetadataTruncated = "truncated"
+	MetadataProgress  = "progress"
+	// MetadataOutput is the non-JSON output around the response decoded by Decode, such as the log lines
+	MetadataOutput = "output"
+)
+
+// SetMetadata sets the execution context value to the response
+func (response *Response) SetMetadata(key string, value interface{}) *Response {
+	if response.Metadata == nil {
+		response.Metadata = make(map[string]interface{})
+	}
+	response.Metadata[key] = value
+	return response
+}
+
+// GetMetadata returns the metadata value by the key
+func (response *Response) GetMetadata(key string) (interface{}, bool) {
+	value, ok := response.Metadata[key]
+	return value, ok
 }
 
 func (response *Response) Error() string {
@@ -144,11 +196,11 @@ func (response *Response) Print() string {
 }
 
 func Return(codeType CodeType, success bool) *Response {
-	return &Response{Code: codeType.Code, Success: success, Err: codeType.Msg}
+	return (&Response{Code: codeType.Code, Success: success, Err: codeType.localMsg()}).classify()
 }
 
 func ReturnFail(codeType CodeType, err string) *Response {
-	return &Response{Code: codeType.Code, Success: false, Err: err}
+	return (&Response{Code: codeType.Code, Success: false, Err: err}).classify()
 }
 
 func ReturnSuccess(result interface{}) *Response {
@@ -160,18 +212,24 @@ func ReturnResultIgnoreCode(result interface{}) *Response {
 }
 
 func ResponseFail(status int32, err string, result interface{}) *Response {
-	return &Response{Code: status, Success: false, Err: err, Result: result}
+	return (&Response{Code: status, Success: false, Err: err, Result: result}).classify()
 }
 
+// ResponseFailWithFlags returns the failed response, the message is rendered by the code template in the default
+// locale and the flags are the template params in order, see MessageParams
 func ResponseFailWithFlags(codeType CodeType, flags ...interface{}) *Response {
-	if flags == nil {
-		return &Response{Code: codeType.Code, Success: false, Err: codeType.Msg}
-	}
-	return &Response{Code: codeType.Code, Success: false, Err: fmt.Sprintf(codeType.Msg, flags...)}
+	return (&Response{Code: codeType.Code, Success: false, Err: codeType.renderFlags(defaultLocale(), flags)}).classify()
+}
+
+// ResponseFailWithParams is like ResponseFailWithFlags, but the template params are given by name
+func ResponseFailWithParams(codeType CodeType, params map[string]interface{}) *Response {
+	msg, _ := codeType.Render(defaultLocale(), params)
+	return (&Response{Code: codeType.Code, Success: false, Err: msg}).classify()
 }
 
 func ResponseFailWithResult(codeType CodeType, result interface{}, flags ...interface{}) *Response {
-	return &Response{Code: codeType.Code, Success: false, Result: result, Err: fmt.Sprintf(codeType.Msg, flags...)}
+	return (&Response{Code: codeType.Code, Success: false, Result: result,
+		Err: codeType.renderFlags(defaultLocale(), flags)}).classify()
 }
 
 func Success() *Response {
@@ -187,11 +245,30 @@ func (response *Response) ToString() string {
 	return fmt.Sprintln(string(bytes))
 }
 
-// Decode return the response that wraps the content
+// DecodeOptions is the options of DecodeWithOptions
+type DecodeOptions struct {
+	// Strict requires the content to be the response JSON only. Otherwise the last JSON object with the code field
+	// is decoded, such as the command output with the log lines before the JSON, and the remainder of the content
+	// is set to the MetadataOutput of the response.
+	Strict bool
+}
+
+// Decode return the response that wraps the content, the log lines around the response JSON are tolerated
 func Decode(content string, defaultValue *Response) *Response {
+	return DecodeWithOptions(content, defaultValue, DecodeOptions{})
+}
+
+// DecodeWithOptions return the response that wraps the content, or the default value if decoding failed
+func DecodeWithOptions(content string, defaultValue *Response, options DecodeOptions) *Response {
 	var resp Response
 	content = strings.TrimSpace(content)
-	err := json.Unmarshal([]byte(content), &resp)
+	payload, remainder := content, ""
+	if !options.Strict {
+		if extracted, rest, ok := extractResponseJSON(content); ok {
+			payload, remainder = extracted, rest
+		}
+	}
+	err := json.Unmarshal([]byte(payload), &resp)
 	if err != nil {
 		if defaultValue == nil {
 			defaultValue = ResponseFailWithFlags(ResultUnmarshalFailed, content, err.Error())
@@ -199,5 +276,107 @@ func Decode(content string, defaultValue *Response) *Response {
 		logrus.Debugf("decode %s err, return default value, %s", content, defaultValue.Print())
 		return defaultValue
 	}
-	return &resp
+	if resp.Encoding != "" {
+		if err := resp.Decompress(); err != nil {
+			logrus.Warnf("decompress the result of %s err, %v", content, err)
+		}
+	}
+	if !resp.Success && resp.Err == "" && resp.Code != IgnoreCode.Code {
+		if codeType, ok := GetCodeType(resp.Code); ok {
+			resp.Err = codeType.localMsg()
+		}
+	}
+	if remainder != "" {
+		resp.SetMetadata(MetadataOutput, remainder)
+	}
+	return resp.classify()
+}
+
+// ExtractJSON finds the last top-level JSON object in the content, it returns the object and the remainder
+// of the content around it. The bool result is false if no JSON object is found.
+func ExtractJSON(content string) (string, string, bool) {
+	span, ok := lastJSONObject(content, len(content))
+	if !ok {
+		return "", content, false
+	}
+	return content[span[0]:span[1]], jsonRemainder(content, span), true
+}
+
+// EndsWithJSON returns true if the content ends with a JSON object, such as the response JSON printed by the
+// blade command, maybe followed by the JSON log lines. The command output embedding JSON in the middle, such
+// as the API payload printed by curl, is not taken as the response.
+func EndsWithJSON(content string) bool {
+	content = strings.TrimSpace(content)
+	span, ok := lastJSONObject(content, len(content))
+	return ok && span[1] == len(content)
+}
+
+// lastJSONObject returns the start and end offsets of the last JSON object ending before end. The object is
+// located by scanning back from the last '}' to its matching '{', so only the trailing object is decoded instead
+// of trying every '{' in the content, which is quadratic for the long outputs.
+func lastJSONObject(content string, end int) ([2]int, bool) {
+	for {
+		last := strings.LastIndexByte(content[:end], '}')
+		if last < 0 {
+			return [2]int{}, false
+		}
+		if start, ok := matchingOpenBrace(content, last); ok && json.Valid([]byte(content[start:last+1])) {
+			return [2]int{start, last + 1}, true
+		}
+		end = last
+	}
+}
+
+// matchingOpenBrace returns the offset of the '{' matching the '}' at the close offset, the braces in the JSON
+// strings are skipped
+func matchingOpenBrace(content string, close int) (int, bool) {
+	depth, inString := 0, false
+	for idx := close; idx >= 0; idx-- {
+		switch c := content[idx]; {
+		case c == '"' && !isEscaped(content, idx):
+			inString = !inString
+		case inString:
+		case c == '}':
+			depth++
+		case c == '{':
+			depth--
+			if depth == 0 {
+				return idx, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// isEscaped returns true if the character at the offset is preceded by an odd number of backslashes
+func isEscaped(content string, idx int) bool {
+	backslashes := 0
+	for idx--; idx >= 0 && content[idx] == '\\'; idx-- {
+		backslashes++
+	}
+	return backslashes%2 == 1
+}
+
+func jsonRemainder(content string, span [2]int) string {
+	remainder := strings.TrimSpace(content[:span[0]]) + "\n" + strings.TrimSpace(content[span[1]:])
+	return strings.TrimSpace(remainder)
+}
+
+// extractResponseJSON finds the last JSON object containing the code field, so the JSON printed by the command
+// itself, such as the JSON log lines after the response, is not taken as the response
+func extractResponseJSON(content string) (string, string, bool) {
+	for end := len(content); ; {
+		span, ok := lastJSONObject(content, end)
+		if !ok {
+			return "", content, false
+		}
+		payload := content[span[0]:span[1]]
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(payload), &fields); err == nil {
+			if _, ok := fields["code"]; ok {
+				return payload, jsonRemainder(content, span), true
+			}
+		}
+		end = span[0]
+	}
 }
@@ -0,0 +1,78 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"sync"
+)
+
+// RollbackFunc recovers the experiment created successfully
+type RollbackFunc func(ctx context.Context) *Response
+
+var (
+	rollbacks     = make(map[string]RollbackFunc)
+	rollbacksLock sync.Mutex
+)
+
+// RegisterRollback records the rollback of the experiment by the uid, the executor invokes it after creating
+// successfully. The rollbacks are kept in memory, so they only work in the process which created the experiment.
+func RegisterRollback(uid string, rollback RollbackFunc) {
+	rollbacksLock.Lock()
+	defer rollbacksLock.Unlock()
+	rollbacks[uid] = rollback
+}
+
+// HasRollback returns true if the rollback of the uid is registered
+func HasRollback(uid string) bool {
+	rollbacksLock.Lock()
+	defer rollbacksLock.Unlock()
+	_, ok := rollbacks[uid]
+	return ok
+}
+
+// RollbackMiddleware returns the middleware which dispatches the destroy to the rollback registered by the uid,
+// the executor is invoked if not found. The rollback is removed after it succeeds, so the destroy can be retried.
+func RollbackMiddleware() ExecutorMiddleware {
+	return ExecutorMiddlewareFunc(func(next ExecFunc) ExecFunc {
+		return func(uid string, ctx context.Context, model *ExpModel) *Response {
+			if _, isDestroy := IsDestroy(ctx); !isDestroy {
+				return next(uid, ctx, model)
+			}
+			return DispatchDestroy(uid, ctx, func(ctx context.Context) *Response {
+				return next(uid, ctx, model)
+			})
+		}
+	})
+}
+
+// DispatchDestroy invokes the rollback registered by the uid, or the fallback if not registered
+func DispatchDestroy(uid string, ctx context.Context, fallback RollbackFunc) *Response {
+	rollbacksLock.Lock()
+	rollback, ok := rollbacks[uid]
+	rollbacksLock.Unlock()
+	if !ok {
+		return fallback(ctx)
+	}
+	response := rollback(ctx)
+	if response != nil && response.Success {
+		rollbacksLock.Lock()
+		delete(rollbacks, uid)
+		rollbacksLock.Unlock()
+	}
+	return response
+}
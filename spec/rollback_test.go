@@ -0,0 +1,51 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRollbackMiddleware(t *testing.T) {
+	executor := &testExecutor{exec: func(uid string, ctx context.Context, model *ExpModel) *Response {
+		if _, isDestroy := IsDestroy(ctx); isDestroy {
+			return ReturnSuccess("executor destroyed")
+		}
+		RegisterRollback(uid, func(ctx context.Context) *Response {
+			return ReturnSuccess("rollback")
+		})
+		return ReturnSuccess(uid)
+	}}
+	wrapped := WithMiddlewares(executor, RollbackMiddleware())
+	if response := wrapped.Exec("uid-1", context.Background(), &ExpModel{}); !response.Success {
+		t.Fatalf("create = %v, want success", response)
+	}
+	if !HasRollback("uid-1") {
+		t.Fatalf("HasRollback() = false, want true")
+	}
+	ctx := SetDestroyFlag(context.Background(), "uid-1")
+	if response := wrapped.Exec("uid-1", ctx, &ExpModel{}); response.Result != "rollback" {
+		t.Errorf("destroy = %v, want the rollback result", response)
+	}
+	if HasRollback("uid-1") {
+		t.Errorf("HasRollback() = true, want removed after rollback")
+	}
+	if response := wrapped.Exec("uid-1", ctx, &ExpModel{}); response.Result != "executor destroyed" {
+		t.Errorf("destroy = %v, want the executor result", response)
+	}
+}
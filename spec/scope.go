@@ -0,0 +1,74 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"fmt"
+)
+
+// The execution scopes of the actions, the empty scope is the host
+const (
+	ScopeHost   = "host"
+	ScopeDocker = "docker"
+	ScopeCri    = "cri"
+	ScopeK8s    = "k8s"
+	ScopeOS     = "os"
+)
+
+func normalizeScope(scope string) string {
+	if scope == "" {
+		return ScopeHost
+	}
+	return scope
+}
+
+// ActionSupportsScope returns true if the action is applicable to the scope, the action without scopes
+// is applicable to all scopes
+func ActionSupportsScope(action ExpActionCommandSpec, scope string) bool {
+	scopes := ActionScopesOf(action)
+	if len(scopes) == 0 {
+		return true
+	}
+	scope = normalizeScope(scope)
+	for _, s := range scopes {
+		if normalizeScope(s) == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ActionsForScope returns the actions of the model applicable to the scope, it is used to list the actions
+// in the help and the generated specs of the scope
+func ActionsForScope(model ExpModelCommandSpec, scope string) []ExpActionCommandSpec {
+	actions := make([]ExpActionCommandSpec, 0)
+	for _, action := range model.Actions() {
+		if ActionSupportsScope(action, scope) {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+// ValidateActionScope returns the ActionNotSupport response if the action is not applicable to the scope
+// of the model
+func ValidateActionScope(action ExpActionCommandSpec, model *ExpModel) *Response {
+	if ActionSupportsScope(action, model.Scope) {
+		return nil
+	}
+	return ResponseFailWithFlags(ActionNotSupport, fmt.Sprintf("%s in the %s scope", action.Name(), normalizeScope(model.Scope)))
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestActionsForScope(t *testing.T) {
+	model := &ExpCommandModel{ExpName: "cpu", ExpActions: []ActionModel{
+		{ActionName: "fullload"},
+		{ActionName: "bind", ActionScopes: []string{ScopeHost}},
+		{ActionName: "throttle", ActionScopes: []string{ScopeDocker, ScopeK8s}},
+	}}
+	tests := []struct {
+		scope string
+		want  []string
+	}{
+		{scope: "", want: []string{"fullload", "bind"}},
+		{scope: ScopeHost, want: []string{"fullload", "bind"}},
+		{scope: ScopeDocker, want: []string{"fullload", "throttle"}},
+		{scope: ScopeCri, want: []string{"fullload"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.scope, func(t *testing.T) {
+			actions := ActionsForScope(model, tt.scope)
+			names := make([]string, 0)
+			for _, action := range actions {
+				names = append(names, action.Name())
+			}
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("ActionsForScope() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateExpCommandScope(t *testing.T) {
+	command := &ExpCommandModel{ExpName: "cpu"}
+	action := &ActionModel{ActionName: "throttle", ActionScopes: []string{ScopeDocker}}
+	if response := ValidateExpCommand(context.Background(), command, action, &ExpModel{Scope: ScopeDocker}); response != nil {
+		t.Errorf("ValidateExpCommand() = %s, want nil", response.Print())
+	}
+	response := ValidateExpCommand(context.Background(), command, action, &ExpModel{})
+	if response == nil || response.Code != ActionNotSupport.Code {
+		t.Errorf("ValidateExpCommand() = %v, want ActionNotSupport", response)
+	}
+}
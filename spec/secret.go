@@ -0,0 +1,138 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// SecretMask replaces the values of the secret flags
+const SecretMask = "******"
+
+// MarkSecretFlags records the secret flags of the action in the model, the matchers and the flags of the
+// target are included. The values are still delivered to the executor, only the copies returned by Masked,
+// MaskedFlags and String are masked.
+func MarkSecretFlags(target ExpModelCommandSpec, action ExpActionCommandSpec, model *ExpModel) {
+	flags := make([]ExpFlagSpec, 0)
+	if target != nil {
+		flags = append(flags, target.Flags()...)
+	}
+	flags = append(flags, action.Matchers()...)
+	flags = append(flags, action.Flags()...)
+	for _, flag := range flags {
+		if FlagSecretOf(flag) && !model.isSecretFlag(flag.FlagName()) {
+			model.SecretFlags = append(model.SecretFlags, flag.FlagName())
+		}
+	}
+}
+
+func (exp *ExpModel) isSecretFlag(name string) bool {
+	for _, secret := range exp.SecretFlags {
+		if secret == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Masked returns the copy of the model whose secret flag values are replaced by SecretMask,
+// it is used to write the model to logs and records
+func (exp *ExpModel) Masked() *ExpModel {
+	masked := *exp
+	if len(exp.SecretFlags) == 0 || exp.ActionFlags == nil {
+		return &masked
+	}
+	masked.ActionFlags = make(map[string]string, len(exp.ActionFlags))
+	for name, value := range exp.ActionFlags {
+		if value != "" && exp.isSecretFlag(name) {
+			value = SecretMask
+		}
+		masked.ActionFlags[name] = value
+	}
+	if exp.ActionFlagValues != nil {
+		masked.ActionFlagValues = make(map[string][]string, len(exp.ActionFlagValues))
+		for name, values := range exp.ActionFlagValues {
+			if exp.isSecretFlag(name) {
+				values = []string{SecretMask}
+			}
+			masked.ActionFlagValues[name] = values
+		}
+	}
+	return &masked
+}
+
+// MaskedFlags returns the flags like GetFlags with the secret values masked
+func (exp *ExpModel) MaskedFlags() string {
+	return exp.Masked().GetFlags()
+}
+
+// MaskSecrets replaces the secret flag values in the text, such as the command line in the error message
+func (exp *ExpModel) MaskSecrets(text string) string {
+	for _, name := range exp.SecretFlags {
+		if value := exp.ActionFlags[name]; value != "" {
+			text = strings.ReplaceAll(text, value, SecretMask)
+		}
+		for _, value := range exp.ActionFlagValues[name] {
+			if value != "" {
+				text = strings.ReplaceAll(text, value, SecretMask)
+			}
+		}
+	}
+	return text
+}
+
+// SecretsContextKey is the context key of the model whose secret flag values are masked by MaskContextSecrets
+const SecretsContextKey ContextKey = "secrets"
+
+// WithSecrets returns a copy of ctx carrying the secret flag values of the model, so the channels can mask
+// them in the command logs and the error messages without the model
+func WithSecrets(ctx context.Context, model *ExpModel) context.Context {
+	if model == nil || len(model.SecretFlags) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, SecretsContextKey, model)
+}
+
+// MaskContextSecrets replaces the secret flag values carried by ctx in the text
+func MaskContextSecrets(ctx context.Context, text string) string {
+	if model, ok := ContextValue(ctx, SecretsContextKey).(*ExpModel); ok {
+		return model.MaskSecrets(text)
+	}
+	return text
+}
+
+// String returns the json encoding of the masked model
+func (exp *ExpModel) String() string {
+	bytes, err := json.Marshal(exp.Masked())
+	if err != nil {
+		return err.Error()
+	}
+	return string(bytes)
+}
+
+// maskResponseSecrets masks the secret flag values in the error messages of the response
+func maskResponseSecrets(model *ExpModel, response *Response) {
+	if response == nil || model == nil || len(model.SecretFlags) == 0 {
+		return
+	}
+	response.Err = model.MaskSecrets(response.Err)
+	for idx := range response.Causes {
+		response.Causes[idx].Message = model.MaskSecrets(response.Causes[idx].Message)
+	}
+}
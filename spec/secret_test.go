@@ -0,0 +1,81 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestMaskSecretFlags(t *testing.T) {
+	action := &ActionModel{
+		ActionMatchers: []ExpFlag{{Name: "host"}},
+		ActionFlags:    []ExpFlag{{Name: "password", Secret: true}},
+	}
+	target := &ExpCommandModel{ExpName: "mysql", ExpFlags: []ExpFlag{{Name: "token", Secret: true}}}
+	model := &ExpModel{ActionFlags: map[string]string{"host": "db01", "password": "p@ss", "token": ""}}
+	MarkSecretFlags(target, action, model)
+	MarkSecretFlags(target, action, model)
+	if len(model.SecretFlags) != 2 {
+		t.Fatalf("SecretFlags = %v, want token and password", model.SecretFlags)
+	}
+
+	masked := model.Masked()
+	if masked.ActionFlags["password"] != SecretMask || masked.ActionFlags["host"] != "db01" || masked.ActionFlags["token"] != "" {
+		t.Errorf("Masked() flags = %v", masked.ActionFlags)
+	}
+	if model.ActionFlags["password"] != "p@ss" {
+		t.Errorf("Masked() changed the original model")
+	}
+	if flags := model.MaskedFlags(); strings.Contains(flags, "p@ss") || !strings.Contains(flags, "--password "+SecretMask) {
+		t.Errorf("MaskedFlags() = %s", flags)
+	}
+	if s := model.String(); strings.Contains(s, "p@ss") {
+		t.Errorf("String() = %s contains the secret", s)
+	}
+
+	executor := &testExecutor{exec: func(uid string, ctx context.Context, model *ExpModel) *Response {
+		if model.ActionFlags["password"] != "p@ss" {
+			t.Errorf("executor got password %s, want p@ss", model.ActionFlags["password"])
+		}
+		if masked := MaskContextSecrets(ctx, "mysql -pp@ss"); masked != "mysql -p"+SecretMask {
+			t.Errorf("MaskContextSecrets() = %s", masked)
+		}
+		return ResponseFailWithFlags(OsCmdExecFailed, "mysql -pp@ss", "denied").
+			AddCauses(Cause{Message: "mysql -pp@ss: exit status 1"})
+	}}
+	response := ExecExperiment(executor, "abc", context.Background(), model)
+	if strings.Contains(response.Err, "p@ss") || strings.Contains(response.Causes[0].Message, "p@ss") {
+		t.Errorf("ExecExperiment() response contains the secret: %s", response.Print())
+	}
+}
+
+func TestMaskContextSecrets(t *testing.T) {
+	if masked := MaskContextSecrets(context.Background(), "p@ss"); masked != "p@ss" {
+		t.Errorf("MaskContextSecrets() without secrets = %s", masked)
+	}
+	model := &ExpModel{ActionFlags: map[string]string{"password": "p@ss"}}
+	if ctx := WithSecrets(context.Background(), model); ctx.Value(SecretsContextKey) != nil {
+		t.Errorf("WithSecrets() carries the model without secret flags")
+	}
+	model.SecretFlags = []string{"password"}
+	ctx := WithSecrets(context.Background(), model)
+	if masked := MaskContextSecrets(ctx, "--password p@ss"); masked != "--password "+SecretMask {
+		t.Errorf("MaskContextSecrets() = %s", masked)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"fmt"
+	"sync"
+)
+
+// The severities of the failed responses
+const (
+	// SeverityWarning is the failure which does not affect the experiment, such as the rule already exists
+	SeverityWarning = "warning"
+	// SeverityError is the failure of the experiment, it is the default severity
+	SeverityError = "error"
+	// SeverityFatal is the failure of the chaosblade environment, no experiments can be run before it is fixed
+	SeverityFatal = "fatal"
+)
+
+// CodeClassification is the severity and the retryability of the code
+type CodeClassification struct {
+	Severity string `json:"severity" yaml:"severity"`
+	// Retryable is true if the failure is transient, such as the command busy, so the request can be retried
+	Retryable bool `json:"retryable" yaml:"retryable"`
+}
+
+var (
+	// codeClassifications contains the codes whose classification differs from the error severity not retryable
+	codeClassifications = map[int32]CodeClassification{
+		Forbidden.Code:                {Severity: SeverityFatal},
+		CommandNetworkExist.Code:      {Severity: SeverityWarning},
+		ChaosbladeFileNotFound.Code:   {Severity: SeverityFatal},
+		ChaosbladeServerStarted.Code:  {Severity: SeverityWarning},
+		UnexpectedStatus.Code:         {Severity: SeverityError, Retryable: true},
+		DockerExecNotFound.Code:       {Severity: SeverityFatal},
+		DockerImagePullFailed.Code:    {Severity: SeverityError, Retryable: true},
+		CriExecNotFound.Code:          {Severity: SeverityFatal},
+		ImagePullFailed.Code:          {Severity: SeverityError, Retryable: true},
+		HandlerExecNotFound.Code:      {Severity: SeverityFatal},
+		PodNotReady.Code:              {Severity: SeverityError, Retryable: true},
+		GenerateUidFailed.Code:        {Severity: SeverityError, Retryable: true},
+		ChaosbladeServiceStoped.Code:  {Severity: SeverityFatal},
+		ChannelNil.Code:               {Severity: SeverityFatal},
+		SandboxGetPortFailed.Code:     {Severity: SeverityError, Retryable: true},
+		BackfileExists.Code:           {Severity: SeverityError, Retryable: true},
+		OsExecutorNotFound.Code:       {Severity: SeverityFatal},
+		ExecutorPanic.Code:            {Severity: SeverityFatal},
+		ExecTimeout.Code:              {Severity: SeverityError, Retryable: true},
+		ExecCanceled.Code:             {Severity: SeverityWarning},
+		ConcurrencyLimitExceeded.Code: {Severity: SeverityError, Retryable: true},
+		ChaosfsClientFailed.Code:      {Severity: SeverityError, Retryable: true},
+	}
+	codeClassificationsLock sync.RWMutex
+)
+
+// RegisterCodeClassification sets the severity and the retryability of the code, it is used by the plugins
+// to classify their custom codes
+func RegisterCodeClassification(code int32, classification CodeClassification) error {
+	switch classification.Severity {
+	case SeverityWarning, SeverityError, SeverityFatal:
+	default:
+		return fmt.Errorf("illegal severity `%s` of code %d", classification.Severity, code)
+	}
+	codeClassificationsLock.Lock()
+	defer codeClassificationsLock.Unlock()
+	codeClassifications[code] = classification
+	return nil
+}
+
+// ClassifyCode returns the severity and the retryability of the code. The success codes have no severity,
+// the others are the errors not retryable unless classified by RegisterCodeClassification.
+func ClassifyCode(code int32) CodeClassification {
+	if code < 300 {
+		return CodeClassification{}
+	}
+	codeClassificationsLock.RLock()
+	defer codeClassificationsLock.RUnlock()
+	if classification, ok := codeClassifications[code]; ok {
+		return classification
+	}
+	return CodeClassification{Severity: SeverityError}
+}
+
+// classify sets the severity and the retryability of the failed response by its code
+func (response *Response) classify() *Response {
+	if response.Success || response.Severity != "" {
+		return response
+	}
+	classification := ClassifyCode(response.Code)
+	response.Severity, response.Retryable = classification.Severity, classification.Retryable
+	return response
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import "testing"
+
+func TestClassifyResponse(t *testing.T) {
+	tests := []struct {
+		name         string
+		response     *Response
+		wantSeverity string
+		wantRetry    bool
+	}{
+		{name: "success", response: ReturnSuccess(nil)},
+		{name: "parameter illegal", response: ResponseFailWithFlags(ParameterIllegal, "a", "b", "c"), wantSeverity: SeverityError},
+		{name: "timeout", response: ResponseFailWithFlags(ExecTimeout, "delay", "10s"), wantSeverity: SeverityError, wantRetry: true},
+		{name: "canceled", response: ReturnFail(ExecCanceled, "canceled"), wantSeverity: SeverityWarning},
+		{name: "panic", response: ResponseFail(ExecutorPanic.Code, "boom", nil), wantSeverity: SeverityFatal},
+		{name: "decoded", response: Decode(`{"code":63072,"success":false}`, nil), wantSeverity: SeverityError, wantRetry: true},
+		{name: "decoded classification kept", response: Decode(`{"code":63072,"success":false,"severity":"fatal"}`, nil),
+			wantSeverity: SeverityFatal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.response.Severity != tt.wantSeverity || tt.response.Retryable != tt.wantRetry {
+				t.Errorf("Severity, Retryable = %s, %v, want %s, %v", tt.response.Severity, tt.response.Retryable,
+					tt.wantSeverity, tt.wantRetry)
+			}
+		})
+	}
+}
+
+func TestRegisterCodeClassification(t *testing.T) {
+	if err := RegisterCodeClassification(99201, CodeClassification{Severity: "critical"}); err == nil {
+		t.Errorf("RegisterCodeClassification() error = nil, want illegal severity")
+	}
+	if err := RegisterCodeClassification(99201, CodeClassification{Severity: SeverityError, Retryable: true}); err != nil {
+		t.Fatalf("RegisterCodeClassification() error = %v", err)
+	}
+	if classification := ClassifyCode(99201); !classification.Retryable {
+		t.Errorf("ClassifyCode() = %+v, want retryable", classification)
+	}
+	if classification := ClassifyCode(99202); classification.Severity != SeverityError || classification.Retryable {
+		t.Errorf("ClassifyCode() = %+v, want the default classification", classification)
+	}
+}
+
+func TestClassificationProto(t *testing.T) {
+	data, err := MarshalResponseProto(ResponseFailWithFlags(PodNotReady, "nginx"))
+	if err != nil {
+		t.Fatalf("MarshalResponseProto() error = %v", err)
+	}
+	response, err := UnmarshalResponseProto(data)
+	if err != nil {
+		t.Fatalf("UnmarshalResponseProto() error = %v", err)
+	}
+	if response.Severity != SeverityError || !response.Retryable {
+		t.Errorf("UnmarshalResponseProto() = %+v, want the classification", response)
+	}
+}
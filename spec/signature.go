@@ -0,0 +1,186 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+// The signature algorithms of the signed response
+const (
+	SignatureHMACSHA256 = "HS256"
+	SignatureEd25519    = "EdDSA"
+)
+
+// ErrInvalidSignature is returned if the signed response is tampered or signed by another key
+var ErrInvalidSignature = errors.New("invalid response signature")
+
+// SignedResponse is the serialized response with its signature, it is relayed through the intermediaries
+// instead of the response so the receiver can verify the response is not tampered
+type SignedResponse struct {
+	// Payload is the response encoded in the content type
+	Payload     []byte `json:"payload"`
+	ContentType string `json:"contentType"`
+	Algorithm   string `json:"algorithm"`
+	// KeyID identifies the key when the keys are rotated, it is optional
+	KeyID     string `json:"keyId,omitempty"`
+	Signature []byte `json:"signature"`
+}
+
+// ResponseSigner signs the serialized response
+type ResponseSigner interface {
+	Algorithm() string
+	KeyID() string
+	Sign(data []byte) ([]byte, error)
+}
+
+// ResponseVerifier verifies the signature of the serialized response
+type ResponseVerifier interface {
+	Algorithm() string
+	KeyID() string
+	Verify(data, signature []byte) bool
+}
+
+// SignResponse encodes the response by the content type negotiated from the accept header and signs it
+func SignResponse(response *Response, accept string, signer ResponseSigner) (*SignedResponse, error) {
+	payload, contentType, err := EncodeResponse(response, accept)
+	if err != nil {
+		return nil, err
+	}
+	signed := &SignedResponse{
+		Payload:     payload,
+		ContentType: contentType,
+		Algorithm:   signer.Algorithm(),
+		KeyID:       signer.KeyID(),
+	}
+	if signed.Signature, err = signer.Sign(signed.signingInput()); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// VerifyResponse verifies the signature by the verifier of the same algorithm and key id, and decodes the response.
+// ErrInvalidSignature is returned if the verification failed.
+func VerifyResponse(signed *SignedResponse, verifier ResponseVerifier) (*Response, error) {
+	if signed == nil {
+		return nil, ErrInvalidSignature
+	}
+	if signed.Algorithm != verifier.Algorithm() {
+		return nil, fmt.Errorf("%w: unexpected algorithm `%s`", ErrInvalidSignature, signed.Algorithm)
+	}
+	if verifier.KeyID() != "" && signed.KeyID != verifier.KeyID() {
+		return nil, fmt.Errorf("%w: unexpected key `%s`", ErrInvalidSignature, signed.KeyID)
+	}
+	if !verifier.Verify(signed.signingInput(), signed.Signature) {
+		return nil, ErrInvalidSignature
+	}
+	return DecodeResponse(signed.Payload, signed.ContentType)
+}
+
+// signingInput binds the content type to the payload, so the payload can not be decoded as another type
+func (s *SignedResponse) signingInput() []byte {
+	input := make([]byte, 0, len(s.ContentType)+1+len(s.Payload))
+	input = append(input, s.ContentType...)
+	input = append(input, '\n')
+	return append(input, s.Payload...)
+}
+
+// HMACSigner signs and verifies the response by HMAC-SHA256 with the shared key
+type HMACSigner struct {
+	keyID string
+	key   []byte
+}
+
+// NewHMACSigner returns the HMAC-SHA256 signer, the key id is optional
+func NewHMACSigner(keyID string, key []byte) *HMACSigner {
+	return &HMACSigner{keyID: keyID, key: key}
+}
+
+func (s *HMACSigner) Algorithm() string {
+	return SignatureHMACSHA256
+}
+
+func (s *HMACSigner) KeyID() string {
+	return s.keyID
+}
+
+func (s *HMACSigner) Sign(data []byte) ([]byte, error) {
+	if len(s.key) == 0 {
+		return nil, errors.New("the hmac key is empty")
+	}
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(data)
+	return mac.Sum(nil), nil
+}
+
+func (s *HMACSigner) Verify(data, signature []byte) bool {
+	expected, err := s.Sign(data)
+	return err == nil && hmac.Equal(expected, signature)
+}
+
+// Ed25519Signer signs the response by the ed25519 private key
+type Ed25519Signer struct {
+	keyID      string
+	privateKey ed25519.PrivateKey
+}
+
+// NewEd25519Signer returns the ed25519 signer, the key id is optional
+func NewEd25519Signer(keyID string, privateKey ed25519.PrivateKey) *Ed25519Signer {
+	return &Ed25519Signer{keyID: keyID, privateKey: privateKey}
+}
+
+func (s *Ed25519Signer) Algorithm() string {
+	return SignatureEd25519
+}
+
+func (s *Ed25519Signer) KeyID() string {
+	return s.keyID
+}
+
+func (s *Ed25519Signer) Sign(data []byte) ([]byte, error) {
+	if len(s.privateKey) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("illegal ed25519 private key size %d", len(s.privateKey))
+	}
+	return ed25519.Sign(s.privateKey, data), nil
+}
+
+// Ed25519Verifier verifies the response by the ed25519 public key
+type Ed25519Verifier struct {
+	keyID     string
+	publicKey ed25519.PublicKey
+}
+
+// NewEd25519Verifier returns the ed25519 verifier, the key id is optional
+func NewEd25519Verifier(keyID string, publicKey ed25519.PublicKey) *Ed25519Verifier {
+	return &Ed25519Verifier{keyID: keyID, publicKey: publicKey}
+}
+
+func (v *Ed25519Verifier) Algorithm() string {
+	return SignatureEd25519
+}
+
+func (v *Ed25519Verifier) KeyID() string {
+	return v.keyID
+}
+
+func (v *Ed25519Verifier) Verify(data, signature []byte) bool {
+	return len(v.publicKey) == ed25519.PublicKeySize && ed25519.Verify(v.publicKey, data, signature)
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"testing"
+)
+
+func TestSignResponse(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	otherPublicKey, _, _ := ed25519.GenerateKey(nil)
+	tests := []struct {
+		name     string
+		accept   string
+		signer   ResponseSigner
+		verifier ResponseVerifier
+		tamper   func(signed *SignedResponse)
+		wantErr  bool
+	}{
+		{name: "hmac", signer: NewHMACSigner("k1", []byte("secret")), verifier: NewHMACSigner("k1", []byte("secret"))},
+		{name: "hmac protobuf", accept: ContentTypeProtobuf, signer: NewHMACSigner("", []byte("secret")),
+			verifier: NewHMACSigner("", []byte("secret"))},
+		{name: "ed25519", signer: NewEd25519Signer("k1", privateKey), verifier: NewEd25519Verifier("k1", publicKey)},
+		{name: "tampered payload", signer: NewHMACSigner("", []byte("secret")), verifier: NewHMACSigner("", []byte("secret")),
+			tamper: func(signed *SignedResponse) { signed.Payload[len(signed.Payload)-2] ^= 1 }, wantErr: true},
+		{name: "tampered content type", signer: NewHMACSigner("", []byte("secret")), verifier: NewHMACSigner("", []byte("secret")),
+			tamper: func(signed *SignedResponse) { signed.ContentType = ContentTypeProtobuf }, wantErr: true},
+		{name: "other hmac key", signer: NewHMACSigner("", []byte("secret")), verifier: NewHMACSigner("", []byte("other")), wantErr: true},
+		{name: "other key id", signer: NewHMACSigner("k1", []byte("secret")), verifier: NewHMACSigner("k2", []byte("secret")), wantErr: true},
+		{name: "other public key", signer: NewEd25519Signer("", privateKey), verifier: NewEd25519Verifier("", otherPublicKey), wantErr: true},
+		{name: "other algorithm", signer: NewHMACSigner("", []byte("secret")), verifier: NewEd25519Verifier("", publicKey), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signed, err := SignResponse(ReturnSuccess("8a4c1ee1"), tt.accept, tt.signer)
+			if err != nil {
+				t.Fatalf("SignResponse() error = %v", err)
+			}
+			if tt.tamper != nil {
+				tt.tamper(signed)
+			}
+			response, err := VerifyResponse(signed, tt.verifier)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidSignature) {
+					t.Errorf("VerifyResponse() error = %v, want ErrInvalidSignature", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyResponse() error = %v", err)
+			}
+			if !response.Success || response.Result != "8a4c1ee1" {
+				t.Errorf("VerifyResponse() = %s, want the signed response", response.Print())
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package spectest provides the checks of the model specs for the plugin tests, for example
+//
+//	func TestSpecs(t *testing.T) {
+//		spectest.Check(t, NewCpuCommandModelSpec())
+//	}
+package spectest
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+	"github.com/chaosblade-io/chaosblade-spec-go/util"
+)
+
+// FuzzIterations is the number of the random values checked for every flag by Check
+const FuzzIterations = 100
+
+// commonFlags are added to every action by the spec conversion
+var commonFlags = []string{spec.TimeoutFlag, "async", "endpoint"}
+
+// Check runs all checks of the model spec and its sub models
+func Check(t testing.TB, modelSpec spec.ExpModelCommandSpec) {
+	t.Helper()
+	CheckRoundTrip(t, modelSpec)
+	CheckFlagUniqueness(t, modelSpec)
+	CheckExamples(t, modelSpec)
+	FuzzFlags(t, modelSpec, FuzzIterations)
+}
+
+// CheckRegistered runs all checks of the model specs registered by spec.RegisterModelSpec
+func CheckRegistered(t testing.TB) {
+	t.Helper()
+	for _, modelSpec := range spec.RegisteredModelSpecs() {
+		Check(t, modelSpec)
+	}
+}
+
+// CheckRoundTrip converts the model spec to the yaml, validates the yaml by util.ValidateModelSpec
+// and checks the yaml is the same after parsed and marshaled again
+func CheckRoundTrip(t testing.TB, modelSpec spec.ExpModelCommandSpec) {
+	t.Helper()
+	var expected bytes.Buffer
+	if err := util.MarshalModelSpec(util.ConvertSpecToModels(modelSpec, spec.ExpPrepareModel{}, ""), &expected); err != nil {
+		t.Errorf("%s: marshal the spec failed, %v", modelSpec.Name(), err)
+		return
+	}
+	models, errs := util.ValidateModelSpec(expected.Bytes())
+	for _, err := range errs {
+		t.Errorf("%s: illegal spec, %s", modelSpec.Name(), err)
+	}
+	if models == nil {
+		return
+	}
+	var actual bytes.Buffer
+	if err := util.MarshalModelSpec(models, &actual); err != nil {
+		t.Errorf("%s: marshal the parsed spec failed, %v", modelSpec.Name(), err)
+		return
+	}
+	if actual.String() != expected.String() {
+		t.Errorf("%s: the spec changed after the yaml round trip\nexpected:\n%s\nactual:\n%s",
+			modelSpec.Name(), expected.String(), actual.String())
+	}
+}
+
+// CheckFlagUniqueness checks the flag names and aliases of every target and the matchers and flags of every action
+// are unique
+func CheckFlagUniqueness(t testing.TB, modelSpec spec.ExpModelCommandSpec) {
+	t.Helper()
+	spec.WalkModels(modelSpec, func(path spec.ModelPath) error {
+		checkUnique(t, path.String(), path.Last().Flags())
+		for _, action := range path.Last().Actions() {
+			flags := append(append([]spec.ExpFlagSpec{}, action.Matchers()...), action.Flags()...)
+			checkUnique(t, path.String()+" "+action.Name(), flags)
+		}
+		return nil
+	})
+}
+
+func checkUnique(t testing.TB, command string, flags []spec.ExpFlagSpec) {
+	t.Helper()
+	names := make(map[string]bool)
+	for _, flag := range flags {
+		for _, name := range append([]string{flag.FlagName()}, spec.FlagAliasesOf(flag)...) {
+			if names[name] {
+				t.Errorf("%s: duplicate flag `%s`", command, name)
+			}
+			names[name] = true
+		}
+	}
+}
+
+// CheckExamples parses the flags of the action examples and the flag examples, and validates them by
+// spec.ValidateExpCommand. The examples of the other commands, such as blade destroy, are skipped.
+func CheckExamples(t testing.TB, modelSpec spec.ExpModelCommandSpec) {
+	t.Helper()
+	spec.WalkModels(modelSpec, func(path spec.ModelPath) error {
+		for _, action := range path.Last().Actions() {
+			command := path.String() + " " + action.Name()
+			examples := append([]spec.Example{}, spec.ActionExamplesOf(action)...)
+			for _, flag := range append(append([]spec.ExpFlagSpec{}, action.Matchers()...), action.Flags()...) {
+				examples = append(examples, spec.FlagExamplesOf(flag)...)
+			}
+			for _, example := range examples {
+				if !strings.Contains(example.Command, command+" ") && !strings.HasSuffix(example.Command, command) {
+					continue
+				}
+				checkExample(t, path, action, example.Command)
+			}
+		}
+		return nil
+	})
+}
+
+func checkExample(t testing.TB, path spec.ModelPath, action spec.ExpActionCommandSpec, command string) {
+	t.Helper()
+	args, err := spec.SplitArgs(command)
+	if err != nil {
+		t.Errorf("`%s`: %v", command, err)
+		return
+	}
+	for idx, arg := range args {
+		if strings.HasPrefix(arg, "--") {
+			args = args[idx:]
+			break
+		}
+	}
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		args = nil
+	}
+	model, err := spec.ArgsToModel(path.Last().Name(), action.Name(), args)
+	if err != nil {
+		t.Errorf("`%s`: %v", command, err)
+		return
+	}
+	flags := append(append(path.Flags(), action.Matchers()...), action.Flags()...)
+	for name := range model.ActionFlags {
+		if _, ok := spec.FindFlag(flags, name); !ok && !isCommonFlag(name) {
+			t.Errorf("`%s`: unknown flag `%s`", command, name)
+		}
+	}
+	if scopes := spec.ActionScopesOf(action); len(scopes) > 0 {
+		model.Scope = scopes[0]
+	}
+	if response := spec.ValidateModelPath(context.Background(), path, action, model); response != nil {
+		t.Errorf("`%s`: %s", command, response.Err)
+	}
+}
+
+func isCommonFlag(name string) bool {
+	for _, flag := range commonFlags {
+		if flag == name {
+			return true
+		}
+	}
+	return false
+}
+
+// FuzzFlags validates the random values and the boundary values of every flag by spec.ValidateFlagValue.
+// It checks the validation does not panic, the values breaking the declared type, enum values, min and max
+// are rejected, and the enum values are accepted.
+func FuzzFlags(t testing.TB, modelSpec spec.ExpModelCommandSpec, iterations int) {
+	t.Helper()
+	random := rand.New(rand.NewSource(1))
+	spec.WalkModels(modelSpec, func(path spec.ModelPath) error {
+		for _, action := range path.Last().Actions() {
+			command := path.String() + " " + action.Name()
+			for _, flag := range append(append(path.Flags(), action.Matchers()...), action.Flags()...) {
+				fuzzFlag(t, command, flag, random, iterations)
+			}
+		}
+		return nil
+	})
+}
+
+// fuzzValues are the values which are likely to break the parsing
+var fuzzValues = []string{"", " ", "0", "-1", "1.5", "1e309", "9223372036854775808", "NaN", "100%", "10MB", "1h",
+	"true", "abc", "--", "'\"", "\x00", "中文", strings.Repeat("9", 1024)}
+
+func fuzzFlag(t testing.TB, command string, flag spec.ExpFlagSpec, random *rand.Rand, iterations int) {
+	t.Helper()
+	validate := func(value string) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Errorf("%s: validate `%q` of the %s flag panic, %v", command, value, flag.FlagName(), r)
+			}
+		}()
+		return spec.ValidateFlagValue(flag, value)
+	}
+	for idx := 0; idx < iterations; idx++ {
+		if idx < len(fuzzValues) {
+			validate(fuzzValues[idx])
+			continue
+		}
+		value := make([]byte, random.Intn(16))
+		random.Read(value)
+		validate(string(value))
+	}
+	rejected := make([]string, 0)
+	switch spec.FlagTypeOf(flag) {
+	case spec.FlagTypeInt, spec.FlagTypeFloat, spec.FlagTypeDuration, spec.FlagTypeSize, spec.FlagTypePercent:
+		rejected = append(rejected, "not-a-number")
+	case spec.FlagTypeBool:
+		rejected = append(rejected, "not-a-bool")
+	case spec.FlagTypeJSON:
+		rejected = append(rejected, "not-an-object", "{")
+	}
+	if len(spec.FlagEnumValuesOf(flag)) > 0 {
+		rejected = append(rejected, "not-an-enum-value")
+		for _, value := range spec.FlagEnumValuesOf(flag) {
+			if err := validate(value); err != nil {
+				t.Errorf("%s: the enum value `%s` of the %s flag is rejected, %v", command, value, flag.FlagName(), err)
+			}
+		}
+	}
+	if validation := spec.FlagValidationOf(flag); validation != nil {
+		if validation.Min != nil {
+			rejected = append(rejected, strconv.FormatFloat(*validation.Min-1, 'f', -1, 64))
+		}
+		if validation.Max != nil {
+			rejected = append(rejected, strconv.FormatFloat(*validation.Max+1, 'f', -1, 64))
+		}
+	}
+	for _, value := range rejected {
+		if validate(value) == nil {
+			t.Errorf("%s: the illegal value `%s` of the %s flag is accepted", command, value, flag.FlagName())
+		}
+	}
+}
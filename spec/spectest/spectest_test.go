@@ -0,0 +1,121 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spectest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// recorder records the failures instead of failing the test
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func newCpuModel(actions ...spec.ActionModel) *spec.ExpCommandModel {
+	return &spec.ExpCommandModel{
+		ExpName:      "cpu",
+		ExpShortDesc: "cpu experiment",
+		ExpActions:   actions,
+	}
+}
+
+func TestCheck(t *testing.T) {
+	min, max := 0.0, 100.0
+	valid := spec.ActionModel{
+		ActionName:      "fullload",
+		ActionShortDesc: "cpu load",
+		ActionFlags: []spec.ExpFlag{
+			{Name: "cpu-percent", Type: spec.FlagTypeInt, Validation: &spec.FlagValidation{Min: &min, Max: &max}},
+			{Name: "mode", Type: spec.FlagTypeEnum, EnumValues: []string{"user", "system"}},
+			{Name: "climb", NoArgs: true, Aliases: []string{"climb-up"}},
+		},
+		ActionExamples: []spec.Example{{Command: "blade create cpu fullload --cpu-percent 60 --mode=user --climb-up --timeout 30"}},
+	}
+	tests := []struct {
+		name    string
+		action  spec.ActionModel
+		wantErr string
+	}{
+		{name: "passed", action: valid},
+		{name: "duplicate flag", action: func() spec.ActionModel {
+			action := valid
+			action.ActionFlags = append(append([]spec.ExpFlag{}, valid.ActionFlags...), spec.ExpFlag{Name: "mode"})
+			return action
+		}(), wantErr: "duplicate flag `mode`"},
+		{name: "duplicate alias", action: func() spec.ActionModel {
+			action := valid
+			action.ActionFlags = append(append([]spec.ExpFlag{}, valid.ActionFlags...), spec.ExpFlag{Name: "climb-up"})
+			return action
+		}(), wantErr: "duplicate flag `climb-up`"},
+		{name: "illegal example", action: func() spec.ActionModel {
+			action := valid
+			action.ActionExamples = []spec.Example{{Command: "blade create cpu fullload --cpu-percent 120"}}
+			return action
+		}(), wantErr: "blade create cpu fullload --cpu-percent 120"},
+		{name: "unknown example flag", action: func() spec.ActionModel {
+			action := valid
+			action.ActionExamples = []spec.Example{{Command: "blade create cpu fullload --cpu-count 1"}}
+			return action
+		}(), wantErr: "unknown flag `cpu-count`"},
+		{name: "enum conflicts with pattern", action: func() spec.ActionModel {
+			action := valid
+			action.ActionFlags = []spec.ExpFlag{{Name: "mode", EnumValues: []string{"user", "sys"},
+				Validation: &spec.FlagValidation{Pattern: "^user$"}}}
+			action.ActionExamples = nil
+			return action
+		}(), wantErr: "the enum value `sys`"},
+		{name: "pattern with min and max", action: func() spec.ActionModel {
+			action := valid
+			action.ActionFlags = []spec.ExpFlag{{Name: "cpu-percent", Type: spec.FlagTypeInt,
+				Validation: &spec.FlagValidation{Min: &min, Max: &max, Pattern: `^-?\d+$`}}}
+			action.ActionExamples = nil
+			return action
+		}()},
+		{name: "unknown flag type", action: func() spec.ActionModel {
+			action := valid
+			action.ActionFlags = []spec.ExpFlag{{Name: "cpu-percent", Type: "integer"}}
+			action.ActionExamples = nil
+			return action
+		}(), wantErr: "unknown type `integer`"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{TB: t}
+			Check(r, newCpuModel(tt.action))
+			if tt.wantErr == "" {
+				if len(r.errors) > 0 {
+					t.Errorf("Check() errors = %v, want none", r.errors)
+				}
+				return
+			}
+			if !strings.Contains(strings.Join(r.errors, "\n"), tt.wantErr) {
+				t.Errorf("Check() errors = %v, want %s", r.errors, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ContentTypeStream is the content type of the streaming response, one json frame per line
+const ContentTypeStream = "application/x-ndjson"
+
+// StreamFrame is one frame of the streaming response. The partial frames carry the results in order,
+// the last frame carries the final response and terminates the stream.
+type StreamFrame struct {
+	Seq    int64       `json:"seq"`
+	Result interface{} `json:"result,omitempty"`
+	Final  *Response   `json:"final,omitempty"`
+}
+
+// ResponseStreamWriter writes the partial results and the final response to the writer
+type ResponseStreamWriter struct {
+	lock    sync.Mutex
+	encoder *json.Encoder
+	seq     int64
+	closed  bool
+}
+
+// NewResponseStreamWriter returns the stream writer which encodes the frames to w
+func NewResponseStreamWriter(w io.Writer) *ResponseStreamWriter {
+	return &ResponseStreamWriter{encoder: json.NewEncoder(w)}
+}
+
+// Write writes the partial result
+func (s *ResponseStreamWriter) Write(result interface{}) error {
+	return s.write(&StreamFrame{Result: result})
+}
+
+// Close writes the final response, the stream can not be written anymore
+func (s *ResponseStreamWriter) Close(final *Response) error {
+	if final == nil {
+		final = ReturnSuccess(nil)
+	}
+	return s.write(&StreamFrame{Final: final})
+}
+
+func (s *ResponseStreamWriter) write(frame *StreamFrame) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed {
+		return fmt.Errorf("the response stream is closed")
+	}
+	s.seq++
+	frame.Seq = s.seq
+	s.closed = frame.Final != nil
+	return s.encoder.Encode(frame)
+}
+
+// ResponseStreamReader reads the frames written by ResponseStreamWriter
+type ResponseStreamReader struct {
+	decoder *json.Decoder
+	seq     int64
+	done    bool
+}
+
+// NewResponseStreamReader returns the stream reader which decodes the frames from r
+func NewResponseStreamReader(r io.Reader) *ResponseStreamReader {
+	return &ResponseStreamReader{decoder: json.NewDecoder(r)}
+}
+
+// Next returns the next frame, io.EOF is returned after the final frame.
+// io.ErrUnexpectedEOF is returned if the stream ends without the final frame.
+func (s *ResponseStreamReader) Next() (*StreamFrame, error) {
+	if s.done {
+		return nil, io.EOF
+	}
+	frame := &StreamFrame{}
+	if err := s.decoder.Decode(frame); err != nil {
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	if frame.Seq != s.seq+1 {
+		return nil, fmt.Errorf("unexpected frame sequence %d, expected %d", frame.Seq, s.seq+1)
+	}
+	s.seq = frame.Seq
+	s.done = frame.Final != nil
+	return frame, nil
+}
+
+// ReadResponseStream invokes the handle with each partial result and returns the final response
+func ReadResponseStream(r io.Reader, handle func(result interface{}) error) (*Response, error) {
+	reader := NewResponseStreamReader(r)
+	for {
+		frame, err := reader.Next()
+		if err != nil {
+			return nil, err
+		}
+		if frame.Final != nil {
+			return frame.Final, nil
+		}
+		if err := handle(frame.Result); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestResponseStream(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer := NewResponseStreamWriter(buf)
+	for _, line := range []string{"line1", "line2"} {
+		if err := writer.Write(line); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := writer.Close(ReturnSuccess("done")); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := writer.Write("line3"); err == nil {
+		t.Errorf("Write() expected error after closed")
+	}
+
+	results := make([]interface{}, 0)
+	final, err := ReadResponseStream(bytes.NewReader(buf.Bytes()), func(result interface{}) error {
+		results = append(results, result)
+		return nil
+	})
+	if err != nil || !final.Success || final.Result != "done" {
+		t.Fatalf("ReadResponseStream() = %v, %v", final, err)
+	}
+	if !reflect.DeepEqual(results, []interface{}{"line1", "line2"}) {
+		t.Errorf("results = %v", results)
+	}
+
+	truncated := buf.Bytes()[:bytes.LastIndexByte(buf.Bytes()[:buf.Len()-1], '\n')+1]
+	_, err = ReadResponseStream(bytes.NewReader(truncated), func(result interface{}) error { return nil })
+	if err != io.ErrUnexpectedEOF {
+		t.Errorf("ReadResponseStream() error = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"strings"
+)
+
+// ModelPath is the path from the root target to the nested sub target, for example k8s -> pod -> network
+type ModelPath []ExpModelCommandSpec
+
+// Names returns the target names of the path
+func (p ModelPath) Names() []string {
+	names := make([]string, 0, len(p))
+	for _, model := range p {
+		names = append(names, model.Name())
+	}
+	return names
+}
+
+func (p ModelPath) String() string {
+	return strings.Join(p.Names(), " ")
+}
+
+// Last returns the sub target of the path
+func (p ModelPath) Last() ExpModelCommandSpec {
+	if len(p) == 0 {
+		return nil
+	}
+	return p[len(p)-1]
+}
+
+// Flags returns the flags inherited down the path, the flag of the nearer target overrides the same name one
+func (p ModelPath) Flags() []ExpFlagSpec {
+	flags := make([]ExpFlagSpec, 0)
+	names := make(map[string]bool)
+	for idx := len(p) - 1; idx >= 0; idx-- {
+		for _, flag := range p[idx].Flags() {
+			if names[flag.FlagName()] {
+				continue
+			}
+			names[flag.FlagName()] = true
+			flags = append(flags, flag)
+		}
+	}
+	return flags
+}
+
+// FindSubModel returns the path of the sub target by the names under the root, the root name is not included
+func FindSubModel(root ExpModelCommandSpec, names ...string) (ModelPath, bool) {
+	path := ModelPath{root}
+	for _, name := range names {
+		var found ExpModelCommandSpec
+		for _, sub := range path.Last().SubModels() {
+			if sub.Name() == name {
+				found = sub
+				break
+			}
+		}
+		if found == nil {
+			return nil, false
+		}
+		path = append(path, found)
+	}
+	return path, true
+}
+
+// FindAction returns the path of the sub target and the action by the command arguments,
+// for example [pod network delay] under the k8s root
+func FindAction(root ExpModelCommandSpec, args ...string) (ModelPath, ExpActionCommandSpec, bool) {
+	if len(args) == 0 {
+		return nil, nil, false
+	}
+	path, ok := FindSubModel(root, args[:len(args)-1]...)
+	if !ok {
+		return nil, nil, false
+	}
+	actionName := args[len(args)-1]
+	for _, action := range path.Last().Actions() {
+		if action.Name() == actionName {
+			return path, action, true
+		}
+		for _, alias := range action.Aliases() {
+			if alias == actionName {
+				return path, action, true
+			}
+		}
+	}
+	return nil, nil, false
+}
+
+// WalkModels invokes the walk func with the path of the root and every nested sub target in depth-first order
+func WalkModels(root ExpModelCommandSpec, walk func(path ModelPath) error) error {
+	return walkModels(ModelPath{root}, walk)
+}
+
+func walkModels(path ModelPath, walk func(path ModelPath) error) error {
+	if err := walk(path); err != nil {
+		return err
+	}
+	for _, sub := range path.Last().SubModels() {
+		subPath := append(append(ModelPath{}, path...), sub)
+		if err := walkModels(subPath, walk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
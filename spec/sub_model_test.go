@@ -0,0 +1,72 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newK8sModel() *ExpCommandModel {
+	return &ExpCommandModel{
+		ExpName:  "k8s",
+		ExpFlags: []ExpFlag{{Name: "kubeconfig"}, {Name: "namespace", Default: "default"}},
+		ExpSubModels: []ExpCommandModel{{
+			ExpName:  "pod",
+			ExpFlags: []ExpFlag{{Name: "names"}, {Name: "namespace", Required: true}},
+			ExpSubModels: []ExpCommandModel{{
+				ExpName:    "network",
+				ExpActions: []ActionModel{{ActionName: "delay", ActionAliases: []string{"latency"}}},
+			}},
+		}},
+	}
+}
+
+func TestFindAction(t *testing.T) {
+	path, action, ok := FindAction(newK8sModel(), "pod", "network", "latency")
+	if !ok || action.Name() != "delay" || path.String() != "k8s pod network" {
+		t.Fatalf("FindAction() = %v, %v, %t", path, action, ok)
+	}
+	names := make([]string, 0)
+	for _, flag := range path.Flags() {
+		names = append(names, flag.FlagName())
+	}
+	if !reflect.DeepEqual(names, []string{"names", "namespace", "kubeconfig"}) {
+		t.Errorf("Flags() = %v, want the inherited flags", names)
+	}
+	if flags := path.Flags(); !flags[1].FlagRequired() {
+		t.Errorf("Flags() namespace is not overridden by the pod flag")
+	}
+	if _, _, ok := FindAction(newK8sModel(), "pod", "delay"); ok {
+		t.Errorf("FindAction() found the action in the wrong target")
+	}
+}
+
+func TestWalkModels(t *testing.T) {
+	paths := make([]string, 0)
+	WalkModels(newK8sModel(), func(path ModelPath) error {
+		paths = append(paths, path.String())
+		return nil
+	})
+	if !reflect.DeepEqual(paths, []string{"k8s", "k8s pod", "k8s pod network"}) {
+		t.Errorf("WalkModels() paths = %v", paths)
+	}
+	document := GenerateOpenAPI("chaosblade", "1.0.0", newK8sModel())
+	if _, ok := document.Paths["/k8s/pod/network/delay"]; !ok {
+		t.Errorf("GenerateOpenAPI() paths = %v, want the nested action", document.Paths)
+	}
+}
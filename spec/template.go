@@ -0,0 +1,136 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TemplateVariable returns the value of the template variable for the experiment
+type TemplateVariable func(uid string) (string, error)
+
+var templatePattern = regexp.MustCompile(`\$\$|\$\{([^}]*)\}`)
+
+var (
+	templateVariables = map[string]TemplateVariable{
+		"hostname": func(uid string) (string, error) {
+			return os.Hostname()
+		},
+		"uid": func(uid string) (string, error) {
+			return uid, nil
+		},
+		"timestamp": func(uid string) (string, error) {
+			return strconv.FormatInt(time.Now().Unix(), 10), nil
+		},
+	}
+	templateVariablesLock sync.RWMutex
+)
+
+// RegisterTemplateVariable registers the variable used as ${name} in the flag values
+func RegisterTemplateVariable(name string, variable TemplateVariable) {
+	templateVariablesLock.Lock()
+	defer templateVariablesLock.Unlock()
+	templateVariables[name] = variable
+}
+
+// ExpandTemplate expands the templates in the value, the supported templates are ${hostname}, ${uid},
+// ${timestamp}, ${env:NAME} and the registered variables. $$ is the escaped $.
+func ExpandTemplate(uid, value string) (string, error) {
+	if !strings.Contains(value, "$") {
+		return value, nil
+	}
+	var expandErr error
+	result := templatePattern.ReplaceAllStringFunc(value, func(match string) string {
+		if match == "$$" || expandErr != nil {
+			return "$"
+		}
+		name := match[2 : len(match)-1]
+		if strings.HasPrefix(name, "env:") {
+			env, ok := os.LookupEnv(strings.TrimPrefix(name, "env:"))
+			if !ok {
+				expandErr = fmt.Errorf("environment variable %s not found", strings.TrimPrefix(name, "env:"))
+			}
+			return env
+		}
+		templateVariablesLock.RLock()
+		variable, ok := templateVariables[name]
+		templateVariablesLock.RUnlock()
+		if !ok {
+			expandErr = fmt.Errorf("unknown template variable %s", name)
+			return ""
+		}
+		expanded, err := variable(uid)
+		if err != nil {
+			expandErr = err
+		}
+		return expanded
+	})
+	return result, expandErr
+}
+
+// ExpandFlagTemplates returns the copy of the model whose flag values, including the repeated values, are expanded
+// by ExpandTemplate, the ParameterIllegal response is returned if failed
+func ExpandFlagTemplates(uid string, model *ExpModel) (*ExpModel, *Response) {
+	expanded := *model
+	expanded.ActionFlags = make(map[string]string, len(model.ActionFlags))
+	for name, value := range model.ActionFlags {
+		result, err := ExpandTemplate(uid, value)
+		if err != nil {
+			return nil, ResponseFailWithFlags(ParameterIllegal, name, value, err)
+		}
+		expanded.ActionFlags[name] = result
+	}
+	if model.ActionFlagValues != nil {
+		expanded.ActionFlagValues = make(map[string][]string, len(model.ActionFlagValues))
+	}
+	for name, values := range model.ActionFlagValues {
+		results := make([]string, len(values))
+		for i, value := range values {
+			result, err := ExpandTemplate(uid, value)
+			if err != nil {
+				return nil, ResponseFailWithFlags(ParameterIllegal, name, value, err)
+			}
+			results[i] = result
+		}
+		expanded.ActionFlagValues[name] = results
+	}
+	return &expanded, nil
+}
+
+// TemplateMiddleware returns the middleware which expands the flag templates before invoking the executor.
+//
+// Deprecated: ExecExperiment expands the flag templates before validating the model, so the typed flags are
+// validated with the expanded values. The middleware is only for the executors invoked without ExecExperiment,
+// using both expands the escaped $$ twice.
+func TemplateMiddleware() ExecutorMiddleware {
+	return ExecutorMiddlewareFunc(func(next ExecFunc) ExecFunc {
+		return func(uid string, ctx context.Context, model *ExpModel) *Response {
+			expanded, response := ExpandFlagTemplates(uid, model)
+			if response != nil {
+				return response
+			}
+			return next(uid, ctx, expanded)
+		}
+	})
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestExpandTemplate(t *testing.T) {
+	os.Setenv("CHAOSBLADE_TEST_DIR", "/tmp/chaos")
+	defer os.Unsetenv("CHAOSBLADE_TEST_DIR")
+	hostname, _ := os.Hostname()
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "plain", want: "plain"},
+		{value: "${env:CHAOSBLADE_TEST_DIR}/${uid}.log", want: "/tmp/chaos/abc.log"},
+		{value: "host-${hostname}", want: "host-" + hostname},
+		{value: "$${uid}", want: "${uid}"},
+		{value: "${unknown}", wantErr: true},
+		{value: "${env:CHAOSBLADE_TEST_NOT_EXIST}", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ExpandTemplate("abc", tt.value)
+			if (err != nil) != tt.wantErr || (!tt.wantErr && got != tt.want) {
+				t.Errorf("ExpandTemplate() = %s, %v, want %s", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateMiddleware(t *testing.T) {
+	executor := &testExecutor{exec: func(uid string, ctx context.Context, model *ExpModel) *Response {
+		return ReturnSuccess(model.ActionFlags["file"])
+	}}
+	model := &ExpModel{ActionFlags: map[string]string{"file": "/tmp/${uid}"}}
+	response := WithMiddlewares(executor, TemplateMiddleware()).Exec("abc", context.Background(), model)
+	if response.Result != "/tmp/abc" || model.ActionFlags["file"] != "/tmp/${uid}" {
+		t.Errorf("Exec() = %v, model flags = %v", response, model.ActionFlags)
+	}
+	model.ActionFlags["file"] = "${unknown}"
+	response = WithMiddlewares(executor, TemplateMiddleware()).Exec("abc", context.Background(), model)
+	if response.Code != ParameterIllegal.Code {
+		t.Errorf("Exec() = %v, want ParameterIllegal", response)
+	}
+}
+
+func TestExecExperimentExpandTemplates(t *testing.T) {
+	defer ResetModelSpecs()
+	disk := &ExpCommandModel{ExpName: "disk", ExpActions: []ActionModel{{ActionName: "fill",
+		ActionFlags: []ExpFlag{{Name: "size", Type: FlagTypeInt}, {Name: "path", Repeated: true}}}}}
+	if err := RegisterModelSpec(disk); err != nil {
+		t.Fatalf("RegisterModelSpec() error = %v", err)
+	}
+	RegisterTemplateVariable("size", func(uid string) (string, error) {
+		return "1024", nil
+	})
+	defer func() {
+		templateVariablesLock.Lock()
+		delete(templateVariables, "size")
+		templateVariablesLock.Unlock()
+	}()
+	executor := &testExecutor{exec: func(uid string, ctx context.Context, model *ExpModel) *Response {
+		return ReturnSuccess(model.ActionFlags["size"] + " " + strings.Join(model.ActionFlagValues["path"], ","))
+	}}
+	model := &ExpModel{Target: "disk", ActionName: "fill",
+		ActionFlags:      map[string]string{"size": "${size}", "path": "/tmp/${uid}"},
+		ActionFlagValues: map[string][]string{"path": {"/tmp/${uid}", "/data/${uid}"}}}
+	response := ExecExperiment(executor, "abc", context.Background(), model)
+	if !response.Success || response.Result != "1024 /tmp/abc,/data/abc" {
+		t.Errorf("ExecExperiment() = %v, want the templates expanded before the validation", response)
+	}
+}
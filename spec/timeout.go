@@ -0,0 +1,92 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ExecTimeoutKey is the context key of the execution timeout requested by the user
+const ExecTimeoutKey ContextKey = "execTimeout"
+
+// WithExecTimeout returns a copy of ctx that requests the execution timeout instead of the action default timeout,
+// the timeout is limited by the action max timeout
+func WithExecTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, ExecTimeoutKey, timeout)
+}
+
+// GetExecTimeout returns the execution timeout of the action, 0 means no timeout. The requested timeout in ctx
+// is used first, then the default timeout of the action, both of them are limited by the max timeout.
+func GetExecTimeout(ctx context.Context, action ExpActionCommandSpec) time.Duration {
+	timeout, _ := ctx.Value(ExecTimeoutKey).(time.Duration)
+	if timeout <= 0 {
+		timeout = parseTimeout(action.Name(), ActionDefaultTimeoutOf(action))
+	}
+	maxTimeout := parseTimeout(action.Name(), ActionMaxTimeoutOf(action))
+	if maxTimeout > 0 && (timeout <= 0 || timeout > maxTimeout) {
+		timeout = maxTimeout
+	}
+	return timeout
+}
+
+func parseTimeout(action, value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	timeout, err := ParseDuration(value)
+	if err != nil {
+		logrus.Warnf("illegal timeout `%s` of the %s action, ignore it, %v", value, action, err)
+		return 0
+	}
+	return timeout
+}
+
+// TimeoutMiddleware returns the middleware which executes the action with the context deadline of GetExecTimeout.
+// The ExecTimeout response is returned when the deadline exceeded, even if the executor ignores the ctx,
+// in which case the executor goroutine keeps running in the background until it returns.
+func TimeoutMiddleware(action ExpActionCommandSpec) ExecutorMiddleware {
+	return ExecutorMiddlewareFunc(func(next ExecFunc) ExecFunc {
+		return func(uid string, ctx context.Context, model *ExpModel) *Response {
+			timeout := GetExecTimeout(ctx, action)
+			if timeout <= 0 {
+				return next(uid, ctx, model)
+			}
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			result := make(chan *Response, 1)
+			go func() {
+				result <- next(uid, ctx, model)
+			}()
+			select {
+			case response := <-result:
+				if !response.Success && ctx.Err() == context.DeadlineExceeded {
+					return ResponseFailWithFlags(ExecTimeout, action.Name(), timeout)
+				}
+				return response
+			case <-ctx.Done():
+				if ctx.Err() == context.DeadlineExceeded {
+					return ResponseFailWithFlags(ExecTimeout, action.Name(), timeout)
+				}
+				// canceled by the caller, the executor handles the cancellation itself
+				return <-result
+			}
+		}
+	})
+}
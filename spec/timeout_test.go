@@ -0,0 +1,71 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGetExecTimeout(t *testing.T) {
+	action := &ActionModel{ActionName: "delay", ActionDefaultTimeout: "30", ActionMaxTimeout: "1m"}
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want time.Duration
+	}{
+		{"default", context.Background(), 30 * time.Second},
+		{"requested", WithExecTimeout(context.Background(), 10*time.Second), 10 * time.Second},
+		{"limited", WithExecTimeout(context.Background(), time.Hour), time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetExecTimeout(tt.ctx, action); got != tt.want {
+				t.Errorf("GetExecTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := GetExecTimeout(context.Background(), &ActionModel{}); got != 0 {
+		t.Errorf("GetExecTimeout() = %v, want 0", got)
+	}
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	action := &ActionModel{ActionName: "delay", ActionDefaultTimeout: "10ms"}
+	block := make(chan struct{})
+	defer close(block)
+	hang := &testExecutor{exec: func(uid string, ctx context.Context, model *ExpModel) *Response {
+		<-block
+		return ReturnSuccess(uid)
+	}}
+	response := WithMiddlewares(hang, TimeoutMiddleware(action)).Exec("uid", context.Background(), &ExpModel{})
+	if response.Code != ExecTimeout.Code {
+		t.Errorf("Exec() = %v, want ExecTimeout", response)
+	}
+
+	quick := &testExecutor{exec: func(uid string, ctx context.Context, model *ExpModel) *Response {
+		if _, ok := ctx.Deadline(); !ok {
+			return ResponseFailWithFlags(ParameterLess, "deadline")
+		}
+		return ReturnSuccess(uid)
+	}}
+	response = WithMiddlewares(quick, TimeoutMiddleware(action)).Exec("uid", context.Background(), &ExpModel{})
+	if !response.Success {
+		t.Errorf("Exec() = %v, want success", response)
+	}
+}
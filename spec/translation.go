@@ -0,0 +1,110 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import "strings"
+
+// Translation is the label and the descriptions in one locale. Desc is the flag description or
+// the short description of the command, LongDesc is only used by the commands.
+type Translation struct {
+	Label    string `yaml:"label,omitempty" json:"label,omitempty"`
+	Desc     string `yaml:"desc,omitempty" json:"desc,omitempty"`
+	LongDesc string `yaml:"longDesc,omitempty" json:"longDesc,omitempty"`
+}
+
+// LocalizedCommandSpec is implemented by the model specs and the action specs of this package, the action spec
+// implemented outside this package must implement ActionTranslationSpec to be localized
+type LocalizedCommandSpec interface {
+	Name() string
+	ShortDesc() string
+	LongDesc() string
+	Translations() map[string]Translation
+}
+
+// LookupTranslation returns the translation of the locale, such as zh-CN or zh_CN.UTF-8. The translation
+// keyed by the language only, such as zh, is used if the locale is not found.
+func LookupTranslation(translations map[string]Translation, l string) (Translation, bool) {
+	if len(translations) == 0 {
+		return Translation{}, false
+	}
+	l = normalizeLocale(l)
+	if translation, ok := translations[l]; ok {
+		return translation, true
+	}
+	language := strings.SplitN(l, "-", 2)[0]
+	var matched *Translation
+	for key, translation := range translations {
+		key = strings.TrimSpace(key)
+		if strings.Contains(key, "-") || strings.Contains(key, "_") {
+			if normalizeLocale(key) == l {
+				return translation, true
+			}
+			continue
+		}
+		if strings.EqualFold(key, language) {
+			translation := translation
+			matched = &translation
+		}
+	}
+	if matched != nil {
+		return *matched, true
+	}
+	return Translation{}, false
+}
+
+// LocalizedFlagDesc returns the flag description in the locale, or the default description if not translated
+func LocalizedFlagDesc(flag ExpFlagSpec, l string) string {
+	if translation, ok := LookupTranslation(FlagTranslationsOf(flag), l); ok && translation.Desc != "" {
+		return translation.Desc
+	}
+	return flag.FlagDesc()
+}
+
+// LocalizedFlagLabel returns the flag label in the locale, falling back to the default label and the flag name
+func LocalizedFlagLabel(flag ExpFlagSpec, l string) string {
+	if translation, ok := LookupTranslation(FlagTranslationsOf(flag), l); ok && translation.Label != "" {
+		return translation.Label
+	}
+	if FlagLabelOf(flag) != "" {
+		return FlagLabelOf(flag)
+	}
+	return flag.FlagName()
+}
+
+// LocalizedShortDesc returns the short description of the command in the locale
+func LocalizedShortDesc(command LocalizedCommandSpec, l string) string {
+	if translation, ok := LookupTranslation(command.Translations(), l); ok && translation.Desc != "" {
+		return translation.Desc
+	}
+	return command.ShortDesc()
+}
+
+// LocalizedLongDesc returns the long description of the command in the locale
+func LocalizedLongDesc(command LocalizedCommandSpec, l string) string {
+	if translation, ok := LookupTranslation(command.Translations(), l); ok && translation.LongDesc != "" {
+		return translation.LongDesc
+	}
+	return command.LongDesc()
+}
+
+// LocalizedLabel returns the label of the command in the locale, or the command name if not translated
+func LocalizedLabel(command LocalizedCommandSpec, l string) string {
+	if translation, ok := LookupTranslation(command.Translations(), l); ok && translation.Label != "" {
+		return translation.Label
+	}
+	return command.Name()
+}
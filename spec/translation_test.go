@@ -0,0 +1,82 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import "testing"
+
+func TestLocalizedFlag(t *testing.T) {
+	flag := &ExpFlag{
+		Name:  "time",
+		Desc:  "delay time, ms",
+		Label: "Delay",
+		Translations: map[string]Translation{
+			"zh_CN": {Label: "延迟", Desc: "延迟时间，单位毫秒"},
+			"ja":    {Desc: "遅延時間"},
+		},
+	}
+	tests := []struct {
+		locale string
+		label  string
+		desc   string
+	}{
+		{LocaleEnUS, "Delay", "delay time, ms"},
+		{"zh-CN", "延迟", "延迟时间，单位毫秒"},
+		{"zh_CN.UTF-8", "延迟", "延迟时间，单位毫秒"},
+		{"ja-JP", "Delay", "遅延時間"},
+		{"fr-FR", "Delay", "delay time, ms"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			if got := LocalizedFlagLabel(flag, tt.locale); got != tt.label {
+				t.Errorf("LocalizedFlagLabel() = %s, want %s", got, tt.label)
+			}
+			if got := LocalizedFlagDesc(flag, tt.locale); got != tt.desc {
+				t.Errorf("LocalizedFlagDesc() = %s, want %s", got, tt.desc)
+			}
+		})
+	}
+	if got := LocalizedFlagLabel(&ExpFlag{Name: "offset"}, "zh-CN"); got != "offset" {
+		t.Errorf("LocalizedFlagLabel() = %s, want offset", got)
+	}
+}
+
+func TestLocalizedCommand(t *testing.T) {
+	action := &ActionModel{
+		ActionName:      "delay",
+		ActionShortDesc: "Delay experiment",
+		ActionLongDesc:  "Delay the network packets",
+		ActionTranslations: map[string]Translation{
+			"zh-CN": {Label: "网络延迟", Desc: "延迟实验"},
+		},
+	}
+	if got := LocalizedShortDesc(action, "zh-CN"); got != "延迟实验" {
+		t.Errorf("LocalizedShortDesc() = %s", got)
+	}
+	if got := LocalizedLongDesc(action, "zh-CN"); got != "Delay the network packets" {
+		t.Errorf("LocalizedLongDesc() = %s", got)
+	}
+	if got := LocalizedLabel(action, "zh-CN"); got != "网络延迟" {
+		t.Errorf("LocalizedLabel() = %s", got)
+	}
+	if got := LocalizedLabel(action, LocaleEnUS); got != "delay" {
+		t.Errorf("LocalizedLabel() = %s", got)
+	}
+	target := &ExpCommandModel{ExpName: "network", ExpShortDesc: "Network experiment"}
+	if got := LocalizedShortDesc(target, "zh-CN"); got != "Network experiment" {
+		t.Errorf("LocalizedShortDesc() = %s", got)
+	}
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+)
+
+// UIDMaxLength is the max length of the experiment uid
+const UIDMaxLength = 64
+
+// UIDProvider generates the experiment uid, such as a ULID or a uid prefixed with the cluster name
+type UIDProvider func() string
+
+var (
+	uidProvider     UIDProvider = randomUID
+	uidProviderLock sync.RWMutex
+)
+
+// SetUIDProvider sets the provider used by GenerateUID, nil restores the default random hex provider
+func SetUIDProvider(provider UIDProvider) {
+	uidProviderLock.Lock()
+	defer uidProviderLock.Unlock()
+	if provider == nil {
+		provider = randomUID
+	}
+	uidProvider = provider
+}
+
+// GenerateUID returns the uid generated by the provider, the uid is checked by ValidateUID
+func GenerateUID() (string, error) {
+	uidProviderLock.RLock()
+	provider := uidProvider
+	uidProviderLock.RUnlock()
+	uid := provider()
+	if err := ValidateUID(uid); err != nil {
+		return "", err
+	}
+	return uid, nil
+}
+
+// randomUID returns 16 hex characters, it returns empty if the random source fails
+func randomUID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// ValidateUID checks the uid only contains letters, digits, '-', '_' and '.', and is not longer than UIDMaxLength
+func ValidateUID(uid string) error {
+	if uid == "" {
+		return fmt.Errorf("uid is empty")
+	}
+	if len(uid) > UIDMaxLength {
+		return fmt.Errorf("uid %s is longer than %d", uid, UIDMaxLength)
+	}
+	for _, c := range uid {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return fmt.Errorf("uid %s contains illegal character %q", uid, c)
+		}
+	}
+	return nil
+}
+
+// ValidateUIDHook is the PreExecHook which rejects the experiment if the uid is illegal,
+// it is registered by RegisterPreExecHook to check the incoming uids
+func ValidateUIDHook(uid string, ctx context.Context, model *ExpModel) *Response {
+	if err := ValidateUID(uid); err != nil {
+		return ResponseFailWithFlags(ParameterIllegal, "uid", uid, err)
+	}
+	return nil
+}
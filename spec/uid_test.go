@@ -0,0 +1,67 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestValidateUID(t *testing.T) {
+	tests := []struct {
+		uid     string
+		wantErr bool
+	}{
+		{"8c3a6e1f0b2d4e5a", false},
+		{"01ARZ3NDEKTSV4RRFFQ69G5FAV", false},
+		{"cluster-a.host_1-8c3a6e1f", false},
+		{"", true},
+		{strings.Repeat("a", UIDMaxLength+1), true},
+		{"abc;rm -rf", true},
+		{"uid/../x", true},
+	}
+	for _, tt := range tests {
+		if err := ValidateUID(tt.uid); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateUID(%q) error = %v, wantErr %v", tt.uid, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSetUIDProvider(t *testing.T) {
+	defer SetUIDProvider(nil)
+	uid, err := GenerateUID()
+	if err != nil || len(uid) != 16 {
+		t.Fatalf("GenerateUID() = %s, %v", uid, err)
+	}
+
+	SetUIDProvider(func() string { return "cluster-a.8c3a6e1f" })
+	if uid, err := GenerateUID(); err != nil || uid != "cluster-a.8c3a6e1f" {
+		t.Errorf("GenerateUID() = %s, %v", uid, err)
+	}
+	SetUIDProvider(func() string { return "cluster a" })
+	if _, err := GenerateUID(); err == nil {
+		t.Errorf("GenerateUID() expected illegal uid error")
+	}
+
+	if response := ValidateUIDHook("a b", context.Background(), &ExpModel{}); response == nil || response.Code != ParameterIllegal.Code {
+		t.Errorf("ValidateUIDHook() = %v, want ParameterIllegal", response)
+	}
+	if response := ValidateUIDHook("abc", context.Background(), &ExpModel{}); response != nil {
+		t.Errorf("ValidateUIDHook() = %v, want nil", response)
+	}
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"context"
+	"strings"
+)
+
+// ExecutorValidator is the optional interface of the executor to check the preconditions before Exec,
+// for example the commands are present and the target exists. It must not perform the injection.
+type ExecutorValidator interface {
+	// Validate returns nil if the experiment can be executed, otherwise returns the failed response
+	Validate(ctx context.Context, model *ExpModel) *Response
+}
+
+// ValidateExecutor invokes the Validate of the executor if implemented, it returns nil if passed.
+// It can be used alone for the check command which does not inject anything.
+func ValidateExecutor(ctx context.Context, executor Executor, model *ExpModel) *Response {
+	validator, ok := executor.(ExecutorValidator)
+	if !ok {
+		return nil
+	}
+	return validator.Validate(ctx, model)
+}
+
+// ValidateRegisteredAction validates the model by the action spec registered by RegisterModelSpec, which is
+// found by the model target and action name, such as the required flags and the flag rules. The target may be
+// the path of the sub target, such as "k8s pod network", and the flags inherited from the targets of the path
+// are validated too, see ValidateModelPath. The flag aliases are resolved to the flag names before the defaults
+// are applied. It returns nil if passed or the action is not registered.
+func ValidateRegisteredAction(ctx context.Context, model *ExpModel) *Response {
+	path, action, ok := findRegisteredAction(model)
+	if !ok {
+		return nil
+	}
+	return ValidateModelPath(ctx, path, action, model)
+}
+
+// findRegisteredAction returns the path and the action spec registered by RegisterModelSpec for the model,
+// the names of the sub target path in the model target are separated by spaces or ActionPathSeparator
+func findRegisteredAction(model *ExpModel) (ModelPath, ExpActionCommandSpec, bool) {
+	names := strings.Fields(strings.ReplaceAll(model.Target, ActionPathSeparator, " "))
+	if len(names) == 0 || model.ActionName == "" {
+		return nil, nil, false
+	}
+	root, ok := GetModelSpec(names[0])
+	if !ok {
+		return nil, nil, false
+	}
+	return FindAction(root, append(names[1:], model.ActionName)...)
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SpecVersion is the version of chaosblade-spec-go, it is written to the models created by the plugin,
+// so the framework knows which version the plugin is compiled against.
+const SpecVersion = "1.8.0"
+
+// Compatibility defines the requirements of the framework to the plugin specs
+type Compatibility struct {
+	// MinSpecVersion is the min spec version the framework supports, empty means no limit
+	MinSpecVersion string
+
+	// DeprecatedActions are the deprecated actions, the key is target.action and the value is the message
+	DeprecatedActions map[string]string
+}
+
+// Check returns error if the spec version of the models is older than the min version,
+// and returns the warnings of the deprecated actions used by the models.
+// It should be evaluated when the framework registers the models.
+func (c *Compatibility) Check(models *Models) ([]string, error) {
+	if c.MinSpecVersion != "" {
+		if models.SpecVersion == "" {
+			return nil, fmt.Errorf("the spec version of %s models is unknown, the plugin is compiled against "+
+				"chaosblade-spec-go older than %s, please upgrade it", models.Kind, c.MinSpecVersion)
+		}
+		result, err := CompareVersion(models.SpecVersion, c.MinSpecVersion)
+		if err != nil {
+			return nil, err
+		}
+		if result < 0 {
+			return nil, fmt.Errorf("the spec version %s of %s models is older than the min version %s, "+
+				"please upgrade chaosblade-spec-go of the plugin", models.SpecVersion, models.Kind, c.MinSpecVersion)
+		}
+	}
+	warnings := make([]string, 0)
+	for _, model := range models.Models {
+		for _, action := range model.ExpActions {
+			key := fmt.Sprintf("%s.%s", model.ExpName, action.ActionName)
+			if msg, ok := c.DeprecatedActions[key]; ok {
+				warnings = append(warnings, fmt.Sprintf("`%s` action is deprecated, %s", key, msg))
+			}
+		}
+	}
+	return warnings, nil
+}
+
+// CompareVersion compares the dotted numeric versions, such as 1.7.0 and v1.8, it returns -1 if v1 < v2,
+// 0 if v1 == v2, otherwise 1. The pre-release and build suffixes are ignored.
+func CompareVersion(v1, v2 string) (int, error) {
+	parts1, err := parseVersion(v1)
+	if err != nil {
+		return 0, err
+	}
+	parts2, err := parseVersion(v2)
+	if err != nil {
+		return 0, err
+	}
+	return compareVersionParts(parts1, parts2), nil
+}
+
+func compareVersionParts(parts1, parts2 []int) int {
+	for idx := 0; idx < len(parts1) || idx < len(parts2); idx++ {
+		var p1, p2 int
+		if idx < len(parts1) {
+			p1 = parts1[idx]
+		}
+		if idx < len(parts2) {
+			p2 = parts2[idx]
+		}
+		if p1 < p2 {
+			return -1
+		}
+		if p1 > p2 {
+			return 1
+		}
+	}
+	return 0
+}
+
+func parseVersion(version string) ([]int, error) {
+	v := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if idx := strings.IndexAny(v, "-+"); idx >= 0 {
+		v = v[:idx]
+	}
+	parts := make([]int, 0)
+	for _, part := range strings.Split(v, ".") {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("illegal version `%s`", version)
+		}
+		parts = append(parts, number)
+	}
+	return parts, nil
+}
@@ -0,0 +1,154 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// versionOperators are the operators of the constraint terms, the longer ones come first
+var versionOperators = []string{">=", "<=", "!=", "==", ">", "<", "=", "~", "^"}
+
+// VersionConstraint is the version range, such as ">=1.2.0, <2.0" or "~4.19 || >=5.4". The terms separated by
+// commas must all match, and the groups separated by || match if any of them matches.
+//   - ~1.2.3 means >=1.2.3, <1.3.0, and ~1 means >=1, <2
+//   - ^1.2.3 means >=1.2.3, <2.0.0, and ^0.2.3 means >=0.2.3, <0.3.0
+//   - the version without operator means =
+type VersionConstraint struct {
+	text   string
+	groups [][]versionTerm
+}
+
+type versionTerm struct {
+	operator string
+	version  []int
+}
+
+// ParseVersionConstraint parses the constraint, the versions are dotted numbers compared by CompareVersion
+func ParseVersionConstraint(constraint string) (*VersionConstraint, error) {
+	result := &VersionConstraint{text: strings.TrimSpace(constraint)}
+	for _, group := range strings.Split(constraint, "||") {
+		terms := make([]versionTerm, 0)
+		for _, item := range strings.Split(group, ",") {
+			term, err := parseVersionTerm(item)
+			if err != nil {
+				return nil, fmt.Errorf("illegal version constraint `%s`, %v", constraint, err)
+			}
+			terms = append(terms, term...)
+		}
+		result.groups = append(result.groups, terms)
+	}
+	return result, nil
+}
+
+// parseVersionTerm returns the terms of the item, ~ and ^ are expanded to the lower and the upper bounds
+func parseVersionTerm(item string) ([]versionTerm, error) {
+	item = strings.TrimSpace(item)
+	if item == "" {
+		return nil, fmt.Errorf("empty term")
+	}
+	operator := "="
+	for _, op := range versionOperators {
+		if strings.HasPrefix(item, op) {
+			operator, item = op, strings.TrimSpace(strings.TrimPrefix(item, op))
+			break
+		}
+	}
+	version, err := parseVersion(item)
+	if err != nil {
+		return nil, err
+	}
+	switch operator {
+	case "==":
+		operator = "="
+	case "~":
+		upper := nextVersion(version, 1)
+		if len(version) == 1 {
+			upper = nextVersion(version, 0)
+		}
+		return []versionTerm{{">=", version}, {"<", upper}}, nil
+	case "^":
+		position := 0
+		for position < len(version)-1 && version[position] == 0 {
+			position++
+		}
+		return []versionTerm{{">=", version}, {"<", nextVersion(version, position)}}, nil
+	}
+	return []versionTerm{{operator, version}}, nil
+}
+
+// nextVersion increases the part at the position and drops the following parts, 1.2.3 at 1 is 1.3
+func nextVersion(version []int, position int) []int {
+	next := make([]int, position+1)
+	copy(next, version)
+	next[position]++
+	return next
+}
+
+// Check returns true if the version satisfies the constraint
+func (c *VersionConstraint) Check(version string) (bool, error) {
+	parts, err := parseVersion(version)
+	if err != nil {
+		return false, err
+	}
+	for _, group := range c.groups {
+		matched := true
+		for _, term := range group {
+			if !term.match(parts) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (t versionTerm) match(version []int) bool {
+	result := compareVersionParts(version, t.version)
+	switch t.operator {
+	case ">=":
+		return result >= 0
+	case "<=":
+		return result <= 0
+	case ">":
+		return result > 0
+	case "<":
+		return result < 0
+	case "!=":
+		return result != 0
+	default:
+		return result == 0
+	}
+}
+
+// String returns the constraint text
+func (c *VersionConstraint) String() string {
+	return c.text
+}
+
+// MatchVersion returns true if the version satisfies the constraint, see VersionConstraint
+func MatchVersion(version, constraint string) (bool, error) {
+	c, err := ParseVersionConstraint(constraint)
+	if err != nil {
+		return false, err
+	}
+	return c.Check(version)
+}
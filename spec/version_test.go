@@ -0,0 +1,116 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spec
+
+import "testing"
+
+func TestCompareVersion(t *testing.T) {
+	tests := []struct {
+		v1, v2 string
+		want   int
+	}{
+		{v1: "1.7.0", v2: "1.7.0", want: 0},
+		{v1: "v1.7", v2: "1.7.0", want: 0},
+		{v1: "1.7.4", v2: "1.8.0", want: -1},
+		{v1: "1.10.0", v2: "1.9.9", want: 1},
+		{v1: "1.8.0-rc1", v2: "1.8.0", want: 0},
+	}
+	for _, tt := range tests {
+		if got, err := CompareVersion(tt.v1, tt.v2); err != nil || got != tt.want {
+			t.Errorf("CompareVersion(%s, %s) = %d, %v, want %d", tt.v1, tt.v2, got, err, tt.want)
+		}
+	}
+}
+
+func TestCompatibility_Check(t *testing.T) {
+	compatibility := &Compatibility{
+		MinSpecVersion:    "1.7.0",
+		DeprecatedActions: map[string]string{"network.dns": "use network dns-spoof instead"},
+	}
+	models := &Models{Kind: "plugin", SpecVersion: "1.6.0"}
+	if _, err := compatibility.Check(models); err == nil {
+		t.Errorf("Check() expected error for older spec version")
+	}
+	models = &Models{Kind: "plugin", SpecVersion: SpecVersion, Models: []ExpCommandModel{
+		{ExpName: "network", ExpActions: []ActionModel{{ActionName: "dns"}, {ActionName: "delay"}}},
+	}}
+	warnings, err := compatibility.Check(models)
+	if err != nil || len(warnings) != 1 {
+		t.Errorf("Check() = %v, %v", warnings, err)
+	}
+}
+
+func TestMatchVersion(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		want       bool
+	}{
+		{"1.5.0", ">=1.2.0, <2.0", true},
+		{"2.0.0", ">=1.2.0, <2.0", false},
+		{"1.1.9", ">= 1.2.0 , < 2.0", false},
+		{"1.2.0", "1.2", true},
+		{"1.2.1", "==1.2", false},
+		{"1.2.1", "!=1.2", true},
+		{"1.2.9", "~1.2.3", true},
+		{"1.3.0", "~1.2.3", false},
+		{"1.9", "~1", true},
+		{"1.9.0", "^1.2.3", true},
+		{"2.0.0", "^1.2.3", false},
+		{"0.2.9", "^0.2.3", true},
+		{"0.3.0", "^0.2.3", false},
+		{"4.19.91-24.1.al8.x86_64", "~4.19 || >=5.4", true},
+		{"5.10.0-60.el8.x86_64", "~4.19 || >=5.4", true},
+		{"5.0.1", "~4.19 || >=5.4", false},
+	}
+	for _, tt := range tests {
+		if got, err := MatchVersion(tt.version, tt.constraint); err != nil || got != tt.want {
+			t.Errorf("MatchVersion(%s, %s) = %t, %v, want %t", tt.version, tt.constraint, got, err, tt.want)
+		}
+	}
+	for _, constraint := range []string{"", ">=", ">=1.2,", "1.x", ">=1.2 || "} {
+		if _, err := ParseVersionConstraint(constraint); err == nil {
+			t.Errorf("ParseVersionConstraint(%q) succeeded, want error", constraint)
+		}
+	}
+}
+
+func TestKernelVersionConstraint(t *testing.T) {
+	tests := []struct {
+		kernelVersion string
+		current       string
+		want          bool
+	}{
+		{"4.9", "4.19.91", true},
+		{"4.9", "3.10.0-1160.el7.x86_64", false},
+		{">=4.9, <5.0", "5.4.0", false},
+		{"<5", "4.19.91", true},
+	}
+	for _, tt := range tests {
+		requirements := &ActionRequirements{KernelVersion: tt.kernelVersion}
+		constraint, err := requirements.KernelVersionConstraint()
+		if err != nil {
+			t.Fatalf("KernelVersionConstraint(%s) failed, %v", tt.kernelVersion, err)
+		}
+		if got, err := constraint.Check(tt.current); err != nil || got != tt.want {
+			t.Errorf("%s.Check(%s) = %t, %v, want %t", constraint, tt.current, got, err, tt.want)
+		}
+	}
+	if got := (&ActionRequirements{KernelVersion: "4.9"}).String(); got != "kernel version: >=4.9" {
+		t.Errorf("String() = %s, want kernel version: >=4.9", got)
+	}
+}
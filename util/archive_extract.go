@@ -0,0 +1,334 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// DefaultExtractMaxSize is the default limit of the total size of the extracted files
+	DefaultExtractMaxSize int64 = 1 << 30
+	// DefaultExtractMaxFiles is the default limit of the count of the extracted entries
+	DefaultExtractMaxFiles = 10000
+)
+
+// SymlinkPolicy decides how the symlink entries in the archive are extracted
+type SymlinkPolicy int
+
+const (
+	// SymlinkReject fails the extraction if the archive contains any symlink, it's the default policy
+	SymlinkReject SymlinkPolicy = iota
+	// SymlinkSkip ignores the symlink entries
+	SymlinkSkip
+	// SymlinkWithinRoot extracts the relative symlinks whose targets are in the extraction root
+	SymlinkWithinRoot
+)
+
+// ExtractOption customizes the limits of UnZip and UnTarGz
+type ExtractOption func(options *extractOptions)
+
+type extractOptions struct {
+	maxSize  int64
+	maxFiles int
+	symlinks SymlinkPolicy
+}
+
+// ExtractMaxSize limits the total size of the extracted files, the size in the headers is not trusted
+func ExtractMaxSize(size int64) ExtractOption {
+	return func(options *extractOptions) {
+		options.maxSize = size
+	}
+}
+
+// ExtractMaxFiles limits the count of the extracted entries, including the directories
+func ExtractMaxFiles(count int) ExtractOption {
+	return func(options *extractOptions) {
+		options.maxFiles = count
+	}
+}
+
+// ExtractSymlinks sets the policy of the symlink entries
+func ExtractSymlinks(policy SymlinkPolicy) ExtractOption {
+	return func(options *extractOptions) {
+		options.symlinks = policy
+	}
+}
+
+// extractor writes the archive entries under the root and accounts the limits
+type extractor struct {
+	root     string
+	realRoot string
+	options  *extractOptions
+	size     int64
+	files    int
+}
+
+func newExtractor(dest string, options []ExtractOption) (*extractor, error) {
+	extractOptions := &extractOptions{
+		maxSize:  DefaultExtractMaxSize,
+		maxFiles: DefaultExtractMaxFiles,
+		symlinks: SymlinkReject,
+	}
+	for _, option := range options {
+		option(extractOptions)
+	}
+	root, err := filepath.Abs(dest)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, err
+	}
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return nil, err
+	}
+	return &extractor{root: root, realRoot: realRoot, options: extractOptions}, nil
+}
+
+// UnZip extracts the zip file to the dest directory. The entries escaping the dest directory, such as
+// "../evil" or the absolute paths, fail the extraction.
+func UnZip(src, dest string, options ...ExtractOption) error {
+	ext, err := newExtractor(dest, options)
+	if err != nil {
+		return err
+	}
+	reader, err := zip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	for _, file := range reader.File {
+		if err := ext.extractZipFile(file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ext *extractor) extractZipFile(file *zip.File) error {
+	mode := file.Mode()
+	switch {
+	case mode.IsDir():
+		return ext.mkdir(file.Name)
+	case mode&os.ModeSymlink != 0:
+		reader, err := file.Open()
+		if err != nil {
+			return err
+		}
+		defer reader.Close()
+		target, err := io.ReadAll(io.LimitReader(reader, 4096))
+		if err != nil {
+			return err
+		}
+		return ext.symlink(file.Name, string(target))
+	case mode.IsRegular():
+		reader, err := file.Open()
+		if err != nil {
+			return err
+		}
+		defer reader.Close()
+		return ext.writeFile(file.Name, reader, mode.Perm())
+	default:
+		return fmt.Errorf("unsupported archive entry %s, mode: %s", file.Name, mode)
+	}
+}
+
+// UnTarGz extracts the gzip compressed tar file to the dest directory. The entries escaping the dest directory
+// and the hard links fail the extraction, the devices and fifos are ignored.
+func UnTarGz(src, dest string, options ...ExtractOption) error {
+	file, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	return UnTar(reader, dest, options...)
+}
+
+// UnTar extracts the tar stream to the dest directory with the same protection as UnTarGz
+func UnTar(reader io.Reader, dest string, options ...ExtractOption) error {
+	ext, err := newExtractor(dest, options)
+	if err != nil {
+		return err
+	}
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := ext.extractTarEntry(header, tarReader); err != nil {
+			return err
+		}
+	}
+}
+
+func (ext *extractor) extractTarEntry(header *tar.Header, reader io.Reader) error {
+	switch header.Typeflag {
+	case tar.TypeDir:
+		return ext.mkdir(header.Name)
+	case tar.TypeReg:
+		return ext.writeFile(header.Name, reader, os.FileMode(header.Mode).Perm())
+	case tar.TypeSymlink:
+		return ext.symlink(header.Name, header.Linkname)
+	case tar.TypeLink:
+		return fmt.Errorf("illegal archive entry %s, hard link is not supported", header.Name)
+	default:
+		return nil
+	}
+}
+
+// resolve returns the path of the entry under the root, or error if the entry escapes the root
+func (ext *extractor) resolve(name string) (string, error) {
+	if filepath.IsAbs(name) || strings.HasPrefix(name, "/") || strings.HasPrefix(name, `\`) {
+		return "", fmt.Errorf("illegal archive entry %s, absolute path is not allowed", name)
+	}
+	path := filepath.Join(ext.root, name)
+	if !containsPath(ext.root, path) {
+		return "", fmt.Errorf("illegal archive entry %s, outside of %s", name, ext.root)
+	}
+	// the symlinks extracted before or existing in the root may redirect the entry, so the real path of
+	// the nearest existing ancestor is checked as well
+	ancestor := filepath.Dir(path)
+	for {
+		if _, err := os.Lstat(ancestor); err == nil {
+			break
+		}
+		ancestor = filepath.Dir(ancestor)
+	}
+	realAncestor, err := filepath.EvalSymlinks(ancestor)
+	if err != nil {
+		return "", err
+	}
+	if !containsPath(ext.realRoot, realAncestor) {
+		return "", fmt.Errorf("illegal archive entry %s, outside of %s via the symlink", name, ext.root)
+	}
+	return path, nil
+}
+
+// containsPath returns true if the path is the root or under the root
+func containsPath(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func (ext *extractor) count(name string) error {
+	ext.files++
+	if ext.options.maxFiles > 0 && ext.files > ext.options.maxFiles {
+		return fmt.Errorf("illegal archive entry %s, the count of the entries exceeds %d", name, ext.options.maxFiles)
+	}
+	return nil
+}
+
+func (ext *extractor) mkdir(name string) error {
+	path, err := ext.resolve(name)
+	if err != nil {
+		return err
+	}
+	if err := ext.count(name); err != nil {
+		return err
+	}
+	return os.MkdirAll(path, 0755)
+}
+
+func (ext *extractor) writeFile(name string, reader io.Reader, perm os.FileMode) (err error) {
+	path, err := ext.resolve(name)
+	if err != nil {
+		return err
+	}
+	if err := ext.count(name); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if perm == 0 {
+		perm = 0644
+	}
+	// never write through an existing symlink
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	if ext.options.maxSize <= 0 {
+		_, err = io.Copy(file, reader)
+		return err
+	}
+	remaining := ext.options.maxSize - ext.size
+	written, err := io.Copy(file, io.LimitReader(reader, remaining+1))
+	ext.size += written
+	if err != nil {
+		return err
+	}
+	if written > remaining {
+		return fmt.Errorf("illegal archive entry %s, the size of the extracted files exceeds %d", name, ext.options.maxSize)
+	}
+	return nil
+}
+
+func (ext *extractor) symlink(name, target string) error {
+	switch ext.options.symlinks {
+	case SymlinkSkip:
+		return nil
+	case SymlinkWithinRoot:
+	default:
+		return fmt.Errorf("illegal archive entry %s, symlink is not allowed", name)
+	}
+	path, err := ext.resolve(name)
+	if err != nil {
+		return err
+	}
+	if filepath.IsAbs(target) || strings.HasPrefix(target, "/") {
+		return fmt.Errorf("illegal archive entry %s, the symlink target %s is absolute", name, target)
+	}
+	if !containsPath(ext.root, filepath.Join(filepath.Dir(path), target)) {
+		return fmt.Errorf("illegal archive entry %s, the symlink target %s is outside of %s", name, target, ext.root)
+	}
+	if err := ext.count(name); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.Symlink(target, path)
+}
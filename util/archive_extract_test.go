@@ -0,0 +1,217 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type archiveEntry struct {
+	name    string
+	content string
+	link    string
+	dir     bool
+}
+
+func writeTarGz(t *testing.T, entries ...archiveEntry) string {
+	buf := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Mode: 0644, Typeflag: tar.TypeReg, Size: int64(len(entry.content))}
+		switch {
+		case entry.dir:
+			header.Typeflag, header.Mode, header.Size = tar.TypeDir, 0755, 0
+		case entry.link != "":
+			header.Typeflag, header.Linkname, header.Size = tar.TypeSymlink, entry.link, 0
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if header.Typeflag == tar.TypeReg {
+			if _, err := tarWriter.Write([]byte(entry.content)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "archive.tar.gz")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func writeZip(t *testing.T, entries ...archiveEntry) string {
+	buf := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(buf)
+	for _, entry := range entries {
+		header := &zip.FileHeader{Name: entry.name}
+		content := entry.content
+		switch {
+		case entry.dir:
+			header.Name = strings.TrimSuffix(entry.name, "/") + "/"
+			header.SetMode(os.ModeDir | 0755)
+		case entry.link != "":
+			header.SetMode(os.ModeSymlink | 0777)
+			content = entry.link
+		default:
+			header.SetMode(0644)
+		}
+		writer, err := zipWriter.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := writer.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "archive.zip")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExtract(t *testing.T) {
+	extractors := map[string]struct {
+		write   func(t *testing.T, entries ...archiveEntry) string
+		extract func(src, dest string, options ...ExtractOption) error
+	}{
+		"zip":    {writeZip, UnZip},
+		"tar.gz": {writeTarGz, UnTarGz},
+	}
+	tests := []struct {
+		name    string
+		entries []archiveEntry
+		options []ExtractOption
+		wantErr string
+		want    map[string]string
+	}{
+		{
+			name:    "files and directories",
+			entries: []archiveEntry{{name: "bin", dir: true}, {name: "bin/tool", content: "tool"}, {name: "conf/a.yaml", content: "a: 1"}},
+			want:    map[string]string{"bin/tool": "tool", "conf/a.yaml": "a: 1"},
+		},
+		{
+			name:    "parent traversal",
+			entries: []archiveEntry{{name: "../evil", content: "evil"}},
+			wantErr: "outside of",
+		},
+		{
+			name:    "nested traversal",
+			entries: []archiveEntry{{name: "bin/../../evil", content: "evil"}},
+			wantErr: "outside of",
+		},
+		{
+			name:    "absolute path",
+			entries: []archiveEntry{{name: "/tmp/evil", content: "evil"}},
+			wantErr: "absolute path",
+		},
+		{
+			name:    "total size",
+			entries: []archiveEntry{{name: "a", content: "12345"}, {name: "b", content: "67890"}},
+			options: []ExtractOption{ExtractMaxSize(8)},
+			wantErr: "exceeds 8",
+		},
+		{
+			name:    "file count",
+			entries: []archiveEntry{{name: "a"}, {name: "b"}, {name: "c"}},
+			options: []ExtractOption{ExtractMaxFiles(2)},
+			wantErr: "exceeds 2",
+		},
+		{
+			name:    "symlink rejected by default",
+			entries: []archiveEntry{{name: "link", link: "bin"}},
+			wantErr: "symlink is not allowed",
+		},
+		{
+			name:    "symlink skipped",
+			entries: []archiveEntry{{name: "link", link: "/etc"}, {name: "a", content: "a"}},
+			options: []ExtractOption{ExtractSymlinks(SymlinkSkip)},
+			want:    map[string]string{"a": "a"},
+		},
+		{
+			name:    "symlink within root",
+			entries: []archiveEntry{{name: "bin/tool", content: "tool"}, {name: "lib/tool", link: "../bin/tool"}},
+			options: []ExtractOption{ExtractSymlinks(SymlinkWithinRoot)},
+			want:    map[string]string{"lib/tool": "tool"},
+		},
+		{
+			name:    "symlink outside of root",
+			entries: []archiveEntry{{name: "link", link: "../outside"}},
+			options: []ExtractOption{ExtractSymlinks(SymlinkWithinRoot)},
+			wantErr: "outside of",
+		},
+		{
+			name:    "absolute symlink",
+			entries: []archiveEntry{{name: "link", link: "/etc"}},
+			options: []ExtractOption{ExtractSymlinks(SymlinkWithinRoot)},
+			wantErr: "is absolute",
+		},
+		{
+			name: "write through the chained symlinks",
+			entries: []archiveEntry{
+				{name: "a/l", link: ".."}, {name: "a/l/m", link: ".."}, {name: "a/l/m/evil", content: "evil"},
+			},
+			options: []ExtractOption{ExtractSymlinks(SymlinkWithinRoot)},
+			wantErr: "outside of",
+		},
+	}
+	for format, extractor := range extractors {
+		for _, tt := range tests {
+			t.Run(format+"/"+tt.name, func(t *testing.T) {
+				src := extractor.write(t, tt.entries...)
+				dest := filepath.Join(t.TempDir(), "root")
+				err := extractor.extract(src, dest, tt.options...)
+				if tt.wantErr != "" {
+					if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+						t.Fatalf("extract() error = %v, want %q", err, tt.wantErr)
+					}
+					if _, err := os.Stat(filepath.Join(filepath.Dir(dest), "evil")); err == nil {
+						t.Errorf("the entry is extracted outside of the root")
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("extract() failed, %v", err)
+				}
+				for name, content := range tt.want {
+					bytes, err := os.ReadFile(filepath.Join(dest, name))
+					if err != nil || string(bytes) != content {
+						t.Errorf("%s = %q, %v, want %q", name, bytes, err, content)
+					}
+				}
+			})
+		}
+	}
+}
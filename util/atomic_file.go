@@ -0,0 +1,102 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// AtomicWriteOption customizes the durability of AtomicWriteFile
+type AtomicWriteOption func(options *atomicWriteOptions)
+
+type atomicWriteOptions struct {
+	syncFile bool
+	syncDir  bool
+}
+
+// AtomicWriteNoSync skips the fsync of the temp file before renaming, the write is still atomic for the readers,
+// but the file may be empty after the power loss
+func AtomicWriteNoSync() AtomicWriteOption {
+	return func(options *atomicWriteOptions) {
+		options.syncFile = false
+	}
+}
+
+// AtomicWriteSyncDir syncs the parent directory after renaming, so the rename itself survives the power loss.
+// It is ignored on the platforms which can not sync the directory, such as windows.
+func AtomicWriteSyncDir() AtomicWriteOption {
+	return func(options *atomicWriteOptions) {
+		options.syncDir = true
+	}
+}
+
+// AtomicWriteFile writes the data to a temp file in the same directory and renames it to the path, so the readers
+// see the old or the new content only, and a crash in the middle of writing never leaves the file corrupted.
+// The temp file is synced before renaming unless AtomicWriteNoSync is specified.
+func AtomicWriteFile(path string, data []byte, perm os.FileMode, options ...AtomicWriteOption) (err error) {
+	writeOptions := &atomicWriteOptions{syncFile: true}
+	for _, option := range options {
+		option(writeOptions)
+	}
+	dir, name := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	file, err := os.CreateTemp(dir, "."+name+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tempName := file.Name()
+	defer func() {
+		if err != nil {
+			file.Close()
+			os.Remove(tempName)
+		}
+	}()
+	if _, err = file.Write(data); err != nil {
+		return err
+	}
+	if err = file.Chmod(perm); err != nil {
+		return err
+	}
+	if writeOptions.syncFile {
+		if err = file.Sync(); err != nil {
+			return err
+		}
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tempName, path); err != nil {
+		return err
+	}
+	if writeOptions.syncDir {
+		syncDir(dir)
+	}
+	return nil
+}
+
+// syncDir fsyncs the directory, the errors are ignored because some platforms and file systems do not support it
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	defer d.Close()
+	d.Sync()
+}
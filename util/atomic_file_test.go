@@ -0,0 +1,50 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestAtomicWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "records.json")
+	if err := AtomicWriteFile(file, []byte("old"), 0600); err != nil {
+		t.Fatalf("AtomicWriteFile() error = %v", err)
+	}
+	if err := AtomicWriteFile(file, []byte("new"), 0640, AtomicWriteNoSync(), AtomicWriteSyncDir()); err != nil {
+		t.Fatalf("AtomicWriteFile() error = %v", err)
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil || string(data) != "new" {
+		t.Errorf("ReadFile() = %s, %v, want new", data, err)
+	}
+	if info, err := os.Stat(file); err == nil && runtime.GOOS != "windows" && info.Mode().Perm() != 0640 {
+		t.Errorf("the file mode = %v, want 0640", info.Mode().Perm())
+	}
+	entries, _ := ioutil.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("the directory has %d files, want the temp file removed", len(entries))
+	}
+	if err := AtomicWriteFile(filepath.Join(dir, "absent", "records.json"), []byte("new"), 0600); err == nil {
+		t.Errorf("AtomicWriteFile() error = nil, want the missing directory error")
+	}
+}
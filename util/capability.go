@@ -0,0 +1,147 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// capabilities are the bit numbers of the linux capabilities defined in linux/capability.h
+var capabilities = map[string]uint{
+	"CAP_CHOWN":              0,
+	"CAP_DAC_OVERRIDE":       1,
+	"CAP_DAC_READ_SEARCH":    2,
+	"CAP_FOWNER":             3,
+	"CAP_FSETID":             4,
+	"CAP_KILL":               5,
+	"CAP_SETGID":             6,
+	"CAP_SETUID":             7,
+	"CAP_SETPCAP":            8,
+	"CAP_LINUX_IMMUTABLE":    9,
+	"CAP_NET_BIND_SERVICE":   10,
+	"CAP_NET_BROADCAST":      11,
+	"CAP_NET_ADMIN":          12,
+	"CAP_NET_RAW":            13,
+	"CAP_IPC_LOCK":           14,
+	"CAP_IPC_OWNER":          15,
+	"CAP_SYS_MODULE":         16,
+	"CAP_SYS_RAWIO":          17,
+	"CAP_SYS_CHROOT":         18,
+	"CAP_SYS_PTRACE":         19,
+	"CAP_SYS_PACCT":          20,
+	"CAP_SYS_ADMIN":          21,
+	"CAP_SYS_BOOT":           22,
+	"CAP_SYS_NICE":           23,
+	"CAP_SYS_RESOURCE":       24,
+	"CAP_SYS_TIME":           25,
+	"CAP_SYS_TTY_CONFIG":     26,
+	"CAP_MKNOD":              27,
+	"CAP_LEASE":              28,
+	"CAP_AUDIT_WRITE":        29,
+	"CAP_AUDIT_CONTROL":      30,
+	"CAP_SETFCAP":            31,
+	"CAP_MAC_OVERRIDE":       32,
+	"CAP_MAC_ADMIN":          33,
+	"CAP_SYSLOG":             34,
+	"CAP_WAKE_ALARM":         35,
+	"CAP_BLOCK_SUSPEND":      36,
+	"CAP_AUDIT_READ":         37,
+	"CAP_PERFMON":            38,
+	"CAP_BPF":                39,
+	"CAP_CHECKPOINT_RESTORE": 40,
+}
+
+// IsRoot returns true if the effective user of the current process is root, it's always false on windows
+func IsRoot() bool {
+	return os.Geteuid() == 0
+}
+
+// NormalizeCapability returns the capability name in the CAP_XXX format, such as net_admin to CAP_NET_ADMIN
+func NormalizeCapability(capability string) string {
+	capability = strings.ToUpper(strings.TrimSpace(capability))
+	if !strings.HasPrefix(capability, "CAP_") {
+		capability = "CAP_" + capability
+	}
+	return capability
+}
+
+// HasCapability returns true if the capability, such as CAP_NET_ADMIN or net_admin, is in the effective set
+// of the current process. The capabilities are linux only, root is taken as having all of them on the others.
+func HasCapability(capability string) (bool, error) {
+	bit, ok := capabilities[NormalizeCapability(capability)]
+	if !ok {
+		return false, fmt.Errorf("unknown capability %s", capability)
+	}
+	if runtime.GOOS != "linux" {
+		return IsRoot(), nil
+	}
+	effective, err := effectiveCapabilities()
+	if err != nil {
+		return false, err
+	}
+	return effective&(1<<bit) != 0, nil
+}
+
+// RequireCapabilities returns nil if the current process has all capabilities, otherwise the
+// EnvironmentNotSatisfied response whose result is the spec.ActionRequirements listing the missing ones.
+// The unknown capability is the ParameterIllegal response.
+func RequireCapabilities(names ...string) *spec.Response {
+	missing := &spec.ActionRequirements{}
+	for _, name := range names {
+		ok, err := HasCapability(name)
+		if err != nil {
+			if _, known := capabilities[NormalizeCapability(name)]; !known {
+				return spec.ResponseFailWithFlags(spec.ParameterIllegal, "capability", name, err)
+			}
+		}
+		if !ok {
+			missing.Capabilities = append(missing.Capabilities, NormalizeCapability(name))
+		}
+	}
+	if missing.IsEmpty() {
+		return nil
+	}
+	response := spec.ResponseFailWithFlags(spec.EnvironmentNotSatisfied, missing)
+	response.Result = missing
+	return response
+}
+
+// effectiveCapabilities returns the CapEff bits in /proc/self/status
+func effectiveCapabilities() (uint64, error) {
+	status := path.Join(procRoot, "self/status")
+	file, err := os.Open(status)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "CapEff:") {
+			return strconv.ParseUint(strings.TrimSpace(strings.TrimPrefix(line, "CapEff:")), 16, 64)
+		}
+	}
+	return 0, fmt.Errorf("CapEff not found in %s", status)
+}
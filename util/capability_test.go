@@ -0,0 +1,52 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+func TestRequireCapabilities(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the capabilities are linux only")
+	}
+	// CAP_NET_ADMIN is bit 12 and CAP_SYS_ADMIN is bit 21
+	fakeProcFS(t, map[string]string{"proc/self/status": "Name:\tblade\nCapEff:\t0000000000001000\n"})
+	if ok, err := HasCapability("net_admin"); err != nil || !ok {
+		t.Errorf("HasCapability(net_admin) = %t, %v, want true", ok, err)
+	}
+	if ok, err := HasCapability("CAP_SYS_ADMIN"); err != nil || ok {
+		t.Errorf("HasCapability(CAP_SYS_ADMIN) = %t, %v, want false", ok, err)
+	}
+	if response := RequireCapabilities("CAP_NET_ADMIN"); response != nil {
+		t.Errorf("RequireCapabilities() = %v, want nil", response)
+	}
+	response := RequireCapabilities("net_admin", "sys_admin", "sys_ptrace")
+	if response == nil || response.Code != spec.EnvironmentNotSatisfied.Code {
+		t.Fatalf("RequireCapabilities() = %v, want EnvironmentNotSatisfied", response)
+	}
+	missing := response.Result.(*spec.ActionRequirements).Capabilities
+	if len(missing) != 2 || missing[0] != "CAP_SYS_ADMIN" || missing[1] != "CAP_SYS_PTRACE" {
+		t.Errorf("missing capabilities = %v, want [CAP_SYS_ADMIN CAP_SYS_PTRACE]", missing)
+	}
+	if response := RequireCapabilities("cap_unknown"); response == nil || response.Code != spec.ParameterIllegal.Code {
+		t.Errorf("RequireCapabilities() of the unknown capability = %v, want ParameterIllegal", response)
+	}
+}
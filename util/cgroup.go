@@ -0,0 +1,156 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// CgroupMode is the layout of the cgroup hierarchies mounted on the host
+type CgroupMode string
+
+const (
+	// CgroupModeNone means the cgroup filesystem is not mounted, such as on darwin
+	CgroupModeNone CgroupMode = ""
+	// CgroupModeLegacy mounts one v1 hierarchy for each controller
+	CgroupModeLegacy CgroupMode = "legacy"
+	// CgroupModeHybrid mounts the v1 controllers and an empty v2 hierarchy in the unified directory
+	CgroupModeHybrid CgroupMode = "hybrid"
+	// CgroupModeUnified mounts the v2 hierarchy only
+	CgroupModeUnified CgroupMode = "unified"
+)
+
+// cgroupRoot and procRoot are replaced in the tests
+var (
+	cgroupRoot = spec.DefaultCGroupPath
+	procRoot   = "/proc"
+)
+
+// GetCgroupMode detects the cgroup mode of the host
+func GetCgroupMode() CgroupMode {
+	switch {
+	case IsExist(path.Join(cgroupRoot, "cgroup.controllers")):
+		return CgroupModeUnified
+	case IsExist(path.Join(cgroupRoot, "unified", "cgroup.controllers")):
+		return CgroupModeHybrid
+	case IsDir(cgroupRoot):
+		return CgroupModeLegacy
+	default:
+		return CgroupModeNone
+	}
+}
+
+// Version returns spec.CgroupV2 for the unified mode and spec.CgroupV1 for the others, the controllers are
+// v1 in the hybrid mode
+func (mode CgroupMode) Version() string {
+	if mode == CgroupModeUnified {
+		return spec.CgroupV2
+	}
+	return spec.CgroupV1
+}
+
+// GetPidCgroupPaths returns the cgroup path of the process in each hierarchy, keyed by the controller name.
+// The path of the v2 hierarchy is keyed by the empty string. The named v1 hierarchies, such as
+// name=systemd, are keyed by the whole name.
+func GetPidCgroupPaths(pid int) (map[string]string, error) {
+	file, err := os.Open(path.Join(procRoot, strconv.Itoa(pid), "cgroup"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseCgroupPaths(file)
+}
+
+// ParseCgroupPaths parses the content of /proc/<pid>/cgroup, the lines are like 4:cpu,cpuacct:/docker/id
+func ParseCgroupPaths(reader io.Reader) (map[string]string, error) {
+	paths := make(map[string]string)
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line, ":", 3)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("illegal cgroup line: %s", line)
+		}
+		if fields[1] == "" {
+			paths[""] = fields[2]
+			continue
+		}
+		for _, controller := range strings.Split(fields[1], ",") {
+			paths[controller] = fields[2]
+		}
+	}
+	return paths, scanner.Err()
+}
+
+// GetPidCgroupDir returns the cgroup directory of the process for the controller, such as
+// /sys/fs/cgroup/cpu/docker/id on v1 or /sys/fs/cgroup/docker/id on v2, where the controller files are.
+func GetPidCgroupDir(pid int, controller string) (string, error) {
+	mode := GetCgroupMode()
+	if mode == CgroupModeNone {
+		return "", fmt.Errorf("cgroup is not mounted in %s", cgroupRoot)
+	}
+	paths, err := GetPidCgroupPaths(pid)
+	if err != nil {
+		return "", err
+	}
+	if mode == CgroupModeUnified {
+		cgroupPath, ok := paths[""]
+		if !ok {
+			return "", fmt.Errorf("the cgroup v2 path of %d not found", pid)
+		}
+		return path.Join(cgroupRoot, cgroupPath), nil
+	}
+	cgroupPath, ok := paths[controller]
+	if !ok {
+		return "", fmt.Errorf("the cgroup path of %d not found for %s controller", pid, controller)
+	}
+	return path.Join(cgroupRoot, strings.TrimPrefix(controller, "name="), cgroupPath), nil
+}
+
+// ReadCgroupFile returns the trimmed content of the controller file in the cgroup directory
+func ReadCgroupFile(dir, file string) (string, error) {
+	bytes, err := os.ReadFile(path.Join(dir, file))
+	if err != nil {
+		return "", fmt.Errorf("read cgroup file %s failed, %v", path.Join(dir, file), err)
+	}
+	return strings.TrimSpace(string(bytes)), nil
+}
+
+// WriteCgroupFile writes the value to the controller file in the cgroup directory. The file is not truncated
+// or created, the kernel parses each write as a whole.
+func WriteCgroupFile(dir, file, value string) error {
+	cgroupFile, err := os.OpenFile(path.Join(dir, file), os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("write %s to %s failed, %v", value, path.Join(dir, file), err)
+	}
+	defer cgroupFile.Close()
+	if _, err := cgroupFile.WriteString(value); err != nil {
+		return fmt.Errorf("write %s to %s failed, %v", value, path.Join(dir, file), err)
+	}
+	return nil
+}
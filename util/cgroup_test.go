@@ -0,0 +1,127 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// fakeProcFS replaces the cgroup and proc roots with the temp directories containing the files
+func fakeProcFS(t *testing.T, files map[string]string) string {
+	root := t.TempDir()
+	for name, content := range files {
+		file := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	oldCgroupRoot, oldProcRoot := cgroupRoot, procRoot
+	cgroupRoot, procRoot = filepath.Join(root, "cgroup"), filepath.Join(root, "proc")
+	t.Cleanup(func() { cgroupRoot, procRoot = oldCgroupRoot, oldProcRoot })
+	return root
+}
+
+func TestGetCgroupMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    CgroupMode
+		version string
+	}{
+		{name: "unified", files: map[string]string{"cgroup/cgroup.controllers": "cpu memory"},
+			want: CgroupModeUnified, version: spec.CgroupV2},
+		{name: "hybrid", files: map[string]string{"cgroup/unified/cgroup.controllers": ""},
+			want: CgroupModeHybrid, version: spec.CgroupV1},
+		{name: "legacy", files: map[string]string{"cgroup/cpu/tasks": ""}, want: CgroupModeLegacy, version: spec.CgroupV1},
+		{name: "none", want: CgroupModeNone, version: spec.CgroupV1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeProcFS(t, tt.files)
+			if got := GetCgroupMode(); got != tt.want || got.Version() != tt.version {
+				t.Errorf("GetCgroupMode() = %q(%s), want %q(%s)", got, got.Version(), tt.want, tt.version)
+			}
+		})
+	}
+}
+
+func TestParseCgroupPaths(t *testing.T) {
+	content := "12:cpu,cpuacct:/docker/abc\n11:memory:/docker/abc\n1:name=systemd:/system.slice\n0::/system.slice/docker.service\n"
+	paths, err := ParseCgroupPaths(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("ParseCgroupPaths() failed, %v", err)
+	}
+	want := map[string]string{
+		"cpu": "/docker/abc", "cpuacct": "/docker/abc", "memory": "/docker/abc",
+		"name=systemd": "/system.slice", "": "/system.slice/docker.service",
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("ParseCgroupPaths() = %v, want %v", paths, want)
+	}
+	if _, err := ParseCgroupPaths(strings.NewReader("illegal")); err == nil {
+		t.Errorf("ParseCgroupPaths() of the illegal line succeeded")
+	}
+}
+
+func TestGetPidCgroupDir(t *testing.T) {
+	t.Run("legacy", func(t *testing.T) {
+		root := fakeProcFS(t, map[string]string{
+			"cgroup/memory/docker/abc/memory.limit_in_bytes": "1024\n",
+			"proc/100/cgroup": "4:memory:/docker/abc\n3:cpu,cpuacct:/docker/abc\n",
+		})
+		dir, err := GetPidCgroupDir(100, "memory")
+		if err != nil || dir != filepath.Join(root, "cgroup/memory/docker/abc") {
+			t.Fatalf("GetPidCgroupDir() = %s, %v", dir, err)
+		}
+		if value, err := ReadCgroupFile(dir, "memory.limit_in_bytes"); err != nil || value != "1024" {
+			t.Errorf("ReadCgroupFile() = %q, %v, want 1024", value, err)
+		}
+		if err := WriteCgroupFile(dir, "memory.limit_in_bytes", "2048"); err != nil {
+			t.Errorf("WriteCgroupFile() failed, %v", err)
+		}
+		if value, _ := ReadCgroupFile(dir, "memory.limit_in_bytes"); value != "2048" {
+			t.Errorf("ReadCgroupFile() after writing = %q, want 2048", value)
+		}
+		if err := WriteCgroupFile(dir, "memory.absent", "1"); err == nil {
+			t.Errorf("WriteCgroupFile() created the absent file")
+		}
+		if _, err := GetPidCgroupDir(100, "blkio"); err == nil {
+			t.Errorf("GetPidCgroupDir() of the absent controller succeeded")
+		}
+	})
+	t.Run("unified", func(t *testing.T) {
+		root := fakeProcFS(t, map[string]string{
+			"cgroup/cgroup.controllers": "cpu memory",
+			"proc/100/cgroup":           "0::/kubepods/pod1\n",
+		})
+		if dir, err := GetPidCgroupDir(100, "cpu"); err != nil || dir != filepath.Join(root, "cgroup/kubepods/pod1") {
+			t.Errorf("GetPidCgroupDir() = %s, %v", dir, err)
+		}
+		if _, err := GetPidCgroupDir(200, "cpu"); err == nil {
+			t.Errorf("GetPidCgroupDir() of the absent process succeeded")
+		}
+	})
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+)
+
+// The checksum algorithms supported by NewHasher
+const (
+	ChecksumMD5    = "md5"
+	ChecksumSHA1   = "sha1"
+	ChecksumSHA256 = "sha256"
+	ChecksumSHA512 = "sha512"
+)
+
+// Hasher is the io.Writer computing the checksum of the written content, it's used with io.Copy, io.TeeReader
+// or io.MultiWriter to verify the large content without loading it into memory
+type Hasher struct {
+	hash    hash.Hash
+	written int64
+}
+
+// NewHasher returns the hasher of the algorithm, which is one of md5, sha1, sha256 and sha512
+func NewHasher(algorithm string) (*Hasher, error) {
+	var h hash.Hash
+	switch strings.ToLower(algorithm) {
+	case ChecksumMD5:
+		h = md5.New()
+	case ChecksumSHA1:
+		h = sha1.New()
+	case ChecksumSHA256:
+		h = sha256.New()
+	case ChecksumSHA512:
+		h = sha512.New()
+	default:
+		return nil, fmt.Errorf("unsupported checksum algorithm: %s", algorithm)
+	}
+	return &Hasher{hash: h}, nil
+}
+
+// Write adds the content to the checksum, it never fails
+func (h *Hasher) Write(p []byte) (int, error) {
+	n, err := h.hash.Write(p)
+	h.written += int64(n)
+	return n, err
+}
+
+// Written returns the count of the written bytes
+func (h *Hasher) Written() int64 {
+	return h.written
+}
+
+// Sum returns the checksum of the written content in lowercase hex
+func (h *Hasher) Sum() string {
+	return hex.EncodeToString(h.hash.Sum(nil))
+}
+
+// Verify returns error if the checksum of the written content is not the expected hex digest
+func (h *Hasher) Verify(want string) error {
+	if got := h.Sum(); got != strings.ToLower(strings.TrimSpace(want)) {
+		return fmt.Errorf("checksum mismatch, got %s, want %s", got, want)
+	}
+	return nil
+}
+
+// ChecksumFile returns the checksum of the file in lowercase hex, the file is read in streaming
+func ChecksumFile(algorithm, path string) (string, error) {
+	hasher, err := NewHasher(algorithm)
+	if err != nil {
+		return "", err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+	return hasher.Sum(), nil
+}
+
+// SHA256File returns the sha256 checksum of the file in lowercase hex
+func SHA256File(path string) (string, error) {
+	return ChecksumFile(ChecksumSHA256, path)
+}
+
+// MD5File returns the md5 checksum of the file in lowercase hex
+func MD5File(path string) (string, error) {
+	return ChecksumFile(ChecksumMD5, path)
+}
+
+// VerifyFileChecksum returns error if the checksum of the file is not the expected hex digest
+func VerifyFileChecksum(algorithm, path, want string) error {
+	got, err := ChecksumFile(algorithm, path)
+	if err != nil {
+		return err
+	}
+	if got != strings.ToLower(strings.TrimSpace(want)) {
+		return fmt.Errorf("checksum mismatch, got %s, want %s", got, want)
+	}
+	return nil
+}
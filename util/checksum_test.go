@@ -0,0 +1,60 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestChecksumFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tool")
+	if err := os.WriteFile(path, []byte("chaosblade"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sha256Sum := "ba480cfdba982f161584d3080b27336bedc4f34f790ca7685a75047fb9a99a25"
+	if got, err := SHA256File(path); err != nil || got != sha256Sum {
+		t.Errorf("SHA256File() = %s, %v, want %s", got, err, sha256Sum)
+	}
+	if got, err := MD5File(path); err != nil || got != "6e6edfd269997ba120a315feb11c0ade" {
+		t.Errorf("MD5File() = %s, %v, want 6e6edfd269997ba120a315feb11c0ade", got, err)
+	}
+	if err := VerifyFileChecksum("SHA256", path, strings.ToUpper(sha256Sum)); err != nil {
+		t.Errorf("VerifyFileChecksum() failed, %v", err)
+	}
+	if err := VerifyFileChecksum("sha256", path, strings.Repeat("0", 64)); err == nil {
+		t.Errorf("VerifyFileChecksum() of the wrong digest succeeded")
+	}
+	if _, err := ChecksumFile("crc32", path); err == nil {
+		t.Errorf("ChecksumFile() of the unsupported algorithm succeeded")
+	}
+
+	hasher, err := NewHasher(ChecksumSHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader := io.TeeReader(strings.NewReader("chaosblade"), hasher)
+	if _, err := io.Copy(io.Discard, reader); err != nil {
+		t.Fatal(err)
+	}
+	if hasher.Written() != 10 || hasher.Verify(sha256Sum) != nil {
+		t.Errorf("Hasher = %s after %d bytes, want %s", hasher.Sum(), hasher.Written(), sha256Sum)
+	}
+}
@@ -0,0 +1,166 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// DefaultNTPServers are queried if no server is specified
+var DefaultNTPServers = []string{"pool.ntp.org", "time.google.com", "time.cloudflare.com"}
+
+// DefaultNTPTimeout is the max time of querying a server, the deadline of the context is respected if earlier
+const DefaultNTPTimeout = 5 * time.Second
+
+// ntpEpochOffset is the seconds from 1900-01-01, the ntp epoch, to 1970-01-01
+const ntpEpochOffset = 2208988800
+
+// ClockDrift is the offset of the host clock measured by the ntp server
+type ClockDrift struct {
+	Server string
+	// Offset is the server time minus the host time, it's negative if the host clock is ahead
+	Offset time.Duration
+	// RTT is the round trip time of the query, the offset is accurate within the half of it
+	RTT time.Duration
+}
+
+// Exceeds returns true if the absolute offset is greater than the max drift
+func (d *ClockDrift) Exceeds(max time.Duration) bool {
+	offset := d.Offset
+	if offset < 0 {
+		offset = -offset
+	}
+	return offset > max
+}
+
+func (d *ClockDrift) String() string {
+	return fmt.Sprintf("offset %s to %s, rtt %s", d.Offset, d.Server, d.RTT)
+}
+
+// CheckClockDrift queries the ntp servers in order by SNTP and returns the drift measured by the first
+// responding one, the servers are host or host:port and DefaultNTPServers are used if empty
+func CheckClockDrift(ctx context.Context, ntpServers []string) (*ClockDrift, error) {
+	if len(ntpServers) == 0 {
+		ntpServers = DefaultNTPServers
+	}
+	errs := make([]string, 0, len(ntpServers))
+	for _, server := range ntpServers {
+		drift, err := queryNTP(ctx, server)
+		if err == nil {
+			return drift, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", server, err))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, fmt.Errorf("query ntp servers failed, %s", strings.Join(errs, "; "))
+}
+
+// RequireClockSync returns the EnvironmentNotSatisfied response if the host clock drifts more than the max drift,
+// or can not be verified because no ntp server responds. The experiments tolerating the unverified clock, such
+// as in the isolated networks, should call CheckClockDrift and warn instead.
+func RequireClockSync(ctx context.Context, ntpServers []string, maxDrift time.Duration) *spec.Response {
+	drift, err := CheckClockDrift(ctx, ntpServers)
+	if err != nil {
+		response := spec.ResponseFailWithFlags(spec.EnvironmentNotSatisfied, "clock synchronization")
+		response.Err = fmt.Sprintf("%s, %v", response.Err, err)
+		return response
+	}
+	if drift.Exceeds(maxDrift) {
+		response := spec.ResponseFailWithFlags(spec.EnvironmentNotSatisfied, "clock synchronization")
+		response.Err = fmt.Sprintf("%s, the clock drift exceeds %s, %s", response.Err, maxDrift, drift)
+		response.Result = drift
+		return response
+	}
+	return spec.ReturnSuccess(drift)
+}
+
+func queryNTP(ctx context.Context, server string) (*ClockDrift, error) {
+	address := server
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		address = net.JoinHostPort(server, "123")
+	}
+	ctx, cancel := context.WithTimeout(ctx, DefaultNTPTimeout)
+	defer cancel()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	request := make([]byte, 48)
+	// leap indicator 0, version 4, mode 3 (client)
+	request[0] = 0x23
+	sent := time.Now()
+	binary.BigEndian.PutUint64(request[40:], toNTPTime(sent))
+	if _, err := conn.Write(request); err != nil {
+		return nil, err
+	}
+	response := make([]byte, 48)
+	n, err := conn.Read(response)
+	if err != nil {
+		return nil, err
+	}
+	received := time.Now()
+	if n < 48 {
+		return nil, fmt.Errorf("short ntp response of %d bytes", n)
+	}
+	if mode := response[0] & 0x07; mode != 4 {
+		return nil, fmt.Errorf("unexpected ntp mode %d", mode)
+	}
+	if response[1] == 0 {
+		return nil, errors.New("kiss-of-death ntp response")
+	}
+	if binary.BigEndian.Uint64(response[24:]) != binary.BigEndian.Uint64(request[40:]) {
+		return nil, errors.New("the ntp response does not match the request")
+	}
+	serverReceived := fromNTPTime(binary.BigEndian.Uint64(response[32:]))
+	serverSent := fromNTPTime(binary.BigEndian.Uint64(response[40:]))
+	return &ClockDrift{
+		Server: server,
+		Offset: (serverReceived.Sub(sent) + serverSent.Sub(received)) / 2,
+		RTT:    received.Sub(sent) - serverSent.Sub(serverReceived),
+	}, nil
+}
+
+// toNTPTime converts the time to the 64 bits ntp timestamp, the high 32 bits are the seconds since the ntp
+// epoch and the low 32 bits are the fraction
+func toNTPTime(t time.Time) uint64 {
+	seconds := uint64(t.Unix() + ntpEpochOffset)
+	fraction := uint64(t.Nanosecond()) << 32 / uint64(time.Second)
+	return seconds<<32 | fraction
+}
+
+func fromNTPTime(timestamp uint64) time.Time {
+	seconds := int64(timestamp>>32) - ntpEpochOffset
+	nanos := (timestamp & 0xffffffff) * uint64(time.Second) >> 32
+	return time.Unix(seconds, int64(nanos))
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+)
+
+// fakeNTPServer answers the SNTP requests with the local time shifted by the skew
+func fakeNTPServer(t *testing.T, skew time.Duration) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen udp failed, %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	go func() {
+		request := make([]byte, 48)
+		for {
+			n, addr, err := conn.ReadFrom(request)
+			if err != nil {
+				return
+			}
+			if n < 48 {
+				continue
+			}
+			now := toNTPTime(time.Now().Add(skew))
+			response := make([]byte, 48)
+			// version 4, mode 4 (server), stratum 1
+			response[0], response[1] = 0x24, 1
+			copy(response[24:32], request[40:48])
+			binary.BigEndian.PutUint64(response[32:], now)
+			binary.BigEndian.PutUint64(response[40:], now)
+			conn.WriteTo(response, addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func TestCheckClockDrift(t *testing.T) {
+	server := fakeNTPServer(t, time.Hour)
+	drift, err := CheckClockDrift(context.Background(), []string{server})
+	if err != nil {
+		t.Fatalf("CheckClockDrift() failed, %v", err)
+	}
+	if drift.Offset < time.Hour-time.Second || drift.Offset > time.Hour+time.Second {
+		t.Errorf("Offset = %s, want about 1h", drift.Offset)
+	}
+	if !drift.Exceeds(time.Minute) || drift.Exceeds(2*time.Hour) {
+		t.Errorf("Exceeds() is wrong for the offset %s", drift.Offset)
+	}
+	if response := RequireClockSync(context.Background(), []string{server}, time.Minute); response.Success {
+		t.Errorf("RequireClockSync() succeeded with the drift %s", drift)
+	}
+}
+
+func TestCheckClockDriftFallback(t *testing.T) {
+	// the closed port does not respond, the next server is queried
+	closed, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen udp failed, %v", err)
+	}
+	unreachable := closed.LocalAddr().String()
+	closed.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	server := fakeNTPServer(t, -time.Second)
+	drift, err := CheckClockDrift(ctx, []string{unreachable, server})
+	if err != nil {
+		t.Fatalf("CheckClockDrift() failed, %v", err)
+	}
+	if drift.Server != server || drift.Offset > 0 {
+		t.Errorf("unexpected drift %s", drift)
+	}
+	if response := RequireClockSync(ctx, []string{server}, time.Minute); !response.Success {
+		t.Errorf("RequireClockSync() failed, %s", response.Err)
+	}
+}
+
+func TestNTPTime(t *testing.T) {
+	now := time.Unix(1700000000, 123456789)
+	if got := fromNTPTime(toNTPTime(now)); got.Sub(now).Abs() > time.Microsecond {
+		t.Errorf("fromNTPTime(toNTPTime(%s)) = %s", now, got)
+	}
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// The formats of WriteCodeCatalog
+const (
+	CatalogFormatJSON = "json"
+	CatalogFormatYAML = "yaml"
+)
+
+// WriteCodeCatalog writes spec.CodeCatalog in the json or yaml format, it is the handler of the cli flag
+// which dumps the response codes, such as blade version --codes yaml
+func WriteCodeCatalog(writer io.Writer, format string) error {
+	catalog := spec.CodeCatalog()
+	switch format {
+	case CatalogFormatJSON, "":
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(catalog)
+	case CatalogFormatYAML:
+		bytes, err := yaml.Marshal(catalog)
+		if err != nil {
+			return err
+		}
+		_, err = writer.Write(bytes)
+		return err
+	}
+	return fmt.Errorf("unsupported catalog format `%s`, only %s and %s are supported", format, CatalogFormatJSON, CatalogFormatYAML)
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+func TestWriteCodeCatalog(t *testing.T) {
+	tests := []struct {
+		format    string
+		unmarshal func([]byte, interface{}) error
+		wantErr   bool
+	}{
+		{format: CatalogFormatJSON, unmarshal: json.Unmarshal},
+		{format: CatalogFormatYAML, unmarshal: yaml.Unmarshal},
+		{format: "xml", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := WriteCodeCatalog(&buf, tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WriteCodeCatalog() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			var entries []spec.CodeCatalogEntry
+			if err := tt.unmarshal(buf.Bytes(), &entries); err != nil {
+				t.Fatalf("unmarshal the catalog failed, %v", err)
+			}
+			if len(entries) != len(spec.CodeCatalog()) || entries[0].Status == "" {
+				t.Errorf("WriteCodeCatalog() = %s, want all the registered codes", buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,316 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// Config is the layered configuration shared by the agents and the plugins. The value of the key is looked up
+// from the layers in order: the value set by Set, such as the flag value, the environment variable, the loaded
+// files in reverse loading order, and the default value.
+//
+// The keys are case-insensitive and the nested keys are joined by dots, for example the key of
+//
+//	server:
+//	  port: 9526
+//
+// is server.port, and it's overridden by the environment variable BLADE_SERVER_PORT if the prefix is BLADE.
+type Config struct {
+	lock      sync.RWMutex
+	envPrefix string
+	overrides map[string]interface{}
+	files     map[string]interface{}
+	defaults  map[string]interface{}
+}
+
+// NewConfig returns the empty config, the environment variables are named by the prefix and the key, the
+// environment variables are not used if the prefix is empty
+func NewConfig(envPrefix string) *Config {
+	return &Config{
+		envPrefix: envPrefix,
+		overrides: make(map[string]interface{}),
+		files:     make(map[string]interface{}),
+		defaults:  make(map[string]interface{}),
+	}
+}
+
+// LoadConfig returns the config with the files loaded in order
+func LoadConfig(envPrefix string, files ...string) (*Config, error) {
+	config := NewConfig(envPrefix)
+	for _, file := range files {
+		if err := config.LoadFile(file); err != nil {
+			return nil, err
+		}
+	}
+	return config, nil
+}
+
+// LoadFile loads the yaml or json file, the values override the ones of the files loaded before
+func (c *Config) LoadFile(file string) error {
+	bytes, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	return c.Load(bytes)
+}
+
+// Load loads the yaml or json content, json is parsed as yaml which is its superset
+func (c *Config) Load(content []byte) error {
+	var values map[interface{}]interface{}
+	if err := yaml.Unmarshal(content, &values); err != nil {
+		return fmt.Errorf("parse the config failed, %v", err)
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	flattenConfig("", values, c.files)
+	return nil
+}
+
+// SetDefault sets the default value of the key, the map value is flattened to the nested keys
+func (c *Config) SetDefault(key string, value interface{}) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	setConfigValue(c.defaults, key, value)
+}
+
+// Set sets the value of the key overriding the others, such as the value of the flag
+func (c *Config) Set(key string, value interface{}) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	setConfigValue(c.overrides, key, value)
+}
+
+// EnvName returns the environment variable name of the key, such as BLADE_SERVER_PORT of server.port
+func (c *Config) EnvName(key string) string {
+	if c.envPrefix == "" {
+		return ""
+	}
+	name := strings.NewReplacer(".", "_", "-", "_").Replace(c.envPrefix + "_" + key)
+	return strings.ToUpper(name)
+}
+
+// Get returns the value of the key and whether it's set in any layer
+func (c *Config) Get(key string) (interface{}, bool) {
+	key = strings.ToLower(key)
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if value, ok := c.overrides[key]; ok {
+		return value, true
+	}
+	if name := c.EnvName(key); name != "" {
+		if value, ok := os.LookupEnv(name); ok {
+			return value, true
+		}
+	}
+	if value, ok := c.files[key]; ok {
+		return value, true
+	}
+	value, ok := c.defaults[key]
+	return value, ok
+}
+
+// IsSet returns true if the key is set in any layer
+func (c *Config) IsSet(key string) bool {
+	_, ok := c.Get(key)
+	return ok
+}
+
+// Keys returns the sorted keys set in the files, the defaults and by Set
+func (c *Config) Keys() []string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	keys := make(map[string]bool)
+	for _, layer := range []map[string]interface{}{c.overrides, c.files, c.defaults} {
+		for key := range layer {
+			keys[key] = true
+		}
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// GetString returns the string value of the key, or empty if the key is not set
+func (c *Config) GetString(key string) string {
+	value, ok := c.Get(key)
+	if !ok || value == nil {
+		return ""
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// GetInt returns the int value of the key, or zero if the key is not set
+func (c *Config) GetInt(key string) (int, error) {
+	value, ok := c.Get(key)
+	if !ok || value == nil {
+		return 0, nil
+	}
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case uint64:
+		return int(v), nil
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), nil
+		}
+	case string:
+		if i, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("the value %v of the config %s is not an integer", value, key)
+}
+
+// GetBool returns the bool value of the key, or false if the key is not set
+func (c *Config) GetBool(key string) (bool, error) {
+	value, ok := c.Get(key)
+	if !ok || value == nil {
+		return false, nil
+	}
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		if b, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+			return b, nil
+		}
+	}
+	return false, fmt.Errorf("the value %v of the config %s is not a bool", value, key)
+}
+
+// GetDuration returns the duration value of the key, the formats are the same as ParseDuration, or zero if the
+// key is not set
+func (c *Config) GetDuration(key string) (time.Duration, error) {
+	value := c.GetString(key)
+	if value == "" {
+		return 0, nil
+	}
+	duration, err := ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("the config %s is illegal, %v", key, err)
+	}
+	return duration, nil
+}
+
+// GetSize returns the bytes of the size value of the key, the formats are the same as ParseSize, or zero if the
+// key is not set
+func (c *Config) GetSize(key string) (int64, error) {
+	value := c.GetString(key)
+	if value == "" {
+		return 0, nil
+	}
+	size, err := ParseSize(value)
+	if err != nil {
+		return 0, fmt.Errorf("the config %s is illegal, %v", key, err)
+	}
+	return size, nil
+}
+
+// GetStringSlice returns the string values of the list, the string value, such as the environment variable,
+// is split by comma
+func (c *Config) GetStringSlice(key string) []string {
+	value, ok := c.Get(key)
+	if !ok || value == nil {
+		return nil
+	}
+	switch v := value.(type) {
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, fmt.Sprintf("%v", item))
+		}
+		return values
+	case []string:
+		return v
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return nil
+		}
+		values := strings.Split(v, ",")
+		for idx := range values {
+			values[idx] = strings.TrimSpace(values[idx])
+		}
+		return values
+	}
+	return []string{fmt.Sprintf("%v", value)}
+}
+
+// FlagConfigProvider returns the provider looking up the flag config keys in the config, it's set by
+// spec.SetFlagConfigProvider. The list values are joined by comma like the values of the repeated flags.
+func (c *Config) FlagConfigProvider() spec.FlagConfigProvider {
+	return func(key string) (string, bool) {
+		value, ok := c.Get(key)
+		if !ok || value == nil {
+			return "", false
+		}
+		switch value.(type) {
+		case []interface{}, []string:
+			return strings.Join(c.GetStringSlice(key), ","), true
+		}
+		return c.GetString(key), true
+	}
+}
+
+func setConfigValue(layer map[string]interface{}, key string, value interface{}) {
+	key = strings.ToLower(key)
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			setConfigValue(layer, key+"."+k, item)
+		}
+	case map[interface{}]interface{}:
+		flattenConfig(key, v, layer)
+	default:
+		layer[key] = value
+	}
+}
+
+// flattenConfig flattens the nested maps to the dotted keys, the lists are kept as the values
+func flattenConfig(prefix string, values map[interface{}]interface{}, layer map[string]interface{}) {
+	for k, value := range values {
+		key := strings.ToLower(fmt.Sprintf("%v", k))
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := value.(map[interface{}]interface{}); ok {
+			flattenConfig(key, nested, layer)
+			continue
+		}
+		layer[key] = value
+	}
+}
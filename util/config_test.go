@@ -0,0 +1,123 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+func TestConfigLayers(t *testing.T) {
+	dir := t.TempDir()
+	yamlFile := filepath.Join(dir, "agent.yaml")
+	jsonFile := filepath.Join(dir, "agent.json")
+	os.WriteFile(yamlFile, []byte("server:\n  port: 9526\n  host: 0.0.0.0\nlog:\n  level: info\ntimeout: 1m\nplugins: [os, jvm]\n"), 0644)
+	os.WriteFile(jsonFile, []byte(`{"log": {"level": "debug"}, "debug": true}`), 0644)
+
+	config, err := LoadConfig("BLADE_TEST", yamlFile, jsonFile)
+	if err != nil {
+		t.Fatalf("LoadConfig() failed, %v", err)
+	}
+	config.SetDefault("server", map[string]interface{}{"port": 80, "mode": "daemon"})
+	t.Setenv("BLADE_TEST_SERVER_HOST", "127.0.0.1")
+
+	if port, err := config.GetInt("server.port"); err != nil || port != 9526 {
+		t.Errorf("server.port = %d, %v, want 9526", port, err)
+	}
+	if got := config.GetString("server.mode"); got != "daemon" {
+		t.Errorf("server.mode = %q, want daemon from the defaults", got)
+	}
+	if got := config.GetString("Server.Host"); got != "127.0.0.1" {
+		t.Errorf("server.host = %q, want 127.0.0.1 from the environment", got)
+	}
+	if got := config.GetString("log.level"); got != "debug" {
+		t.Errorf("log.level = %q, want debug from the later file", got)
+	}
+	if debug, err := config.GetBool("debug"); err != nil || !debug {
+		t.Errorf("debug = %t, %v, want true", debug, err)
+	}
+	if timeout, err := config.GetDuration("timeout"); err != nil || timeout != time.Minute {
+		t.Errorf("timeout = %s, %v, want 1m", timeout, err)
+	}
+	if got := config.GetStringSlice("plugins"); !reflect.DeepEqual(got, []string{"os", "jvm"}) {
+		t.Errorf("plugins = %v, want [os jvm]", got)
+	}
+	config.Set("log.level", "warn")
+	if got := config.GetString("log.level"); got != "warn" {
+		t.Errorf("log.level = %q, want warn set explicitly", got)
+	}
+	if config.IsSet("absent") {
+		t.Errorf("IsSet(absent) = true")
+	}
+}
+
+func TestConfigFlagConfigProvider(t *testing.T) {
+	config := NewConfig("BLADE_TEST")
+	if err := config.Load([]byte("network:\n  interface: eth1\n  ports: [80, 443]\n  timeout: 30\n")); err != nil {
+		t.Fatalf("Load() failed, %v", err)
+	}
+	provider := config.FlagConfigProvider()
+	tests := []struct {
+		key    string
+		want   string
+		wantOk bool
+	}{
+		{"network.interface", "eth1", true},
+		{"network.ports", "80,443", true},
+		{"network.timeout", "30", true},
+		{"network.absent", "", false},
+	}
+	for _, tt := range tests {
+		if got, ok := provider(tt.key); got != tt.want || ok != tt.wantOk {
+			t.Errorf("provider(%s) = %q, %t, want %q, %t", tt.key, got, ok, tt.want, tt.wantOk)
+		}
+	}
+
+	spec.SetFlagConfigProvider(provider)
+	defer spec.SetFlagConfigProvider(nil)
+	flag := &spec.ExpFlag{Name: "interface", Default: "eth0", ConfigKey: "network.interface"}
+	if value, ok := spec.ResolveFlagValue(flag, map[string]string{}); !ok || value != "eth1" {
+		t.Errorf("ResolveFlagValue() = %q, %t, want eth1 from the config", value, ok)
+	}
+}
+
+func TestConfigTypedErrors(t *testing.T) {
+	config := NewConfig("")
+	if err := config.Load([]byte("port: abc\nsize: 10XB\nplugins: os, jvm\n")); err != nil {
+		t.Fatalf("Load() failed, %v", err)
+	}
+	if _, err := config.GetInt("port"); err == nil {
+		t.Errorf("GetInt(port) of abc succeeded")
+	}
+	if _, err := config.GetSize("size"); err == nil {
+		t.Errorf("GetSize(size) of 10XB succeeded")
+	}
+	if got := config.GetStringSlice("plugins"); !reflect.DeepEqual(got, []string{"os", "jvm"}) {
+		t.Errorf("plugins = %v, want [os jvm]", got)
+	}
+	if err := config.Load([]byte("port: [")); err == nil {
+		t.Errorf("Load() of the illegal content succeeded")
+	}
+	if _, err := LoadConfig("", filepath.Join(t.TempDir(), "absent.yaml")); err == nil {
+		t.Errorf("LoadConfig() of the absent file succeeded")
+	}
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"os"
+	"path"
+	"strings"
+)
+
+// The container runtimes detected by ContainerRuntime
+const (
+	ContainerRuntimeDocker     = "docker"
+	ContainerRuntimeContainerd = "containerd"
+	ContainerRuntimeCRIO       = "cri-o"
+	ContainerRuntimePodman     = "podman"
+	ContainerRuntimeLXC        = "lxc"
+	// ContainerRuntimeUnknown means in the container but the runtime is not recognized, such as under kubernetes
+	// with an unknown cri
+	ContainerRuntimeUnknown = "unknown"
+)
+
+// fsRoot is replaced in the tests
+var fsRoot = "/"
+
+// runtimeMarkers are the substrings of the cgroup paths and the mount sources created by the runtimes,
+// the more specific ones come first
+var runtimeMarkers = []struct {
+	marker  string
+	runtime string
+}{
+	{"cri-containerd", ContainerRuntimeContainerd},
+	{"/var/lib/containerd/", ContainerRuntimeContainerd},
+	{"crio-", ContainerRuntimeCRIO},
+	{"libpod", ContainerRuntimePodman},
+	{"/var/lib/containers/", ContainerRuntimeCRIO},
+	{"/docker/", ContainerRuntimeDocker},
+	{"docker-", ContainerRuntimeDocker},
+	{"/var/lib/docker/", ContainerRuntimeDocker},
+	{"/lxc/", ContainerRuntimeLXC},
+}
+
+// InContainer returns true if the current process runs in a container
+func InContainer() bool {
+	return ContainerRuntime() != ""
+}
+
+// InKubernetes returns true if the current process runs in a kubernetes pod
+func InKubernetes() bool {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		return true
+	}
+	return strings.Contains(readProcFile("1/cgroup"), "kubepods")
+}
+
+// ContainerRuntime returns the runtime of the container which the current process runs in, such as docker or
+// containerd, or empty if not in a container. The runtime is detected by the container environment variable,
+// the marker files, the cgroup of the init process and the mounts in order.
+func ContainerRuntime() string {
+	switch env := os.Getenv("container"); env {
+	case "":
+	case "oci":
+		return ContainerRuntimeUnknown
+	default:
+		return env
+	}
+	if IsExist(path.Join(fsRoot, ".dockerenv")) {
+		return ContainerRuntimeDocker
+	}
+	if IsExist(path.Join(fsRoot, "run/.containerenv")) {
+		return ContainerRuntimePodman
+	}
+	cgroups := readProcFile("1/cgroup")
+	if runtime := matchRuntime(cgroups); runtime != "" {
+		return runtime
+	}
+	// the cgroup namespace hides the paths on cgroup v2, the overlay root mount still tells the runtime
+	if runtime := matchRuntime(rootMount()); runtime != "" {
+		return runtime
+	}
+	if strings.Contains(cgroups, "kubepods") || os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		return ContainerRuntimeUnknown
+	}
+	return ""
+}
+
+func matchRuntime(content string) string {
+	for _, marker := range runtimeMarkers {
+		if strings.Contains(content, marker.marker) {
+			return marker.runtime
+		}
+	}
+	return ""
+}
+
+// rootMount returns the line of the root mount in /proc/self/mountinfo
+func rootMount() string {
+	for _, line := range strings.Split(readProcFile("self/mountinfo"), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 4 && fields[4] == "/" {
+			return line
+		}
+	}
+	return ""
+}
+
+func readProcFile(name string) string {
+	bytes, err := os.ReadFile(path.Join(procRoot, name))
+	if err != nil {
+		return ""
+	}
+	return string(bytes)
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"testing"
+)
+
+func TestContainerRuntime(t *testing.T) {
+	hostMountinfo := "22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw\n"
+	tests := []struct {
+		name           string
+		env            map[string]string
+		files          map[string]string
+		want           string
+		wantKubernetes bool
+	}{
+		{name: "host", files: map[string]string{"proc/1/cgroup": "0::/init.scope\n", "proc/self/mountinfo": hostMountinfo}},
+		{name: "container env", env: map[string]string{"container": "lxc"}, want: ContainerRuntimeLXC},
+		{name: "docker marker", files: map[string]string{".dockerenv": ""}, want: ContainerRuntimeDocker},
+		{name: "podman marker", files: map[string]string{"run/.containerenv": ""}, want: ContainerRuntimePodman},
+		{name: "docker cgroup", files: map[string]string{"proc/1/cgroup": "4:memory:/docker/abc\n"}, want: ContainerRuntimeDocker},
+		{
+			name:           "containerd under kubernetes",
+			files:          map[string]string{"proc/1/cgroup": "4:memory:/kubepods/burstable/pod1/cri-containerd-abc.scope\n"},
+			want:           ContainerRuntimeContainerd,
+			wantKubernetes: true,
+		},
+		{
+			name: "docker mount on cgroup v2",
+			files: map[string]string{
+				"proc/1/cgroup":       "0::/\n",
+				"proc/self/mountinfo": "600 500 0:50 / / rw - overlay overlay rw,lowerdir=/var/lib/docker/overlay2/l/A\n",
+			},
+			want: ContainerRuntimeDocker,
+		},
+		{
+			name:           "unknown runtime in kubernetes",
+			env:            map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1"},
+			files:          map[string]string{"proc/self/mountinfo": hostMountinfo},
+			want:           ContainerRuntimeUnknown,
+			wantKubernetes: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("container", "")
+			t.Setenv("KUBERNETES_SERVICE_HOST", "")
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			root := fakeProcFS(t, tt.files)
+			oldRoot := fsRoot
+			fsRoot = root
+			defer func() { fsRoot = oldRoot }()
+			if got := ContainerRuntime(); got != tt.want {
+				t.Errorf("ContainerRuntime() = %q, want %q", got, tt.want)
+			}
+			if got := InContainer(); got != (tt.want != "") {
+				t.Errorf("InContainer() = %t, want %t", got, tt.want != "")
+			}
+			if got := InKubernetes(); got != tt.wantKubernetes {
+				t.Errorf("InKubernetes() = %t, want %t", got, tt.wantKubernetes)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+// DiskUsageStat is the usage of the filesystem containing the path
+type DiskUsageStat struct {
+	Path  string `json:"path"`
+	Total uint64 `json:"total"`
+	// Free is all the free bytes, including the ones reserved for root
+	Free uint64 `json:"free"`
+	// Available is the free bytes available to the unprivileged users
+	Available uint64 `json:"available"`
+	Used      uint64 `json:"used"`
+	// UsedPercent is the used percent seen by the unprivileged users, the same as df
+	UsedPercent float64 `json:"usedPercent"`
+	// The inode stats are zero if the filesystem does not report them, such as on windows
+	InodesTotal uint64 `json:"inodesTotal"`
+	InodesFree  uint64 `json:"inodesFree"`
+	InodesUsed  uint64 `json:"inodesUsed"`
+}
+
+// DiskUsage returns the usage of the filesystem containing the path
+func DiskUsage(path string) (*DiskUsageStat, error) {
+	usage, err := diskUsage(path)
+	if err != nil {
+		return nil, err
+	}
+	usage.Path = path
+	if usage.Total >= usage.Free {
+		usage.Used = usage.Total - usage.Free
+	}
+	if usage.InodesTotal >= usage.InodesFree {
+		usage.InodesUsed = usage.InodesTotal - usage.InodesFree
+	}
+	if capacity := usage.Used + usage.Available; capacity > 0 {
+		usage.UsedPercent = float64(usage.Used) / float64(capacity) * 100
+	}
+	return usage, nil
+}
+
+// FillSize returns the bytes to write to make the used percent reach the percent, it's zero if the usage is
+// already above the percent, and never more than the available bytes
+func (usage *DiskUsageStat) FillSize(percent float64) uint64 {
+	target := percent / 100 * float64(usage.Used+usage.Available)
+	if target <= float64(usage.Used) {
+		return 0
+	}
+	size := uint64(target) - usage.Used
+	if size > usage.Available {
+		return usage.Available
+	}
+	return size
+}
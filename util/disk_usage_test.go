@@ -0,0 +1,53 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"testing"
+)
+
+func TestDiskUsage(t *testing.T) {
+	usage, err := DiskUsage(t.TempDir())
+	if err != nil {
+		t.Fatalf("DiskUsage() failed, %v", err)
+	}
+	if usage.Total == 0 || usage.Used+usage.Free != usage.Total || usage.Available > usage.Free {
+		t.Errorf("DiskUsage() = %+v, want consistent stats", usage)
+	}
+	if usage.UsedPercent < 0 || usage.UsedPercent > 100 {
+		t.Errorf("UsedPercent = %f, want in [0, 100]", usage.UsedPercent)
+	}
+}
+
+func TestFillSize(t *testing.T) {
+	usage := &DiskUsageStat{Used: 400, Available: 600}
+	tests := []struct {
+		percent float64
+		want    uint64
+	}{
+		{percent: 80, want: 400},
+		{percent: 40, want: 0},
+		{percent: 10, want: 0},
+		{percent: 100, want: 600},
+		{percent: 120, want: 600},
+	}
+	for _, tt := range tests {
+		if got := usage.FillSize(tt.percent); got != tt.want {
+			t.Errorf("FillSize(%v) = %d, want %d", tt.percent, got, tt.want)
+		}
+	}
+}
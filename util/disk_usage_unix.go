@@ -0,0 +1,39 @@
+//go:build !windows
+// +build !windows
+
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"syscall"
+)
+
+func diskUsage(path string) (*DiskUsageStat, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return nil, err
+	}
+	blockSize := uint64(stat.Bsize)
+	return &DiskUsageStat{
+		Total:       uint64(stat.Blocks) * blockSize,
+		Free:        uint64(stat.Bfree) * blockSize,
+		Available:   uint64(stat.Bavail) * blockSize,
+		InodesTotal: uint64(stat.Files),
+		InodesFree:  uint64(stat.Ffree),
+	}, nil
+}
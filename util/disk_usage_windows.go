@@ -0,0 +1,36 @@
+//go:build windows
+// +build windows
+
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+func diskUsage(path string) (*DiskUsageStat, error) {
+	name, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+	usage := &DiskUsageStat{}
+	if err := windows.GetDiskFreeSpaceEx(name, &usage.Available, &usage.Total, &usage.Free); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
@@ -0,0 +1,276 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultDownloadTimeout is the default timeout of connecting and waiting for the response headers
+const DefaultDownloadTimeout = 30 * time.Second
+
+// downloadPartSuffix is the suffix of the partial file, the download is resumed from it
+const downloadPartSuffix = ".part"
+
+// downloadValidatorSuffix is the suffix of the file saving the ETag or Last-Modified of the partial file, it's
+// sent by If-Range when resuming, so the content changed on the server is not appended to the stale part
+const downloadValidatorSuffix = ".part.validator"
+
+// errDownloadRestart means the part file can not be verified and the download restarts from the beginning
+var errDownloadRestart = errors.New("restart the download")
+
+// DownloadProgress is called after each chunk written, total is -1 if the server does not tell the length
+type DownloadProgress func(written, total int64)
+
+// DownloadOption customizes the Download
+type DownloadOption func(options *downloadOptions)
+
+type downloadOptions struct {
+	checksumAlgorithm string
+	checksum          string
+	proxy             string
+	timeout           time.Duration
+	progress          DownloadProgress
+	resume            bool
+}
+
+// DownloadChecksum verifies the downloaded file by the hex digest, the algorithm is one of md5, sha1, sha256
+// and sha512. The file is removed if the checksum does not match.
+func DownloadChecksum(algorithm, digest string) DownloadOption {
+	return func(options *downloadOptions) {
+		options.checksumAlgorithm = strings.ToLower(algorithm)
+		options.checksum = strings.ToLower(digest)
+	}
+}
+
+// DownloadProxy downloads via the proxy url, the proxy from the environment variables is used by default
+func DownloadProxy(proxy string) DownloadOption {
+	return func(options *downloadOptions) {
+		options.proxy = proxy
+	}
+}
+
+// DownloadTimeout sets the timeout of connecting and waiting for the response headers, the body transfer is
+// bounded by the ctx only, so large files are not interrupted
+func DownloadTimeout(timeout time.Duration) DownloadOption {
+	return func(options *downloadOptions) {
+		options.timeout = timeout
+	}
+}
+
+// DownloadWithProgress reports the progress of the download
+func DownloadWithProgress(progress DownloadProgress) DownloadOption {
+	return func(options *downloadOptions) {
+		options.progress = progress
+	}
+}
+
+// DownloadNoResume always downloads from the beginning, the partial file left by the last download is discarded
+func DownloadNoResume() DownloadOption {
+	return func(options *downloadOptions) {
+		options.resume = false
+	}
+}
+
+// Download fetches the url to the dest file. The content is written to dest.part first and renamed to dest
+// after the checksum verified, so dest never contains the partial content. If dest.part exists, the download
+// is resumed by the range request with If-Range of the ETag or Last-Modified saved at the start, and restarted
+// if the server does not support the range or the content changed. The part without the validator is only
+// resumed if the checksum is specified to verify it.
+func Download(ctx context.Context, rawURL, dest string, options ...DownloadOption) error {
+	downloadOptions := &downloadOptions{timeout: DefaultDownloadTimeout, resume: true}
+	for _, option := range options {
+		option(downloadOptions)
+	}
+	if downloadOptions.checksumAlgorithm != "" {
+		if _, err := NewHasher(downloadOptions.checksumAlgorithm); err != nil {
+			return err
+		}
+	}
+	client, err := downloadClient(downloadOptions)
+	if err != nil {
+		return err
+	}
+	part := dest + downloadPartSuffix
+	validatorFile := dest + downloadValidatorSuffix
+	verifiable := downloadOptions.checksumAlgorithm != ""
+	validator := ""
+	if bytes, err := os.ReadFile(validatorFile); err == nil {
+		validator = strings.TrimSpace(string(bytes))
+	}
+	if !downloadOptions.resume || validator == "" && !verifiable {
+		if err := removeDownloadPart(part, validatorFile); err != nil {
+			return err
+		}
+		validator = ""
+	}
+	err = fetch(ctx, client, rawURL, part, validatorFile, validator, verifiable, downloadOptions.progress)
+	if err == errDownloadRestart {
+		if err := removeDownloadPart(part, validatorFile); err != nil {
+			return err
+		}
+		err = fetch(ctx, client, rawURL, part, validatorFile, "", verifiable, downloadOptions.progress)
+	}
+	if err != nil {
+		return err
+	}
+	if verifiable {
+		err := VerifyFileChecksum(downloadOptions.checksumAlgorithm, part, downloadOptions.checksum)
+		if err != nil {
+			removeDownloadPart(part, validatorFile)
+			return fmt.Errorf("download %s failed, %v", rawURL, err)
+		}
+	}
+	if err := os.Rename(part, dest); err != nil {
+		return err
+	}
+	os.Remove(validatorFile)
+	return nil
+}
+
+func removeDownloadPart(part, validatorFile string) error {
+	for _, file := range []string{part, validatorFile} {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// responseValidator returns the strong ETag or the Last-Modified of the response, the weak ETag can not be used
+// by If-Range
+func responseValidator(response *http.Response) string {
+	if etag := response.Header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return response.Header.Get("Last-Modified")
+}
+
+func downloadClient(options *downloadOptions) (*http.Client, error) {
+	proxy := http.ProxyFromEnvironment
+	if options.proxy != "" {
+		proxyURL, err := url.Parse(options.proxy)
+		if err != nil {
+			return nil, fmt.Errorf("illegal proxy %s, %v", options.proxy, err)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:                 proxy,
+			DialContext:           (&net.Dialer{Timeout: options.timeout}).DialContext,
+			TLSHandshakeTimeout:   options.timeout,
+			ResponseHeaderTimeout: options.timeout,
+		},
+	}, nil
+}
+
+// fetch appends the rest of the content to the part file, the validator of the content is saved to the
+// validator file when the download starts from the beginning
+func fetch(ctx context.Context, client *http.Client, rawURL, part, validatorFile, validator string, verifiable bool,
+	progress DownloadProgress) error {
+	file, err := os.OpenFile(part, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	offset, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		request.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if validator != "" {
+			request.Header.Set("If-Range", validator)
+		}
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	total := response.ContentLength
+	switch {
+	case response.StatusCode == http.StatusPartialContent && offset > 0:
+		if !strings.HasPrefix(response.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)) {
+			return fmt.Errorf("download %s failed, unexpected content range: %s", rawURL,
+				response.Header.Get("Content-Range"))
+		}
+		if total >= 0 {
+			total += offset
+		}
+	case response.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// the part file is complete or stale, the checksum decides, or restart if nothing to verify against
+		if !verifiable {
+			return errDownloadRestart
+		}
+		return nil
+	case response.StatusCode >= 200 && response.StatusCode < 300:
+		// the server ignores the range or the content changed, restart from the beginning
+		if err := file.Truncate(0); err != nil {
+			return err
+		}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		offset = 0
+		if err := os.WriteFile(validatorFile, []byte(responseValidator(response)), 0644); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("download %s failed, status: %s", rawURL, response.Status)
+	}
+	var writer io.Writer = file
+	if progress != nil {
+		writer = &progressWriter{writer: file, written: offset, total: total, progress: progress}
+	}
+	written, err := io.Copy(writer, response.Body)
+	if err != nil {
+		return fmt.Errorf("download %s failed, %v", rawURL, err)
+	}
+	if total >= 0 && offset+written != total {
+		return fmt.Errorf("download %s failed, got %d bytes, want %d", rawURL, offset+written, total)
+	}
+	return file.Close()
+}
+
+type progressWriter struct {
+	writer   io.Writer
+	written  int64
+	total    int64
+	progress DownloadProgress
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.written += int64(n)
+	w.progress(w.written, w.total)
+	return n, err
+}
@@ -0,0 +1,188 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDownload(t *testing.T) {
+	content := []byte(strings.Repeat("chaosblade", 1000))
+	sum := sha256.Sum256(content)
+	digest := hex.EncodeToString(sum[:])
+	var ranges atomic.Value
+	ranges.Store("")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges.Store(r.Header.Get("Range"))
+		http.ServeContent(w, r, "tool", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+
+	t.Run("verified", func(t *testing.T) {
+		dest := filepath.Join(t.TempDir(), "tool")
+		var written, total int64
+		err := Download(context.Background(), server.URL, dest, DownloadChecksum("SHA256", digest),
+			DownloadWithProgress(func(w, n int64) { written, total = w, n }))
+		if err != nil {
+			t.Fatalf("Download() failed, %v", err)
+		}
+		if got, _ := os.ReadFile(dest); !bytes.Equal(got, content) {
+			t.Errorf("Download() wrote %d bytes, want %d", len(got), len(content))
+		}
+		if written != int64(len(content)) || total != int64(len(content)) {
+			t.Errorf("progress = %d/%d, want %d/%d", written, total, len(content), len(content))
+		}
+		if IsExist(dest + downloadPartSuffix) {
+			t.Errorf("the part file is left")
+		}
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		dest := filepath.Join(t.TempDir(), "tool")
+		if err := os.WriteFile(dest+downloadPartSuffix, content[:4000], 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := Download(context.Background(), server.URL, dest, DownloadChecksum("sha256", digest)); err != nil {
+			t.Fatalf("Download() failed, %v", err)
+		}
+		if got := ranges.Load().(string); got != "bytes=4000-" {
+			t.Errorf("Range = %q, want bytes=4000-", got)
+		}
+		if got, _ := os.ReadFile(dest); !bytes.Equal(got, content) {
+			t.Errorf("Download() resumed to %d bytes, want %d", len(got), len(content))
+		}
+	})
+
+	t.Run("no resume", func(t *testing.T) {
+		dest := filepath.Join(t.TempDir(), "tool")
+		if err := os.WriteFile(dest+downloadPartSuffix, []byte("stale"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := Download(context.Background(), server.URL, dest, DownloadNoResume()); err != nil {
+			t.Fatalf("Download() failed, %v", err)
+		}
+		if got := ranges.Load().(string); got != "" {
+			t.Errorf("Range = %q, want none", got)
+		}
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		dest := filepath.Join(t.TempDir(), "tool")
+		err := Download(context.Background(), server.URL, dest, DownloadChecksum("sha256", strings.Repeat("0", 64)))
+		if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+			t.Fatalf("Download() error = %v, want checksum mismatch", err)
+		}
+		if IsExist(dest) || IsExist(dest+downloadPartSuffix) {
+			t.Errorf("the mismatched file is left")
+		}
+	})
+
+	t.Run("status", func(t *testing.T) {
+		err := Download(context.Background(), server.URL+"/missing", filepath.Join(t.TempDir(), "tool"),
+			DownloadChecksum("crc32", digest))
+		if err == nil || !strings.Contains(err.Error(), "unsupported checksum algorithm") {
+			t.Errorf("Download() error = %v, want unsupported algorithm", err)
+		}
+		notFound := httptest.NewServer(http.NotFoundHandler())
+		defer notFound.Close()
+		err = Download(context.Background(), notFound.URL, filepath.Join(t.TempDir(), "tool"))
+		if err == nil || !strings.Contains(err.Error(), "404") {
+			t.Errorf("Download() error = %v, want 404", err)
+		}
+	})
+
+	t.Run("unverifiable part", func(t *testing.T) {
+		dest := filepath.Join(t.TempDir(), "tool")
+		if err := os.WriteFile(dest+downloadPartSuffix, []byte("stale"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := Download(context.Background(), server.URL, dest); err != nil {
+			t.Fatalf("Download() failed, %v", err)
+		}
+		if got := ranges.Load().(string); got != "" {
+			t.Errorf("Range = %q, want none without the validator and the checksum", got)
+		}
+		if got, _ := os.ReadFile(dest); !bytes.Equal(got, content) {
+			t.Errorf("Download() wrote %d bytes, want %d", len(got), len(content))
+		}
+	})
+}
+
+func TestDownloadIfRange(t *testing.T) {
+	content := []byte(strings.Repeat("chaosblade", 1000))
+	var ifRange atomic.Value
+	ifRange.Store("")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifRange.Store(r.Header.Get("If-Range"))
+		w.Header().Set("ETag", `"v2"`)
+		http.ServeContent(w, r, "tool", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		part        []byte
+		validator   string
+		wantIfRange string
+	}{
+		{name: "resumed", part: content[:4000], validator: `"v2"`, wantIfRange: `"v2"`},
+		{name: "changed", part: []byte(strings.Repeat("x", 4000)), validator: `"v1"`, wantIfRange: `"v1"`},
+		// restarted without the range since there is no checksum to verify the part
+		{name: "range not satisfiable", part: append(append([]byte{}, content...), 'x'), validator: `"v2"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dest := filepath.Join(t.TempDir(), "tool")
+			os.WriteFile(dest+downloadPartSuffix, tt.part, 0644)
+			os.WriteFile(dest+downloadValidatorSuffix, []byte(tt.validator), 0644)
+			if err := Download(context.Background(), server.URL, dest); err != nil {
+				t.Fatalf("Download() failed, %v", err)
+			}
+			if got, _ := os.ReadFile(dest); !bytes.Equal(got, content) {
+				t.Errorf("Download() wrote %d bytes, want %d", len(got), len(content))
+			}
+			if got := ifRange.Load().(string); got != tt.wantIfRange {
+				t.Errorf("If-Range = %q, want %q", got, tt.wantIfRange)
+			}
+			if IsExist(dest + downloadValidatorSuffix) {
+				t.Errorf("the validator file is left")
+			}
+		})
+	}
+
+	// the validator is saved at the start, so the interrupted download is resumed by If-Range
+	dest := filepath.Join(t.TempDir(), "tool")
+	part := dest + downloadPartSuffix
+	if err := fetch(context.Background(), http.DefaultClient, server.URL, part, dest+downloadValidatorSuffix, "",
+		false, nil); err != nil {
+		t.Fatalf("fetch() failed, %v", err)
+	}
+	if got, _ := os.ReadFile(dest + downloadValidatorSuffix); string(got) != `"v2"` {
+		t.Errorf("the saved validator = %q, want \"v2\"", got)
+	}
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EnvSnapshot is the values of the environment variables at a moment, it's saved to the file by Save, so the
+// experiment mutating the variables restores them on destroy even if the agent restarted.
+type EnvSnapshot struct {
+	// Vars are the variables set at the snapshot time
+	Vars map[string]string `json:"vars"`
+	// Unset are the requested variables absent at the snapshot time, they are unset on restoring
+	Unset []string `json:"unset,omitempty"`
+	// Full is true if all variables are snapshotted, the variables set after the snapshot are unset on restoring
+	Full bool `json:"full,omitempty"`
+	// Time is when the snapshot is taken
+	Time time.Time `json:"time"`
+}
+
+// SnapshotEnv returns the snapshot of the variables of the keys, or all variables if no key is specified
+func SnapshotEnv(keys ...string) *EnvSnapshot {
+	snapshot := &EnvSnapshot{Vars: make(map[string]string), Time: time.Now()}
+	if len(keys) == 0 {
+		snapshot.Full = true
+		for _, item := range os.Environ() {
+			if key, value, ok := strings.Cut(item, "="); ok && key != "" {
+				snapshot.Vars[key] = value
+			}
+		}
+		return snapshot
+	}
+	for _, key := range keys {
+		if value, ok := os.LookupEnv(key); ok {
+			snapshot.Vars[key] = value
+		} else {
+			snapshot.Unset = append(snapshot.Unset, key)
+		}
+	}
+	return snapshot
+}
+
+// RestoreEnv restores the variables to the snapshot, it returns the first error and keeps restoring the others
+func RestoreEnv(snapshot *EnvSnapshot) error {
+	var firstErr error
+	record := func(err error) {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if snapshot.Full {
+		for _, item := range os.Environ() {
+			if key, _, ok := strings.Cut(item, "="); ok && key != "" {
+				if _, exists := snapshot.Vars[key]; !exists {
+					record(os.Unsetenv(key))
+				}
+			}
+		}
+	}
+	keys := make([]string, 0, len(snapshot.Vars))
+	for key := range snapshot.Vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		record(os.Setenv(key, snapshot.Vars[key]))
+	}
+	for _, key := range snapshot.Unset {
+		record(os.Unsetenv(key))
+	}
+	return firstErr
+}
+
+// Save writes the snapshot to the file atomically, the file is only readable by the current user because the
+// variables may contain the secrets
+func (snapshot *EnvSnapshot) Save(path string) error {
+	bytes, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	return AtomicWriteFile(path, bytes, 0600)
+}
+
+// LoadEnvSnapshot reads the snapshot saved by Save
+func LoadEnvSnapshot(path string) (*EnvSnapshot, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &EnvSnapshot{}
+	if err := json.Unmarshal(bytes, snapshot); err != nil {
+		return nil, fmt.Errorf("illegal env snapshot %s, %v", path, err)
+	}
+	if snapshot.Vars == nil {
+		snapshot.Vars = make(map[string]string)
+	}
+	return snapshot, nil
+}
+
+// RestoreEnvFile restores the variables to the snapshot saved in the file and removes the file, the file is
+// kept if restoring fails, so it can be retried
+func RestoreEnvFile(path string) error {
+	snapshot, err := LoadEnvSnapshot(path)
+	if err != nil {
+		return err
+	}
+	if err := RestoreEnv(snapshot); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSnapshotEnv(t *testing.T) {
+	t.Setenv("BLADE_TEST_KEPT", "kept")
+	t.Setenv("BLADE_TEST_ADDED", "")
+	os.Unsetenv("BLADE_TEST_ADDED")
+
+	snapshot := SnapshotEnv("BLADE_TEST_KEPT", "BLADE_TEST_ADDED")
+	path := filepath.Join(t.TempDir(), "env.json")
+	if err := snapshot.Save(path); err != nil {
+		t.Fatalf("Save() failed, %v", err)
+	}
+	os.Setenv("BLADE_TEST_KEPT", "changed")
+	os.Setenv("BLADE_TEST_ADDED", "added")
+	if err := RestoreEnvFile(path); err != nil {
+		t.Fatalf("RestoreEnvFile() failed, %v", err)
+	}
+	if got := os.Getenv("BLADE_TEST_KEPT"); got != "kept" {
+		t.Errorf("BLADE_TEST_KEPT = %q, want kept", got)
+	}
+	if _, ok := os.LookupEnv("BLADE_TEST_ADDED"); ok {
+		t.Errorf("BLADE_TEST_ADDED is not unset")
+	}
+	if IsExist(path) {
+		t.Errorf("the snapshot file is not removed after restoring")
+	}
+	if _, err := LoadEnvSnapshot(path); err == nil {
+		t.Errorf("LoadEnvSnapshot() of the absent file succeeded")
+	}
+}
+
+func TestSnapshotEnvFull(t *testing.T) {
+	t.Setenv("BLADE_TEST_FULL", "before")
+	snapshot := SnapshotEnv()
+	t.Setenv("BLADE_TEST_FULL", "after")
+	t.Setenv("BLADE_TEST_NEW", "new")
+	if err := RestoreEnv(snapshot); err != nil {
+		t.Fatalf("RestoreEnv() failed, %v", err)
+	}
+	if got := os.Getenv("BLADE_TEST_FULL"); got != "before" {
+		t.Errorf("BLADE_TEST_FULL = %q, want before", got)
+	}
+	if _, ok := os.LookupEnv("BLADE_TEST_NEW"); ok {
+		t.Errorf("BLADE_TEST_NEW set after the full snapshot is not unset")
+	}
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// fileLockRetryInterval is the interval of retrying to acquire the lock by Lock
+const fileLockRetryInterval = 50 * time.Millisecond
+
+// errLockWouldBlock is returned by tryLockFile if the file is locked by others
+var errLockWouldBlock = errors.New("the file is locked")
+
+// FileLock is the exclusive lock of the file shared by the processes, such as the concurrent blade invocations.
+// It is implemented by flock on unix and LockFileEx on windows. The lock file is created if absent and is
+// kept after unlocking, removing it would let the others lock a different file.
+type FileLock struct {
+	path string
+	lock sync.Mutex
+	file *os.File
+}
+
+// NewFileLock returns the lock of the file, the lock is not acquired until TryLock or Lock is called
+func NewFileLock(path string) *FileLock {
+	return &FileLock{path: path}
+}
+
+// Path returns the lock file path
+func (l *FileLock) Path() string {
+	return l.path
+}
+
+// TryLock acquires the lock without waiting, it returns false if the lock is held by others
+func (l *FileLock) TryLock() (bool, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.file != nil {
+		return false, fmt.Errorf("the lock of %s is already held", l.path)
+	}
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return false, err
+	}
+	if err := tryLockFile(file); err != nil {
+		file.Close()
+		if err == errLockWouldBlock {
+			return false, nil
+		}
+		return false, err
+	}
+	l.file = file
+	return true, nil
+}
+
+// Lock acquires the lock, it waits until the lock is released by others or the ctx is done
+func (l *FileLock) Lock(ctx context.Context) error {
+	ticker := time.NewTicker(fileLockRetryInterval)
+	defer ticker.Stop()
+	for {
+		locked, err := l.TryLock()
+		if err != nil || locked {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("lock %s failed, %v", l.path, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// Unlock releases the lock, it returns error if the lock is not held
+func (l *FileLock) Unlock() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.file == nil {
+		return fmt.Errorf("the lock of %s is not held", l.path)
+	}
+	err := unlockFile(l.file)
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+	l.file = nil
+	return err
+}
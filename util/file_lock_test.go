@@ -0,0 +1,57 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blade.lock")
+	first, second := NewFileLock(path), NewFileLock(path)
+
+	if locked, err := first.TryLock(); err != nil || !locked {
+		t.Fatalf("first TryLock() = %t, %v, want true", locked, err)
+	}
+	if locked, err := first.TryLock(); err == nil || locked {
+		t.Errorf("TryLock() of the held lock = %t, %v, want error", locked, err)
+	}
+	if locked, err := second.TryLock(); err != nil || locked {
+		t.Errorf("second TryLock() = %t, %v, want false", locked, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := second.Lock(ctx); err == nil {
+		t.Errorf("second Lock() succeeded while the lock is held")
+	}
+
+	if err := first.Unlock(); err != nil {
+		t.Fatalf("Unlock() failed, %v", err)
+	}
+	if err := first.Unlock(); err == nil {
+		t.Errorf("Unlock() of the released lock succeeded")
+	}
+	if err := second.Lock(context.Background()); err != nil {
+		t.Fatalf("second Lock() after unlocking failed, %v", err)
+	}
+	if err := second.Unlock(); err != nil {
+		t.Errorf("second Unlock() failed, %v", err)
+	}
+}
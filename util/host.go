@@ -0,0 +1,83 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"context"
+	"time"
+
+	"github.com/shirou/gopsutil/cpu"
+	"github.com/shirou/gopsutil/host"
+	"github.com/shirou/gopsutil/mem"
+)
+
+// HostInformation is the characteristics of the host, used to gate the actions in the executors and preflight checks
+type HostInformation struct {
+	Hostname string `json:"hostname"`
+	// OS is the operating system, such as linux or darwin
+	OS string `json:"os"`
+	// Distro is the distribution, such as centos or ubuntu, and DistroFamily is such as rhel or debian
+	Distro        string `json:"distro,omitempty"`
+	DistroFamily  string `json:"distroFamily,omitempty"`
+	DistroVersion string `json:"distroVersion,omitempty"`
+	KernelVersion string `json:"kernelVersion,omitempty"`
+	Arch          string `json:"arch,omitempty"`
+	// Virtualization is the virtualization system, such as kvm or docker, empty on the bare metal
+	Virtualization     string `json:"virtualization,omitempty"`
+	VirtualizationRole string `json:"virtualizationRole,omitempty"`
+	// CPUCount is the count of the logical cpus
+	CPUCount int    `json:"cpuCount"`
+	CPUModel string `json:"cpuModel,omitempty"`
+	// TotalMemory is the total memory in bytes
+	TotalMemory uint64        `json:"totalMemory"`
+	Uptime      time.Duration `json:"uptime"`
+}
+
+// HostInfo collects the information of the current host
+func HostInfo(ctx context.Context) (*HostInformation, error) {
+	info, err := host.InfoWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cpuCount, err := cpu.CountsWithContext(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	memory, err := mem.VirtualMemoryWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hostInfo := &HostInformation{
+		Hostname:           info.Hostname,
+		OS:                 info.OS,
+		Distro:             info.Platform,
+		DistroFamily:       info.PlatformFamily,
+		DistroVersion:      info.PlatformVersion,
+		KernelVersion:      info.KernelVersion,
+		Arch:               info.KernelArch,
+		Virtualization:     info.VirtualizationSystem,
+		VirtualizationRole: info.VirtualizationRole,
+		CPUCount:           cpuCount,
+		TotalMemory:        memory.Total,
+		Uptime:             time.Duration(info.Uptime) * time.Second,
+	}
+	// the model is absent on some architectures, such as arm, it's not an error
+	if cpus, err := cpu.InfoWithContext(ctx); err == nil && len(cpus) > 0 {
+		hostInfo.CPUModel = cpus[0].ModelName
+	}
+	return hostInfo, nil
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// The host identity is resolved once and cached, so the responses and the logs are stamped with the same host
+// even if the network changes during the experiment. It can be overridden by the environment variables
+// CHAOSBLADE_HOSTNAME and CHAOSBLADE_HOST_IPS, and the ips of the interface CHAOSBLADE_HOST_INTERFACE are
+// preferred.
+var hostIdentity struct {
+	lock     sync.Mutex
+	hostname string
+	fqdn     string
+	ips      []string
+}
+
+// LocalHostname returns the hostname of the host
+func LocalHostname() string {
+	hostIdentity.lock.Lock()
+	defer hostIdentity.lock.Unlock()
+	if hostIdentity.hostname == "" {
+		hostIdentity.hostname = resolveHostname()
+	}
+	return hostIdentity.hostname
+}
+
+// LocalFQDN returns the fully qualified domain name of the host, or the hostname if it can't be resolved
+func LocalFQDN() string {
+	hostname := LocalHostname()
+	hostIdentity.lock.Lock()
+	defer hostIdentity.lock.Unlock()
+	if hostIdentity.fqdn == "" {
+		hostIdentity.fqdn = hostname
+		if cname, err := net.LookupCNAME(hostname); err == nil && strings.TrimSuffix(cname, ".") != "" {
+			hostIdentity.fqdn = strings.TrimSuffix(cname, ".")
+		}
+	}
+	return hostIdentity.fqdn
+}
+
+// LocalIPs returns the ips of the host in the preference order: the ips of the CHAOSBLADE_HOST_INTERFACE
+// interface, the default route interface, and the other up interfaces by the index. The ipv4 addresses are
+// before the ipv6 ones of the same interface, and the loopback and the link-local addresses are only returned
+// if no other address exists.
+func LocalIPs() []string {
+	hostIdentity.lock.Lock()
+	defer hostIdentity.lock.Unlock()
+	if hostIdentity.ips == nil {
+		hostIdentity.ips = resolveHostIPs()
+	}
+	return append([]string{}, hostIdentity.ips...)
+}
+
+// LocalIP returns the preferred ip of the host, or empty if the host has no ip
+func LocalIP() string {
+	ips := LocalIPs()
+	if len(ips) == 0 {
+		return ""
+	}
+	return ips[0]
+}
+
+// ResetHostIdentity clears the cached host identity, it's resolved again on the next call
+func ResetHostIdentity() {
+	hostIdentity.lock.Lock()
+	defer hostIdentity.lock.Unlock()
+	hostIdentity.hostname = ""
+	hostIdentity.fqdn = ""
+	hostIdentity.ips = nil
+}
+
+func resolveHostname() string {
+	if hostname := strings.TrimSpace(os.Getenv(spec.HostnameEnv)); hostname != "" {
+		return hostname
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		return "localhost"
+	}
+	return hostname
+}
+
+func resolveHostIPs() []string {
+	if value := strings.TrimSpace(os.Getenv(spec.HostIPsEnv)); value != "" {
+		ips := make([]string, 0)
+		for _, item := range strings.Split(value, ",") {
+			if ip := net.ParseIP(strings.TrimSpace(item)); ip != nil {
+				ips = append(ips, ip.String())
+			}
+		}
+		if len(ips) > 0 {
+			return ips
+		}
+	}
+	interfaces, err := ListInterfaces()
+	if err != nil {
+		return []string{}
+	}
+	return preferredIPs(interfaces, strings.TrimSpace(os.Getenv(spec.HostInterfaceEnv)))
+}
+
+// preferredIPs returns the ips of the interfaces in the preference order of LocalIPs
+func preferredIPs(interfaces []NetInterface, preferred string) []string {
+	candidates := make([]NetInterface, 0, len(interfaces))
+	for _, iface := range interfaces {
+		if iface.Up || iface.Name == preferred {
+			candidates = append(candidates, iface)
+		}
+	}
+	rank := func(iface NetInterface) int {
+		switch {
+		case iface.Name == preferred:
+			return 0
+		case iface.Default:
+			return 1
+		case iface.Loopback:
+			return 3
+		}
+		return 2
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return rank(candidates[i]) < rank(candidates[j]) })
+
+	ips, fallbacks := make([]string, 0), make([]string, 0)
+	for _, iface := range candidates {
+		var v4, v6 []string
+		for _, address := range iface.Addresses {
+			ip, _, err := net.ParseCIDR(address)
+			if err != nil {
+				ip = net.ParseIP(address)
+			}
+			if ip == nil {
+				continue
+			}
+			if ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+				fallbacks = append(fallbacks, ip.String())
+			} else if ip.To4() != nil {
+				v4 = append(v4, ip.String())
+			} else {
+				v6 = append(v6, ip.String())
+			}
+		}
+		ips = append(append(ips, v4...), v6...)
+	}
+	if len(ips) == 0 {
+		return fallbacks
+	}
+	return ips
+}
+
+var hostIdentityHookOnce sync.Once
+
+// AddHostIdentityHook stamps the logs with the host field of LocalHostname, it's added once by InitLog
+func AddHostIdentityHook() {
+	hostIdentityHookOnce.Do(func() {
+		logrus.AddHook(hostIdentityHook{})
+	})
+}
+
+type hostIdentityHook struct{}
+
+func (hostIdentityHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (hostIdentityHook) Fire(entry *logrus.Entry) error {
+	if _, ok := entry.Data[spec.MetadataHost]; !ok {
+		entry.Data[spec.MetadataHost] = LocalHostname()
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+func TestLocalHostIdentityOverride(t *testing.T) {
+	t.Setenv(spec.HostnameEnv, "blade-node-1")
+	t.Setenv(spec.HostIPsEnv, "10.0.0.5, illegal, fd00::5")
+	ResetHostIdentity()
+	defer ResetHostIdentity()
+
+	if got := LocalHostname(); got != "blade-node-1" {
+		t.Errorf("LocalHostname() = %s, want blade-node-1", got)
+	}
+	if got := LocalIPs(); !reflect.DeepEqual(got, []string{"10.0.0.5", "fd00::5"}) {
+		t.Errorf("LocalIPs() = %v, want [10.0.0.5 fd00::5]", got)
+	}
+	// the identity is cached until reset
+	t.Setenv(spec.HostnameEnv, "blade-node-2")
+	if got := LocalHostname(); got != "blade-node-1" {
+		t.Errorf("LocalHostname() = %s after the env changes, want the cached blade-node-1", got)
+	}
+	ResetHostIdentity()
+	if got := LocalHostname(); got != "blade-node-2" {
+		t.Errorf("LocalHostname() = %s after reset, want blade-node-2", got)
+	}
+}
+
+func TestPreferredIPs(t *testing.T) {
+	interfaces := []NetInterface{
+		{Name: "lo", Index: 1, Up: true, Loopback: true, Addresses: []string{"127.0.0.1/8", "::1/128"}},
+		{Name: "eth0", Index: 2, Up: true, Addresses: []string{"fe80::1/64", "fd00::2/64", "192.168.1.2/24"}},
+		{Name: "eth1", Index: 3, Up: true, Default: true, Addresses: []string{"10.0.0.3/16"}},
+		{Name: "eth2", Index: 4, Addresses: []string{"10.1.0.4/16"}},
+	}
+	if got := preferredIPs(interfaces, ""); !reflect.DeepEqual(got, []string{"10.0.0.3", "192.168.1.2", "fd00::2"}) {
+		t.Errorf("preferredIPs() = %v", got)
+	}
+	if got := preferredIPs(interfaces, "eth2"); got[0] != "10.1.0.4" {
+		t.Errorf("preferredIPs() with the preferred interface = %v", got)
+	}
+	if got := preferredIPs(interfaces[:1], ""); !reflect.DeepEqual(got, []string{"127.0.0.1", "::1"}) {
+		t.Errorf("preferredIPs() of the loopback only = %v", got)
+	}
+}
+
+func TestHostIdentityHook(t *testing.T) {
+	t.Setenv(spec.HostnameEnv, "blade-node-1")
+	ResetHostIdentity()
+	defer ResetHostIdentity()
+
+	logger := logrus.New()
+	var buffer bytes.Buffer
+	logger.SetOutput(&buffer)
+	logger.AddHook(hostIdentityHook{})
+	logger.Info("stamped")
+	if !strings.Contains(buffer.String(), "host=blade-node-1") {
+		t.Errorf("the log is not stamped with the host, %s", buffer.String())
+	}
+}
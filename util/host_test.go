@@ -0,0 +1,36 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"context"
+	"runtime"
+	"testing"
+)
+
+func TestHostInfo(t *testing.T) {
+	info, err := HostInfo(context.Background())
+	if err != nil {
+		t.Fatalf("HostInfo() failed, %v", err)
+	}
+	if info.OS != runtime.GOOS {
+		t.Errorf("OS = %s, want %s", info.OS, runtime.GOOS)
+	}
+	if info.CPUCount <= 0 || info.TotalMemory == 0 {
+		t.Errorf("CPUCount = %d, TotalMemory = %d, want positive", info.CPUCount, info.TotalMemory)
+	}
+}
@@ -0,0 +1,171 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NetInterface is the network interface of the host
+type NetInterface struct {
+	Name         string `json:"name"`
+	Index        int    `json:"index"`
+	MTU          int    `json:"mtu"`
+	HardwareAddr string `json:"hardwareAddr,omitempty"`
+	// Up is true if the interface is administratively up
+	Up       bool `json:"up"`
+	Loopback bool `json:"loopback"`
+	// Addresses are the ip addresses in the CIDR notation, such as 192.168.1.10/24
+	Addresses []string `json:"addresses,omitempty"`
+	// Default is true if the default route goes through the interface
+	Default bool `json:"default"`
+}
+
+// ListInterfaces returns the network interfaces sorted by the index
+func ListInterfaces() ([]NetInterface, error) {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	defaultName := defaultRouteInterface()
+	result := make([]NetInterface, 0, len(interfaces))
+	for _, iface := range interfaces {
+		netInterface := NetInterface{
+			Name:         iface.Name,
+			Index:        iface.Index,
+			MTU:          iface.MTU,
+			HardwareAddr: iface.HardwareAddr.String(),
+			Up:           iface.Flags&net.FlagUp != 0,
+			Loopback:     iface.Flags&net.FlagLoopback != 0,
+			Default:      iface.Name == defaultName,
+		}
+		if addrs, err := iface.Addrs(); err == nil {
+			for _, addr := range addrs {
+				netInterface.Addresses = append(netInterface.Addresses, addr.String())
+			}
+		}
+		result = append(result, netInterface)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Index < result[j].Index })
+	return result, nil
+}
+
+// GetInterface returns the interface by the name, the error lists the available interfaces to validate
+// the interface flags
+func GetInterface(name string) (*NetInterface, error) {
+	interfaces, err := ListInterfaces()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(interfaces))
+	for idx := range interfaces {
+		if interfaces[idx].Name == name {
+			return &interfaces[idx], nil
+		}
+		names = append(names, interfaces[idx].Name)
+	}
+	return nil, fmt.Errorf("interface %s not found, available: %s", name, strings.Join(names, ", "))
+}
+
+// DefaultInterface returns the interface of the default route, or the first up and non-loopback interface
+// with addresses if the default route is absent
+func DefaultInterface() (*NetInterface, error) {
+	interfaces, err := ListInterfaces()
+	if err != nil {
+		return nil, err
+	}
+	for idx := range interfaces {
+		if interfaces[idx].Default {
+			return &interfaces[idx], nil
+		}
+	}
+	for idx := range interfaces {
+		if interfaces[idx].Up && !interfaces[idx].Loopback && len(interfaces[idx].Addresses) > 0 {
+			return &interfaces[idx], nil
+		}
+	}
+	return nil, fmt.Errorf("default interface not found")
+}
+
+// defaultRouteInterface returns the interface name of the ipv4 default route, it reads /proc/net/route on
+// linux and falls back to the interface of the outbound address on the others
+func defaultRouteInterface() string {
+	if name, ok := parseDefaultRoute(); ok {
+		return name
+	}
+	// dialing udp sends no packet, it only selects the source address by the routing table
+	conn, err := net.Dial("udp", "192.0.2.1:9")
+	if err != nil {
+		return ""
+	}
+	defer conn.Close()
+	local, ok := conn.LocalAddr().(*net.UDPAddr)
+	if !ok {
+		return ""
+	}
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return ""
+	}
+	for _, iface := range interfaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.Equal(local.IP) {
+				return iface.Name
+			}
+		}
+	}
+	return ""
+}
+
+// parseDefaultRoute returns the interface of the route whose destination and mask are zero in /proc/net/route,
+// the one with the lowest metric wins if there are many. ok is false if the file is unreadable.
+func parseDefaultRoute() (name string, ok bool) {
+	file, err := os.Open(path.Join(procRoot, "net/route"))
+	if err != nil {
+		return "", false
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	// skip the header
+	scanner.Scan()
+	lowestMetric := -1
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 8 || fields[1] != "00000000" || fields[7] != "00000000" {
+			continue
+		}
+		metric, err := strconv.Atoi(fields[6])
+		if err != nil {
+			continue
+		}
+		if lowestMetric < 0 || metric < lowestMetric {
+			name, lowestMetric = fields[0], metric
+		}
+	}
+	return name, true
+}
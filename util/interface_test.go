@@ -0,0 +1,56 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestListInterfaces(t *testing.T) {
+	interfaces, err := ListInterfaces()
+	if err != nil {
+		t.Fatalf("ListInterfaces() failed, %v", err)
+	}
+	var loopback *NetInterface
+	for idx := range interfaces {
+		if interfaces[idx].Loopback {
+			loopback = &interfaces[idx]
+		}
+	}
+	if loopback == nil {
+		t.Skip("no loopback interface")
+	}
+	if got, err := GetInterface(loopback.Name); err != nil || got.Index != loopback.Index {
+		t.Errorf("GetInterface(%s) = %v, %v", loopback.Name, got, err)
+	}
+	if _, err := GetInterface("absent0"); err == nil || !strings.Contains(err.Error(), loopback.Name) {
+		t.Errorf("GetInterface() error = %v, want the available interfaces", err)
+	}
+}
+
+func TestParseDefaultRoute(t *testing.T) {
+	fakeProcFS(t, map[string]string{"proc/net/route": strings.Join([]string{
+		"Iface\tDestination\tGateway\tFlags\tRefCnt\tUse\tMetric\tMask\tMTU\tWindow\tIRTT",
+		"eth0\t0002A8C0\t00000000\t0001\t0\t0\t0\t00FFFFFF\t0\t0\t0",
+		"wlan0\t00000000\t0102A8C0\t0003\t0\t0\t600\t00000000\t0\t0\t0",
+		"eth1\t00000000\t0101A8C0\t0003\t0\t0\t100\t00000000\t0\t0\t0",
+	}, "\n")})
+	if name, ok := parseDefaultRoute(); !ok || name != "eth1" {
+		t.Errorf("parseDefaultRoute() = %s, %t, want eth1", name, ok)
+	}
+}
@@ -0,0 +1,148 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// IptablesTable is the table parsed from the iptables-save output
+type IptablesTable struct {
+	Name   string
+	Chains []IptablesChain
+	Rules  []IptablesRule
+}
+
+// IptablesChain is the chain declared in the table, the policy of the user-defined chain is -
+type IptablesChain struct {
+	Name    string
+	Policy  string
+	Packets uint64
+	Bytes   uint64
+}
+
+// IptablesRule is the rule appended to the chain, such as -A INPUT -p tcp --dport 22 -j ACCEPT
+type IptablesRule struct {
+	Chain string
+	// Args are the arguments after the chain with the quotes removed
+	Args []string
+	// Target is the value of -j or -g
+	Target string
+	// Comment is the value of --comment, the rules added by the experiments can be found by it
+	Comment string
+}
+
+// ParseIptablesSave parses the output of iptables-save or ip6tables-save
+//
+//	*filter
+//	:INPUT ACCEPT [0:0]
+//	-A INPUT -p tcp -m comment --comment "chaosblade" -j DROP
+//	COMMIT
+func ParseIptablesSave(output string) ([]IptablesTable, error) {
+	tables := make([]IptablesTable, 0)
+	var table *IptablesTable
+	for idx, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "*"):
+			tables = append(tables, IptablesTable{Name: line[1:]})
+			table = &tables[len(tables)-1]
+			continue
+		case line == "COMMIT":
+			table = nil
+			continue
+		}
+		if table == nil {
+			return nil, fmt.Errorf("line %d `%s` is outside of the table", idx+1, line)
+		}
+		if strings.HasPrefix(line, ":") {
+			chain, err := parseIptablesChain(line[1:])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", idx+1, err)
+			}
+			table.Chains = append(table.Chains, chain)
+			continue
+		}
+		rule, err := parseIptablesRule(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", idx+1, err)
+		}
+		table.Rules = append(table.Rules, rule)
+	}
+	if table != nil {
+		return nil, fmt.Errorf("the table %s is not committed", table.Name)
+	}
+	return tables, nil
+}
+
+// ChainRules returns the rules of the chain in order
+func (t IptablesTable) ChainRules(chain string) []IptablesRule {
+	rules := make([]IptablesRule, 0)
+	for _, rule := range t.Rules {
+		if rule.Chain == chain {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// parseIptablesChain parses the chain declaration, such as INPUT ACCEPT [10:600]
+func parseIptablesChain(declaration string) (IptablesChain, error) {
+	fields := strings.Fields(declaration)
+	if len(fields) < 2 {
+		return IptablesChain{}, fmt.Errorf("illegal chain declaration `%s`", declaration)
+	}
+	chain := IptablesChain{Name: fields[0], Policy: fields[1]}
+	if len(fields) > 2 {
+		counters := strings.TrimSuffix(strings.TrimPrefix(fields[2], "["), "]")
+		packets, bytes, ok := strings.Cut(counters, ":")
+		var err1, err2 error
+		chain.Packets, err1 = strconv.ParseUint(packets, 10, 64)
+		chain.Bytes, err2 = strconv.ParseUint(bytes, 10, 64)
+		if !ok || err1 != nil || err2 != nil {
+			return IptablesChain{}, fmt.Errorf("illegal counters of the chain declaration `%s`", declaration)
+		}
+	}
+	return chain, nil
+}
+
+func parseIptablesRule(line string) (IptablesRule, error) {
+	args, err := spec.SplitArgs(line)
+	if err != nil {
+		return IptablesRule{}, err
+	}
+	if len(args) < 2 || args[0] != "-A" && args[0] != "--append" {
+		return IptablesRule{}, fmt.Errorf("illegal rule `%s`", line)
+	}
+	rule := IptablesRule{Chain: args[1], Args: args[2:]}
+	for idx := 0; idx+1 < len(rule.Args); idx++ {
+		switch rule.Args[idx] {
+		case "-j", "--jump", "-g", "--goto":
+			rule.Target = rule.Args[idx+1]
+		case "--comment":
+			rule.Comment = rule.Args[idx+1]
+		}
+	}
+	return rule, nil
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseIptablesSave(t *testing.T) {
+	output := `# Generated by iptables-save v1.8.7
+*nat
+:PREROUTING ACCEPT [0:0]
+COMMIT
+*filter
+:INPUT ACCEPT [120:7200]
+:CHAOS - [0:0]
+-A INPUT -p tcp -m tcp --dport 22 -j ACCEPT
+-A INPUT -s 10.0.0.0/8 -m comment --comment "chaosblade \"exp\" 1" -j CHAOS
+-A CHAOS -j DROP
+COMMIT
+`
+	tables, err := ParseIptablesSave(output)
+	if err != nil {
+		t.Fatalf("ParseIptablesSave() failed, %v", err)
+	}
+	if len(tables) != 2 || tables[1].Name != "filter" {
+		t.Fatalf("unexpected tables %+v", tables)
+	}
+	filter := tables[1]
+	if !reflect.DeepEqual(filter.Chains[0], IptablesChain{Name: "INPUT", Policy: "ACCEPT", Packets: 120, Bytes: 7200}) {
+		t.Errorf("unexpected chain %+v", filter.Chains[0])
+	}
+	rules := filter.ChainRules("INPUT")
+	if len(rules) != 2 {
+		t.Fatalf("INPUT has %d rules, want 2", len(rules))
+	}
+	if rules[1].Target != "CHAOS" || rules[1].Comment != `chaosblade "exp" 1` {
+		t.Errorf("unexpected rule %+v", rules[1])
+	}
+	if rules[0].Target != "ACCEPT" || !reflect.DeepEqual(rules[0].Args[:2], []string{"-p", "tcp"}) {
+		t.Errorf("unexpected rule %+v", rules[0])
+	}
+}
+
+func TestParseIptablesSaveIllegal(t *testing.T) {
+	for _, output := range []string{
+		"-A INPUT -j DROP",
+		"*filter\n-A INPUT -j DROP",
+		"*filter\n:INPUT ACCEPT [x]\nCOMMIT",
+		"*filter\n-I INPUT -j DROP\nCOMMIT",
+	} {
+		if _, err := ParseIptablesSave(output); err == nil {
+			t.Errorf("ParseIptablesSave(%q) succeeded", output)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// sysModuleRoot is replaced in the tests
+var sysModuleRoot = "/sys/module"
+
+// IsKernelModuleLoaded returns true if the kernel module is loaded or built into the kernel, the - and _ in
+// the name are the same. It's always false except linux.
+func IsKernelModuleLoaded(name string) bool {
+	name = normalizeKernelModule(name)
+	if IsExist(path.Join(sysModuleRoot, name)) {
+		return true
+	}
+	file, err := os.Open(path.Join(procRoot, "modules"))
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) > 0 && fields[0] == name {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeKernelModule(name string) string {
+	return strings.ReplaceAll(strings.TrimSpace(name), "-", "_")
+}
+
+// LoadKernelModule loads the kernel module by modprobe of the channel if it's not loaded, the module loaded
+// already is not loaded again. In the dry run, the module is not loaded and the result is the command which
+// would be run, or empty if nothing to do. The EnvironmentNotSatisfied response is returned if the module
+// can not be loaded, such as modprobe is absent or the module is not installed.
+func LoadKernelModule(ctx context.Context, channel spec.Channel, name string, dryRun bool) *spec.Response {
+	name = normalizeKernelModule(name)
+	if name == "" || strings.ContainsAny(name, " \t/;&|") {
+		return spec.ResponseFailWithFlags(spec.ParameterIllegal, "module", name, "illegal kernel module name")
+	}
+	if IsKernelModuleLoaded(name) {
+		return spec.ReturnSuccess("")
+	}
+	command := fmt.Sprintf("modprobe %s", name)
+	if dryRun {
+		return spec.ReturnSuccess(command)
+	}
+	missing := &spec.ActionRequirements{KernelModules: []string{name}}
+	if !channel.IsCommandAvailable(ctx, "modprobe") {
+		missing.Commands = []string{"modprobe"}
+	} else if response := channel.Run(ctx, "modprobe", name); !response.Success {
+		failed := spec.ResponseFailWithFlags(spec.EnvironmentNotSatisfied, missing)
+		failed.Err = fmt.Sprintf("%s, %s failed, %s", failed.Err, command, response.Err)
+		failed.Result = missing
+		return failed
+	} else if IsKernelModuleLoaded(name) || !IsDir(sysModuleRoot) {
+		// the module can not be verified if sysfs is not mounted, such as in some containers
+		return spec.ReturnSuccess(command)
+	}
+	response := spec.ResponseFailWithFlags(spec.EnvironmentNotSatisfied, missing)
+	response.Result = missing
+	return response
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// modprobeChannel runs modprobe by creating the module directory
+type modprobeChannel struct {
+	spec.Channel
+	available bool
+	runs      []string
+}
+
+func (c *modprobeChannel) IsCommandAvailable(ctx context.Context, commandName string) bool {
+	return c.available
+}
+
+func (c *modprobeChannel) Run(ctx context.Context, script, args string) *spec.Response {
+	c.runs = append(c.runs, script+" "+args)
+	if args == "absent" {
+		return spec.ResponseFailWithFlags(spec.OsCmdExecFailed, script, "module absent not found")
+	}
+	os.Mkdir(filepath.Join(sysModuleRoot, args), 0755)
+	return spec.ReturnSuccess("")
+}
+
+func TestKernelModule(t *testing.T) {
+	root := fakeProcFS(t, map[string]string{
+		"sys/module/ip_tables/refcnt": "1",
+		"proc/modules":                "nf_conntrack 139264 1 - Live 0x0000000000000000\n",
+	})
+	oldRoot := sysModuleRoot
+	sysModuleRoot = filepath.Join(root, "sys/module")
+	defer func() { sysModuleRoot = oldRoot }()
+
+	for _, name := range []string{"ip_tables", "ip-tables", "nf_conntrack"} {
+		if !IsKernelModuleLoaded(name) {
+			t.Errorf("IsKernelModuleLoaded(%s) = false, want true", name)
+		}
+	}
+	if IsKernelModuleLoaded("sch_netem") {
+		t.Errorf("IsKernelModuleLoaded(sch_netem) = true, want false")
+	}
+
+	channel := &modprobeChannel{available: true}
+	if response := LoadKernelModule(context.Background(), channel, "sch-netem", true); !response.Success ||
+		response.Result != "modprobe sch_netem" || len(channel.runs) != 0 {
+		t.Errorf("LoadKernelModule() in dry run = %v, runs: %v", response, channel.runs)
+	}
+	if response := LoadKernelModule(context.Background(), channel, "sch_netem", false); !response.Success ||
+		!IsKernelModuleLoaded("sch_netem") {
+		t.Errorf("LoadKernelModule() = %v, want loaded", response)
+	}
+	if response := LoadKernelModule(context.Background(), channel, "sch_netem", false); !response.Success || len(channel.runs) != 1 {
+		t.Errorf("LoadKernelModule() of the loaded module runs %v, want no run", channel.runs)
+	}
+	response := LoadKernelModule(context.Background(), channel, "absent", false)
+	if response.Success || response.Code != spec.EnvironmentNotSatisfied.Code {
+		t.Errorf("LoadKernelModule() of the absent module = %v, want EnvironmentNotSatisfied", response)
+	}
+	response = LoadKernelModule(context.Background(), &modprobeChannel{}, "sch_tbf", false)
+	if missing, ok := response.Result.(*spec.ActionRequirements); !ok || len(missing.Commands) != 1 {
+		t.Errorf("LoadKernelModule() without modprobe = %v, want the missing modprobe", response)
+	}
+	if response := LoadKernelModule(context.Background(), channel, "a;rm -rf /", false); response.Success {
+		t.Errorf("LoadKernelModule() of the illegal name succeeded")
+	}
+}
@@ -73,6 +73,7 @@ func InitLog(programType int) {
 		DisableColors:   true,
 	}
 	logrus.SetFormatter(formatter)
+	AddHostIdentityHook()
 
 	if Debug {
 		logrus.SetLevel(logrus.DebugLevel)
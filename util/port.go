@@ -0,0 +1,93 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// PortRange is the inclusive range of the ports, the zero value means any port assigned by the system
+type PortRange struct {
+	Start int
+	End   int
+}
+
+// ParsePortRange parses the range like 8000-9000, a single port like 8080 is the range of one port
+func ParsePortRange(value string) (PortRange, error) {
+	start, end := value, value
+	if index := strings.Index(value, "-"); index >= 0 {
+		start, end = value[:index], value[index+1:]
+	}
+	startPort, err := strconv.Atoi(strings.TrimSpace(start))
+	if err != nil {
+		return PortRange{}, fmt.Errorf("illegal port range %s, %v", value, err)
+	}
+	endPort, err := strconv.Atoi(strings.TrimSpace(end))
+	if err != nil {
+		return PortRange{}, fmt.Errorf("illegal port range %s, %v", value, err)
+	}
+	portRange := PortRange{Start: startPort, End: endPort}
+	return portRange, portRange.validate()
+}
+
+func (r PortRange) validate() error {
+	if r.Start < 1 || r.End > 65535 || r.Start > r.End {
+		return fmt.Errorf("illegal port range %d-%d, must be in 1-65535", r.Start, r.End)
+	}
+	return nil
+}
+
+// String returns the range like 8000-9000
+func (r PortRange) String() string {
+	return fmt.Sprintf("%d-%d", r.Start, r.End)
+}
+
+// IsPortFree returns true if the tcp port can be listened on all interfaces. Unlike CheckPortInUse, it also
+// detects the ports listened on the other interfaces and the ports without permission.
+func IsPortFree(port int) bool {
+	listener, err := net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(port)))
+	if err != nil {
+		return false
+	}
+	listener.Close()
+	return true
+}
+
+// GetFreePort returns a free tcp port in the range, the zero range returns the port assigned by the system.
+// The probe starts from a random port in the range, so the concurrent callers rarely pick the same one. The
+// port is released before returning, the caller should listen it as soon as possible.
+func GetFreePort(portRange PortRange) (int, error) {
+	if portRange == (PortRange{}) {
+		return GetUnusedPort()
+	}
+	if err := portRange.validate(); err != nil {
+		return 0, err
+	}
+	size := portRange.End - portRange.Start + 1
+	offset := rand.Intn(size)
+	for i := 0; i < size; i++ {
+		port := portRange.Start + (offset+i)%size
+		if IsPortFree(port) {
+			return port, nil
+		}
+	}
+	return 0, fmt.Errorf("no free port in %s", portRange)
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"net"
+	"testing"
+)
+
+func TestParsePortRange(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    PortRange
+		wantErr bool
+	}{
+		{value: "8000-9000", want: PortRange{8000, 9000}},
+		{value: "8080", want: PortRange{8080, 8080}},
+		{value: " 80 - 81 ", want: PortRange{80, 81}},
+		{value: "9000-8000", wantErr: true},
+		{value: "0-10", wantErr: true},
+		{value: "1-65536", wantErr: true},
+		{value: "a-b", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePortRange(tt.value)
+		if (err != nil) != tt.wantErr || (!tt.wantErr && got != tt.want) {
+			t.Errorf("ParsePortRange(%q) = %v, %v, want %v, wantErr %t", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestGetFreePort(t *testing.T) {
+	listener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	used := listener.Addr().(*net.TCPAddr).Port
+	if IsPortFree(used) {
+		t.Errorf("IsPortFree(%d) = true, want false for the listened port", used)
+	}
+	if _, err := GetFreePort(PortRange{used, used}); err == nil {
+		t.Errorf("GetFreePort() of the used port succeeded")
+	}
+
+	port, err := GetFreePort(PortRange{})
+	if err != nil || !IsPortFree(port) {
+		t.Fatalf("GetFreePort() = %d, %v, want a free port", port, err)
+	}
+	if got, err := GetFreePort(PortRange{port, port}); err != nil || got != port {
+		t.Errorf("GetFreePort(%d-%d) = %d, %v, want %d", port, port, got, err, port)
+	}
+	if _, err := GetFreePort(PortRange{10, 5}); err == nil {
+		t.Errorf("GetFreePort() of the illegal range succeeded")
+	}
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/process"
+)
+
+// userHZ is the unit of the times in /proc/<pid>/stat, it's the fixed USER_HZ of the kernel abi
+const userHZ = 100
+
+// ProcessStartTime returns the time when the process started. It reads /proc/<pid>/stat on linux and falls
+// back to gopsutil on the others, so comparing the start times tells if the process restarted.
+func ProcessStartTime(pid int) (time.Time, error) {
+	if IsDir(procRoot) {
+		return procStartTime(pid)
+	}
+	p, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return time.Time{}, err
+	}
+	createTime, err := p.CreateTime()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(createTime), nil
+}
+
+// ProcessUptime returns how long the process has been running
+func ProcessUptime(pid int) (time.Duration, error) {
+	startTime, err := ProcessStartTime(pid)
+	if err != nil {
+		return 0, err
+	}
+	return time.Since(startTime), nil
+}
+
+func procStartTime(pid int) (time.Time, error) {
+	stat, err := os.ReadFile(path.Join(procRoot, strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return time.Time{}, err
+	}
+	// the comm in the parentheses may contain the spaces and the parentheses, the fields follow the last one
+	content := string(stat)
+	idx := strings.LastIndex(content, ")")
+	if idx < 0 {
+		return time.Time{}, fmt.Errorf("illegal stat of process %d", pid)
+	}
+	// the starttime is the 22nd field, the 20th after the comm
+	fields := strings.Fields(content[idx+1:])
+	if len(fields) < 20 {
+		return time.Time{}, fmt.Errorf("illegal stat of process %d", pid)
+	}
+	ticks, err := strconv.ParseUint(fields[19], 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("illegal start time of process %d, %v", pid, err)
+	}
+	bootTime, err := procBootTime()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return bootTime.Add(time.Duration(ticks) * time.Second / userHZ), nil
+}
+
+// procBootTime returns the boot time from the btime line of /proc/stat
+func procBootTime() (time.Time, error) {
+	stat, err := os.ReadFile(path.Join(procRoot, "stat"))
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, line := range strings.Split(string(stat), "\n") {
+		if strings.HasPrefix(line, "btime ") {
+			seconds, err := strconv.ParseInt(strings.TrimSpace(strings.TrimPrefix(line, "btime ")), 10, 64)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("illegal btime %s, %v", line, err)
+			}
+			return time.Unix(seconds, 0), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("btime not found in %s", path.Join(procRoot, "stat"))
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestProcessStartTime(t *testing.T) {
+	uptime, err := ProcessUptime(os.Getpid())
+	if err != nil {
+		t.Fatalf("ProcessUptime() failed, %v", err)
+	}
+	if uptime < 0 || uptime > time.Hour {
+		t.Errorf("ProcessUptime() = %s, want the uptime of the test process", uptime)
+	}
+}
+
+func TestProcStartTime(t *testing.T) {
+	fakeProcFS(t, map[string]string{
+		"proc/stat":     "cpu  1 2 3\nbtime 1700000000\nprocesses 100\n",
+		"proc/100/stat": "100 (java (main) x) S 1 100 100 0 -1 4194560 1 0 0 0 0 0 0 0 20 0 1 0 12345 1000 100",
+		"proc/200/stat": "200 (short) S 1",
+	})
+	want := time.Unix(1700000000, 0).Add(123450 * time.Millisecond)
+	if got, err := ProcessStartTime(100); err != nil || !got.Equal(want) {
+		t.Errorf("ProcessStartTime() = %v, %v, want %v", got, err, want)
+	}
+	if _, err := ProcessStartTime(200); err == nil {
+		t.Errorf("ProcessStartTime() of the illegal stat succeeded")
+	}
+	if _, err := ProcessStartTime(300); err == nil {
+		t.Errorf("ProcessStartTime() of the absent process succeeded")
+	}
+}
@@ -0,0 +1,196 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PSRecord is the process parsed from the ps output
+type PSRecord struct {
+	Pid  int
+	Ppid int
+	User string
+	// Stat is the process state, such as S or R+
+	Stat string
+	// Comm is the executable name
+	Comm string
+	// Args is the command line with the arguments
+	Args string
+	// Fields are the values of all columns by the normalized column names
+	Fields map[string]string
+}
+
+// psColumnAliases normalizes the column names and the headers of procps and BusyBox
+var psColumnAliases = map[string]string{
+	"command": "args",
+	"cmd":     "args",
+	"ucomm":   "comm",
+	"ruser":   "user",
+	"euser":   "user",
+	"uname":   "user",
+	"s":       "stat",
+	"state":   "stat",
+}
+
+// greedyPSColumns are the columns may contain spaces, they take the rest of the line if they are the last column
+var greedyPSColumns = map[string]bool{"args": true, "comm": true}
+
+// ParsePS parses the output of `ps -o <columns>`, such as
+//
+//	ParsePS(output, "user", "pid", "ppid", "args")
+//
+// The columns are inferred from the header if not specified, so the default output of BusyBox ps, whose header
+// is `PID USER TIME COMMAND`, is also supported. The header line is skipped if present. The last column takes the
+// rest of the line if it's args or comm, because the command line contains spaces.
+func ParsePS(output string, columns ...string) ([]PSRecord, error) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) == "" {
+		return []PSRecord{}, nil
+	}
+	headerFields := strings.Fields(lines[0])
+	hasHeader := isPSHeader(headerFields)
+	if len(columns) == 0 {
+		if !hasHeader {
+			return nil, fmt.Errorf("the columns are not specified and the ps output has no header")
+		}
+		columns = headerFields
+	}
+	names := make([]string, len(columns))
+	for idx, column := range columns {
+		names[idx] = normalizePSColumn(column)
+	}
+	if hasHeader {
+		lines = lines[1:]
+	}
+	records := make([]PSRecord, 0, len(lines))
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		record, err := parsePSLine(line, names)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func parsePSLine(line string, names []string) (PSRecord, error) {
+	last := len(names) - 1
+	greedy := greedyPSColumns[names[last]]
+	var fields []string
+	if greedy {
+		fields = splitPSFields(line, last)
+	} else {
+		fields = strings.Fields(line)
+	}
+	// the greedy column may be empty, such as the zombie process of BusyBox
+	if len(fields) < len(names) && !(greedy && len(fields) == last) || len(fields) > len(names) {
+		return PSRecord{}, fmt.Errorf("the ps line `%s` does not match the columns %s", line, strings.Join(names, ","))
+	}
+	record := PSRecord{Fields: make(map[string]string, len(names))}
+	for idx, name := range names {
+		value := ""
+		if idx < len(fields) {
+			value = fields[idx]
+		}
+		record.Fields[name] = value
+		var err error
+		switch name {
+		case "pid":
+			record.Pid, err = strconv.Atoi(value)
+		case "ppid":
+			record.Ppid, err = strconv.Atoi(value)
+		case "user", "uid":
+			if record.User == "" {
+				record.User = value
+			}
+		case "stat":
+			record.Stat = value
+		case "comm":
+			record.Comm = value
+		case "args":
+			record.Args = value
+		}
+		if err != nil {
+			return PSRecord{}, fmt.Errorf("the %s `%s` of the ps line `%s` is not a number", name, value, line)
+		}
+	}
+	if record.Comm == "" && record.Args != "" {
+		record.Comm = psCommOfArgs(record.Args)
+	}
+	return record, nil
+}
+
+// splitPSFields splits the line into the whitespace separated fields, the rest after n fields is the last one
+func splitPSFields(line string, n int) []string {
+	fields := make([]string, 0, n+1)
+	rest := strings.TrimSpace(line)
+	for len(fields) < n && rest != "" {
+		end := strings.IndexAny(rest, " \t")
+		if end < 0 {
+			fields = append(fields, rest)
+			rest = ""
+			break
+		}
+		fields = append(fields, rest[:end])
+		rest = strings.TrimLeft(rest[end:], " \t")
+	}
+	if rest != "" {
+		fields = append(fields, rest)
+	}
+	return fields
+}
+
+// psCommOfArgs returns the executable name of the command line, the kernel threads are shown as [kthreadd]
+func psCommOfArgs(args string) string {
+	if strings.HasPrefix(args, "[") && strings.HasSuffix(args, "]") {
+		return strings.TrimSuffix(strings.TrimPrefix(args, "["), "]")
+	}
+	executable := strings.Fields(args)[0]
+	return executable[strings.LastIndex(executable, "/")+1:]
+}
+
+// isPSHeader returns true if the fields are the column headers, which are upper case and contain PID
+func isPSHeader(fields []string) bool {
+	hasPid := false
+	for _, field := range fields {
+		if field != strings.ToUpper(field) {
+			return false
+		}
+		if field == "PID" {
+			hasPid = true
+		}
+	}
+	return hasPid
+}
+
+func normalizePSColumn(column string) string {
+	name := strings.ToLower(strings.TrimSpace(column))
+	// the column may be renamed by ps -o pid=ID
+	if idx := strings.Index(name, "="); idx >= 0 {
+		name = name[:idx]
+	}
+	if alias, ok := psColumnAliases[name]; ok {
+		return alias
+	}
+	return name
+}
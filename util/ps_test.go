@@ -0,0 +1,81 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"testing"
+)
+
+func TestParsePS(t *testing.T) {
+	output := `USER         PID    PPID COMMAND
+root           1       0 /sbin/init splash
+root           2       0 [kthreadd]
+admin       4321       1 /usr/bin/java -Dname="a b" -jar app.jar
+`
+	records, err := ParsePS(output, "user", "pid", "ppid", "args")
+	if err != nil {
+		t.Fatalf("ParsePS() failed, %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("ParsePS() returns %d records, want 3", len(records))
+	}
+	java := records[2]
+	if java.User != "admin" || java.Pid != 4321 || java.Ppid != 1 || java.Comm != "java" ||
+		java.Args != `/usr/bin/java -Dname="a b" -jar app.jar` {
+		t.Errorf("unexpected record %+v", java)
+	}
+	if records[1].Comm != "kthreadd" {
+		t.Errorf("Comm of the kernel thread = %q, want kthreadd", records[1].Comm)
+	}
+}
+
+func TestParsePSBusyBox(t *testing.T) {
+	output := `PID   USER     TIME  COMMAND
+    1 root      0:00 /bin/sh -c sleep 1000
+   12 1000      0:01 nginx: worker process
+   13 root      0:00
+`
+	records, err := ParsePS(output)
+	if err != nil {
+		t.Fatalf("ParsePS() failed, %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("ParsePS() returns %d records, want 3", len(records))
+	}
+	if records[1].Pid != 12 || records[1].User != "1000" || records[1].Args != "nginx: worker process" ||
+		records[1].Fields["time"] != "0:01" {
+		t.Errorf("unexpected record %+v", records[1])
+	}
+	if records[2].Args != "" {
+		t.Errorf("Args of the zombie process = %q, want empty", records[2].Args)
+	}
+}
+
+func TestParsePSIllegal(t *testing.T) {
+	if _, err := ParsePS("root abc 1 /bin/sh", "user", "pid", "ppid", "args"); err == nil {
+		t.Errorf("ParsePS() of the illegal pid succeeded")
+	}
+	if _, err := ParsePS("root 1 0 /bin/sh"); err == nil {
+		t.Errorf("ParsePS() without columns and header succeeded")
+	}
+	if _, err := ParsePS("1 0 5", "pid", "ppid"); err == nil {
+		t.Errorf("ParsePS() of the line with extra fields succeeded")
+	}
+	if records, err := ParsePS("", "pid"); err != nil || len(records) != 0 {
+		t.Errorf("ParsePS() of the empty output = %v, %v", records, err)
+	}
+}
@@ -0,0 +1,153 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"text/template"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+var (
+	templateFuncs = template.FuncMap{
+		"default":    defaultValue,
+		"required":   requiredValue,
+		"empty":      isEmptyValue,
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"trim":       strings.TrimSpace,
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"split":      func(sep, s string) []string { return strings.Split(s, sep) },
+		"join":       joinValues,
+		"list":       func(values ...interface{}) []interface{} { return values },
+		"quote":      func(value interface{}) string { return fmt.Sprintf("%q", fmt.Sprint(value)) },
+		"squote":     func(value interface{}) string { return "'" + fmt.Sprint(value) + "'" },
+		"shellQuote": func(value interface{}) string { return spec.QuoteArgs([]string{fmt.Sprint(value)}) },
+		"indent":     indent,
+		"nindent":    func(spaces int, s string) string { return "\n" + indent(spaces, s) },
+		"toJson":     toJSON,
+		"toYaml":     toYAML,
+	}
+	templateFuncsLock sync.RWMutex
+)
+
+// RegisterTemplateFunc registers the function used by RenderTemplate, it overrides the built-in one of the name
+func RegisterTemplateFunc(name string, fn interface{}) {
+	templateFuncsLock.Lock()
+	defer templateFuncsLock.Unlock()
+	templateFuncs[name] = fn
+}
+
+// RenderTemplate renders the text/template with the data, the missing keys are errors instead of <no value>.
+// Besides the built-in functions of text/template, the sprig-like functions are supported: default, required,
+// empty, upper, lower, trim, trimPrefix, trimSuffix, replace, contains, hasPrefix, hasSuffix, split, join,
+// list, quote, squote, shellQuote, indent, nindent, toJson and toYaml. Use shellQuote for the values in the
+// shell scripts, so the user input never breaks out of the argument.
+func RenderTemplate(tmpl string, data map[string]interface{}) (string, error) {
+	templateFuncsLock.RLock()
+	funcs := make(template.FuncMap, len(templateFuncs))
+	for name, fn := range templateFuncs {
+		funcs[name] = fn
+	}
+	templateFuncsLock.RUnlock()
+	t, err := template.New("render").Option("missingkey=error").Funcs(funcs).Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("parse template failed, %v", err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render template failed, %v", err)
+	}
+	return buf.String(), nil
+}
+
+// defaultValue returns the value, or the defaultValue if the value is empty
+func defaultValue(defaultValue, value interface{}) interface{} {
+	if isEmptyValue(value) {
+		return defaultValue
+	}
+	return value
+}
+
+func requiredValue(message string, value interface{}) (interface{}, error) {
+	if isEmptyValue(value) {
+		return nil, fmt.Errorf("%s", message)
+	}
+	return value, nil
+}
+
+// isEmptyValue returns true for nil, the zero value and the empty collections
+func isEmptyValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	default:
+		return v.IsZero()
+	}
+}
+
+func joinValues(sep string, values interface{}) string {
+	v := reflect.ValueOf(values)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return fmt.Sprint(values)
+	}
+	items := make([]string, 0, v.Len())
+	for idx := 0; idx < v.Len(); idx++ {
+		items = append(items, fmt.Sprint(v.Index(idx).Interface()))
+	}
+	return strings.Join(items, sep)
+}
+
+func indent(spaces int, s string) string {
+	padding := strings.Repeat(" ", spaces)
+	return padding + strings.ReplaceAll(s, "\n", "\n"+padding)
+}
+
+func toJSON(value interface{}) (string, error) {
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+func toYAML(value interface{}) (string, error) {
+	bytes, err := yaml.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(bytes), "\n"), nil
+}
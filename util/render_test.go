@@ -0,0 +1,68 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	data := map[string]interface{}{
+		"file":    "/tmp/a b'c",
+		"ports":   []string{"80", "443"},
+		"timeout": "",
+		"rules":   map[string]interface{}{"delay": "10ms"},
+	}
+	tests := []struct {
+		name    string
+		tmpl    string
+		want    string
+		wantErr string
+	}{
+		{name: "shell quote", tmpl: "rm -f {{ shellQuote .file }}", want: `rm -f '/tmp/a b'\''c'`},
+		{name: "join", tmpl: `{{ join "," .ports }}`, want: "80,443"},
+		{name: "default", tmpl: `{{ default "30" .timeout }}`, want: "30"},
+		{name: "pipeline", tmpl: `{{ .file | trimPrefix "/tmp/" | upper | quote }}`, want: `"A B'C"`},
+		{name: "json", tmpl: `{{ toJson .rules }}`, want: `{"delay":"10ms"}`},
+		{name: "yaml", tmpl: `rules:{{ toYaml .rules | nindent 2 }}`, want: "rules:\n  delay: 10ms"},
+		{name: "missing key", tmpl: "{{ .absent }}", wantErr: "absent"},
+		{name: "required", tmpl: `{{ required "timeout is required" .timeout }}`, wantErr: "timeout is required"},
+		{name: "parse error", tmpl: "{{ .file ", wantErr: "parse template failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderTemplate(tt.tmpl, data)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("RenderTemplate() = %q, %v, want error %q", got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("RenderTemplate() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterTemplateFunc(t *testing.T) {
+	RegisterTemplateFunc("repeat", strings.Repeat)
+	if got, err := RenderTemplate(`{{ repeat "ab" 2 }}`, nil); err != nil || got != "abab" {
+		t.Errorf("RenderTemplate() = %q, %v, want abab", got, err)
+	}
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// RetryPolicy decides how many times and how long Retry retries the operation
+type RetryPolicy struct {
+	// InitialInterval is the wait before the first retry, the default interval is used if it's zero
+	InitialInterval time.Duration
+	// MaxInterval caps the wait between the retries, zero means no cap
+	MaxInterval time.Duration
+	// Multiplier grows the wait after each retry, the wait is constant if it's less than 1
+	Multiplier float64
+	// Jitter randomizes the wait by the fraction in [0, 1], 0.2 means the wait is in [0.8, 1.2] of the interval
+	Jitter float64
+	// MaxAttempts limits the count of the calls including the first one, zero means no limit
+	MaxAttempts int
+	// MaxElapsedTime stops retrying once the time since the first call exceeds it, zero means no limit.
+	// The default MaxAttempts is used if both of them are zero, so the zero policy never retries forever.
+	MaxElapsedTime time.Duration
+	// RetryIf returns true if the error is transient, all errors are retried if it's nil
+	RetryIf func(err error) bool
+}
+
+// DefaultRetryPolicy returns the policy retrying 5 times in about 3 seconds
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		InitialInterval: 200 * time.Millisecond,
+		MaxInterval:     2 * time.Second,
+		Multiplier:      2,
+		Jitter:          0.2,
+		MaxAttempts:     5,
+	}
+}
+
+// RetryableResponse is the RetryIf predicate retrying the failed spec.Response classified as retryable,
+// such as the busy command, the other errors are not retried
+func RetryableResponse(err error) bool {
+	var response *spec.Response
+	return errors.As(err, &response) && response.Retryable
+}
+
+// withDefaults returns the policy whose zero interval and limits are replaced by DefaultRetryPolicy
+func (policy RetryPolicy) withDefaults() RetryPolicy {
+	defaults := DefaultRetryPolicy()
+	if policy.InitialInterval <= 0 {
+		policy.InitialInterval = defaults.InitialInterval
+	}
+	if policy.MaxAttempts <= 0 && policy.MaxElapsedTime <= 0 {
+		policy.MaxAttempts = defaults.MaxAttempts
+	}
+	return policy
+}
+
+// Retry calls the operation until it succeeds, the error is not retryable, the attempts or the elapsed time
+// run out, or the ctx is done. It returns the last error of the operation.
+func Retry(ctx context.Context, policy RetryPolicy, operation func() error) error {
+	policy = policy.withDefaults()
+	start := time.Now()
+	interval := policy.InitialInterval
+	for attempt := 1; ; attempt++ {
+		err := operation()
+		if err == nil {
+			return nil
+		}
+		if policy.RetryIf != nil && !policy.RetryIf(err) {
+			return err
+		}
+		if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+			return err
+		}
+		wait := jitter(interval, policy.Jitter)
+		if policy.MaxElapsedTime > 0 && time.Since(start)+wait > policy.MaxElapsedTime {
+			return err
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		if policy.Multiplier > 1 {
+			interval = time.Duration(float64(interval) * policy.Multiplier)
+		}
+		if policy.MaxInterval > 0 && interval > policy.MaxInterval {
+			interval = policy.MaxInterval
+		}
+	}
+}
+
+func jitter(interval time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 || interval <= 0 {
+		return interval
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+	delta := float64(interval) * fraction
+	return time.Duration(float64(interval) - delta + rand.Float64()*2*delta)
+}
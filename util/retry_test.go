@@ -0,0 +1,115 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+func TestRetry(t *testing.T) {
+	transient := errors.New("transient")
+	fast := RetryPolicy{InitialInterval: time.Millisecond, Multiplier: 2, MaxInterval: 4 * time.Millisecond, Jitter: 0.5}
+	tests := []struct {
+		name      string
+		policy    func(RetryPolicy) RetryPolicy
+		failures  int
+		err       error
+		wantCalls int
+		wantErr   bool
+	}{
+		{name: "succeed after retries", policy: func(p RetryPolicy) RetryPolicy { p.MaxAttempts = 5; return p },
+			failures: 2, err: transient, wantCalls: 3},
+		{name: "attempts run out", policy: func(p RetryPolicy) RetryPolicy { p.MaxAttempts = 3; return p },
+			failures: 10, err: transient, wantCalls: 3, wantErr: true},
+		{name: "not retryable", policy: func(p RetryPolicy) RetryPolicy {
+			p.RetryIf = func(err error) bool { return err != transient }
+			return p
+		}, failures: 10, err: transient, wantCalls: 1, wantErr: true},
+		{name: "elapsed time runs out", policy: func(p RetryPolicy) RetryPolicy { p.MaxElapsedTime = 20 * time.Millisecond; return p },
+			failures: 1000, err: transient, wantErr: true},
+		{name: "retryable response", policy: func(p RetryPolicy) RetryPolicy { p.MaxAttempts = 5; p.RetryIf = RetryableResponse; return p },
+			failures: 1, err: spec.ResponseFailWithFlags(spec.PodNotReady, "pod"), wantCalls: 2},
+		{name: "not retryable response", policy: func(p RetryPolicy) RetryPolicy { p.MaxAttempts = 5; p.RetryIf = RetryableResponse; return p },
+			failures: 1, err: spec.ResponseFailWithFlags(spec.ParameterLess, "pid"), wantCalls: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := Retry(context.Background(), tt.policy(fast), func() error {
+				calls++
+				if calls <= tt.failures {
+					return tt.err
+				}
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Retry() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if tt.wantCalls > 0 && calls != tt.wantCalls {
+				t.Errorf("Retry() called %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryZeroPolicy(t *testing.T) {
+	policy := RetryPolicy{}.withDefaults()
+	defaults := DefaultRetryPolicy()
+	if policy.InitialInterval != defaults.InitialInterval || policy.MaxAttempts != defaults.MaxAttempts {
+		t.Errorf("withDefaults() = %+v, want the default interval and attempts", policy)
+	}
+	if policy = (RetryPolicy{MaxElapsedTime: time.Second}).withDefaults(); policy.MaxAttempts != 0 {
+		t.Errorf("withDefaults() MaxAttempts = %d, want no limit with MaxElapsedTime", policy.MaxAttempts)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	calls := 0
+	err := Retry(ctx, RetryPolicy{Jitter: 0.1}, func() error {
+		calls++
+		return errors.New("failed")
+	})
+	if err == nil || calls != defaults.MaxAttempts {
+		t.Errorf("Retry() with the zero policy = %v after %d calls, want error after %d calls", err, calls, defaults.MaxAttempts)
+	}
+}
+
+func TestRetryContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := Retry(ctx, RetryPolicy{InitialInterval: time.Hour}, func() error {
+		calls++
+		cancel()
+		return errors.New("failed")
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("Retry() = %v after %d calls, want error after 1 call", err, calls)
+	}
+}
+
+func TestJitter(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		if got := jitter(100*time.Millisecond, 0.2); got < 80*time.Millisecond || got > 120*time.Millisecond {
+			t.Fatalf("jitter() = %s, want in [80ms, 120ms]", got)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrPathEscape is returned by SecureJoin if the path escapes the root
+var ErrPathEscape = errors.New("path escapes from the root")
+
+// SecureJoin joins the user supplied path to the root and resolves the symlinks, it returns ErrPathEscape if
+// the path goes above the root by .. or by the symlinks. The absolute path is taken as relative to the root,
+// and .. is resolved lexically before the symlinks. The path does not need to exist, the existing part is
+// resolved and the rest is appended. The result is checked at the call time only, the caller should not
+// let the others modify the directories under the root before using it.
+func SecureJoin(root, userPath string) (string, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+	realRoot, err := filepath.EvalSymlinks(absRoot)
+	if err != nil {
+		return "", err
+	}
+	joined := filepath.Join(absRoot, userPath)
+	if !containsPath(absRoot, joined) {
+		return "", fmt.Errorf("%w: %s, root: %s", ErrPathEscape, userPath, root)
+	}
+	existing, rest := joined, ""
+	for {
+		_, err := os.Lstat(existing)
+		if err == nil {
+			break
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+		rest = filepath.Join(filepath.Base(existing), rest)
+		existing = filepath.Dir(existing)
+	}
+	// the dangling symlink can not be resolved, writing through it may create the file anywhere
+	realPath, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s failed, %v", existing, err)
+	}
+	resolved := filepath.Join(realPath, rest)
+	if !containsPath(realRoot, resolved) {
+		return "", fmt.Errorf("%w: %s resolves to %s, root: %s", ErrPathEscape, userPath, resolved, root)
+	}
+	return resolved, nil
+}
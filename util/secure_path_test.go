@@ -0,0 +1,78 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSecureJoin(t *testing.T) {
+	base, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := filepath.Join(base, "root")
+	for _, dir := range []string{filepath.Join(root, "scripts"), filepath.Join(base, "outside")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	links := map[string]string{
+		"inner":    "scripts",
+		"escape":   "../outside",
+		"absolute": filepath.Join(base, "outside"),
+		"dangling": filepath.Join(base, "absent"),
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(root, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		userPath string
+		want     string
+		wantErr  error
+	}{
+		{userPath: "scripts/run.sh", want: filepath.Join(root, "scripts/run.sh")},
+		{userPath: "/scripts/run.sh", want: filepath.Join(root, "scripts/run.sh")},
+		{userPath: "new/dir/file", want: filepath.Join(root, "new/dir/file")},
+		{userPath: "inner/run.sh", want: filepath.Join(root, "scripts/run.sh")},
+		{userPath: "scripts/../inner", want: filepath.Join(root, "scripts")},
+		{userPath: "../outside/file", wantErr: ErrPathEscape},
+		{userPath: "scripts/../../outside", wantErr: ErrPathEscape},
+		{userPath: "escape/file", wantErr: ErrPathEscape},
+		{userPath: "absolute", wantErr: ErrPathEscape},
+	}
+	for _, tt := range tests {
+		got, err := SecureJoin(root, tt.userPath)
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("SecureJoin(%s) = %s, %v, want %v", tt.userPath, got, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("SecureJoin(%s) = %s, %v, want %s", tt.userPath, got, err, tt.want)
+		}
+	}
+	if _, err := SecureJoin(root, "dangling/file"); err == nil {
+		t.Errorf("SecureJoin() through the dangling symlink succeeded")
+	}
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// The parsing is implemented in spec and shared with the size and duration flags, so the values accepted by
+// the plugins are the same as the flags.
+
+// ParseSize parses the size value to bytes, such as 512, 100K, 10MB, 1Gi. The units are based on 1024.
+func ParseSize(value string) (int64, error) {
+	return spec.ParseSize(value)
+}
+
+// FormatSize formats the bytes in the largest unit based on 1024, such as 512B, 1.5KB or 10MB
+func FormatSize(size int64) string {
+	return spec.FormatSize(size)
+}
+
+// ParseDuration parses the duration value, such as 30, 10s, 1m30s or 2d12h. The integer value without unit is
+// seconds, and d is the unit of 24 hours.
+func ParseDuration(value string) (time.Duration, error) {
+	return spec.ParseDuration(value)
+}
+
+// FormatDuration formats the duration in the units up to days, such as 2d12h, 1h30m or 500ms
+func FormatDuration(duration time.Duration) string {
+	return spec.FormatDuration(duration)
+}
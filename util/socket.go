@@ -0,0 +1,174 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Socket is the socket parsed from the ss or netstat output
+type Socket struct {
+	// Proto is the protocol, such as tcp, udp or tcp6, it's empty if ss doesn't output the Netid column
+	Proto string
+	// State is the normalized state, such as LISTEN, ESTABLISHED, TIME_WAIT, or UNCONN of the udp socket
+	State     string
+	RecvQ     int
+	SendQ     int
+	LocalAddr string
+	LocalPort string
+	PeerAddr  string
+	PeerPort  string
+	// Processes are the processes using the socket, which are output by ss -p or netstat -p
+	Processes []SocketProcess
+}
+
+// SocketProcess is the process using the socket
+type SocketProcess struct {
+	Name string
+	Pid  int
+}
+
+// ssStates normalizes the ss states to the netstat states
+var ssStates = map[string]string{
+	"ESTAB": "ESTABLISHED",
+}
+
+// ssProcessExp matches the processes of ss, such as users:(("sshd",pid=812,fd=3)), the pid= is absent in the
+// older ss, such as users:(("sshd",812,fd=3))
+var ssProcessExp = regexp.MustCompile(`\("([^"]*)",(?:pid=)?(\d+)`)
+
+// ParseSS parses the output of ss, such as `ss -tunap`, the header is skipped and the Netid column is optional
+//
+//	Netid State  Recv-Q Send-Q Local Address:Port Peer Address:Port Process
+//	tcp   LISTEN 0      128          0.0.0.0:22        0.0.0.0:*     users:(("sshd",pid=812,fd=3))
+func ParseSS(output string) ([]Socket, error) {
+	sockets := make([]Socket, 0)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] == "Netid" || fields[0] == "State" {
+			continue
+		}
+		socket := Socket{}
+		// the netid is lower case and the state is upper case
+		if fields[0] == strings.ToLower(fields[0]) {
+			socket.Proto = fields[0]
+			fields = fields[1:]
+		}
+		if len(fields) < 5 {
+			return nil, fmt.Errorf("illegal ss line `%s`", line)
+		}
+		socket.State = normalizeSocketState(fields[0])
+		if err := parseSocketQueues(&socket, fields[1], fields[2]); err != nil {
+			return nil, fmt.Errorf("illegal ss line `%s`, %v", line, err)
+		}
+		socket.LocalAddr, socket.LocalPort = splitSocketAddress(fields[3])
+		socket.PeerAddr, socket.PeerPort = splitSocketAddress(fields[4])
+		for _, match := range ssProcessExp.FindAllStringSubmatch(strings.Join(fields[5:], " "), -1) {
+			pid, _ := strconv.Atoi(match[2])
+			socket.Processes = append(socket.Processes, SocketProcess{Name: match[1], Pid: pid})
+		}
+		sockets = append(sockets, socket)
+	}
+	return sockets, nil
+}
+
+// ParseNetstat parses the output of netstat, such as `netstat -tunap`, the headers and the unix sockets are
+// skipped. The State column of the udp socket may be empty.
+//
+//	Proto Recv-Q Send-Q Local Address   Foreign Address   State    PID/Program name
+//	tcp        0      0 0.0.0.0:22      0.0.0.0:*         LISTEN   812/sshd
+//	udp        0      0 0.0.0.0:68      0.0.0.0:*                  600/dhclient
+func ParseNetstat(output string) ([]Socket, error) {
+	sockets := make([]Socket, 0)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], "tcp") && !strings.HasPrefix(fields[0], "udp") &&
+			!strings.HasPrefix(fields[0], "raw") {
+			continue
+		}
+		if len(fields) < 5 {
+			return nil, fmt.Errorf("illegal netstat line `%s`", line)
+		}
+		socket := Socket{Proto: fields[0]}
+		if err := parseSocketQueues(&socket, fields[1], fields[2]); err != nil {
+			return nil, fmt.Errorf("illegal netstat line `%s`, %v", line, err)
+		}
+		socket.LocalAddr, socket.LocalPort = splitSocketAddress(fields[3])
+		socket.PeerAddr, socket.PeerPort = splitSocketAddress(fields[4])
+		rest := fields[5:]
+		// the state is absent for the udp socket, the program is pid/name or -
+		if len(rest) > 0 && !strings.Contains(rest[0], "/") && rest[0] != "-" {
+			socket.State = normalizeSocketState(rest[0])
+			rest = rest[1:]
+		}
+		if len(rest) > 0 {
+			if pid, name, ok := strings.Cut(strings.Join(rest, " "), "/"); ok {
+				if id, err := strconv.Atoi(pid); err == nil {
+					socket.Processes = []SocketProcess{{Name: name, Pid: id}}
+				}
+			}
+		}
+		sockets = append(sockets, socket)
+	}
+	return sockets, nil
+}
+
+// Pids returns the pids of the processes using the socket
+func (s Socket) Pids() []int {
+	pids := make([]int, 0, len(s.Processes))
+	for _, process := range s.Processes {
+		pids = append(pids, process.Pid)
+	}
+	return pids
+}
+
+func parseSocketQueues(socket *Socket, recvQ, sendQ string) error {
+	var err error
+	if socket.RecvQ, err = strconv.Atoi(recvQ); err != nil {
+		return fmt.Errorf("illegal Recv-Q %s", recvQ)
+	}
+	if socket.SendQ, err = strconv.Atoi(sendQ); err != nil {
+		return fmt.Errorf("illegal Send-Q %s", sendQ)
+	}
+	return nil
+}
+
+func normalizeSocketState(state string) string {
+	state = strings.ReplaceAll(strings.ToUpper(state), "-", "_")
+	if normalized, ok := ssStates[state]; ok {
+		return normalized
+	}
+	return state
+}
+
+// splitSocketAddress splits the address and the port, such as 0.0.0.0:22, [::1]:22, :::22, *:* or
+// 127.0.0.53%lo:53, the brackets and the interface are removed from the address
+func splitSocketAddress(value string) (string, string) {
+	idx := strings.LastIndex(value, ":")
+	if idx < 0 {
+		return value, ""
+	}
+	addr, port := value[:idx], value[idx+1:]
+	if zone := strings.Index(addr, "%"); zone >= 0 {
+		addr = addr[:zone]
+	}
+	addr = strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]")
+	return addr, port
+}
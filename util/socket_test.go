@@ -0,0 +1,78 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSS(t *testing.T) {
+	output := `Netid State      Recv-Q Send-Q   Local Address:Port   Peer Address:Port
+tcp   LISTEN     0      128       *:80                 *:* users:(("tengine",pid=237768,fd=6),("tengine",pid=237767,fd=6))
+tcp   ESTAB      0      36        [::ffff:10.0.0.5]:22  [::ffff:10.0.0.1]:51234 users:(("sshd",237,fd=3))
+udp   UNCONN     0      0         127.0.0.53%lo:53     0.0.0.0:*
+`
+	sockets, err := ParseSS(output)
+	if err != nil {
+		t.Fatalf("ParseSS() failed, %v", err)
+	}
+	if len(sockets) != 3 {
+		t.Fatalf("ParseSS() returns %d sockets, want 3", len(sockets))
+	}
+	if got := sockets[0].Pids(); !reflect.DeepEqual(got, []int{237768, 237767}) {
+		t.Errorf("pids of the listening socket = %v", got)
+	}
+	established := sockets[1]
+	if established.State != "ESTABLISHED" || established.SendQ != 36 || established.LocalAddr != "::ffff:10.0.0.5" ||
+		established.LocalPort != "22" || established.PeerPort != "51234" || established.Processes[0].Name != "sshd" {
+		t.Errorf("unexpected socket %+v", established)
+	}
+	if sockets[2].Proto != "udp" || sockets[2].LocalAddr != "127.0.0.53" || sockets[2].PeerPort != "*" {
+		t.Errorf("unexpected socket %+v", sockets[2])
+	}
+	if _, err := ParseSS("LISTEN x 0 *:80 *:*"); err == nil {
+		t.Errorf("ParseSS() of the illegal queue succeeded")
+	}
+}
+
+func TestParseNetstat(t *testing.T) {
+	output := `Active Internet connections (servers and established)
+Proto Recv-Q Send-Q Local Address           Foreign Address         State       PID/Program name
+tcp        0      0 0.0.0.0:22              0.0.0.0:*               LISTEN      812/sshd: /usr/sbin
+tcp6       0      0 :::8080                 :::*                    LISTEN      -
+udp        0      0 0.0.0.0:68              0.0.0.0:*                           600/dhclient
+Active UNIX domain sockets (servers and established)
+unix  2      [ ACC ]     STREAM     LISTENING     17563    1/init               /run/systemd/private
+`
+	sockets, err := ParseNetstat(output)
+	if err != nil {
+		t.Fatalf("ParseNetstat() failed, %v", err)
+	}
+	if len(sockets) != 3 {
+		t.Fatalf("ParseNetstat() returns %d sockets, want 3", len(sockets))
+	}
+	if sockets[0].State != "LISTEN" || !reflect.DeepEqual(sockets[0].Processes, []SocketProcess{{Name: "sshd: /usr/sbin", Pid: 812}}) {
+		t.Errorf("unexpected socket %+v", sockets[0])
+	}
+	if sockets[1].LocalAddr != "::" || sockets[1].LocalPort != "8080" || len(sockets[1].Processes) != 0 {
+		t.Errorf("unexpected socket %+v", sockets[1])
+	}
+	if sockets[2].State != "" || sockets[2].Pids()[0] != 600 {
+		t.Errorf("unexpected udp socket %+v", sockets[2])
+	}
+}
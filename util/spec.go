@@ -17,9 +17,11 @@
 package util
 
 import (
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"strings"
 
 	"gopkg.in/yaml.v2"
 
@@ -46,31 +48,309 @@ func MarshalModelSpec(models *spec.Models, writer io.Writer) error {
 	return nil
 }
 
-// ParseSpecsToModel parses the yaml file to spec.Models and set the executor to the spec.Models
+// ParseSpecsToModel parses the yaml file to spec.Models and set the executor to the spec.Models,
+// the includes in the file are resolved by LoadSpecFile
 func ParseSpecsToModel(file string, executor spec.Executor) (*spec.Models, error) {
-	bytes, err := ioutil.ReadFile(file)
+	models, err := LoadSpecFile(file)
 	if err != nil {
 		return nil, err
 	}
-	models := &spec.Models{}
-	err = yaml.Unmarshal(bytes, models)
+	for idx := range models.Models {
+		models.Models[idx].ExpExecutor = executor
+	}
+	return models, nil
+}
+
+// ApplyModelOverridesFile overlays the user-provided yaml file onto the models at startup,
+// the unknown fields in the file are rejected to avoid silently ignored typos
+func ApplyModelOverridesFile(models *spec.Models, file string) error {
+	bytes, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	overrides := &spec.ModelOverrides{}
+	if err := yaml.UnmarshalStrict(bytes, overrides); err != nil {
+		return fmt.Errorf("parse the override file %s failed, %v", file, err)
+	}
+	return spec.ApplyOverrides(models, overrides)
+}
+
+// SpecValidationError contains all the problems found in the spec file
+type SpecValidationError struct {
+	File   string
+	Errors []string
+}
+
+func (e *SpecValidationError) Error() string {
+	return fmt.Sprintf("invalid spec file %s:\n  %s", e.File, strings.Join(e.Errors, "\n  "))
+}
+
+// ParseSpecsToModelStrict is like ParseSpecsToModel, but fails on unknown fields, type errors and missing
+// required keys instead of unmarshalling into zero values. It returns *SpecValidationError if the file is invalid.
+func ParseSpecsToModelStrict(file string, executor spec.Executor) (*spec.Models, error) {
+	bytes, err := ioutil.ReadFile(file)
 	if err != nil {
 		return nil, err
 	}
+	models, errs := ValidateModelSpec(bytes)
+	if len(errs) > 0 {
+		return nil, &SpecValidationError{File: file, Errors: errs}
+	}
 	for idx := range models.Models {
 		models.Models[idx].ExpExecutor = executor
 	}
 	return models, nil
 }
 
+// ValidateModelSpec unmarshals the spec content strictly and returns the models with all the problems found.
+// All problems are reported with line numbers and the field paths, the missing key is reported with the line
+// of the mapping which should contain it.
+func ValidateModelSpec(content []byte) (*spec.Models, []string) {
+	models := &spec.Models{}
+	if err := yaml.UnmarshalStrict(content, models); err != nil {
+		if typeErr, ok := err.(*yaml.TypeError); ok {
+			return models, typeErr.Errors
+		}
+		return models, []string{err.Error()}
+	}
+	errs := make([]string, 0)
+	required := func(value, path string) {
+		if strings.TrimSpace(value) == "" {
+			errs = append(errs, fmt.Sprintf("%s: required key is missing", path))
+		}
+	}
+	checkFlags := func(flags []spec.ExpFlag, path string) {
+		for idx, flag := range flags {
+			flagPath := fmt.Sprintf("%s[%d]", path, idx)
+			required(flag.Name, flagPath+".name")
+			if _, ok := flagTypes[flag.Type]; !ok {
+				errs = append(errs, fmt.Sprintf("%s.type: unknown type `%s`", flagPath, flag.Type))
+			}
+			if flag.Type == spec.FlagTypeEnum && len(flag.EnumValues) == 0 {
+				errs = append(errs, fmt.Sprintf("%s.enumValues: required for enum type", flagPath))
+			}
+			if _, ok := matchOperators[flag.Operator]; !ok {
+				errs = append(errs, fmt.Sprintf("%s.operator: unknown operator `%s`", flagPath, flag.Operator))
+			}
+			if flag.Schema != nil && flag.Type != spec.FlagTypeJSON {
+				errs = append(errs, fmt.Sprintf("%s.schema: only for json type", flagPath))
+			}
+			if flag.Hidden && flag.Required {
+				errs = append(errs, fmt.Sprintf("%s.hidden: the required flag can not be hidden", flagPath))
+			}
+			if err := spec.ValidateCondition(flag.RequiredIf); flag.RequiredIf != "" && err != nil {
+				errs = append(errs, fmt.Sprintf("%s.requiredIf: %v", flagPath, err))
+			}
+			if err := spec.ValidateCondition(flag.ForbiddenIf); flag.ForbiddenIf != "" && err != nil {
+				errs = append(errs, fmt.Sprintf("%s.forbiddenIf: %v", flagPath, err))
+			}
+		}
+	}
+	checkTimeout := func(timeout, path string) {
+		if _, err := spec.ParseDuration(timeout); timeout != "" && err != nil {
+			errs = append(errs, fmt.Sprintf("%s: illegal duration `%s`", path, timeout))
+		}
+	}
+	required(models.Version, "version")
+	required(models.Kind, "kind")
+	var checkModel func(model spec.ExpCommandModel, modelPath string)
+	checkModel = func(model spec.ExpCommandModel, modelPath string) {
+		required(model.ExpName, modelPath+".target")
+		checkFlags(model.ExpFlags, modelPath+".flags")
+		for groupIdx, group := range model.ExpFlagGroups {
+			groupPath := fmt.Sprintf("%s.flagGroups[%d]", modelPath, groupIdx)
+			if _, ok := flagGroupTypes[group.Type]; !ok {
+				errs = append(errs, fmt.Sprintf("%s.type: unknown type `%s`", groupPath, group.Type))
+			}
+			if len(group.Flags) < 2 {
+				errs = append(errs, fmt.Sprintf("%s.flags: at least two flags are required", groupPath))
+			}
+		}
+		for actionIdx, action := range model.ExpActions {
+			actionPath := fmt.Sprintf("%s.actions[%d]", modelPath, actionIdx)
+			required(action.ActionName, actionPath+".action")
+			checkTimeout(action.ActionDefaultTimeout, actionPath+".defaultTimeout")
+			checkTimeout(action.ActionMaxTimeout, actionPath+".maxTimeout")
+			checkTimeout(action.ActionDefaultDuration, actionPath+".defaultDuration")
+			checkTimeout(action.ActionMaxDuration, actionPath+".maxDuration")
+			if action.ActionMaxConcurrency < 0 {
+				errs = append(errs, fmt.Sprintf("%s.maxConcurrency: must not be negative", actionPath))
+			}
+			for idx, precondition := range action.ActionPreconditions {
+				if _, err := spec.ParsePrecondition(precondition); err != nil {
+					errs = append(errs, fmt.Sprintf("%s.preconditions[%d]: %v", actionPath, idx, err))
+				}
+			}
+			if requirements := action.ActionRequirements; requirements != nil {
+				if _, err := requirements.KernelVersionConstraint(); requirements.KernelVersion != "" && err != nil {
+					errs = append(errs, fmt.Sprintf("%s.requirements.kernelVersion: %v", actionPath, err))
+				}
+				switch requirements.CgroupVersion {
+				case "", spec.CgroupV1, spec.CgroupV2:
+				default:
+					errs = append(errs, fmt.Sprintf("%s.requirements.cgroupVersion: unknown version `%s`",
+						actionPath, requirements.CgroupVersion))
+				}
+			}
+			checkFlags(action.ActionMatchers, actionPath+".matchers")
+			checkFlags(action.ActionFlags, actionPath+".flags")
+		}
+		for subIdx, sub := range model.ExpSubModels {
+			checkModel(sub, fmt.Sprintf("%s.subModels[%d]", modelPath, subIdx))
+		}
+	}
+	for idx, model := range models.Models {
+		checkModel(model, fmt.Sprintf("items[%d]", idx))
+	}
+	lines := yamlLines(content)
+	for idx, err := range errs {
+		errs[idx] = fmt.Sprintf("line %d: %s", lines.locate(err[:strings.Index(err, ":")]), err)
+	}
+	return models, errs
+}
+
+// yamlLineIndex is the line numbers of the field paths in the yaml content, such as items[0].actions[1].flags
+type yamlLineIndex map[string]int
+
+// locate returns the line of the path, or the line of the nearest parent if the path is absent
+func (index yamlLineIndex) locate(path string) int {
+	for {
+		if line, ok := index[path]; ok {
+			return line
+		}
+		idx := strings.LastIndexAny(path, ".[")
+		if idx < 0 {
+			return index[""]
+		}
+		path = path[:idx]
+	}
+}
+
+// yamlLines indexes the lines of the mappings, keys and sequence items of the block style yaml, which is
+// written by MarshalModelSpec. The flow collections and the block scalars are not indexed.
+func yamlLines(content []byte) yamlLineIndex {
+	type mapping struct {
+		indent  int
+		path    string
+		lastKey string
+	}
+	join := func(path, key string) string {
+		if path == "" {
+			return key
+		}
+		return path + "." + key
+	}
+	index := make(yamlLineIndex)
+	counters := make(map[string]int)
+	stack := []*mapping{{indent: 0}}
+	scalarIndent := -1
+	for number, line := range strings.Split(string(content), "\n") {
+		number++
+		text := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if text == "" || strings.HasPrefix(text, "#") || text == "---" {
+			continue
+		}
+		if scalarIndent >= 0 && indent > scalarIndent {
+			continue
+		}
+		scalarIndent = -1
+		if _, ok := index[""]; !ok {
+			index[""] = number
+		}
+		for len(stack) > 1 && stack[len(stack)-1].indent > indent {
+			stack = stack[:len(stack)-1]
+		}
+		top := stack[len(stack)-1]
+		if text == "-" || strings.HasPrefix(text, "- ") {
+			sequence := join(top.path, top.lastKey)
+			item := fmt.Sprintf("%s[%d]", sequence, counters[sequence])
+			counters[sequence]++
+			index[item] = number
+			rest := strings.TrimLeft(strings.TrimPrefix(text, "-"), " ")
+			if _, _, ok := yamlKey(rest); !ok {
+				continue
+			}
+			// the mapping of the item starts after the dash
+			top = &mapping{indent: indent + len(text) - len(rest), path: item}
+			stack = append(stack, top)
+			text, indent = rest, top.indent
+		}
+		key, value, ok := yamlKey(text)
+		if !ok {
+			continue
+		}
+		if indent > top.indent {
+			top = &mapping{indent: indent, path: join(top.path, top.lastKey)}
+			stack = append(stack, top)
+		}
+		top.lastKey = key
+		index[join(top.path, key)] = number
+		if strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">") {
+			scalarIndent = indent
+		}
+	}
+	return index
+}
+
+// yamlKey returns the key and the value of the `key: value` line
+func yamlKey(text string) (key, value string, ok bool) {
+	idx := strings.Index(text, ":")
+	if idx <= 0 || (idx+1 < len(text) && text[idx+1] != ' ') || strings.HasPrefix(text, "{") ||
+		strings.HasPrefix(text, "[") {
+		return "", "", false
+	}
+	return strings.Trim(text[:idx], `"'`), strings.TrimSpace(text[idx+1:]), true
+}
+
+var matchOperators = map[string]struct{}{
+	"":                       {},
+	spec.MatchOperatorEquals: {},
+	spec.MatchOperatorRegex:  {},
+	spec.MatchOperatorIn:     {},
+	spec.MatchOperatorNot:    {},
+	spec.MatchOperatorPrefix: {},
+}
+
+var flagTypes = map[string]struct{}{
+	"":                    {},
+	spec.FlagTypeString:   {},
+	spec.FlagTypeInt:      {},
+	spec.FlagTypeFloat:    {},
+	spec.FlagTypeBool:     {},
+	spec.FlagTypeDuration: {},
+	spec.FlagTypeSize:     {},
+	spec.FlagTypePercent:  {},
+	spec.FlagTypeEnum:     {},
+	spec.FlagTypeJSON:     {},
+}
+
+var flagGroupTypes = map[string]struct{}{
+	spec.FlagGroupExactlyOne:        {},
+	spec.FlagGroupMutuallyExclusive: {},
+	spec.FlagGroupRequiredTogether:  {},
+	spec.FlagGroupRequires:          {},
+}
+
 // ConvertSpecToModels converts the spec.ExpModelCommandSpec to spec.Models
 func ConvertSpecToModels(commandSpec spec.ExpModelCommandSpec, prepare spec.ExpPrepareModel, scope string) *spec.Models {
 	models := &spec.Models{
-		Version: "v1",
-		Kind:    "plugin",
-		Models:  make([]spec.ExpCommandModel, 0),
+		Version:     "v1",
+		Kind:        "plugin",
+		SpecVersion: spec.SpecVersion,
+		Models:      make([]spec.ExpCommandModel, 0),
 	}
+	models.Models = append(models.Models, convertSpecToModel(commandSpec, nil, prepare, scope))
+	return models
+}
 
+// convertSpecToModel converts the command spec and its sub models recursively,
+// the flags of the parents are added to the actions of the sub models
+func convertSpecToModel(commandSpec spec.ExpModelCommandSpec, parentFlags []spec.ExpFlagSpec,
+	prepare spec.ExpPrepareModel, scope string) spec.ExpCommandModel {
+	targetFlags := make([]spec.ExpFlagSpec, 0)
+	targetFlags = append(targetFlags, commandSpec.Flags()...)
+	targetFlags = append(targetFlags, parentFlags...)
 	model := spec.ExpCommandModel{
 		ExpName:         commandSpec.Name(),
 		ExpShortDesc:    commandSpec.ShortDesc(),
@@ -79,8 +359,10 @@ func ConvertSpecToModels(commandSpec spec.ExpModelCommandSpec, prepare spec.ExpP
 		ExpSubTargets:   make([]string, 0),
 		ExpPrepareModel: prepare,
 		ExpScope:        scope,
+		ExpFlagGroups:   commandSpec.FlagGroups(),
+		ExpTranslations: commandSpec.Translations(),
 	}
-	for _, action := range commandSpec.Actions() {
+	for _, action := range spec.ActionsForScope(commandSpec, scope) {
 		actionModel := spec.ActionModel{
 			ActionName:      action.Name(),
 			ActionAliases:   action.Aliases(),
@@ -90,13 +372,7 @@ func ConvertSpecToModels(commandSpec spec.ExpModelCommandSpec, prepare spec.ExpP
 			ActionMatchers: func() []spec.ExpFlag {
 				matchers := make([]spec.ExpFlag, 0)
 				for _, m := range action.Matchers() {
-					matchers = append(matchers, spec.ExpFlag{
-						Name:                  m.FlagName(),
-						Desc:                  m.FlagDesc(),
-						NoArgs:                m.FlagNoArgs(),
-						Required:              m.FlagRequired(),
-						RequiredWhenDestroyed: m.FlagRequiredWhenDestroyed(),
-					})
+					matchers = append(matchers, convertFlagSpec(m))
 				}
 				return matchers
 			}(),
@@ -107,36 +383,24 @@ func ConvertSpecToModels(commandSpec spec.ExpModelCommandSpec, prepare spec.ExpP
 					if _, ok := flagsMap[m.FlagName()]; ok {
 						continue
 					}
-					flags = append(flags, spec.ExpFlag{
-						Name:                  m.FlagName(),
-						Desc:                  m.FlagDesc(),
-						NoArgs:                m.FlagNoArgs(),
-						Required:              m.FlagRequired(),
-						RequiredWhenDestroyed: m.FlagRequiredWhenDestroyed(),
-					})
+					flags = append(flags, convertFlagSpec(m))
 					flagsMap[m.FlagName()] = struct{}{}
 				}
-				for _, m := range commandSpec.Flags() {
+				for _, m := range targetFlags {
 					if _, ok := flagsMap[m.FlagName()]; ok {
 						continue
 					}
-					flags = append(flags, spec.ExpFlag{
-						Name:                  m.FlagName(),
-						Desc:                  m.FlagDesc(),
-						NoArgs:                m.FlagNoArgs(),
-						Required:              m.FlagRequired(),
-						RequiredWhenDestroyed: m.FlagRequiredWhenDestroyed(),
-					})
+					flags = append(flags, convertFlagSpec(m))
 					flagsMap[m.FlagName()] = struct{}{}
 				}
-				if _, ok := flagsMap["timeout"]; !ok {
+				if _, ok := flagsMap[spec.TimeoutFlag]; !ok {
 					flags = append(flags, spec.ExpFlag{
-						Name:                  "timeout",
+						Name:                  spec.TimeoutFlag,
 						Desc:                  "set timeout for experiment",
 						Required:              false,
 						RequiredWhenDestroyed: false,
 					})
-					flagsMap["timeout"] = struct{}{}
+					flagsMap[spec.TimeoutFlag] = struct{}{}
 				}
 				if _, ok := flagsMap["async"]; !ok {
 					flags = append(flags, spec.ExpFlag{
@@ -157,14 +421,58 @@ func ConvertSpecToModels(commandSpec spec.ExpModelCommandSpec, prepare spec.ExpP
 				}
 				return flags
 			}(),
-			ActionPrograms:    action.Programs(),
-			ActionCategories:  action.Categories(),
-			ActionProcessHang: action.ProcessHang(),
+			ActionPrograms:        action.Programs(),
+			ActionCategories:      action.Categories(),
+			ActionProcessHang:     action.ProcessHang(),
+			ActionDeprecated:      spec.ActionDeprecatedOf(action),
+			ActionReplacedBy:      spec.ActionReplacedByOf(action),
+			ActionDefaultTimeout:  spec.ActionDefaultTimeoutOf(action),
+			ActionMaxTimeout:      spec.ActionMaxTimeoutOf(action),
+			ActionMaxConcurrency:  spec.ActionMaxConcurrencyOf(action),
+			ActionDefaultDuration: spec.ActionDefaultDurationOf(action),
+			ActionMaxDuration:     spec.ActionMaxDurationOf(action),
+			ActionScopes:          spec.ActionScopesOf(action),
+			ActionPreconditions:   spec.ActionPreconditionsOf(action),
+			ActionRequirements:    spec.ActionRequirementsOf(action),
+			ActionExamples:        spec.ActionExamplesOf(action),
+			ActionTranslations:    spec.ActionTranslationsOf(action),
 		}
 		model.ExpActions = append(model.ExpActions, actionModel)
 	}
-	models.Models = append(models.Models, model)
-	return models
+	for _, sub := range commandSpec.SubModels() {
+		model.ExpSubModels = append(model.ExpSubModels, convertSpecToModel(sub, targetFlags, spec.ExpPrepareModel{}, scope))
+	}
+	return model
+}
+
+// convertFlagSpec returns the flag model of the flag spec
+func convertFlagSpec(m spec.ExpFlagSpec) spec.ExpFlag {
+	return spec.ExpFlag{
+		Name:                  m.FlagName(),
+		Desc:                  m.FlagDesc(),
+		NoArgs:                m.FlagNoArgs(),
+		Required:              m.FlagRequired(),
+		RequiredWhenDestroyed: m.FlagRequiredWhenDestroyed(),
+		Type:                  spec.FlagTypeOf(m),
+		EnumValues:            spec.FlagEnumValuesOf(m),
+		Validation:            spec.FlagValidationOf(m),
+		EnvVar:                spec.FlagEnvVarOf(m),
+		ConfigKey:             spec.FlagConfigKeyOf(m),
+		Deprecated:            spec.FlagDeprecatedOf(m),
+		ReplacedBy:            spec.FlagReplacedByOf(m),
+		Operator:              spec.FlagOperatorOf(m),
+		Examples:              spec.FlagExamplesOf(m),
+		Secret:                spec.FlagSecretOf(m),
+		Label:                 spec.FlagLabelOf(m),
+		Translations:          spec.FlagTranslationsOf(m),
+		RequiredIf:            spec.FlagRequiredIfOf(m),
+		ForbiddenIf:           spec.FlagForbiddenIfOf(m),
+		Repeated:              spec.FlagRepeatedOf(m),
+		Hidden:                spec.FlagHiddenOf(m),
+		Group:                 spec.FlagGroupOf(m),
+		Aliases:               spec.FlagAliasesOf(m),
+		Schema:                spec.FlagSchemaOf(m),
+	}
 }
 
 // AddModels adds the child model to parent
@@ -185,6 +493,7 @@ func MergeModels(models ...*spec.Models) *spec.Models {
 	for _, model := range models {
 		result.Version = model.Version
 		result.Kind = model.Kind
+		result.SpecVersion = model.SpecVersion
 		result.Models = append(result.Models, model.Models...)
 	}
 	return result
@@ -0,0 +1,146 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// specIncludes is the include directive of the spec file, the paths are relative to the including file
+type specIncludes struct {
+	Includes []string `yaml:"includes"`
+}
+
+// LoadSpecFile loads the spec file which may be split into fragments by the top-level includes, for example
+//
+//	includes: [flags.yaml, network.yaml]
+//	items:
+//	  - target: disk
+//	    actions:
+//	      - action: fill
+//	        flags:
+//	          - <<: *timeout
+//
+// The models of the included files are appended after the models of the including file, and the anchors
+// defined in the included files, such as the shared flag definitions, can be referenced by the aliases
+// and the merge keys in the including file. The includes are resolved recursively and cycles are rejected.
+func LoadSpecFile(file string) (*spec.Models, error) {
+	return loadSpecFile(file, make(map[string]bool))
+}
+
+func loadSpecFile(file string, loading map[string]bool) (*spec.Models, error) {
+	content, includes, err := expandSpecFile(file, loading)
+	if err != nil {
+		return nil, err
+	}
+	models := &spec.Models{}
+	if err := yaml.Unmarshal(content, models); err != nil {
+		return nil, fmt.Errorf("parse the spec file %s failed, %v", file, err)
+	}
+	for _, include := range includes {
+		included, err := loadSpecFile(include, loading)
+		if err != nil {
+			return nil, err
+		}
+		models.Models = append(models.Models, included.Models...)
+	}
+	return models, nil
+}
+
+// expandSpecFile returns the content of the file prefixed with the expanded content of the included files,
+// which are nested under the hidden keys so their anchors are defined before being referenced
+func expandSpecFile(file string, loading map[string]bool) ([]byte, []string, error) {
+	path, err := filepath.Abs(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	if loading[path] {
+		return nil, nil, fmt.Errorf("the spec file %s is included cyclically", file)
+	}
+	loading[path] = true
+	defer delete(loading, path)
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	directive := &specIncludes{}
+	if err := yaml.Unmarshal(includesSection(content), directive); err != nil {
+		return nil, nil, fmt.Errorf("parse the includes of the spec file %s failed, %v", file, err)
+	}
+	if len(directive.Includes) == 0 {
+		return content, nil, nil
+	}
+	var builder strings.Builder
+	includes := make([]string, 0, len(directive.Includes))
+	for idx, include := range directive.Includes {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(path), include)
+		}
+		included, _, err := expandSpecFile(include, loading)
+		if err != nil {
+			return nil, nil, err
+		}
+		builder.WriteString(fmt.Sprintf("x-include-%d:\n", idx))
+		builder.WriteString(indentYaml(included))
+		includes = append(includes, include)
+	}
+	builder.Write(content)
+	return []byte(builder.String()), includes, nil
+}
+
+// includesSection returns the top-level includes key with its value, the whole file can not be parsed
+// before the anchors of the included files are defined
+func includesSection(content []byte) []byte {
+	lines := strings.Split(string(content), "\n")
+	for idx, line := range lines {
+		if !strings.HasPrefix(line, "includes:") {
+			continue
+		}
+		end := idx + 1
+		for ; end < len(lines); end++ {
+			next := lines[end]
+			if strings.TrimSpace(next) != "" && !strings.HasPrefix(next, " ") && !strings.HasPrefix(next, "-") &&
+				!strings.HasPrefix(next, "#") {
+				break
+			}
+		}
+		return []byte(strings.Join(lines[idx:end], "\n"))
+	}
+	return nil
+}
+
+// indentYaml nests the yaml document under a key by indenting the non-empty lines
+func indentYaml(content []byte) string {
+	text := strings.TrimPrefix(string(content), "---\n")
+	var builder strings.Builder
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		if strings.TrimSpace(line) != "" {
+			builder.WriteString("  ")
+		}
+		builder.WriteString(line)
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSpecFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadSpecFile(t *testing.T) {
+	dir := writeSpecFiles(t, map[string]string{
+		"flags.yaml": `---
+flags:
+  timeout: &timeout
+    name: timeout
+    desc: timeout of the experiment
+    type: int
+`,
+		"network.yaml": `includes: [flags.yaml]
+items:
+  - target: network
+    actions:
+      - action: delay
+        flags:
+          - <<: *timeout
+            required: true
+`,
+		"main.yaml": `version: v1
+kind: plugin
+includes:
+  - flags.yaml
+  - network.yaml
+items:
+  - target: disk
+    actions:
+      - action: fill
+        flags:
+          - *timeout
+          - <<: *timeout
+            name: size
+`,
+	})
+	models, err := LoadSpecFile(filepath.Join(dir, "main.yaml"))
+	if err != nil {
+		t.Fatalf("LoadSpecFile() error = %v", err)
+	}
+	if models.Version != "v1" || len(models.Models) != 2 {
+		t.Fatalf("LoadSpecFile() = %+v, want disk and network", models)
+	}
+	disk, network := models.Models[0], models.Models[1]
+	if disk.ExpName != "disk" || network.ExpName != "network" {
+		t.Fatalf("LoadSpecFile() targets = %s, %s", disk.ExpName, network.ExpName)
+	}
+	flags := disk.ExpActions[0].ActionFlags
+	if flags[0].Name != "timeout" || flags[0].Type != "int" || flags[1].Name != "size" || flags[1].Desc != "timeout of the experiment" {
+		t.Errorf("disk flags = %+v", flags)
+	}
+	if flag := network.ExpActions[0].ActionFlags[0]; flag.Name != "timeout" || !flag.Required {
+		t.Errorf("network flag = %+v", flag)
+	}
+}
+
+func TestLoadSpecFile_Cycle(t *testing.T) {
+	dir := writeSpecFiles(t, map[string]string{
+		"a.yaml": "includes: [b.yaml]\n",
+		"b.yaml": "includes: [a.yaml]\n",
+	})
+	if _, err := LoadSpecFile(filepath.Join(dir, "a.yaml")); err == nil || !strings.Contains(err.Error(), "cyclically") {
+		t.Errorf("LoadSpecFile() error = %v, want cycle error", err)
+	}
+}
@@ -0,0 +1,194 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+func TestValidateModelSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name: "valid",
+			content: `version: v1
+kind: plugin
+items:
+- target: cpu
+  actions:
+  - action: fullload
+    flags:
+    - name: cpu-percent
+      type: int
+`,
+		},
+		{
+			name: "unknown field",
+			content: `version: v1
+kind: plugin
+items:
+- target: cpu
+  actions:
+  - action: fullload
+    flag: []
+`,
+			want: []string{"line 7: field flag not found"},
+		},
+		{
+			name: "type error",
+			content: `version: v1
+kind: plugin
+items:
+- target: cpu
+  actions:
+  - action: fullload
+    actionProcessHang: maybe
+`,
+			want: []string{"line 7: cannot unmarshal"},
+		},
+		{
+			name: "missing required",
+			content: `version: v1
+items:
+- actions:
+  - flags:
+    - name: mode
+      type: enum
+`,
+			want: []string{"line 1: kind: required", "line 3: items[0].target: required",
+				"line 4: items[0].actions[0].action: required", "line 5: items[0].actions[0].flags[0].enumValues: required"},
+		},
+		{
+			name: "illegal values",
+			content: `version: v1
+kind: plugin
+items:
+- target: process
+  actions:
+  - action: kill
+    defaultTimeout: 1x
+    matchers:
+    - name: process
+      operator: contains
+`,
+			want: []string{"line 7: items[0].actions[0].defaultTimeout: illegal duration",
+				"line 10: items[0].actions[0].matchers[0].operator: unknown operator"},
+		},
+		{
+			name: "json flags",
+			content: `version: v1
+kind: plugin
+items:
+- target: http
+  actions:
+  - action: abort
+    flags:
+    - name: rules
+      type: json
+      schema:
+        type: array
+        items:
+          type: object
+          required: [path]
+    - name: path
+      schema:
+        type: string
+`,
+			want: []string{"line 16: items[0].actions[0].flags[1].schema: only for json type"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errs := ValidateModelSpec([]byte(tt.content))
+			if len(errs) != len(tt.want) {
+				t.Fatalf("ValidateModelSpec() errs = %v, want %v", errs, tt.want)
+			}
+			for idx, err := range errs {
+				if !strings.HasPrefix(err, tt.want[idx]) {
+					t.Errorf("ValidateModelSpec() err = %s, want prefix %s", err, tt.want[idx])
+				}
+			}
+		})
+	}
+}
+
+func TestYamlLines(t *testing.T) {
+	content := `version: v1
+kind: plugin
+items:
+- target: cpu
+  longDesc: |
+    - target: fake
+  actions:
+  - action: fullload
+    flags:
+    - name: cpu-percent
+  -   action: burn
+- actions: []
+  subModels:
+  - target: pod
+`
+	lines := yamlLines([]byte(content))
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"kind", 2},
+		{"items[0]", 4},
+		{"items[0].actions[0]", 8},
+		{"items[0].actions[0].flags[0].name", 10},
+		{"items[0].actions[1].action", 11},
+		{"items[1]", 12},
+		{"items[1].target", 12},
+		{"items[1].subModels[0].target", 14},
+		{"items[2].target", 3},
+	}
+	for _, tt := range tests {
+		if got := lines.locate(tt.path); got != tt.want {
+			t.Errorf("locate(%s) = %d, want %d", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestConvertSpecToModelsWithSubModels(t *testing.T) {
+	root := &spec.ExpCommandModel{
+		ExpName:  "k8s",
+		ExpFlags: []spec.ExpFlag{{Name: "kubeconfig"}},
+		ExpSubModels: []spec.ExpCommandModel{{
+			ExpName:    "pod",
+			ExpFlags:   []spec.ExpFlag{{Name: "names"}},
+			ExpActions: []spec.ActionModel{{ActionName: "delete"}},
+		}},
+	}
+	models := ConvertSpecToModels(root, spec.ExpPrepareModel{}, "")
+	if len(models.Models) != 1 || len(models.Models[0].ExpSubModels) != 1 {
+		t.Fatalf("ConvertSpecToModels() = %+v, want one sub model", models.Models)
+	}
+	flags := make(map[string]bool)
+	for _, flag := range models.Models[0].ExpSubModels[0].ExpActions[0].ActionFlags {
+		flags[flag.Name] = true
+	}
+	if !flags["names"] || !flags["kubeconfig"] {
+		t.Errorf("sub model action flags = %v, want the inherited flags", flags)
+	}
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// sysctlPath returns the file of the kernel parameter in /proc/sys. The key is dotted like
+// net.ipv4.tcp_syncookies, or slashed like net/ipv4/conf/eth0.100/rp_filter if the names contain dots.
+// The key escaping /proc/sys by .. or the symlinks is rejected by SecureJoin.
+func sysctlPath(key string) (string, error) {
+	key = strings.TrimSpace(key)
+	if !strings.Contains(key, "/") {
+		key = strings.ReplaceAll(key, ".", "/")
+	}
+	if strings.Trim(key, "/") == "" {
+		return "", fmt.Errorf("illegal sysctl key `%s`", key)
+	}
+	file, err := SecureJoin(path.Join(procRoot, "sys"), key)
+	if err != nil {
+		return "", fmt.Errorf("illegal sysctl key `%s`, %v", key, err)
+	}
+	return file, nil
+}
+
+// SysctlGet returns the value of the kernel parameter without the trailing newline
+func SysctlGet(key string) (string, error) {
+	file, err := sysctlPath(key)
+	if err != nil {
+		return "", err
+	}
+	value, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("get sysctl %s failed, %v", key, err)
+	}
+	return strings.TrimRight(string(value), "\n"), nil
+}
+
+// SysctlSet sets the value of the kernel parameter, the original value is not recorded, see Sysctl
+func SysctlSet(key, value string) error {
+	file, err := sysctlPath(key)
+	if err != nil {
+		return err
+	}
+	// never create the file, the absent key is the unsupported parameter
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_TRUNC, 0)
+	if err == nil {
+		_, err = f.WriteString(value)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("set sysctl %s to %s failed, %v", key, value, err)
+	}
+	return nil
+}
+
+// Sysctl sets the kernel parameters and records their original values, so they are restored together. The
+// originals are kept in memory, persist Originals with the experiment and restore them by RestoreSysctl if the
+// experiment is destroyed by another process.
+type Sysctl struct {
+	lock      sync.Mutex
+	keys      []string
+	originals map[string]string
+}
+
+// NewSysctl returns the Sysctl recording nothing
+func NewSysctl() *Sysctl {
+	return &Sysctl{originals: make(map[string]string)}
+}
+
+// Get returns the current value of the kernel parameter
+func (s *Sysctl) Get(key string) (string, error) {
+	return SysctlGet(key)
+}
+
+// Set sets the kernel parameter, the value before the first Set of the key is recorded as the original
+func (s *Sysctl) Set(key, value string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.originals[key]; !ok {
+		original, err := SysctlGet(key)
+		if err != nil {
+			return err
+		}
+		s.originals[key] = original
+		s.keys = append(s.keys, key)
+	}
+	return SysctlSet(key, value)
+}
+
+// Originals returns a copy of the original values of the kernel parameters set
+func (s *Sysctl) Originals() map[string]string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	originals := make(map[string]string, len(s.originals))
+	for key, value := range s.originals {
+		originals[key] = value
+	}
+	return originals
+}
+
+// Restore restores the kernel parameters in the reverse order of setting, the restored ones are not recorded
+// anymore, so the failed ones can be retried. It returns the first error and keeps restoring the others.
+func (s *Sysctl) Restore() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var firstErr error
+	remaining := make([]string, 0)
+	for idx := len(s.keys) - 1; idx >= 0; idx-- {
+		key := s.keys[idx]
+		if err := SysctlSet(key, s.originals[key]); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			remaining = append([]string{key}, remaining...)
+			continue
+		}
+		delete(s.originals, key)
+	}
+	s.keys = remaining
+	return firstErr
+}
+
+// Rollback returns the rollback restoring the kernel parameters, it's registered by spec.RegisterRollback
+func (s *Sysctl) Rollback() spec.RollbackFunc {
+	return func(ctx context.Context) *spec.Response {
+		if err := s.Restore(); err != nil {
+			return spec.ResponseFailWithFlags(spec.OsCmdExecFailed, "sysctl", err)
+		}
+		return spec.ReturnSuccess("")
+	}
+}
+
+// RestoreSysctl restores the original values recorded by Sysctl.Originals, it returns the first error and keeps
+// restoring the others
+func RestoreSysctl(originals map[string]string) error {
+	var firstErr error
+	for key, value := range originals {
+		if err := SysctlSet(key, value); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
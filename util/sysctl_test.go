@@ -0,0 +1,87 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSysctl(t *testing.T) {
+	fakeProcFS(t, map[string]string{
+		"proc/sys/net/ipv4/tcp_syncookies":          "1\n",
+		"proc/sys/net/ipv4/tcp_rmem":                "4096\t131072\t6291456\n",
+		"proc/sys/net/ipv4/conf/eth0.100/rp_filter": "2\n",
+	})
+	if value, err := SysctlGet("net.ipv4.tcp_rmem"); err != nil || value != "4096\t131072\t6291456" {
+		t.Errorf("SysctlGet() = %q, %v", value, err)
+	}
+	if value, err := SysctlGet("net/ipv4/conf/eth0.100/rp_filter"); err != nil || value != "2" {
+		t.Errorf("SysctlGet() of the slashed key = %q, %v", value, err)
+	}
+	outside := t.TempDir()
+	os.WriteFile(filepath.Join(outside, "passwd"), []byte("root"), 0644)
+	if err := os.Symlink(outside, filepath.Join(procRoot, "sys", "escape")); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"", "../../etc/passwd", "net.ipv4.absent", "escape/passwd"} {
+		if _, err := SysctlGet(key); err == nil {
+			t.Errorf("SysctlGet(%q) succeeded, want error", key)
+		}
+	}
+
+	sysctl := NewSysctl()
+	if err := sysctl.Set("net.ipv4.tcp_syncookies", "0"); err != nil {
+		t.Fatalf("Set() failed, %v", err)
+	}
+	if err := sysctl.Set("net.ipv4.tcp_syncookies", "2"); err != nil {
+		t.Fatalf("Set() failed, %v", err)
+	}
+	if value, _ := sysctl.Get("net.ipv4.tcp_syncookies"); value != "2" {
+		t.Errorf("Get() = %q, want 2", value)
+	}
+	if originals := sysctl.Originals(); originals["net.ipv4.tcp_syncookies"] != "1" || len(originals) != 1 {
+		t.Errorf("Originals() = %v, want the value before the first Set", originals)
+	}
+	if err := sysctl.Set("net.ipv4.absent", "1"); err == nil {
+		t.Errorf("Set() of the absent key succeeded")
+	}
+	if response := sysctl.Rollback()(context.Background()); !response.Success {
+		t.Fatalf("Rollback() = %v", response)
+	}
+	if value, _ := SysctlGet("net.ipv4.tcp_syncookies"); value != "1" {
+		t.Errorf("the value after restoring = %q, want 1", value)
+	}
+	if len(sysctl.Originals()) != 0 {
+		t.Errorf("Originals() after restoring = %v, want empty", sysctl.Originals())
+	}
+
+	if err := SysctlSet("net.ipv4.tcp_syncookies", "0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SysctlSet("net.ipv4.absent", "1"); err == nil {
+		t.Errorf("SysctlSet() of the absent key succeeded")
+	}
+	if err := RestoreSysctl(map[string]string{"net.ipv4.tcp_syncookies": "1"}); err != nil {
+		t.Errorf("RestoreSysctl() failed, %v", err)
+	}
+	if value, _ := SysctlGet("net.ipv4.tcp_syncookies"); value != "1" {
+		t.Errorf("the value after RestoreSysctl = %q, want 1", value)
+	}
+}
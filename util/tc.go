@@ -0,0 +1,101 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TcQdisc is the queueing discipline parsed from the `tc qdisc show` output
+type TcQdisc struct {
+	// Kind is the qdisc type, such as netem, prio or pfifo_fast
+	Kind   string
+	Handle string
+	Dev    string
+	// Parent is root, ingress or the parent class id, such as 1:4
+	Parent string
+	Refcnt int
+	// Options are the qdisc specific options, such as limit 1000 delay 100ms 10ms loss 10%
+	Options []string
+}
+
+// IsRoot returns true if the qdisc is the root qdisc of the device
+func (q TcQdisc) IsRoot() bool {
+	return q.Parent == "root"
+}
+
+// Option returns the value following the option name, such as 100ms of delay
+func (q TcQdisc) Option(name string) (string, bool) {
+	for idx, option := range q.Options {
+		if option == name && idx+1 < len(q.Options) {
+			return q.Options[idx+1], true
+		}
+	}
+	return "", false
+}
+
+// ParseTcQdisc parses the output of `tc qdisc show`, the statistics lines of -s are skipped
+//
+//	qdisc netem 8001: dev eth0 root refcnt 2 limit 1000 delay 100ms  10ms loss 10%
+//	qdisc netem 20: dev eth0 parent 1:4 limit 1000 delay 3s
+func ParseTcQdisc(output string) ([]TcQdisc, error) {
+	qdiscs := make([]TcQdisc, 0)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] != "qdisc" {
+			continue
+		}
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("illegal qdisc line `%s`", line)
+		}
+		qdisc := TcQdisc{Kind: fields[1], Handle: fields[2]}
+		idx := 3
+	loop:
+		for idx < len(fields) {
+			switch fields[idx] {
+			case "dev", "parent", "refcnt":
+				if idx+1 == len(fields) {
+					return nil, fmt.Errorf("the value of %s is missing in the qdisc line `%s`", fields[idx], line)
+				}
+				value := fields[idx+1]
+				switch fields[idx] {
+				case "dev":
+					qdisc.Dev = value
+				case "parent":
+					qdisc.Parent = value
+				case "refcnt":
+					refcnt, err := strconv.Atoi(value)
+					if err != nil {
+						return nil, fmt.Errorf("illegal refcnt %s in the qdisc line `%s`", value, line)
+					}
+					qdisc.Refcnt = refcnt
+				}
+				idx += 2
+			case "root", "ingress", "clsact":
+				qdisc.Parent = fields[idx]
+				idx++
+			default:
+				break loop
+			}
+		}
+		qdisc.Options = fields[idx:]
+		qdiscs = append(qdiscs, qdisc)
+	}
+	return qdiscs, nil
+}
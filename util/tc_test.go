@@ -0,0 +1,56 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"testing"
+)
+
+func TestParseTcQdisc(t *testing.T) {
+	output := `qdisc noqueue 0: dev lo root refcnt 2
+qdisc prio 1: dev eth0 root refcnt 2 bands 4 priomap 1 2 2 2 1 2 0 0 1 1 1 1 1 1 1 1
+ Sent 1000 bytes 10 pkt (dropped 0, overlimits 0 requeues 0)
+qdisc netem 20: dev eth0 parent 1:4 limit 1000 delay 100ms  10ms loss 10%
+qdisc ingress ffff: dev eth0 parent ffff:fff1 ----------------
+`
+	qdiscs, err := ParseTcQdisc(output)
+	if err != nil {
+		t.Fatalf("ParseTcQdisc() failed, %v", err)
+	}
+	if len(qdiscs) != 4 {
+		t.Fatalf("ParseTcQdisc() returns %d qdiscs, want 4", len(qdiscs))
+	}
+	if !qdiscs[1].IsRoot() || qdiscs[1].Refcnt != 2 || qdiscs[1].Kind != "prio" || qdiscs[1].Handle != "1:" {
+		t.Errorf("unexpected qdisc %+v", qdiscs[1])
+	}
+	netem := qdiscs[2]
+	if netem.Dev != "eth0" || netem.Parent != "1:4" || netem.IsRoot() {
+		t.Errorf("unexpected qdisc %+v", netem)
+	}
+	if delay, ok := netem.Option("delay"); !ok || delay != "100ms" {
+		t.Errorf("delay = %s, %t, want 100ms", delay, ok)
+	}
+	if loss, _ := netem.Option("loss"); loss != "10%" {
+		t.Errorf("loss = %s, want 10%%", loss)
+	}
+	if _, ok := netem.Option("rate"); ok {
+		t.Errorf("Option(rate) is found")
+	}
+	if _, err := ParseTcQdisc("qdisc netem 1: dev eth0 refcnt x"); err == nil {
+		t.Errorf("ParseTcQdisc() of the illegal refcnt succeeded")
+	}
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tempDirBase is the directory containing all temp directories created by NewTempDir, replaced in the tests
+var tempDirBase = filepath.Join(os.TempDir(), "chaosblade")
+
+var (
+	tempDirs     = make(map[string]*TempDir)
+	tempDirsLock sync.Mutex
+)
+
+// TempDir is the temp directory registered in the process-wide cleanup registry, it's removed by Remove,
+// CleanupTempDirs, or PurgeExpired of the later processes if the process crashes.
+type TempDir struct {
+	path string
+}
+
+// NewTempDir creates the temp directory only accessible by the current user, the name starts with the prefix
+func NewTempDir(prefix string) (*TempDir, error) {
+	if err := os.MkdirAll(tempDirBase, 0755); err != nil {
+		return nil, err
+	}
+	// the base in the shared temp directory may be created by others, never follow the symlink
+	info, err := os.Lstat(tempDirBase)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("the temp directory base %s is not a directory", tempDirBase)
+	}
+	path, err := os.MkdirTemp(tempDirBase, strings.TrimSuffix(prefix, "-")+"-")
+	if err != nil {
+		return nil, err
+	}
+	dir := &TempDir{path: path}
+	tempDirsLock.Lock()
+	defer tempDirsLock.Unlock()
+	tempDirs[path] = dir
+	return dir, nil
+}
+
+// Path returns the path of the temp directory
+func (dir *TempDir) Path() string {
+	return dir.path
+}
+
+// Join returns the path of the file in the temp directory, such as the script to execute. The path escaping the
+// temp directory by .. or the symlinks is rejected by SecureJoin.
+func (dir *TempDir) Join(elem ...string) (string, error) {
+	return SecureJoin(dir.path, filepath.Join(elem...))
+}
+
+// Remove removes the temp directory and unregisters it
+func (dir *TempDir) Remove() error {
+	tempDirsLock.Lock()
+	delete(tempDirs, dir.path)
+	tempDirsLock.Unlock()
+	return os.RemoveAll(dir.path)
+}
+
+// CleanupTempDirs removes all temp directories created by the current process, it returns the first error
+// and keeps removing the others
+func CleanupTempDirs() error {
+	tempDirsLock.Lock()
+	dirs := make([]*TempDir, 0, len(tempDirs))
+	for _, dir := range tempDirs {
+		dirs = append(dirs, dir)
+	}
+	tempDirsLock.Unlock()
+	var firstErr error
+	for _, dir := range dirs {
+		if err := dir.Remove(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+type tempDirShutdownHook struct{}
+
+func (tempDirShutdownHook) Shutdown() error {
+	return CleanupTempDirs()
+}
+
+// TempDirShutdownHook returns the hook of Hold which cleans up the temp directories
+func TempDirShutdownHook() ShutdownHook {
+	return tempDirShutdownHook{}
+}
+
+// PurgeExpired removes the temp directories not modified within the age, such as the ones left by the crashed
+// processes. The directories of the current process are kept. It returns the count of the removed directories.
+func PurgeExpired(age time.Duration) (int, error) {
+	entries, err := os.ReadDir(tempDirBase)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	tempDirsLock.Lock()
+	defer tempDirsLock.Unlock()
+	deadline := time.Now().Add(-age)
+	purged := 0
+	var firstErr error
+	for _, entry := range entries {
+		path := filepath.Join(tempDirBase, entry.Name())
+		if _, ok := tempDirs[path]; ok || !entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(deadline) {
+			continue
+		}
+		if err := os.RemoveAll(path); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		purged++
+	}
+	return purged, firstErr
+}
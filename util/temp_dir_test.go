@@ -0,0 +1,81 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTempDir(t *testing.T) {
+	oldBase := tempDirBase
+	tempDirBase = filepath.Join(t.TempDir(), "chaosblade")
+	defer func() { tempDirBase = oldBase }()
+
+	dir, err := NewTempDir("script")
+	if err != nil {
+		t.Fatalf("NewTempDir() failed, %v", err)
+	}
+	if !strings.HasPrefix(filepath.Base(dir.Path()), "script-") {
+		t.Errorf("Path() = %s, want the prefix script-", dir.Path())
+	}
+	if info, err := os.Stat(dir.Path()); err != nil || info.Mode().Perm() != 0700 {
+		t.Errorf("the temp directory mode = %v, %v, want 0700", info, err)
+	}
+	script, err := dir.Join("run.sh")
+	if err != nil {
+		t.Fatalf("Join() failed, %v", err)
+	}
+	if err := os.WriteFile(script, []byte("echo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dir.Join("..", "run.sh"); !errors.Is(err, ErrPathEscape) {
+		t.Errorf("Join() of the escaping path = %v, want ErrPathEscape", err)
+	}
+
+	expired := filepath.Join(tempDirBase, "script-expired")
+	if err := os.Mkdir(expired, 0700); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	for _, path := range []string{expired, dir.Path()} {
+		if err := os.Chtimes(path, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if purged, err := PurgeExpired(time.Hour); err != nil || purged != 1 {
+		t.Errorf("PurgeExpired() = %d, %v, want 1", purged, err)
+	}
+	if IsExist(expired) || !IsExist(dir.Path()) {
+		t.Errorf("PurgeExpired() removed the wrong directories")
+	}
+
+	other, err := NewTempDir("download")
+	if err != nil {
+		t.Fatalf("NewTempDir() failed, %v", err)
+	}
+	if err := other.Remove(); err != nil || IsExist(other.Path()) {
+		t.Errorf("Remove() = %v, want removed", err)
+	}
+	if err := TempDirShutdownHook().Shutdown(); err != nil || IsExist(dir.Path()) {
+		t.Errorf("Shutdown() = %v, want the temp directories removed", err)
+	}
+}
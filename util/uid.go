@@ -0,0 +1,232 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// The kinds of the uid providers created by NewUIDProvider
+const (
+	// UIDKindRandom is the default 16 hex characters
+	UIDKindRandom = "random"
+	// UIDKindUUID is the random uuid of version 4, such as 3f8e1c2a-9b4d-4e6f-8a1b-2c3d4e5f6a7b
+	UIDKindUUID = "uuid"
+	// UIDKindULID is the lexicographically sortable ulid, such as 01ARZ3NDEKTSV4RRFFQ69G5FAV
+	UIDKindULID = "ulid"
+	// UIDKindSnowflake is the 19 decimal digits sortable id containing the node id, unique across the nodes
+	UIDKindSnowflake = "snowflake"
+)
+
+const (
+	snowflakeNodeBits     = 10
+	snowflakeSequenceBits = 12
+	// SnowflakeMaxNode is the max node id of the snowflake provider
+	SnowflakeMaxNode = 1<<snowflakeNodeBits - 1
+	snowflakeMaxSeq  = 1<<snowflakeSequenceBits - 1
+)
+
+// snowflakeEpoch is the start of the snowflake timestamps, 2020-01-01 UTC
+var snowflakeEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// crockfordAlphabet is the base32 alphabet of ulid, without I, L, O and U
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewUIDProvider returns the provider of the kind for spec.SetUIDProvider, the node id is only used by snowflake
+// and must be unique in the deployment, such as the ordinal of the statefulset pod
+func NewUIDProvider(kind string, node int64) (spec.UIDProvider, error) {
+	switch kind {
+	case "", UIDKindRandom:
+		return randomUID, nil
+	case UIDKindUUID:
+		return NewUUID, nil
+	case UIDKindULID:
+		return NewULIDProvider(), nil
+	case UIDKindSnowflake:
+		return NewSnowflakeProvider(node)
+	default:
+		return nil, fmt.Errorf("unsupported uid kind %s, must be one of %s", kind,
+			strings.Join([]string{UIDKindRandom, UIDKindUUID, UIDKindULID, UIDKindSnowflake}, ", "))
+	}
+}
+
+// UseUIDProvider sets the provider of the kind by spec.SetUIDProvider, so GenerateUid returns the ids of the kind
+func UseUIDProvider(kind string, node int64) error {
+	provider, err := NewUIDProvider(kind, node)
+	if err != nil {
+		return err
+	}
+	spec.SetUIDProvider(provider)
+	return nil
+}
+
+// randomUID returns 16 hex characters, the same as the default provider of spec
+func randomUID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// NewUUID returns a random uuid of version 4, or empty if the random source fails
+func NewUUID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// IsUUID returns true if the value is a uuid in the canonical form, any version
+func IsUUID(value string) bool {
+	if len(value) != 36 {
+		return false
+	}
+	for idx, c := range value {
+		switch idx {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// NewULIDProvider returns the provider of the monotonic ulids, the ulids generated in the same millisecond
+// increase the random part, so they are sorted in the generated order
+func NewULIDProvider() spec.UIDProvider {
+	var lock sync.Mutex
+	var lastTime uint64
+	var lastEntropy [10]byte
+	return func() string {
+		lock.Lock()
+		defer lock.Unlock()
+		now := uint64(time.Now().UnixMilli())
+		if now <= lastTime {
+			// increase the entropy as a big endian number, move to the next millisecond if it overflows
+			now = lastTime
+			overflow := true
+			for idx := len(lastEntropy) - 1; idx >= 0 && overflow; idx-- {
+				lastEntropy[idx]++
+				overflow = lastEntropy[idx] == 0
+			}
+			if overflow {
+				now++
+			}
+		} else if _, err := rand.Read(lastEntropy[:]); err != nil {
+			return ""
+		}
+		lastTime = now
+		return encodeULID(now, lastEntropy)
+	}
+}
+
+func encodeULID(ms uint64, entropy [10]byte) string {
+	// 48 bits timestamp and 80 bits entropy are 128 bits, encoded to 26 characters of 5 bits
+	var b [16]byte
+	b[0], b[1], b[2], b[3], b[4], b[5] = byte(ms>>40), byte(ms>>32), byte(ms>>24), byte(ms>>16), byte(ms>>8), byte(ms)
+	copy(b[6:], entropy[:])
+	hi, lo := binary.BigEndian.Uint64(b[:8]), binary.BigEndian.Uint64(b[8:])
+	var out [26]byte
+	for idx := 25; idx >= 0; idx-- {
+		out[idx] = crockfordAlphabet[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// IsULID returns true if the value is a ulid of 26 crockford base32 characters
+func IsULID(value string) bool {
+	_, err := ULIDTime(value)
+	return err == nil
+}
+
+// ULIDTime returns the time encoded in the ulid
+func ULIDTime(value string) (time.Time, error) {
+	if len(value) != 26 || value[0] > '7' {
+		return time.Time{}, fmt.Errorf("illegal ulid %s", value)
+	}
+	var ms uint64
+	for idx, c := range strings.ToUpper(value) {
+		digit := strings.IndexRune(crockfordAlphabet, c)
+		if digit < 0 {
+			return time.Time{}, fmt.Errorf("illegal ulid %s", value)
+		}
+		// the first 10 characters are the 48 bits timestamp with 2 leading zero bits
+		if idx < 10 {
+			ms = ms<<5 | uint64(digit)
+		}
+	}
+	return time.UnixMilli(int64(ms)), nil
+}
+
+// NewSnowflakeProvider returns the provider of the snowflake ids of the node, the ids are 41 bits milliseconds
+// since 2020, 10 bits node id and 12 bits sequence, formatted as 19 decimal digits so they sort as strings.
+// The provider never blocks, it borrows the next millisecond if the sequence runs out or the clock goes back.
+func NewSnowflakeProvider(node int64) (spec.UIDProvider, error) {
+	if node < 0 || node > SnowflakeMaxNode {
+		return nil, fmt.Errorf("illegal snowflake node %d, must be in [0, %d]", node, SnowflakeMaxNode)
+	}
+	var lock sync.Mutex
+	var lastTime, sequence int64
+	return func() string {
+		lock.Lock()
+		defer lock.Unlock()
+		now := time.Since(snowflakeEpoch).Milliseconds()
+		if now <= lastTime {
+			now = lastTime
+			sequence++
+			if sequence > snowflakeMaxSeq {
+				now, sequence = now+1, 0
+			}
+		} else {
+			sequence = 0
+		}
+		lastTime = now
+		id := now<<(snowflakeNodeBits+snowflakeSequenceBits) | node<<snowflakeSequenceBits | sequence
+		return fmt.Sprintf("%019d", id)
+	}, nil
+}
+
+// ParseSnowflake returns the time and the node id encoded in the snowflake id
+func ParseSnowflake(value string) (time.Time, int64, error) {
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 0 || len(value) != 19 {
+		return time.Time{}, 0, fmt.Errorf("illegal snowflake id %s", value)
+	}
+	ms := id >> (snowflakeNodeBits + snowflakeSequenceBits)
+	node := id >> snowflakeSequenceBits & SnowflakeMaxNode
+	return snowflakeEpoch.Add(time.Duration(ms) * time.Millisecond), node, nil
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+func TestUIDProviders(t *testing.T) {
+	for _, kind := range []string{UIDKindRandom, UIDKindUUID, UIDKindULID, UIDKindSnowflake} {
+		t.Run(kind, func(t *testing.T) {
+			if err := UseUIDProvider(kind, 7); err != nil {
+				t.Fatalf("UseUIDProvider() failed, %v", err)
+			}
+			defer spec.SetUIDProvider(nil)
+			uids := make([]string, 0, 10000)
+			seen := make(map[string]bool)
+			for i := 0; i < cap(uids); i++ {
+				uid, err := GenerateUid()
+				if err != nil {
+					t.Fatalf("GenerateUid() failed, %v", err)
+				}
+				if seen[uid] {
+					t.Fatalf("GenerateUid() returned the duplicate uid %s", uid)
+				}
+				seen[uid] = true
+				uids = append(uids, uid)
+			}
+			if (kind == UIDKindULID || kind == UIDKindSnowflake) && !sort.StringsAreSorted(uids) {
+				t.Errorf("the %s uids are not sorted", kind)
+			}
+		})
+	}
+	if _, err := NewUIDProvider("uuid7", 0); err == nil {
+		t.Errorf("NewUIDProvider() of the unsupported kind succeeded")
+	}
+	if _, err := NewSnowflakeProvider(SnowflakeMaxNode + 1); err == nil {
+		t.Errorf("NewSnowflakeProvider() of the illegal node succeeded")
+	}
+}
+
+func TestUIDValidation(t *testing.T) {
+	uuid := NewUUID()
+	if !IsUUID(uuid) || uuid[14] != '4' {
+		t.Errorf("NewUUID() = %s, want a uuid of version 4", uuid)
+	}
+	for _, value := range []string{"", "3f8e1c2a-9b4d-4e6f-8a1b-2c3d4e5f6a7", "3f8e1c2a_9b4d-4e6f-8a1b-2c3d4e5f6a7b", "3f8e1c2a-9b4d-4e6f-8a1b-2c3d4e5f6a7g"} {
+		if IsUUID(value) {
+			t.Errorf("IsUUID(%q) = true, want false", value)
+		}
+	}
+
+	before := time.Now().Truncate(time.Millisecond)
+	ulid := NewULIDProvider()()
+	if got, err := ULIDTime(ulid); err != nil || got.Before(before) || time.Since(got) > time.Second {
+		t.Errorf("ULIDTime(%s) = %v, %v, want about %v", ulid, got, err, before)
+	}
+	if got, err := ULIDTime("01ARZ3NDEKTSV4RRFFQ69G5FAV"); err != nil || got.UnixMilli() != 1469922850259 {
+		t.Errorf("ULIDTime() = %v, %v, want 1469922850259", got.UnixMilli(), err)
+	}
+	for _, value := range []string{"01ARZ3NDEKTSV4RRFFQ69G5FA", "81ARZ3NDEKTSV4RRFFQ69G5FAV", "01ARZ3NDEKTSV4RRFFQ69G5FAU"} {
+		if IsULID(value) {
+			t.Errorf("IsULID(%q) = true, want false", value)
+		}
+	}
+
+	provider, _ := NewSnowflakeProvider(42)
+	id := provider()
+	if got, node, err := ParseSnowflake(id); err != nil || node != 42 || time.Since(got) > time.Second {
+		t.Errorf("ParseSnowflake(%s) = %v, %d, %v, want node 42", id, got, node, err)
+	}
+	if _, _, err := ParseSnowflake("123"); err == nil {
+		t.Errorf("ParseSnowflake() of the short id succeeded")
+	}
+}
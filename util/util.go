@@ -95,14 +95,9 @@ func GetYamlHome() string {
 	return yamlPath
 }
 
-// GenerateUid for exp
+// GenerateUid for exp, the uid is generated by the provider set by UseUIDProvider or spec.SetUIDProvider
 func GenerateUid() (string, error) {
-	b := make([]byte, 8)
-	_, err := rand.Read(b)
-	if err != nil {
-		return "", err
-	}
-	return hex.EncodeToString(b), nil
+	return spec.GenerateUID()
 }
 
 // GenerateContainerId for container
@@ -0,0 +1,34 @@
+/*
+ * Copyright 1999-2019 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// CompareVersions compares the dotted numeric versions, such as 4.19.91 and v1.8, it returns -1 if v1 < v2,
+// 0 if v1 == v2, otherwise 1. The pre-release and build suffixes are ignored, so the kernel releases like
+// 5.10.0-60.el8.x86_64 are compared by 5.10.0.
+func CompareVersions(v1, v2 string) (int, error) {
+	return spec.CompareVersion(v1, v2)
+}
+
+// MatchVersionConstraint returns true if the version satisfies the constraint, such as ">=1.2.0, <2.0" or
+// "~4.19 || >=5.4", see spec.VersionConstraint for the syntax
+func MatchVersionConstraint(version, constraint string) (bool, error) {
+	return spec.MatchVersion(version, constraint)
+}